@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/pkg/client"
+)
+
+// command is a subcommand. setup registers its flags and returns what runs
+// once they are parsed; required flags are checked, and destructive
+// commands get a --yes flag they need, before logging in.
+type command struct {
+	name        string
+	required    []string
+	destructive bool
+	setup       func(fs *flag.FlagSet) func(ctx context.Context, a *app) error
+}
+
+var commands = []command{
+	{name: "venue create", required: []string{"location", "capacity"}, setup: createVenue},
+	{name: "performer create", required: []string{"name"}, setup: createPerformer},
+	{name: "event create", required: []string{"venue", "performer", "name", "date"}, setup: createEvent},
+	{name: "tickets generate", required: []string{"event", "seat-map"}, setup: generateTickets},
+	{name: "cdc sync", required: []string{"event"}, setup: syncEvent},
+	{name: "cdc reindex", destructive: true, setup: reindex},
+	{name: "bookings list", required: []string{"user"}, setup: listBookings},
+	{name: "bookings cancel", required: []string{"booking"}, destructive: true, setup: cancelBooking},
+	{name: "users grant-admin", required: []string{"user"}, destructive: true, setup: setRole("admin")},
+	{name: "users revoke-admin", required: []string{"user"}, destructive: true, setup: setRole("user")},
+	{name: "queue", required: []string{"event"}, setup: showQueue},
+	{name: "stats", required: []string{"event"}, setup: showStats},
+}
+
+// lookup finds the command named by the leading args and returns the
+// args after its name
+func lookup(args []string) (*command, []string, bool) {
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == commands[i].name {
+			return &commands[i], args[len(words):], true
+		}
+	}
+	return nil, nil, false
+}
+
+func createVenue(fs *flag.FlagSet) func(context.Context, *app) error {
+	location := fs.String("location", "", "where the venue is, e.g. \"Royal Albert Hall, London\"")
+	capacity := fs.Int("capacity", 0, "attendees the venue holds")
+	country := fs.String("country", "", "ISO 3166-1 alpha-2 code; picks the tax rate on receipts")
+	seatMapFile := fs.String("seat-map", "", "JSON seat map file to store with the venue")
+
+	return func(ctx context.Context, a *app) error {
+		input := client.VenueInput{Location: *location, Capacity: *capacity, Country: *country}
+		if *seatMapFile != "" {
+			data, err := os.ReadFile(*seatMapFile)
+			if err != nil {
+				return err
+			}
+			if _, err := parseSeatMap(data); err != nil {
+				return err
+			}
+			input.SeatMap = string(data)
+		}
+
+		venue, err := a.client.CreateVenue(ctx, input)
+		if err != nil {
+			return err
+		}
+		return a.print(venue, nil, [][]string{
+			{"ID", fmt.Sprint(venue.ID)},
+			{"Location", venue.Location},
+			{"Capacity", fmt.Sprint(venue.Capacity)},
+			{"Country", venue.Country},
+		})
+	}
+}
+
+func createPerformer(fs *flag.FlagSet) func(context.Context, *app) error {
+	name := fs.String("name", "", "performer name")
+	genre := fs.String("genre", "", "genre, used for recommendations")
+	description := fs.String("description", "", "description")
+
+	return func(ctx context.Context, a *app) error {
+		performer, err := a.client.CreatePerformer(ctx, client.PerformerInput{Name: *name, Genre: *genre, Description: *description})
+		if err != nil {
+			return err
+		}
+		return a.print(performer, nil, [][]string{
+			{"ID", fmt.Sprint(performer.ID)},
+			{"Name", performer.Name},
+			{"Genre", performer.Genre},
+		})
+	}
+}
+
+func createEvent(fs *flag.FlagSet) func(context.Context, *app) error {
+	venueID := fs.Uint("venue", 0, "venue ID")
+	performerID := fs.Uint("performer", 0, "performer ID")
+	name := fs.String("name", "", "event name")
+	description := fs.String("description", "", "description")
+	date := fs.String("date", "", "start time, RFC 3339 or \"2006-01-02 15:04\" in UTC")
+	currency := fs.String("currency", "", "ticket currency; defaults to the server's")
+	capacity := fs.Int("capacity", 0, "attendees admitted; defaults to the venue's capacity")
+	queue := fs.Bool("queue", false, "admit buyers through the waiting queue")
+
+	return func(ctx context.Context, a *app) error {
+		start, err := parseDate(*date)
+		if err != nil {
+			return err
+		}
+
+		input := client.EventInput{
+			VenueID:       *venueID,
+			PerformerID:   *performerID,
+			Name:          *name,
+			Description:   *description,
+			Date:          start,
+			Currency:      *currency,
+			RequiresQueue: *queue,
+		}
+		if *capacity > 0 {
+			input.Capacity = capacity
+		}
+
+		event, err := a.client.CreateEvent(ctx, input)
+		if err != nil {
+			return err
+		}
+		return a.print(event, nil, [][]string{
+			{"ID", fmt.Sprint(event.ID)},
+			{"Name", event.Name},
+			{"Date", formatTime(event.Date)},
+			{"Venue", event.Venue.Location},
+			{"Performer", event.Performer.Name},
+			{"Capacity", fmt.Sprint(event.Capacity)},
+			{"Currency", event.Currency},
+		})
+	}
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --date %q: use RFC 3339 or \"2006-01-02 15:04\"", value)
+}
+
+func generateTickets(fs *flag.FlagSet) func(context.Context, *app) error {
+	eventID := fs.Uint("event", 0, "event ID")
+	seatMapFile := fs.String("seat-map", "", "JSON seat map file: sections, rows, seatsPerRow, price and optional sectionPrices")
+	price := fs.String("price", "", "price of seats in sections without their own; overrides the file's price")
+	dryRun := fs.Bool("dry-run", false, "list the tickets without creating them")
+
+	return func(ctx context.Context, a *app) error {
+		data, err := os.ReadFile(*seatMapFile)
+		if err != nil {
+			return err
+		}
+		m, err := parseSeatMap(data)
+		if err != nil {
+			return err
+		}
+		if *price != "" {
+			m.Price = json.Number(*price)
+		}
+		tickets, err := m.tickets()
+		if err != nil {
+			return err
+		}
+
+		if *dryRun {
+			rows := make([][]string, len(tickets))
+			for i, ticket := range tickets {
+				rows[i] = []string{ticket.Seat, ticket.Price.String()}
+			}
+			return a.print(tickets, []string{"SEAT", "PRICE"}, rows)
+		}
+
+		batch, err := a.client.CreateTickets(ctx, *eventID, tickets)
+		if err != nil {
+			return err
+		}
+		return a.print(batch, nil, [][]string{
+			{"Event", fmt.Sprint(batch.EventID)},
+			{"Created", fmt.Sprint(batch.Created)},
+			{"Total tickets", fmt.Sprint(batch.Total)},
+		})
+	}
+}
+
+func syncEvent(fs *flag.FlagSet) func(context.Context, *app) error {
+	eventID := fs.Uint("event", 0, "event ID")
+
+	return func(ctx context.Context, a *app) error {
+		if err := a.client.SyncEvent(ctx, *eventID); err != nil {
+			return err
+		}
+		result := map[string]interface{}{"eventId": *eventID, "synced": true}
+		return a.print(result, nil, [][]string{{fmt.Sprintf("Event %d synced", *eventID)}})
+	}
+}
+
+func reindex(fs *flag.FlagSet) func(context.Context, *app) error {
+	return func(ctx context.Context, a *app) error {
+		job, err := a.client.Reindex(ctx)
+		var apiErr *client.APIError
+		if errors.Is(err, client.ErrSyncInProgress) && errors.As(err, &apiErr) {
+			return fmt.Errorf("a full sync is already running (job %v)", apiErr.Details["jobId"])
+		}
+		if err != nil {
+			return err
+		}
+		return a.print(job, nil, [][]string{
+			{"Job", job.JobID},
+			{"Status", job.Status},
+		})
+	}
+}
+
+func listBookings(fs *flag.FlagSet) func(context.Context, *app) error {
+	userID := fs.Uint("user", 0, "user ID")
+
+	return func(ctx context.Context, a *app) error {
+		bookings, err := a.client.ListUserBookings(ctx, *userID)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(bookings))
+		for i, booking := range bookings {
+			rows[i] = []string{
+				fmt.Sprint(booking.ID),
+				fmt.Sprint(booking.TicketID),
+				booking.Ticket.Seat,
+				booking.Status,
+				booking.Price.String(),
+				booking.AmountPaid.String(),
+				formatTime(booking.ReservedAt),
+			}
+		}
+		return a.print(bookings, []string{"ID", "TICKET", "SEAT", "STATUS", "PRICE", "PAID", "RESERVED"}, rows)
+	}
+}
+
+func cancelBooking(fs *flag.FlagSet) func(context.Context, *app) error {
+	bookingID := fs.Uint("booking", 0, "booking ID; paid bookings are refunded")
+
+	return func(ctx context.Context, a *app) error {
+		cancellation, err := a.client.CancelBooking(ctx, *bookingID)
+		if err != nil {
+			return err
+		}
+
+		rows := [][]string{{"Booking", fmt.Sprint(*bookingID)}, {"Status", "cancelled"}}
+		if cancellation.Refund != nil {
+			rows = append(rows, []string{"Refund", fmt.Sprintf("%s %s (%s)", cancellation.Refund.Amount, cancellation.Refund.Currency, cancellation.Refund.Status)})
+		}
+		return a.print(cancellation, nil, rows)
+	}
+}
+
+func setRole(role string) func(fs *flag.FlagSet) func(context.Context, *app) error {
+	return func(fs *flag.FlagSet) func(context.Context, *app) error {
+		userID := fs.Uint("user", 0, "user ID")
+
+		return func(ctx context.Context, a *app) error {
+			account, err := a.client.SetUserRole(ctx, *userID, role)
+			if err != nil {
+				return err
+			}
+			return a.print(account, nil, [][]string{
+				{"ID", fmt.Sprint(account.ID)},
+				{"Email", account.Email},
+				{"Role", account.Role},
+				{"Note", "tokens issued before the change keep the old role until they expire"},
+			})
+		}
+	}
+}
+
+func showQueue(fs *flag.FlagSet) func(context.Context, *app) error {
+	eventID := fs.Uint("event", 0, "event ID")
+
+	return func(ctx context.Context, a *app) error {
+		status, err := a.client.QueueStatus(ctx, *eventID)
+		if err != nil {
+			return err
+		}
+		return a.print(status, nil, [][]string{
+			{"Event", fmt.Sprint(status.EventID)},
+			{"Waiting", fmt.Sprint(status.QueueLength)},
+			{"Admitted", fmt.Sprint(status.ActiveAdmissions)},
+		})
+	}
+}
+
+func showStats(fs *flag.FlagSet) func(context.Context, *app) error {
+	eventID := fs.Uint("event", 0, "event ID")
+
+	return func(ctx context.Context, a *app) error {
+		capacity, err := a.client.EventCapacity(ctx, *eventID)
+		if err != nil {
+			return err
+		}
+		summary, err := a.client.BookingSummary(ctx, *eventID)
+		if err != nil {
+			return err
+		}
+
+		stats := struct {
+			Capacity *client.Capacity       `json:"capacity"`
+			Summary  *client.BookingSummary `json:"summary"`
+		}{capacity, summary}
+		return a.print(stats, nil, [][]string{
+			{"Capacity", fmt.Sprint(capacity.Capacity)},
+			{"Tickets", fmt.Sprint(capacity.Total)},
+			{"Available", fmt.Sprint(capacity.Available)},
+			{"Reserved", fmt.Sprint(capacity.Reserved)},
+			{"Sold", fmt.Sprint(capacity.Sold)},
+			{"Revenue", fmt.Sprintf("%s %s", summary.TotalRevenue, summary.Currency)},
+			{"Average price", fmt.Sprintf("%s %s", summary.AveragePrice, summary.Currency)},
+			{"Refunded", fmt.Sprintf("%s %s", summary.RefundedAmount, summary.Currency)},
+			{"Unique buyers", fmt.Sprint(summary.UniqueBuyers)},
+			{"Conversion", fmt.Sprintf("%.1f%%", summary.ConversionRate*100)},
+		})
+	}
+}
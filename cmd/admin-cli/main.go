@@ -0,0 +1,174 @@
+// Command admin-cli runs common operator tasks against the API gateway:
+// creating venues, performers, events and tickets, syncing the search
+// index, managing bookings and admin roles, and inspecting events.
+//
+// It authenticates with TICKETMASTER_TOKEN, or by logging in interactively
+// when the variable is unset. TICKETMASTER_URL points it at the gateway.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/pkg/client"
+)
+
+const usage = `Usage: admin-cli [global flags] <command> [flags]
+
+Commands:
+  venue create       --location --capacity [--country] [--seat-map FILE]
+  performer create   --name [--genre] [--description]
+  event create       --venue --performer --name --date [--currency] [--capacity] [--queue]
+  tickets generate   --event --seat-map FILE [--dry-run]
+  cdc sync           --event
+  cdc reindex        --yes
+  bookings list      --user
+  bookings cancel    --booking --yes
+  users grant-admin  --user --yes
+  users revoke-admin --user --yes
+  queue              --event
+  stats              --event
+
+Global flags:
+`
+
+// app is what every command runs with
+type app struct {
+	client *client.Client
+	json   bool
+}
+
+func main() {
+	global := flag.NewFlagSet("admin-cli", flag.ContinueOnError)
+	baseURL := global.String("url", envOr("TICKETMASTER_URL", "http://localhost:8080"), "gateway base URL (env TICKETMASTER_URL)")
+	jsonOutput := global.Bool("json", false, "print JSON instead of tables")
+	timeout := global.Duration("timeout", 30*time.Second, "timeout of each request")
+	global.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		global.PrintDefaults()
+	}
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	cmd, rest, ok := lookup(global.Args())
+	if !ok {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	// Command flags are checked before asking for credentials
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	action := cmd.setup(fs)
+	var yes *bool
+	if cmd.destructive {
+		yes = fs.Bool("yes", false, "confirm the change")
+	}
+	if err := fs.Parse(rest); err != nil {
+		os.Exit(2)
+	}
+	if missing := missingFlags(fs, cmd.required); len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "Missing required flags: --%s\n", strings.Join(missing, ", --"))
+		fs.Usage()
+		os.Exit(2)
+	}
+	if yes != nil && !*yes {
+		fmt.Fprintf(os.Stderr, "%s needs --yes to confirm\n", cmd.name)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := []client.Option{client.WithTimeout(*timeout)}
+	token := os.Getenv("TICKETMASTER_TOKEN")
+	if token != "" {
+		opts = append(opts, client.WithToken(token))
+	}
+	a := &app{client: client.New(*baseURL, opts...), json: *jsonOutput}
+
+	var err error
+	if token == "" {
+		err = a.login(ctx)
+	}
+	if err == nil {
+		err = action(ctx, a)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// login asks for credentials on the terminal and logs in with them
+func (a *app) login(ctx context.Context) error {
+	if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		return errors.New("TICKETMASTER_TOKEN is not set and stdin is not a terminal to log in from")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprint(os.Stderr, "Email: ")
+	email, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read email: %w", err)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := readPassword(reader)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+
+	if _, err := a.client.Login(ctx, strings.TrimSpace(email), password); err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	return nil
+}
+
+// readPassword reads a line with terminal echo turned off via stty. Where
+// stty is unavailable the password is echoed.
+func readPassword(reader *bufio.Reader) (string, error) {
+	if err := stty("-echo"); err == nil {
+		defer stty("echo")
+	}
+	line, err := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// missingFlags returns the names of required flags that were not set
+func missingFlags(fs *flag.FlagSet, required []string) []string {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var missing []string
+	for _, name := range required {
+		if !set[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
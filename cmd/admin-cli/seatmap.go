@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/pkg/client"
+)
+
+// seatMap is the venue seat map format, e.g.
+//
+//	{"sections": ["A", "B"], "rows": 20, "seatsPerRow": 30}
+//
+// For generating tickets it may also price the seats: price applies to
+// every section not listed in sectionPrices.
+type seatMap struct {
+	Sections      []string               `json:"sections"`
+	Rows          int                    `json:"rows"`
+	SeatsPerRow   int                    `json:"seatsPerRow"`
+	Price         json.Number            `json:"price,omitempty"`
+	SectionPrices map[string]json.Number `json:"sectionPrices,omitempty"`
+}
+
+func parseSeatMap(data []byte) (*seatMap, error) {
+	var m seatMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid seat map: %w", err)
+	}
+	if len(m.Sections) == 0 || m.Rows < 1 || m.SeatsPerRow < 1 {
+		return nil, errors.New("invalid seat map: sections, rows and seatsPerRow are required")
+	}
+	for section := range m.SectionPrices {
+		if !slices.Contains(m.Sections, section) {
+			return nil, fmt.Errorf("invalid seat map: sectionPrices names unknown section %q", section)
+		}
+	}
+	return &m, nil
+}
+
+// tickets lists every seat of the map, labeled section-row-seat (e.g.
+// "A-3-12"), at its section's price
+func (m *seatMap) tickets() ([]client.TicketInput, error) {
+	tickets := make([]client.TicketInput, 0, len(m.Sections)*m.Rows*m.SeatsPerRow)
+	for _, section := range m.Sections {
+		price, ok := m.SectionPrices[section]
+		if !ok {
+			price = m.Price
+		}
+		if price == "" {
+			return nil, fmt.Errorf("section %s has no price; set price or sectionPrices in the seat map, or pass --price", section)
+		}
+
+		for row := 1; row <= m.Rows; row++ {
+			for seat := 1; seat <= m.SeatsPerRow; seat++ {
+				tickets = append(tickets, client.TicketInput{
+					Seat:  fmt.Sprintf("%s-%d-%d", section, row, seat),
+					Price: price,
+				})
+			}
+		}
+	}
+	return tickets, nil
+}
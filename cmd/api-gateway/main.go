@@ -1,54 +1,93 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/middleware"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/gateway"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/session"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.APIGateway)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
 	// Connect to database
-	db, err := database.Connect(cfg)
+	db, err := database.Connect(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Create service
-	gatewayService := gateway.NewService(cfg, db)
+	// Connect to Redis for login sessions
+	redisClient, err := redis.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+	defer redisClient.Close()
 
-	// Setup Gin router
-	r := gin.Default()
+	// Create service
+	gatewayService := gateway.NewService(cfg, db, session.NewStore(redisClient))
 
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	// Report NOT_READY while the database is unreachable
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go gatewayService.WatchDatabase(ctx)
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	// Hand developers an admin token so admin endpoints can be tried out
+	if cfg.IsDevelopment() {
+		token, refreshToken, err := gatewayService.DevAdminTokens(ctx)
+		if err != nil {
+			log.Printf("Failed to issue the development admin token: %v", err)
+		} else {
+			log.Printf("Development admin token: %s", token)
+			log.Printf("Development admin refresh token: %s", refreshToken)
 		}
+	}
+
+	// Setup Gin router in the mode APP_ENV calls for
+	server.SetMode(cfg)
+	r := gin.New()
 
-		c.Next()
-	})
+	// Log requests with secrets redacted, and recover from panics
+	redactor := applog.NewLogRedactor(cfg.LogRedactKeys)
+	r.Use(middleware.RequestLogger(redactor), gin.Recovery())
+
+	// Tag every request with an ID for the logs
+	r.Use(middleware.RequestIDMiddleware())
+
+	// Allow browsers on the configured origins
+	r.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
+
+	// Serve MessagePack to clients that ask for it
+	r.Use(middleware.ContentNegotiationMiddleware())
+
+	// Log request bodies when debugging
+	debugLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r.Use(middleware.RequestBodyLogger(debugLogger, cfg.DebugLogBodies, redactor))
 
 	// Setup routes
 	gatewayService.SetupRoutes(r)
 
 	// Start server
+	srv, err := server.New(cfg, cfg.APIGatewayPort, r)
+	if err != nil {
+		log.Fatal("Failed to configure API Gateway:", err)
+	}
 	log.Printf("API Gateway starting on port %s", cfg.APIGatewayPort)
-	if err := r.Run(":" + cfg.APIGatewayPort); err != nil {
+	if err := server.ListenAndServe(cfg, srv); err != nil {
 		log.Fatal("Failed to start API Gateway:", err)
 	}
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/kafka"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// consumerGroup is the Kafka consumer group of the audit log writer
+	consumerGroup = "booking-events-audit"
+
+	// retryDelay is the pause after a failed poll or write
+	retryDelay = 5 * time.Second
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load(config.BookingConsumer)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	// Connect to database
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		log.Println("Shutting down booking consumer...")
+		cancel()
+	}()
+
+	// Join the consumer group
+	hostname, _ := os.Hostname()
+	consumer, err := kafka.NewClient(cfg).NewConsumer(ctx, consumerGroup, hostname, cfg.BookingEventsTopic)
+	if err != nil {
+		log.Fatal("Failed to create Kafka consumer:", err)
+	}
+	defer consumer.Close(context.Background())
+
+	log.Printf("Booking consumer reading %s", cfg.BookingEventsTopic)
+	for ctx.Err() == nil {
+		if err := consumeBatch(ctx, db, consumer); err != nil && ctx.Err() == nil {
+			log.Printf("Booking consumer error: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+
+	log.Println("Booking consumer stopped")
+}
+
+// consumeBatch appends one poll's worth of booking events to the audit table
+// and commits their offsets. Offsets are only committed after the rows are
+// stored, and redelivered records are skipped by their topic position.
+func consumeBatch(ctx context.Context, db *gorm.DB, consumer *kafka.Consumer) error {
+	records, err := consumer.Poll(ctx)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	rows := make([]models.BookingEvent, 0, len(records))
+	for _, record := range records {
+		var event kafka.BookingEvent
+		if err := json.Unmarshal(record.Value, &event); err != nil {
+			log.Printf("Skipping malformed booking event at %s/%d/%d: %v", record.Topic, record.Partition, record.Offset, err)
+			continue
+		}
+
+		payload := "{}"
+		if len(event.Payload) > 0 {
+			payload = string(event.Payload)
+		}
+		rows = append(rows, models.BookingEvent{
+			EventType:  event.EventType,
+			BookingID:  event.BookingID,
+			UserID:     event.UserID,
+			TicketID:   event.TicketID,
+			Payload:    payload,
+			OccurredAt: event.Timestamp,
+			Topic:      record.Topic,
+			Partition:  record.Partition,
+			Offset:     record.Offset,
+		})
+	}
+
+	if len(rows) > 0 {
+		if err := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+		log.Printf("Stored %d booking events", len(rows))
+	}
+
+	return consumer.Commit(ctx)
+}
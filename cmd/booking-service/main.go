@@ -1,58 +1,148 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/email"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/kafka"
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/metrics"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/middleware"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/booking"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// shutdownTimeout bounds how long shutdown waits for in-flight requests
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.BookingService)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
 	// Connect to database
-	db, err := database.Connect(cfg)
+	db, err := database.Connect(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Connect to Redis
-	redisClient := redis.NewClient(cfg)
+	// Connect to Redis, or keep locks and queues in process for local development
+	var redisClient redis.Store
+	if cfg.RedisInMemory {
+		log.Printf("Using in-memory Redis store; state is not shared with other processes")
+		redisClient = inmem.New()
+	} else {
+		client, err := redis.NewClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis:", err)
+		}
+		client.UseMetrics(metrics.NewLockMetrics(prometheus.DefaultRegisterer))
+		redisClient = client
+	}
+
+	// Publish booking state transitions to Kafka when enabled
+	var kafkaClient *kafka.Client
+	if cfg.KafkaEnabled {
+		kafkaClient = kafka.NewClient(cfg)
+	}
+
+	// Charge through the mock or real Stripe
+	paymentClient, err := payment.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to configure payments:", err)
+	}
 
 	// Create service
-	bookingService := booking.NewService(db, redisClient, cfg)
+	bookingService := booking.NewService(db, redisClient, cfg, paymentClient, email.NewLogSender(), notify.NewLogNotifier(), kafkaClient)
 
-	// Setup Gin router
-	r := gin.Default()
+	// Watch for Redis recovery while the lock circuit breaker is open
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	go bookingService.StartRedisProbe(ctx)
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Report NOT_READY while the database is unreachable
+	go bookingService.WatchDatabase(ctx)
+
+	// Report Redis outages on the health check
+	go bookingService.WatchRedis(ctx)
+
+	// Release expired reservations in the background
+	go bookingService.StartExpiryWorker(ctx)
+
+	// Release reservations the moment their lock expires
+	if cfg.LockExpiryEventsEnabled {
+		go bookingService.StartLockExpiryListener(ctx)
+	}
+
+	// Setup Gin router in the mode APP_ENV calls for
+	server.SetMode(cfg)
+	r := gin.New()
+
+	// Log requests with secrets redacted, and recover from panics
+	redactor := applog.NewLogRedactor(cfg.LogRedactKeys)
+	r.Use(middleware.RequestLogger(redactor), gin.Recovery())
+
+	// Tag every request with an ID for the logs
+	r.Use(middleware.RequestIDMiddleware())
+
+	// Allow browsers on the configured origins
+	r.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
+
+	// Serve MessagePack to clients that ask for it
+	r.Use(middleware.ContentNegotiationMiddleware())
 
-		c.Next()
-	})
+	// Log request bodies when debugging
+	debugLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r.Use(middleware.RequestBodyLogger(debugLogger, cfg.DebugLogBodies, redactor))
 
 	// Setup routes
 	bookingService.SetupRoutes(r)
 
 	// Start server
-	log.Printf("Booking Service starting on port %s", cfg.BookingServicePort)
-	if err := r.Run(":" + cfg.BookingServicePort); err != nil {
-		log.Fatal("Failed to start Booking Service:", err)
+	srv, err := server.New(cfg, cfg.BookingServicePort, r)
+	if err != nil {
+		log.Fatal("Failed to configure Booking Service:", err)
+	}
+	go func() {
+		log.Printf("Booking Service starting on port %s", cfg.BookingServicePort)
+		if err := server.ListenAndServe(cfg, srv); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start Booking Service:", err)
+		}
+	}()
+
+	// Wait for a shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+	log.Println("Shutting down Booking Service...")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	// Let in-flight confirmations finish before their lock renewals stop
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
 	}
+	cancel()
+	bookingService.Shutdown(shutdownCtx)
+	log.Println("Booking Service stopped")
 }
@@ -0,0 +1,52 @@
+package cmd_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCommandsBuild compiles every cmd/* binary and checks that the
+// module's own packages each one imports live under the module path and
+// not in a second internals/ copy
+func TestCommandsBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds every command")
+	}
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatalf("failed to list commands: %v", err)
+	}
+	module := goCommand(t, "list", "-m")
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			goCommand(t, "build", "-o", filepath.Join(t.TempDir(), name), "./"+name)
+
+			deps := goCommand(t, "list", "-deps", "-f", "{{if .Module}}{{if .Module.Main}}{{.ImportPath}}{{end}}{{end}}", "./"+name)
+			for _, dep := range strings.Fields(deps) {
+				if !strings.HasPrefix(dep, module+"/") || strings.HasPrefix(dep, module+"/internals/") {
+					t.Errorf("%s imports %s, outside %s/internal", name, dep, module)
+				}
+			}
+		})
+	}
+}
+
+// goCommand runs the go tool and returns its trimmed output
+func goCommand(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("go", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -3,27 +3,38 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/middleware"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/cdc"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds how long shutdown waits for in-flight work
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.CDCService)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
 	// Connect to database
-	db, err := database.Connect(cfg)
+	db, err := database.Connect(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -34,48 +45,136 @@ func main() {
 		log.Fatal("Failed to connect to Elasticsearch:", err)
 	}
 
+	// Connect to Redis for change notifications and leader election
+	var redisClient redis.Store
+	if cfg.CDCPubSubEnabled || cfg.CDCLeaderElection {
+		client, err := redis.NewClient(cfg)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis:", err)
+		}
+		defer client.Close()
+		redisClient = client
+	}
+
 	// Create service
-	cdcService := cdc.NewService(db, esClient, cfg)
+	cdcService := cdc.NewService(db, esClient, cfg, redisClient)
 
-	// Setup Gin router
-	r := gin.Default()
+	// Create the search indices once per database
+	if err := cdcService.EnsureIndices(context.Background()); err != nil {
+		log.Fatal("Failed to create search indices:", err)
+	}
 
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	// Setup Gin router in the mode APP_ENV calls for
+	server.SetMode(cfg)
+	r := gin.New()
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Log requests with secrets redacted, and recover from panics
+	redactor := applog.NewLogRedactor(cfg.LogRedactKeys)
+	r.Use(middleware.RequestLogger(redactor), gin.Recovery())
+
+	// Tag every request with an ID for the logs
+	r.Use(middleware.RequestIDMiddleware())
+
+	// Allow browsers on the configured origins
+	r.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
 
-		c.Next()
-	})
+	// Serve MessagePack to clients that ask for it
+	r.Use(middleware.ContentNegotiationMiddleware())
+
+	// Log request bodies when debugging
+	debugLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r.Use(middleware.RequestBodyLogger(debugLogger, cfg.DebugLogBodies, redactor))
 
 	// Setup routes
 	cdcService.SetupRoutes(r)
 
-	// Start CDC worker in background
+	// Background work stops on ctx; leader election outlives it so the lease
+	// is only released once in-flight work has drained
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	electionCtx, stopElection := context.WithCancel(context.Background())
+	defer stopElection()
 
-	go cdcService.StartCDCWorker(ctx)
-
-	// Handle graceful shutdown
+	// Signals received during the initial sync are handled once it is done
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Catch up on changes missed while the service was down. With leader
+	// election the replica that wins the lease does this instead.
+	electionDone := make(chan struct{})
+	if cfg.CDCLeaderElection {
+		go func() {
+			defer close(electionDone)
+			cdcService.StartLeaderElection(electionCtx)
+		}()
+	} else {
+		close(electionDone)
+		if err := cdcService.InitialSync(ctx); err != nil {
+			log.Printf("Initial sync failed: %v", err)
+		}
+	}
+
+	// Report NOT_READY while the database is unreachable
+	go cdcService.WatchDatabase(ctx)
+
+	var workers sync.WaitGroup
+	workers.Add(1)
 	go func() {
-		<-sigChan
-		log.Println("Shutting down CDC Service...")
-		cancel()
+		defer workers.Done()
+		cdcService.StartCDCWorker(ctx)
 	}()
 
+	// Re-index immediately on change notifications from other services
+	if cfg.CDCPubSubEnabled {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			cdcService.StartChangeSubscriber(ctx, redisClient)
+		}()
+	}
+
 	// Start server
-	log.Printf("CDC Service starting on port %s", cfg.CDCServicePort)
-	if err := r.Run(":" + cfg.CDCServicePort); err != nil {
-		log.Fatal("Failed to start CDC Service:", err)
+	srv, err := server.New(cfg, cfg.CDCServicePort, r)
+	if err != nil {
+		log.Fatal("Failed to configure CDC Service:", err)
 	}
+	go func() {
+		log.Printf("CDC Service starting on port %s", cfg.CDCServicePort)
+		if err := server.ListenAndServe(cfg, srv); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start CDC Service:", err)
+		}
+	}()
+
+	// Wait for a shutdown signal
+	<-sigChan
+	log.Println("Shutting down CDC Service...")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	// Stop taking requests, then let the worker finish its current tick and
+	// any full sync finish its in-flight batches. Checkpoints only cover
+	// indexed rows, so work cut off by the deadline is redone on restart.
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
+	}
+	cancel()
+	if err := cdcService.DrainJobs(shutdownCtx); err != nil {
+		log.Printf("Full sync drain error: %v", err)
+	}
+
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Println("CDC worker did not stop before the shutdown deadline")
+	}
+
+	stopElection()
+	<-electionDone
+	log.Println("CDC Service stopped")
 }
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/middleware"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/event"
 
 	"github.com/gin-gonic/gin"
@@ -12,43 +20,66 @@ import (
 
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.EventService)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
 	// Connect to database
-	db, err := database.Connect(cfg)
+	db, err := database.Connect(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// Connect to Redis for change notifications and the seat availability cache
+	redisClient, err := redis.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+	defer redisClient.Close()
+
 	// Create service
-	eventService := event.NewService(db)
+	eventService := event.NewService(db, cfg, notify.NewLogNotifier(), redisClient)
+
+	// Report NOT_READY while the database is unreachable
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go eventService.WatchDatabase(ctx)
+
+	// Hard-delete rows soft-deleted longer ago than the retention window
+	go database.RunPurge(ctx, db, cfg.SoftDeleteRetention, database.PurgeInterval)
 
-	// Setup Gin router
-	r := gin.Default()
+	// Setup Gin router in the mode APP_ENV calls for
+	server.SetMode(cfg)
+	r := gin.New()
 
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	// Log requests with secrets redacted, and recover from panics
+	redactor := applog.NewLogRedactor(cfg.LogRedactKeys)
+	r.Use(middleware.RequestLogger(redactor), gin.Recovery())
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Tag every request with an ID for the logs
+	r.Use(middleware.RequestIDMiddleware())
 
-		c.Next()
-	})
+	// Allow browsers on the configured origins
+	r.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
+
+	// Serve MessagePack to clients that ask for it
+	r.Use(middleware.ContentNegotiationMiddleware())
+
+	// Log request bodies when debugging
+	debugLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r.Use(middleware.RequestBodyLogger(debugLogger, cfg.DebugLogBodies, redactor))
 
 	// Setup routes
 	eventService.SetupRoutes(r)
 
 	// Start server
+	srv, err := server.New(cfg, cfg.EventServicePort, r)
+	if err != nil {
+		log.Fatal("Failed to configure Event Service:", err)
+	}
 	log.Printf("Event Service starting on port %s", cfg.EventServicePort)
-	if err := r.Run(":" + cfg.EventServicePort); err != nil {
+	if err := server.ListenAndServe(cfg, srv); err != nil {
 		log.Fatal("Failed to start Event Service:", err)
 	}
 }
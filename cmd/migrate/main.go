@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
@@ -8,20 +10,43 @@ import (
 )
 
 func main() {
+	defaults := database.DefaultSeedOptions()
+	seed := flag.Bool("seed", false, "seed sample data after migrating")
+	events := flag.Int("events", defaults.Events, "events to seed")
+	ticketsPerEvent := flag.Int("tickets-per-event", defaults.TicketsPerEvent, "tickets to seed per event")
+	users := flag.Int("users", defaults.Users, "users to seed, each with a confirmed booking")
+	force := flag.Bool("force", false, "truncate existing data and reseed")
+	flag.Parse()
+
+	if *events < 0 || *ticketsPerEvent < 0 || *users < 0 {
+		log.Fatal("--events, --tickets-per-event and --users must not be negative")
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.Tool)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
-	// Connect to database
-	db, err := database.Connect(cfg)
+	// Connect to database, which also migrates the schema
+	db, err := database.Connect(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	if !*seed {
+		log.Println("Database migration completed successfully")
+		return
+	}
+
 	// Seed sample data
-	if err := database.SeedData(db); err != nil {
+	opts := database.SeedOptions{
+		Events:          *events,
+		TicketsPerEvent: *ticketsPerEvent,
+		Users:           *users,
+		Force:           *force,
+	}
+	if err := database.Seed(db, opts); err != nil {
 		log.Fatal("Failed to seed data:", err)
 	}
 
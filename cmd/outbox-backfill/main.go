@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load(config.Tool)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	// Connect to database
+	db, err := database.Connect(context.Background(), cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Seed outbox rows for existing events
+	count, err := outbox.Backfill(db)
+	if err != nil {
+		log.Fatal("Failed to backfill outbox:", err)
+	}
+
+	log.Printf("Outbox backfill completed: %d events queued for indexing", count)
+}
@@ -2,8 +2,14 @@ package main
 
 import (
 	"log"
+	"log/slog"
+	"os"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/middleware"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/search"
 
 	"github.com/gin-gonic/gin"
@@ -11,40 +17,55 @@ import (
 
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(config.SearchService)
 	if err != nil {
 		log.Fatal("Failed to load config:", err)
 	}
 
+	// Connect to Redis for users' search history
+	redisClient, err := redis.NewClient(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to Redis:", err)
+	}
+	defer redisClient.Close()
+
 	// Create service
-	searchService, err := search.NewService(cfg)
+	searchService, err := search.NewService(cfg, redisClient)
 	if err != nil {
 		log.Fatal("Failed to create search service:", err)
 	}
 
-	// Setup Gin router
-	r := gin.Default()
+	// Setup Gin router in the mode APP_ENV calls for
+	server.SetMode(cfg)
+	r := gin.New()
+
+	// Log requests with secrets redacted, and recover from panics
+	redactor := applog.NewLogRedactor(cfg.LogRedactKeys)
+	r.Use(middleware.RequestLogger(redactor), gin.Recovery())
 
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+	// Tag every request with an ID for the logs
+	r.Use(middleware.RequestIDMiddleware())
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Allow browsers on the configured origins
+	r.Use(middleware.CORSMiddleware(cfg.CORSAllowedOrigins))
 
-		c.Next()
-	})
+	// Serve MessagePack to clients that ask for it
+	r.Use(middleware.ContentNegotiationMiddleware())
+
+	// Log request bodies when debugging
+	debugLogger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r.Use(middleware.RequestBodyLogger(debugLogger, cfg.DebugLogBodies, redactor))
 
 	// Setup routes
 	searchService.SetupRoutes(r)
 
 	// Start server
+	srv, err := server.New(cfg, cfg.SearchServicePort, r)
+	if err != nil {
+		log.Fatal("Failed to configure Search Service:", err)
+	}
 	log.Printf("Search Service starting on port %s", cfg.SearchServicePort)
-	if err := r.Run(":" + cfg.SearchServicePort); err != nil {
+	if err := server.ListenAndServe(cfg, srv); err != nil {
 		log.Fatal("Failed to start Search Service:", err)
 	}
 }
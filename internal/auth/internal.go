@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalTokenHeader carries the shared secret for service-to-service calls
+const InternalTokenHeader = "X-Internal-Token"
+
+// InternalAuthMiddleware rejects requests that do not carry the configured
+// internal token
+func InternalAuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(InternalTokenHeader)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Internal token required", nil))
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.InternalAuthToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid internal token", nil))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SetInternalToken adds the internal token to an outgoing service request
+func SetInternalToken(req *http.Request, cfg *config.Config) {
+	req.Header.Set(InternalTokenHeader, cfg.InternalAuthToken)
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInternalAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{InternalAuthToken: "internal-secret"}
+
+	router := gin.New()
+	router.POST("/cdc/sync-all", InternalAuthMiddleware(cfg), func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, gin.H{"message": "sync started"})
+	})
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "missing token", wantCode: http.StatusUnauthorized, wantErr: apperrors.ErrCodeUnauthorized},
+		{name: "wrong token", token: "guess", wantCode: http.StatusUnauthorized, wantErr: apperrors.ErrCodeInvalidToken},
+		{name: "token prefix", token: "internal", wantCode: http.StatusUnauthorized, wantErr: apperrors.ErrCodeInvalidToken},
+		{name: "valid token", token: "internal-secret", wantCode: http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/cdc/sync-all", nil)
+			if tt.token != "" {
+				req.Header.Set(InternalTokenHeader, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if tt.wantErr == "" {
+				return
+			}
+			var resp apperrors.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if resp.Code != tt.wantErr {
+				t.Errorf("got error code %q, want %q", resp.Code, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetInternalTokenIsAccepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{InternalAuthToken: "internal-secret"}
+
+	router := gin.New()
+	router.POST("/cdc/sync-event/:id", InternalAuthMiddleware(cfg), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/cdc/sync-event/1", nil)
+	SetInternalToken(req, cfg)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("got status %d for a request carrying the internal token, want 200", rec.Code)
+	}
+}
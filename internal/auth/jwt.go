@@ -1,23 +1,59 @@
 package auth
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// mfaChallengePurpose marks tokens that only prove the password step of an
+// MFA login; they are rejected as regular access tokens
+const mfaChallengePurpose = "mfa_challenge"
+
+// mfaChallengeExpiry is how long a user has to submit their TOTP code
+const mfaChallengeExpiry = 5 * time.Minute
+
+// Token types. Access tokens authenticate requests; refresh tokens are only
+// accepted by POST /auth/token/refresh to obtain a new access token. Tokens
+// issued before types existed have none and are treated as access tokens.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
 )
 
 type Claims struct {
-	UserID uint   `json:"userId"`
-	Email  string `json:"email"`
+	UserID  uint   `json:"userId"`
+	Email   string `json:"email"`
+	Role    string `json:"role,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+
+	TokenType string `json:"tokenType,omitempty"`
+
+	// SessionID names the gateway session the token belongs to; logging out
+	// deletes the session, revoking the token
+	SessionID string `json:"sid,omitempty"`
+
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(cfg *config.Config, userID uint, email string) (string, error) {
+// IsAdmin reports whether the token was issued to an administrator
+func (c *Claims) IsAdmin() bool {
+	return c.Role == models.RoleAdmin
+}
+
+func GenerateToken(cfg *config.Config, userID uint, email, role, sessionID string) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		TokenType: TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWTExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -30,6 +66,90 @@ func GenerateToken(cfg *config.Config, userID uint, email string) (string, error
 }
 
 func ValidateToken(cfg *config.Config, tokenString string) (*Claims, error) {
+	claims, err := parseToken(cfg, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" || claims.TokenType == TokenTypeRefresh {
+		return nil, fmt.Errorf("token cannot be used for authentication")
+	}
+	return claims, nil
+}
+
+// GenerateRefreshToken issues a long-lived token that can be exchanged for
+// new access tokens of the same session. Its ID is random, so every token
+// is distinct even when issued in the same second.
+func GenerateRefreshToken(cfg *config.Config, userID uint, email, role, sessionID string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(cfg.JWTRefreshExpiry)
+	claims := &Claims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		TokenType: TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(cfg.JWTSecret))
+	return signed, expiresAt, err
+}
+
+// ValidateRefreshToken validates a token issued by GenerateRefreshToken
+func ValidateRefreshToken(cfg *config.Config, tokenString string) (*Claims, error) {
+	claims, err := parseToken(cfg, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, fmt.Errorf("not a refresh token")
+	}
+	return claims, nil
+}
+
+// HashRefreshToken is the form in which refresh tokens are stored for
+// revocation checks
+func HashRefreshToken(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateMFAChallengeToken issues a short-lived token for a user who passed
+// the password check but still has to present a TOTP code
+func GenerateMFAChallengeToken(cfg *config.Config, userID uint, email string) (string, error) {
+	claims := &Claims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: mfaChallengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(mfaChallengeExpiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+// ValidateMFAChallengeToken validates a token issued by GenerateMFAChallengeToken
+func ValidateMFAChallengeToken(cfg *config.Config, tokenString string) (*Claims, error) {
+	claims, err := parseToken(cfg, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != mfaChallengePurpose {
+		return nil, fmt.Errorf("not an MFA challenge token")
+	}
+	return claims, nil
+}
+
+func parseToken(cfg *config.Config, tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -3,12 +3,16 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
+	// Deployment environment: development, test or production
+	AppEnv string
+
 	// Database
 	DBHost     string
 	DBPort     string
@@ -16,17 +20,67 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
+	// Database connection pool, slow-query logging and startup retries
+	DBMaxOpenConns       int
+	DBMaxIdleConns       int
+	DBConnMaxLifetime    time.Duration
+	DBSlowQueryThreshold time.Duration // Queries at least this slow are logged; 0 disables
+	DBConnectTimeout     time.Duration // How long Connect keeps retrying an unreachable database
+
+	// Read replicas as Postgres DSNs; reads outside transactions are spread
+	// over them round-robin, writes stay on the primary
+	DBReplicaDSNs []string
+
+	// How long soft-deleted rows are kept before the event service purges
+	// them; 0 disables the purge
+	SoftDeleteRetention time.Duration
+
 	// Redis
-	RedisHost     string
-	RedisPort     string
-	RedisPassword string
+	RedisHost      string
+	RedisPort      string
+	RedisPassword  string
+	RedisKeyPrefix string // Namespaces cache keys when environments share Redis
+	RedisInMemory  bool   // Keep booking state in process instead of Redis (development only)
+
+	// Redis connection pool; zero sizes keep the go-redis defaults
+	RedisPoolSize        int
+	RedisMinIdleConns    int
+	RedisDialTimeout     time.Duration
+	RedisReadTimeout     time.Duration
+	RedisWriteTimeout    time.Duration
+	RedisMaxRetries      int
+	RedisMinRetryBackoff time.Duration
+	RedisMaxRetryBackoff time.Duration
 
 	// Elasticsearch
 	ElasticsearchURL string
 
 	// JWT
-	JWTSecret string
-	JWTExpiry time.Duration
+	JWTSecret        string
+	JWTExpiry        time.Duration // Lifetime of access tokens
+	JWTRefreshExpiry time.Duration // Lifetime of refresh tokens and their sessions
+
+	// Shared secret for service-to-service calls
+	InternalAuthToken string
+
+	// Enables development-only endpoints such as POST /admin/dev/reset
+	DevMode bool
+
+	// Logs request bodies, with secrets masked, at debug level
+	DebugLogBodies bool
+
+	// Keys whose values are redacted from request and body logs
+	LogRedactKeys []string
+
+	// Origins browsers may call the API from; "*" allows any and is refused
+	// in production
+	CORSAllowedOrigins []string
+
+	// HTTP/2 for every service: over TLS when a certificate is configured,
+	// h2c otherwise
+	HTTP2Enabled bool
+	TLSCertFile  string
+	TLSKeyFile   string
 
 	// Service Ports
 	APIGatewayPort     string
@@ -35,29 +89,113 @@ type Config struct {
 	BookingServicePort string
 	CDCServicePort     string
 
+	// Gateway retries of a failed forward: attempts after the first, and the
+	// delay before the first retry, doubling after each
+	GatewayRetryAttempts int
+	GatewayRetryDelay    time.Duration
+
+	// CDC
+	CDCSyncDriftThreshold   float64
+	CDCLagDegradedThreshold time.Duration
+	CDCSyncConcurrency      int
+	CDCPubSubEnabled        bool
+	CDCLeaderElection       bool
+	CDCLeaderLeaseTTL       time.Duration
+	CDCAdvertiseURL         string
+
+	// Booking
+	ReservationWindowMinutes int           // How long a reservation holds a ticket unless the event overrides it
+	LockKeeperMax            int           // Concurrent ticket lock renewals the booking service runs
+	LockExpiryEventsEnabled  bool          // Expire reservations on Redis keyspace events
+	AdmissionWindow          int           // Queued users admitted at once for events that require the queue
+	AdmissionTTL             time.Duration // How long an admitted user has to reserve
+	AdmissionSigningKey      string        // Signs admission tokens; keep it apart from JWTSecret
+	PaymentWorkers           int           // Goroutines charging confirmed bookings
+	PaymentQueueSize         int           // Charges that may wait for a worker before confirms get 503
+	PaymentRetryLimit        int           // Payment retries per booking before a failed charge releases it
+
+	// Kafka (through the REST Proxy)
+	KafkaEnabled       bool
+	KafkaRestURL       string
+	BookingEventsTopic string
+
 	// Mock Stripe
 	MockStripeEnabled     bool
 	MockStripeSuccessRate float64
+	MockStripeLatency     string // "random", or a fixed delay such as "0s"
+	MockStripeSeed        int64  // Seeds outcomes and latency; 0 seeds from the clock
+
+	// Stripe, used when the mock is disabled
+	StripeSecretKey     string
+	StripeAPIURL        string // Point at stripe-mock for local testing
+	StripeWebhookSecret string // Verifies the signature of webhook events
+
+	// Pricing
+	DefaultCurrency   string             // Currency of events created without one; must be a supported code
+	ServiceFeePercent float64            // Per-ticket service fee as a percentage of face value
+	ServiceFeeFlat    float64            // Flat per-ticket service fee, added to the percentage
+	TaxRates          map[string]float64 // Tax percentage by venue country, from TAX_RATES=US:8.875,GB:20
+	DefaultTaxRate    float64            // Tax percentage for venues whose country has no rate
 }
 
-func Load() (*Config, error) {
+// Load reads the configuration from the environment and validates it for
+// the services the calling binary runs
+func Load(services Service) (*Config, error) {
 	godotenv.Load()
 	
 	config := &Config{
+		AppEnv: strings.ToLower(getEnv("APP_ENV", EnvProduction)),
+
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "password"),
 		DBName:     getEnv("DB_NAME", "ticketmaster"),
 
-		RedisHost:     getEnv("REDIS_HOST", "localhost"),
-		RedisPort:     getEnv("REDIS_PORT", "6379"),
-		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		DBMaxOpenConns:       getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:       getEnvInt("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetime:    parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "30m")),
+		DBSlowQueryThreshold: parseDuration(getEnv("DB_SLOW_QUERY_THRESHOLD", "200ms")),
+		DBConnectTimeout:     parseDuration(getEnv("DB_CONNECT_TIMEOUT", "60s")),
+
+		DBReplicaDSNs: parseList(getEnv("DB_REPLICA_DSNS", "")),
+
+		SoftDeleteRetention: parseDuration(getEnv("SOFT_DELETE_RETENTION", "720h")),
+
+		RedisHost:      getEnv("REDIS_HOST", "localhost"),
+		RedisPort:      getEnv("REDIS_PORT", "6379"),
+		RedisPassword:  getEnv("REDIS_PASSWORD", ""),
+		RedisKeyPrefix: getEnv("REDIS_KEY_PREFIX", ""),
+		RedisInMemory:  getEnvBool("REDIS_IN_MEMORY", false),
+
+		RedisPoolSize:        getEnvInt("REDIS_POOL_SIZE", 0),
+		RedisMinIdleConns:    getEnvInt("REDIS_MIN_IDLE_CONNS", 0),
+		RedisDialTimeout:     parseDuration(getEnv("REDIS_DIAL_TIMEOUT", "5s")),
+		RedisReadTimeout:     parseDuration(getEnv("REDIS_READ_TIMEOUT", "3s")),
+		RedisWriteTimeout:    parseDuration(getEnv("REDIS_WRITE_TIMEOUT", "3s")),
+		RedisMaxRetries:      getEnvInt("REDIS_MAX_RETRIES", 3),
+		RedisMinRetryBackoff: parseDuration(getEnv("REDIS_MIN_RETRY_BACKOFF", "8ms")),
+		RedisMaxRetryBackoff: parseDuration(getEnv("REDIS_MAX_RETRY_BACKOFF", "512ms")),
 
 		ElasticsearchURL: getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
 
-		JWTSecret: getEnv("JWT_SECRET", "your-secret-key-here"),
-		JWTExpiry: parseDuration(getEnv("JWT_EXPIRY", "24h")),
+		JWTSecret:        getEnv("JWT_SECRET", "your-secret-key-here"),
+		JWTExpiry:        parseDuration(getEnv("JWT_EXPIRY", "15m")),
+		JWTRefreshExpiry: parseDuration(getEnv("JWT_REFRESH_EXPIRY", "168h")),
+
+		InternalAuthToken: getEnv("INTERNAL_AUTH_TOKEN", "your-internal-token-here"),
+
+		DevMode: getEnvBool("DEV_MODE", false),
+
+		DebugLogBodies: getEnvBool("DEBUG_LOG_BODIES", false),
+
+		LogRedactKeys: parseList(getEnv("LOG_REDACT_KEYS", "email,password,payment_details,token,authorization,cvv")),
+
+		CORSAllowedOrigins: parseList(getEnv("CORS_ALLOWED_ORIGINS", "")),
+
+		HTTP2Enabled: getEnvBool("HTTP2_ENABLED", false),
+		TLSCertFile:  getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:   getEnv("TLS_KEY_FILE", ""),
 
 		APIGatewayPort:     getEnv("API_GATEWAY_PORT", "8080"),
 		SearchServicePort:  getEnv("SEARCH_SERVICE_PORT", "8081"),
@@ -65,13 +203,68 @@ func Load() (*Config, error) {
 		BookingServicePort: getEnv("BOOKING_SERVICE_PORT", "8083"),
 		CDCServicePort:     getEnv("CDC_SERVICE_PORT", "8084"),
 
+		GatewayRetryAttempts: getEnvInt("GATEWAY_RETRY_ATTEMPTS", 2),
+		GatewayRetryDelay:    parseDuration(getEnv("GATEWAY_RETRY_DELAY", "100ms")),
+
+		CDCSyncDriftThreshold:   getEnvFloat("CDC_SYNC_DRIFT_THRESHOLD", 0.01),
+		CDCLagDegradedThreshold: parseDuration(getEnv("CDC_LAG_DEGRADED_THRESHOLD", "2m")),
+		CDCSyncConcurrency:      getEnvInt("CDC_SYNC_CONCURRENCY", 4),
+		CDCPubSubEnabled:        getEnvBool("CDC_PUBSUB_ENABLED", false),
+		CDCLeaderElection:       getEnvBool("CDC_LEADER_ELECTION_ENABLED", false),
+		CDCLeaderLeaseTTL:       parseDuration(getEnv("CDC_LEADER_LEASE_TTL", "15s")),
+		CDCAdvertiseURL:         getEnv("CDC_ADVERTISE_URL", "http://localhost:"+getEnv("CDC_SERVICE_PORT", "8084")),
+
+		ReservationWindowMinutes: getEnvInt("RESERVATION_WINDOW_MINUTES", 10),
+		LockKeeperMax:            getEnvInt("LOCK_KEEPER_MAX", 1000),
+		LockExpiryEventsEnabled:  getEnvBool("LOCK_EXPIRY_EVENTS_ENABLED", false),
+		AdmissionWindow:          getEnvInt("ADMISSION_WINDOW", 100),
+		AdmissionTTL:             parseDuration(getEnv("ADMISSION_TTL", "5m")),
+		AdmissionSigningKey:      getEnv("ADMISSION_SIGNING_KEY", "your-admission-key-here"),
+		PaymentWorkers:           getEnvInt("PAYMENT_WORKERS", 16),
+		PaymentQueueSize:         getEnvInt("PAYMENT_QUEUE_SIZE", 1000),
+		PaymentRetryLimit:        getEnvInt("PAYMENT_RETRY_LIMIT", 3),
+
+		KafkaEnabled:       getEnvBool("KAFKA_ENABLED", false),
+		KafkaRestURL:       getEnv("KAFKA_REST_URL", "http://localhost:8090"),
+		BookingEventsTopic: getEnv("KAFKA_BOOKING_EVENTS_TOPIC", "booking-events"),
+
 		MockStripeEnabled:     getEnvBool("MOCK_STRIPE_ENABLED", true),
 		MockStripeSuccessRate: getEnvFloat("MOCK_STRIPE_SUCCESS_RATE", 0.95),
+		MockStripeLatency:     getEnv("MOCK_STRIPE_LATENCY", "random"),
+		MockStripeSeed:        int64(getEnvInt("MOCK_STRIPE_SEED", 0)),
+
+		StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
+		StripeAPIURL:        getEnv("STRIPE_API_URL", "https://api.stripe.com"),
+		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+
+		DefaultCurrency:   strings.ToLower(getEnv("DEFAULT_CURRENCY", "twd")),
+		ServiceFeePercent: getEnvFloat("SERVICE_FEE_PERCENT", 0),
+		ServiceFeeFlat:    getEnvFloat("SERVICE_FEE_FLAT", 0),
+		TaxRates:          parseRates(getEnv("TAX_RATES", "")),
+		DefaultTaxRate:    getEnvFloat("DEFAULT_TAX_RATE", 0),
+	}
+
+	// Outside production, browsers may call from anywhere unless told otherwise
+	if len(config.CORSAllowedOrigins) == 0 && !config.IsProduction() {
+		config.CORSAllowedOrigins = []string{"*"}
 	}
 
+	if err := config.Validate(services); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
+// IsProduction reports whether APP_ENV is production
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == EnvProduction
+}
+
+// IsDevelopment reports whether APP_ENV is development
+func (c *Config) IsDevelopment() bool {
+	return c.AppEnv == EnvDevelopment
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value != "" {
@@ -90,6 +283,14 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvFloat(key string, defaultValue float64) float64{
 	value := os.Getenv(key)
 	valueBool, err := strconv.ParseFloat(value, 64) // 64 is bitsize
@@ -100,6 +301,35 @@ func getEnvFloat(key string, defaultValue float64) float64{
 	}
 }
 
+// parseList reads a comma-separated list, dropping empty entries
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseRates reads "KEY:rate,KEY:rate" into a map keyed by upper-case key,
+// skipping malformed entries
+func parseRates(s string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+		rates[strings.ToUpper(strings.TrimSpace(key))] = rate
+	}
+	return rates
+}
+
 func parseDuration(s string) time.Duration {
 	duration, err := time.ParseDuration(s)
 	if err != nil {
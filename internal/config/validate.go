@@ -0,0 +1,327 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+)
+
+// Service identifies the binary loading the configuration, so validation
+// only demands what that binary uses. Combine with | when one process runs
+// several.
+type Service uint
+
+const (
+	APIGateway Service = 1 << iota
+	SearchService
+	EventService
+	BookingService
+	CDCService
+	BookingConsumer
+	Tool // cmd/migrate and cmd/outbox-backfill
+)
+
+// Environments accepted in APP_ENV
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvProduction  = "production"
+)
+
+// Insecure defaults that only development may run with
+const (
+	defaultJWTSecret           = "your-secret-key-here"
+	defaultInternalAuthToken   = "your-internal-token-here"
+	defaultAdmissionSigningKey = "your-admission-key-here"
+)
+
+// minProductionSecretLength is the shortest JWT secret production accepts
+const minProductionSecretLength = 32
+
+// Which services need each backend
+const (
+	usesDatabase = APIGateway | EventService | BookingService | CDCService | BookingConsumer | Tool
+	usesRedis    = APIGateway | SearchService | EventService | BookingService
+	usesJWT      = APIGateway | SearchService | EventService | BookingService | CDCService
+	usesInternal = APIGateway | EventService | CDCService
+	usesHTTP     = APIGateway | SearchService | EventService | BookingService | CDCService
+)
+
+// typedEnv lists the variables Load parses into non-string fields, which
+// fall back to their defaults when malformed. Keep it in step with Load.
+var typedEnv = map[string]string{
+	"DB_MAX_OPEN_CONNS":           "int",
+	"DB_MAX_IDLE_CONNS":           "int",
+	"DB_CONN_MAX_LIFETIME":        "duration",
+	"DB_SLOW_QUERY_THRESHOLD":     "duration",
+	"DB_CONNECT_TIMEOUT":          "duration",
+	"SOFT_DELETE_RETENTION":       "duration",
+	"REDIS_IN_MEMORY":             "bool",
+	"REDIS_POOL_SIZE":             "int",
+	"REDIS_MIN_IDLE_CONNS":        "int",
+	"REDIS_DIAL_TIMEOUT":          "duration",
+	"REDIS_READ_TIMEOUT":          "duration",
+	"REDIS_WRITE_TIMEOUT":         "duration",
+	"REDIS_MAX_RETRIES":           "int",
+	"REDIS_MIN_RETRY_BACKOFF":     "duration",
+	"REDIS_MAX_RETRY_BACKOFF":     "duration",
+	"JWT_EXPIRY":                  "duration",
+	"JWT_REFRESH_EXPIRY":          "duration",
+	"DEV_MODE":                    "bool",
+	"DEBUG_LOG_BODIES":            "bool",
+	"HTTP2_ENABLED":               "bool",
+	"GATEWAY_RETRY_ATTEMPTS":      "int",
+	"GATEWAY_RETRY_DELAY":         "duration",
+	"CDC_SYNC_DRIFT_THRESHOLD":    "float",
+	"CDC_LAG_DEGRADED_THRESHOLD":  "duration",
+	"CDC_SYNC_CONCURRENCY":        "int",
+	"CDC_PUBSUB_ENABLED":          "bool",
+	"CDC_LEADER_ELECTION_ENABLED": "bool",
+	"CDC_LEADER_LEASE_TTL":        "duration",
+	"RESERVATION_WINDOW_MINUTES":  "int",
+	"LOCK_KEEPER_MAX":             "int",
+	"LOCK_EXPIRY_EVENTS_ENABLED":  "bool",
+	"ADMISSION_WINDOW":            "int",
+	"ADMISSION_TTL":               "duration",
+	"PAYMENT_WORKERS":             "int",
+	"PAYMENT_QUEUE_SIZE":          "int",
+	"PAYMENT_RETRY_LIMIT":         "int",
+	"KAFKA_ENABLED":               "bool",
+	"MOCK_STRIPE_ENABLED":         "bool",
+	"MOCK_STRIPE_SUCCESS_RATE":    "float",
+	"MOCK_STRIPE_SEED":            "int",
+	"SERVICE_FEE_PERCENT":         "float",
+	"SERVICE_FEE_FLAT":            "float",
+	"DEFAULT_TAX_RATE":            "float",
+	"TAX_RATES":                   "rates",
+}
+
+// Validate checks the configuration for the given services and reports
+// every problem at once. The default JWT secret, internal token and
+// admission signing key are rejected unless APP_ENV is development, and
+// production additionally refuses development conveniences: wildcard CORS,
+// dev mode, request body logging and the in-memory Redis store.
+func (c *Config) Validate(services Service) error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.AppEnv {
+	case EnvDevelopment, EnvTest, EnvProduction:
+	default:
+		fail("APP_ENV: must be development, test or production, got %q", c.AppEnv)
+	}
+
+	errs = append(errs, malformedEnv()...)
+
+	if services&usesDatabase != 0 {
+		checkPort(fail, "DB_PORT", c.DBPort)
+		if c.DBHost == "" || c.DBUser == "" || c.DBName == "" {
+			fail("DB_HOST, DB_USER and DB_NAME are required")
+		}
+		if c.DBMaxOpenConns < 1 {
+			fail("DB_MAX_OPEN_CONNS: must be at least 1")
+		}
+		if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+			fail("DB_MAX_IDLE_CONNS: must be between 0 and DB_MAX_OPEN_CONNS")
+		}
+		if c.DBConnMaxLifetime < 0 || c.DBSlowQueryThreshold < 0 || c.DBConnectTimeout < 0 {
+			fail("DB_CONN_MAX_LIFETIME, DB_SLOW_QUERY_THRESHOLD and DB_CONNECT_TIMEOUT must not be negative")
+		}
+	}
+
+	if services&(EventService|BookingService) != 0 && !slices.Contains(validation.SupportedCurrencies, c.DefaultCurrency) {
+		fail("DEFAULT_CURRENCY: must be one of %s, got %q", strings.Join(validation.SupportedCurrencies, ", "), c.DefaultCurrency)
+	}
+
+	if services&EventService != 0 && c.SoftDeleteRetention < 0 {
+		fail("SOFT_DELETE_RETENTION: must not be negative")
+	}
+
+	if c.RedisInMemory && services&BookingService != 0 && !c.IsDevelopment() {
+		fail("REDIS_IN_MEMORY: only allowed when APP_ENV=development")
+	}
+	if services&usesRedis != 0 && !(services == BookingService && c.RedisInMemory) {
+		checkPort(fail, "REDIS_PORT", c.RedisPort)
+	}
+	if services&CDCService != 0 && (c.CDCPubSubEnabled || c.CDCLeaderElection) {
+		checkPort(fail, "REDIS_PORT", c.RedisPort)
+	}
+
+	if services&(SearchService|CDCService) != 0 {
+		checkURL(fail, "ELASTICSEARCH_URL", c.ElasticsearchURL)
+	}
+
+	if services&usesJWT != 0 {
+		if c.JWTSecret == defaultJWTSecret && !c.IsDevelopment() {
+			fail("JWT_SECRET: the default secret is only allowed when APP_ENV=development")
+		} else if c.IsProduction() && len(c.JWTSecret) < minProductionSecretLength {
+			fail("JWT_SECRET: must be at least %d characters in production", minProductionSecretLength)
+		}
+		if c.JWTExpiry <= 0 {
+			fail("JWT_EXPIRY: must be positive")
+		}
+		if c.JWTRefreshExpiry < c.JWTExpiry {
+			fail("JWT_REFRESH_EXPIRY: must be at least JWT_EXPIRY")
+		}
+	}
+
+	if services&usesInternal != 0 && c.InternalAuthToken == defaultInternalAuthToken && !c.IsDevelopment() {
+		fail("INTERNAL_AUTH_TOKEN: the default token is only allowed when APP_ENV=development")
+	}
+
+	if services&usesHTTP != 0 && (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		fail("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if services&usesHTTP != 0 && c.IsProduction() {
+		if len(c.CORSAllowedOrigins) == 0 {
+			fail("CORS_ALLOWED_ORIGINS: required in production")
+		}
+		for _, origin := range c.CORSAllowedOrigins {
+			if origin == "*" {
+				fail("CORS_ALLOWED_ORIGINS: \"*\" is not allowed in production")
+			}
+		}
+		if c.DevMode {
+			fail("DEV_MODE: not allowed in production")
+		}
+		if c.DebugLogBodies {
+			fail("DEBUG_LOG_BODIES: not allowed in production")
+		}
+	}
+
+	ports := []struct {
+		service Service
+		key     string
+		value   string
+	}{
+		{APIGateway, "API_GATEWAY_PORT", c.APIGatewayPort},
+		{SearchService, "SEARCH_SERVICE_PORT", c.SearchServicePort},
+		{EventService, "EVENT_SERVICE_PORT", c.EventServicePort},
+		{BookingService, "BOOKING_SERVICE_PORT", c.BookingServicePort},
+		{CDCService, "CDC_SERVICE_PORT", c.CDCServicePort},
+	}
+	for _, port := range ports {
+		if services&port.service != 0 {
+			checkPort(fail, port.key, port.value)
+		}
+	}
+
+	if services&APIGateway != 0 && (c.GatewayRetryAttempts < 0 || c.GatewayRetryDelay < 0) {
+		fail("GATEWAY_RETRY_ATTEMPTS and GATEWAY_RETRY_DELAY must not be negative")
+	}
+
+	if services&BookingService != 0 {
+		if c.ReservationWindowMinutes < 1 {
+			fail("RESERVATION_WINDOW_MINUTES: must be at least 1")
+		}
+		if c.AdmissionSigningKey == defaultAdmissionSigningKey && !c.IsDevelopment() {
+			fail("ADMISSION_SIGNING_KEY: the default key is only allowed when APP_ENV=development")
+		}
+		if c.PaymentWorkers < 1 {
+			fail("PAYMENT_WORKERS: must be at least 1")
+		}
+		if c.PaymentQueueSize < 0 || c.PaymentRetryLimit < 0 {
+			fail("PAYMENT_QUEUE_SIZE and PAYMENT_RETRY_LIMIT must not be negative")
+		}
+		if c.MockStripeEnabled {
+			if c.MockStripeSuccessRate < 0 || c.MockStripeSuccessRate > 1 {
+				fail("MOCK_STRIPE_SUCCESS_RATE: must be between 0 and 1, got %g", c.MockStripeSuccessRate)
+			}
+		} else {
+			if c.StripeSecretKey == "" {
+				fail("STRIPE_SECRET_KEY: required when MOCK_STRIPE_ENABLED=false")
+			}
+			checkURL(fail, "STRIPE_API_URL", c.StripeAPIURL)
+		}
+		if c.ServiceFeePercent < 0 || c.ServiceFeeFlat < 0 || c.DefaultTaxRate < 0 {
+			fail("SERVICE_FEE_PERCENT, SERVICE_FEE_FLAT and DEFAULT_TAX_RATE must not be negative")
+		}
+	}
+
+	if services&(BookingService|BookingConsumer) != 0 && (c.KafkaEnabled || services&BookingConsumer != 0) {
+		checkURL(fail, "KAFKA_REST_URL", c.KafkaRestURL)
+	}
+
+	if services&CDCService != 0 {
+		if c.CDCSyncDriftThreshold < 0 || c.CDCSyncDriftThreshold > 1 {
+			fail("CDC_SYNC_DRIFT_THRESHOLD: must be between 0 and 1, got %g", c.CDCSyncDriftThreshold)
+		}
+		if c.CDCSyncConcurrency < 1 {
+			fail("CDC_SYNC_CONCURRENCY: must be at least 1")
+		}
+		if c.CDCLeaderElection {
+			checkURL(fail, "CDC_ADVERTISE_URL", c.CDCAdvertiseURL)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// malformedEnv reports the typed variables that are set but do not parse
+func malformedEnv() []error {
+	var errs []error
+	for key, kind := range typedEnv {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch kind {
+		case "bool":
+			_, err = strconv.ParseBool(value)
+		case "int":
+			_, err = strconv.Atoi(value)
+		case "float":
+			_, err = strconv.ParseFloat(value, 64)
+		case "duration":
+			_, err = time.ParseDuration(value)
+		case "rates":
+			for _, pair := range strings.Split(value, ",") {
+				_, rate, ok := strings.Cut(pair, ":")
+				if !ok {
+					err = fmt.Errorf("%q is not KEY:rate", strings.TrimSpace(pair))
+					break
+				}
+				if _, err = strconv.ParseFloat(strings.TrimSpace(rate), 64); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: malformed %s %q", key, kind, value))
+		}
+	}
+
+	// Map order is random; keep the report stable
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+func checkPort(fail func(string, ...interface{}), key, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		fail("%s: must be a port number between 1 and 65535, got %q", key, value)
+	}
+}
+
+func checkURL(fail func(string, ...interface{}), key, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("%s: must be an http or https URL, got %q", key, value)
+	}
+}
@@ -1,133 +1,222 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-func Connect(cfg *config.Config) (*gorm.DB, error) {
+// Postgres error codes
+const (
+	deadlockDetected = "40P01"
+	uniqueViolation  = "23505"
+)
+
+// retryBaseDelay is the backoff before the second attempt of RunWithRetry;
+// it doubles per attempt and gets up to 100% jitter
+const retryBaseDelay = 20 * time.Millisecond
+
+// Connect backoff while the database is unreachable
+const (
+	connectBaseDelay = 500 * time.Millisecond
+	connectMaxDelay  = 5 * time.Second
+)
+
+// Connect opens the connection pool and migrates the schema. A database
+// that is not up yet is retried with backoff for up to DBConnectTimeout,
+// so services started before Postgres do not crash-loop; ctx cancels the
+// wait. With DBReplicaDSNs set, reads outside transactions go to the
+// replicas; use Primary for reads that must see the latest writes.
+func Connect(ctx context.Context, cfg *config.Config) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+	gormConfig := &gorm.Config{
+		Logger: newQueryLogger(slog.Default(), cfg.DBSlowQueryThreshold),
+	}
+
+	deadline := time.Now().Add(cfg.DBConnectTimeout)
+	delay := connectBaseDelay
+	var db *gorm.DB
+	for attempt := 1; ; attempt++ {
+		var err error
+		db, err = gorm.Open(postgres.Open(dsn), gormConfig)
+		if err == nil {
+			break
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("Database unavailable (attempt %d), retrying in %v: %v", attempt, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to database: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, connectMaxDelay)
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to configure connection pool: %w", err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
 
 	// Run migrations
-	if err := models.Migrate(db); err != nil {
+	if err := models.Migrate(db.WithContext(ctx)); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Register replicas once the schema is migrated, so migration only ever
+	// inspects the primary
+	if len(cfg.DBReplicaDSNs) > 0 {
+		replicas := make([]gorm.Dialector, len(cfg.DBReplicaDSNs))
+		for i, replicaDSN := range cfg.DBReplicaDSNs {
+			replicas[i] = postgres.Open(replicaDSN)
+		}
+		if err := UseReplicas(db, cfg, replicas...); err != nil {
+			return nil, err
+		}
+		log.Printf("Routing reads to %d replica(s)", len(replicas))
+	}
+
 	log.Println("Database connected and migrated successfully")
 	return db, nil
 }
 
-func SeedData(db *gorm.DB) error {
-	// Check if data already exists
-	var count int64
-	db.Model(&models.Venue{}).Count(&count)
-	if count > 0 {
-		log.Println("Data already seeded, skipping...")
-		return nil
+// UseReplicas sends db's reads outside transactions to the replicas in
+// turn, leaving writes and Primary reads on db's own connection
+func UseReplicas(db *gorm.DB, cfg *config.Config, replicas ...gorm.Dialector) error {
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.StrictRoundRobinPolicy(),
+	}).
+		SetMaxOpenConns(cfg.DBMaxOpenConns).
+		SetMaxIdleConns(cfg.DBMaxIdleConns).
+		SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
 	}
+	return nil
+}
 
-	// Create venues
-	venues := []models.Venue{
-		{Location: "Madison Square Garden, New York", SeatMap: `{"sections": ["A", "B", "C"], "rows": 50, "seatsPerRow": 20}`, Capacity: 20789},
-		{Location: "Hollywood Bowl, Los Angeles", SeatMap: `{"sections": ["1", "2", "3"], "rows": 30, "seatsPerRow": 25}`, Capacity: 17500},
-		{Location: "Royal Albert Hall, London", SeatMap: `{"sections": ["Stalls", "Circle", "Gallery"], "rows": 40, "seatsPerRow": 15}`, Capacity: 5272},
-	}
+// Primary pins db's queries to the primary, for reads that decide a write
+// and cannot act on a replica's stale rows. Without replicas it changes
+// nothing.
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write)
+}
 
-	for _, venue := range venues {
-		if err := db.Create(&venue).Error; err != nil {
-			return fmt.Errorf("failed to create venue: %w", err)
-		}
+// WithTransaction runs fn in a transaction: it commits when fn succeeds and
+// rolls back when fn returns an error or panics, re-raising the panic.
+// fn's error is returned unchanged; a failed commit is wrapped.
+func WithTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
 	}
 
-	// Create performers
-	performers := []models.Performer{
-		{Name: "Taylor Swift", Description: "Pop superstar", Genre: "Pop"},
-		{Name: "Coldplay", Description: "British rock band", Genre: "Rock"},
-		{Name: "Ed Sheeran", Description: "Singer-songwriter", Genre: "Pop"},
-		{Name: "Billie Eilish", Description: "Alternative pop artist", Genre: "Alternative"},
-	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
 
-	for _, performer := range performers {
-		if err := db.Create(&performer).Error; err != nil {
-			return fmt.Errorf("failed to create performer: %w", err)
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback().Error; rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			log.Printf("Failed to roll back transaction: %v", rbErr)
 		}
+		return err
 	}
 
-	// Create events
-	events := []models.Event{
-		{VenueID: 1, PerformerID: 1, Name: "Taylor Swift - Eras Tour", Description: "The Eras Tour is coming to Madison Square Garden", Date: parseDate("2024-06-15T20:00:00Z")},
-		{VenueID: 2, PerformerID: 2, Name: "Coldplay - Music of the Spheres", Description: "Experience Coldplay's cosmic journey", Date: parseDate("2024-07-20T19:30:00Z")},
-		{VenueID: 3, PerformerID: 3, Name: "Ed Sheeran - Mathematics Tour", Description: "Ed Sheeran's intimate acoustic performance", Date: parseDate("2024-08-10T20:00:00Z")},
-		{VenueID: 1, PerformerID: 4, Name: "Billie Eilish - Happier Than Ever", Description: "Billie Eilish's hauntingly beautiful performance", Date: parseDate("2024-09-05T19:00:00Z")},
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	return nil
+}
 
-	var createdEvents []models.Event
-	for _, event := range events {
-		if err := db.Create(&event).Error; err != nil {
-			return fmt.Errorf("failed to create event: %w", err)
+// RunWithRetry runs fn in a transaction, retrying the whole transaction with
+// jittered backoff when Postgres aborts it with a deadlock, up to maxAttempts
+// attempts in total. Any other error is returned immediately, and the
+// retries stop once ctx is done.
+func RunWithRetry(ctx context.Context, db *gorm.DB, fn func(*gorm.DB) error, maxAttempts int) error {
+	db = db.WithContext(ctx)
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = WithTransaction(db, fn)
+		if err == nil || !IsDeadlock(err) {
+			return err
+		}
+		if attempt == maxAttempts {
+			break
 		}
-		createdEvents = append(createdEvents, event)
-	}
 
-	// Create tickets for each event
-	for _, event := range createdEvents {
-		if err := createTicketsForEvent(db, event.ID, event.VenueID); err != nil {
-			return fmt.Errorf("failed to create tickets for event %d: %w", event.ID, err)
+		delay := retryBaseDelay << (attempt - 1)
+		delay += time.Duration(rand.Int63n(int64(delay)))
+		log.Printf("Transaction deadlocked (attempt %d of %d), retrying in %v", attempt, maxAttempts, delay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transaction abandoned after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(delay):
 		}
 	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", maxAttempts, err)
+}
 
-	log.Println("Sample data seeded successfully")
-	return nil
+// IsDeadlock reports whether err is a Postgres deadlock error
+func IsDeadlock(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == deadlockDetected
 }
 
-func createTicketsForEvent(db *gorm.DB, eventID, venueID uint) error {
-	var venue models.Venue
-	if err := db.First(&venue, venueID).Error; err != nil {
-		return err
-	}
+// IsUniqueViolation reports whether err is a Postgres unique constraint
+// violation
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
 
-	// Create tickets with different price tiers
-	priceTiers := []struct {
-		section string
-		price   float64
-		count   int
-	}{
-		{"VIP", 299.99, 100},
-		{"Premium", 199.99, 200},
-		{"Standard", 99.99, 300},
-		{"Economy", 49.99, 400},
+// ResetData empties every table, children first, and restarts their ID
+// sequences so SeedData produces the same IDs every time. The migration
+// history keeps its other steps but forgets the seed, since the seeded
+// data is gone.
+func ResetData(db *gorm.DB) error {
+	all := models.All()
+	tables := make([]string, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if _, ok := all[i].(*models.MigrationHistory); ok {
+			continue
+		}
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(all[i]); err != nil {
+			return fmt.Errorf("failed to resolve table name: %w", err)
+		}
+		tables = append(tables, stmt.Schema.Table)
 	}
 
-	for _, tier := range priceTiers {
-		for i := 0; i < tier.count; i++ {
-			ticket := models.Ticket{
-				EventID: eventID,
-				Seat:    fmt.Sprintf("%s-%d", tier.section, i+1),
-				Price:   tier.price,
-				Status:  "available",
-			}
-			if err := db.Create(&ticket).Error; err != nil {
-				return err
-			}
-		}
+	if err := db.Exec("TRUNCATE TABLE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+		return fmt.Errorf("failed to truncate tables: %w", err)
+	}
+	if err := db.Where("version = ?", SeedVersion).Delete(&models.MigrationHistory{}).Error; err != nil {
+		return fmt.Errorf("failed to clear seed history: %w", err)
 	}
 
+	log.Printf("Reset %d tables", len(tables))
 	return nil
 }
-
-func parseDate(dateStr string) time.Time {
-	t, _ := time.Parse(time.RFC3339, dateStr)
-	return t
-}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// openWithReplica opens a primary and a replica in separate sqlite files,
+// with nothing copying rows between them, and routes the primary's reads
+// to the replica. It returns the routed handle and a direct one to the
+// replica.
+func openWithReplica(t *testing.T) (*gorm.DB, *gorm.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	primary := testutil.OpenDB(t, filepath.Join(dir, "primary.db"))
+	replicaPath := filepath.Join(dir, "replica.db")
+	replica := testutil.OpenDB(t, replicaPath)
+
+	if err := UseReplicas(primary, &config.Config{}, testutil.Dialector(replicaPath)); err != nil {
+		t.Fatalf("failed to register replica: %v", err)
+	}
+	return primary, replica
+}
+
+func TestReplicaRouting(t *testing.T) {
+	db, replica := openWithReplica(t)
+
+	written := models.Performer{Name: "written to the primary"}
+	if err := db.Create(&written).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+	var count int64
+	replica.Model(&models.Performer{}).Count(&count)
+	if count != 0 {
+		t.Fatal("a write reached the replica")
+	}
+
+	// The replica has not caught up, so a routed read misses the row
+	var performer models.Performer
+	if err := db.First(&performer, written.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("read found the primary's row (err %v), want it routed to the replica", err)
+	}
+
+	if err := Primary(db).First(&performer, written.ID).Error; err != nil {
+		t.Fatalf("Primary read missed the row just written: %v", err)
+	}
+
+	err := WithTransaction(db, func(tx *gorm.DB) error {
+		return tx.First(&models.Performer{}, written.ID).Error
+	})
+	if err != nil {
+		t.Fatalf("read inside a transaction missed the primary's row: %v", err)
+	}
+
+	// Rows replicated by the time of the read are served from the replica
+	if err := replica.Create(&models.Performer{Name: "replicated"}).Error; err != nil {
+		t.Fatalf("failed to seed replica: %v", err)
+	}
+	var names []string
+	if err := db.Model(&models.Performer{}).Pluck("name", &names).Error; err != nil {
+		t.Fatalf("routed read failed: %v", err)
+	}
+	if len(names) != 1 || names[0] != "replicated" {
+		t.Errorf("routed read returned %v, want only the replica's row", names)
+	}
+}
+
+func TestNoReplicasReadsFromPrimary(t *testing.T) {
+	db := testutil.NewDB(t)
+
+	written := models.Performer{Name: "written"}
+	if err := db.Create(&written).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+	if err := db.First(&models.Performer{}, written.ID).Error; err != nil {
+		t.Fatalf("read without replicas missed the row just written: %v", err)
+	}
+	if err := Primary(db).First(&models.Performer{}, written.ID).Error; err != nil {
+		t.Fatalf("Primary read without replicas failed: %v", err)
+	}
+}
+
+func TestRunWithRetryStopsWhenContextIsDone(t *testing.T) {
+	db := testutil.NewDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	deadlock := &pgconn.PgError{Code: deadlockDetected}
+	start := time.Now()
+	err := RunWithRetry(ctx, db, func(tx *gorm.DB) error {
+		attempts++
+		cancel()
+		return deadlock
+	}, 10)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("ran %d attempts after the context was cancelled, want 1", attempts)
+	}
+	if elapsed := time.Since(start); elapsed > retryBaseDelay {
+		t.Errorf("returned after %v, want no backoff once the context is done", elapsed)
+	}
+}
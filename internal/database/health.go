@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// healthCheckTimeout bounds a single database ping
+const healthCheckTimeout = 3 * time.Second
+
+// HealthCheckInterval is how often services ping their database
+const HealthCheckInterval = 10 * time.Second
+
+// HealthCheck pings the database. database/sql replaces broken pooled
+// connections on use, so a failed ping also clears connections left over
+// from before a Postgres restart.
+func HealthCheck(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
+// WatchHealth pings the database every interval until ctx is done and calls
+// onUnhealthy with the error of every failed ping
+func WatchHealth(ctx context.Context, db *gorm.DB, interval time.Duration, onUnhealthy func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := HealthCheck(db); err != nil {
+				onUnhealthy(err)
+			}
+		}
+	}
+}
+
+// Readiness tracks recent health check failures for a service's health
+// endpoint. A failure counts until two intervals pass without another one,
+// so the service becomes ready again once pings succeed.
+type Readiness struct {
+	service  string
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastErr  error
+	failedAt time.Time
+}
+
+func NewReadiness(service string, interval time.Duration) *Readiness {
+	return &Readiness{service: service, interval: interval}
+}
+
+// Watch runs WatchHealth, logging and recording every failure
+func (r *Readiness) Watch(ctx context.Context, db *gorm.DB) {
+	WatchHealth(ctx, db, r.interval, func(err error) {
+		log.Printf("level=error service=%s component=database msg=%q error=%q", r.service, "health check failed", err.Error())
+
+		r.mu.Lock()
+		r.lastErr = err
+		r.failedAt = time.Now()
+		r.mu.Unlock()
+	})
+}
+
+// Err returns the latest health check failure while it still counts
+func (r *Readiness) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.lastErr == nil || time.Since(r.failedAt) > 2*r.interval {
+		return nil
+	}
+	return r.lastErr
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Versions of the one-off data steps recorded in the migration history
+const (
+	SeedVersion    = "seed_v1"
+	ESIndexVersion = "es_index_v2" // v2: esVersion on events
+)
+
+// AppliedMigration returns the history record of version, or nil if the
+// step has not run
+func AppliedMigration(db *gorm.DB, version string) (*models.MigrationHistory, error) {
+	var record models.MigrationHistory
+	if err := db.First(&record, "version = ?", version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	return &record, nil
+}
+
+// RecordMigration marks version as applied now, replacing an earlier record
+// of it so a forced rerun stores the new checksum
+func RecordMigration(db *gorm.DB, version, checksum string) error {
+	record := models.MigrationHistory{Version: version, AppliedAt: time.Now(), Checksum: checksum}
+	if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", version, err)
+	}
+	return nil
+}
+
+// Checksum digests what a step applies, for its history record
+func Checksum(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
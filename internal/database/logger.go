@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/requestid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// queryLogger logs failed queries and queries slower than a threshold
+// through slog, tagged with the ID of the request that ran them. Record
+// not found is an expected outcome and is not logged.
+type queryLogger struct {
+	logger    *slog.Logger
+	threshold time.Duration
+	level     logger.LogLevel
+}
+
+// newQueryLogger logs queries slower than threshold; 0 logs only failures
+func newQueryLogger(l *slog.Logger, threshold time.Duration) *queryLogger {
+	return &queryLogger{logger: l, threshold: threshold, level: logger.Warn}
+}
+
+func (l *queryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...), "request_id", requestid.FromContext(ctx))
+	}
+}
+
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...), "request_id", requestid.FromContext(ctx))
+	}
+}
+
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...), "request_id", requestid.FromContext(ctx))
+	}
+}
+
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "query failed",
+			"request_id", requestid.FromContext(ctx),
+			"elapsed_ms", elapsed.Milliseconds(),
+			"rows", rows,
+			"sql", sql,
+			"error", err)
+	case l.threshold > 0 && elapsed >= l.threshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "slow query",
+			"request_id", requestid.FromContext(ctx),
+			"elapsed_ms", elapsed.Milliseconds(),
+			"threshold_ms", l.threshold.Milliseconds(),
+			"rows", rows,
+			"sql", sql)
+	}
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PurgeInterval is how often the event service purges soft-deleted rows
+const PurgeInterval = time.Hour
+
+// purgeSteps hard-delete rows soft-deleted before @cutoff, children before
+// their parents. A row is kept while something still points at it:
+// bookings with payments or refunds are financial records, and their
+// tickets, events and users stay with them.
+var purgeSteps = []struct{ table, query string }{
+	{"bookings", `DELETE FROM bookings WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.booking_id = bookings.id)
+		AND NOT EXISTS (SELECT 1 FROM refunds r WHERE r.booking_id = bookings.id)`},
+	{"tickets", `DELETE FROM tickets WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM bookings b WHERE b.ticket_id = tickets.id)`},
+	{"ticket_tiers", `DELETE FROM ticket_tiers WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM tickets t WHERE t.tier_id = ticket_tiers.id)`},
+	{"events", `DELETE FROM events WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM tickets t WHERE t.event_id = events.id)`},
+	{"users", `DELETE FROM users WHERE deleted_at < @cutoff
+		AND NOT EXISTS (SELECT 1 FROM bookings b WHERE b.user_id = users.id)`},
+}
+
+// PurgeDeleted hard-deletes rows soft-deleted before cutoff and returns how
+// many were removed from each table
+func PurgeDeleted(ctx context.Context, db *gorm.DB, cutoff time.Time) (map[string]int64, error) {
+	purged := make(map[string]int64, len(purgeSteps))
+	for _, step := range purgeSteps {
+		result := db.WithContext(ctx).Exec(step.query, map[string]interface{}{"cutoff": cutoff})
+		if result.Error != nil {
+			return purged, fmt.Errorf("failed to purge %s: %w", step.table, result.Error)
+		}
+		purged[step.table] = result.RowsAffected
+	}
+	return purged, nil
+}
+
+// RunPurge purges rows soft-deleted more than retention ago every interval
+// until ctx is done. The deletes are idempotent, so every replica may run
+// it. A retention of 0 disables the purge.
+func RunPurge(ctx context.Context, db *gorm.DB, retention, interval time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := PurgeDeleted(ctx, db, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Soft-delete purge failed: %v", err)
+			}
+			for table, n := range purged {
+				if n > 0 {
+					log.Printf("Purged %d soft-deleted rows from %s", n, table)
+				}
+			}
+		}
+	}
+}
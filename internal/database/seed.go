@@ -0,0 +1,333 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// seedBatchSize is how many tickets one INSERT writes
+const seedBatchSize = 500
+
+// seedUserPassword is the password of every seeded user
+const seedUserPassword = "password"
+
+// SeedOptions sizes the seeded dataset
+type SeedOptions struct {
+	Events          int  // The sample events first, then upcoming events cycling through the sample venues and performers
+	TicketsPerEvent int  // Split 1:2:3:4 across the VIP, Premium, Standard and Economy tiers
+	Users           int  // Each user gets one confirmed booking
+	Force           bool // Truncate existing data and reseed instead of skipping
+}
+
+// DefaultSeedOptions is the dataset SeedData creates
+func DefaultSeedOptions() SeedOptions {
+	return SeedOptions{Events: 8, TicketsPerEvent: 1000, Users: 3}
+}
+
+// seedTiers are the price tiers of every seeded event; weights split the
+// tickets between them
+var seedTiers = []struct {
+	name   string
+	price  money.Amount
+	weight int
+}{
+	{"VIP", 29999, 1},
+	{"Premium", 19999, 2},
+	{"Standard", 9999, 3},
+	{"Economy", 4999, 4},
+}
+
+// SeedData seeds the default sample dataset unless data is already present
+func SeedData(db *gorm.DB) error {
+	return Seed(db, DefaultSeedOptions())
+}
+
+// Seed creates venues, performers, events with tiered tickets, users and
+// a confirmed booking per user, all in one transaction so a failure leaves
+// nothing behind, and records SeedVersion in the migration history in the
+// same transaction. Once recorded it does nothing unless Force is set,
+// which truncates every table first. IDs are the same on every run against
+// an empty database.
+func Seed(db *gorm.DB, opts SeedOptions) error {
+	applied, err := AppliedMigration(db, SeedVersion)
+	if err != nil {
+		return err
+	}
+	if applied == nil {
+		if applied, err = recordLegacySeed(db); err != nil {
+			return err
+		}
+	}
+	if applied != nil {
+		if !opts.Force {
+			log.Printf("Data already seeded (%s at %s), skipping...", applied.Version, applied.AppliedAt.Format(time.RFC3339))
+			return nil
+		}
+		if err := ResetData(db); err != nil {
+			return err
+		}
+	}
+
+	// Hash once; bcrypt is deliberately slow
+	password, err := bcrypt.GenerateFromPassword([]byte(seedUserPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash seed password: %w", err)
+	}
+
+	start := time.Now()
+	err = WithTransaction(db, func(tx *gorm.DB) error {
+		venues, performers, err := seedDirectory(tx)
+		if err != nil {
+			return err
+		}
+
+		events, err := seedEvents(tx, venues, performers, opts.Events)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := seedTickets(tx, event.ID, opts.TicketsPerEvent); err != nil {
+				return fmt.Errorf("failed to create tickets for event %d: %w", event.ID, err)
+			}
+		}
+
+		if err := seedUsers(tx, events, opts.Users, string(password)); err != nil {
+			return err
+		}
+
+		return RecordMigration(tx, SeedVersion, seedChecksum(opts))
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Seeded %d events with %d tickets each and %d users in %v",
+		opts.Events, opts.TicketsPerEvent, opts.Users, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// seedChecksum identifies the dataset opts produce
+func seedChecksum(opts SeedOptions) string {
+	return Checksum(fmt.Sprintf("events=%d tickets=%d users=%d", opts.Events, opts.TicketsPerEvent, opts.Users))
+}
+
+// legacySeedChecksum marks data seeded before the migration history existed
+const legacySeedChecksum = "legacy"
+
+// recordLegacySeed records SeedVersion for a database seeded before the
+// migration history existed, so it is not seeded a second time. It returns
+// nil if the database holds no data.
+func recordLegacySeed(db *gorm.DB) (*models.MigrationHistory, error) {
+	var count int64
+	if err := db.Model(&models.Venue{}).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check for existing data: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	if err := RecordMigration(db, SeedVersion, legacySeedChecksum); err != nil {
+		return nil, err
+	}
+	return AppliedMigration(db, SeedVersion)
+}
+
+func seedDirectory(tx *gorm.DB) ([]models.Venue, []models.Performer, error) {
+	venues := []models.Venue{
+		{Location: "Madison Square Garden, New York", SeatMap: `{"sections": ["A", "B", "C"], "rows": 50, "seatsPerRow": 20}`, Capacity: 20789, Country: "US"},
+		{Location: "Hollywood Bowl, Los Angeles", SeatMap: `{"sections": ["1", "2", "3"], "rows": 30, "seatsPerRow": 25}`, Capacity: 17500, Country: "US"},
+		{Location: "Royal Albert Hall, London", SeatMap: `{"sections": ["Stalls", "Circle", "Gallery"], "rows": 40, "seatsPerRow": 15}`, Capacity: 5272, Country: "GB"},
+	}
+	if err := tx.Create(&venues).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to create venues: %w", err)
+	}
+
+	performers := []models.Performer{
+		{Name: "Taylor Swift", Description: "Pop superstar", Genre: "Pop"},
+		{Name: "Coldplay", Description: "British rock band", Genre: "Rock"},
+		{Name: "Ed Sheeran", Description: "Singer-songwriter", Genre: "Pop"},
+		{Name: "Billie Eilish", Description: "Alternative pop artist", Genre: "Alternative"},
+	}
+	if err := tx.Create(&performers).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to create performers: %w", err)
+	}
+
+	return venues, performers, nil
+}
+
+// seedEvents creates the sample events, then as many upcoming ones as it
+// takes to reach n, one week apart starting next month
+func seedEvents(tx *gorm.DB, venues []models.Venue, performers []models.Performer, n int) ([]models.Event, error) {
+	events := []models.Event{
+		{VenueID: venues[0].ID, PerformerID: performers[0].ID, Name: "Taylor Swift - Eras Tour", Description: "The Eras Tour is coming to Madison Square Garden", Date: parseDate("2024-06-15T20:00:00Z")},
+		{VenueID: venues[1].ID, PerformerID: performers[1].ID, Name: "Coldplay - Music of the Spheres", Description: "Experience Coldplay's cosmic journey", Date: parseDate("2024-07-20T19:30:00Z")},
+		{VenueID: venues[2].ID, PerformerID: performers[2].ID, Name: "Ed Sheeran - Mathematics Tour", Description: "Ed Sheeran's intimate acoustic performance", Date: parseDate("2024-08-10T20:00:00Z")},
+		{VenueID: venues[0].ID, PerformerID: performers[3].ID, Name: "Billie Eilish - Happier Than Ever", Description: "Billie Eilish's hauntingly beautiful performance", Date: parseDate("2024-09-05T19:00:00Z")},
+	}
+	if n < len(events) {
+		events = events[:n]
+	}
+
+	firstDate := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0).Add(20 * time.Hour)
+	for i := len(events); i < n; i++ {
+		performer := performers[i%len(performers)]
+		venue := venues[i%len(venues)]
+		events = append(events, models.Event{
+			VenueID:     venue.ID,
+			PerformerID: performer.ID,
+			Name:        fmt.Sprintf("%s - Live #%d", performer.Name, i+1),
+			Description: fmt.Sprintf("%s live at %s", performer.Name, venue.Location),
+			Date:        firstDate.AddDate(0, 0, 7*(i-4)),
+		})
+	}
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	if err := tx.Create(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to create events: %w", err)
+	}
+	return events, nil
+}
+
+// seedTickets creates the event's tiers and n tickets split between them
+func seedTickets(tx *gorm.DB, eventID uint, n int) error {
+	totalWeight := 0
+	for _, t := range seedTiers {
+		totalWeight += t.weight
+	}
+
+	remaining := n
+	for i, t := range seedTiers {
+		count := n * t.weight / totalWeight
+		if i == len(seedTiers)-1 {
+			count = remaining
+		}
+		remaining -= count
+
+		tier := models.TicketTier{
+			EventID:        eventID,
+			Name:           t.name,
+			Price:          t.price,
+			TotalCount:     count,
+			AvailableCount: count,
+		}
+		if err := tx.Create(&tier).Error; err != nil {
+			return err
+		}
+
+		tickets := make([]models.Ticket, count)
+		for j := range tickets {
+			tickets[j] = models.Ticket{
+				EventID: eventID,
+				TierID:  &tier.ID,
+				Seat:    fmt.Sprintf("%s-%d", t.name, j+1),
+				Price:   t.price,
+				Status:  models.TicketAvailable,
+			}
+		}
+		if len(tickets) > 0 {
+			if err := tx.CreateInBatches(&tickets, seedBatchSize).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// seedUsers creates n users, user1@example.com and so on, each with a
+// confirmed and paid booking for the cheapest available ticket of one of
+// the events
+func seedUsers(tx *gorm.DB, events []models.Event, n int, passwordHash string) error {
+	for i := 1; i <= n; i++ {
+		user := models.User{
+			Email:    fmt.Sprintf("user%d@example.com", i),
+			Password: passwordHash,
+			Name:     fmt.Sprintf("Sample User %d", i),
+			Role:     models.RoleUser,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		if len(events) == 0 {
+			continue
+		}
+		event := events[(i-1)%len(events)]
+
+		var ticket models.Ticket
+		err := tx.Where("event_id = ? AND status = ?", event.ID, models.TicketAvailable).Order("price, id").First(&ticket).Error
+		if err == gorm.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to pick a ticket: %w", err)
+		}
+
+		if err := seedBooking(tx, &user, &ticket, event.Currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedBooking books the ticket for the user as if they had reserved and
+// paid for it an hour ago
+func seedBooking(tx *gorm.DB, user *models.User, ticket *models.Ticket, currency string) error {
+	reservedAt := time.Now().Add(-time.Hour)
+	paymentID := fmt.Sprintf("pi_seed_%d", ticket.ID)
+
+	if err := tx.Model(ticket).Updates(map[string]interface{}{
+		"status":  models.TicketBooked,
+		"user_id": user.ID,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to book ticket: %w", err)
+	}
+	if ticket.TierID != nil {
+		if err := tx.Model(&models.TicketTier{}).Where("id = ?", *ticket.TierID).
+			Update("available_count", gorm.Expr("available_count - 1")).Error; err != nil {
+			return fmt.Errorf("failed to update ticket tier: %w", err)
+		}
+	}
+
+	booking := models.Booking{
+		TicketID:   ticket.ID,
+		UserID:     user.ID,
+		Status:     models.BookingConfirmed,
+		ReservedAt: reservedAt,
+		ExpiresAt:  reservedAt.Add(10 * time.Minute),
+		PaymentID:  paymentID,
+		Price:      ticket.Price,
+		AmountPaid: ticket.Price,
+	}
+	if err := tx.Create(&booking).Error; err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	record := models.Payment{
+		IntentID:  paymentID,
+		BookingID: booking.ID,
+		UserID:    user.ID,
+		Amount:    ticket.Price,
+		Currency:  currency,
+		Status:    models.PaymentSucceeded,
+		Provider:  payment.ProviderMockStripe,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to record payment: %w", err)
+	}
+	return nil
+}
+
+func parseDate(dateStr string) time.Time {
+	t, _ := time.Parse(time.RFC3339, dateStr)
+	return t
+}
@@ -0,0 +1,265 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+)
+
+// Indices for venue and performer search, kept next to the events index
+const (
+	VenuesIndex     = "venues"
+	PerformersIndex = "performers"
+)
+
+// venuesMapping is the venues index's mapping
+const venuesMapping = `{
+	"mappings": {
+		"properties": {
+			"id": {"type": "integer"},
+			"location": {"type": "text", "analyzer": "standard"},
+			"capacity": {"type": "integer"}
+		}
+	}
+}`
+
+// performersMapping is the performers index's mapping
+const performersMapping = `{
+	"mappings": {
+		"properties": {
+			"id": {"type": "integer"},
+			"name": {"type": "text", "analyzer": "standard"},
+			"description": {"type": "text", "analyzer": "standard"},
+			"genre": {"type": "keyword"}
+		}
+	}
+}`
+
+// directorySearchSize is the number of venues or performers a search returns
+const directorySearchSize = 20
+
+// CreateVenueIndex creates the venues index if it does not exist
+func (c *Client) CreateVenueIndex() error {
+	return c.createIndexIfMissing(VenuesIndex, venuesMapping)
+}
+
+// CreatePerformerIndex creates the performers index if it does not exist
+func (c *Client) CreatePerformerIndex() error {
+	return c.createIndexIfMissing(PerformersIndex, performersMapping)
+}
+
+// IndexVenue writes a venue's search document
+func (c *Client) IndexVenue(ctx context.Context, venue *models.ElasticsearchVenue) error {
+	return c.putDocument(ctx, VenuesIndex, venue.ID, venue)
+}
+
+// IndexPerformer writes a performer's search document
+func (c *Client) IndexPerformer(ctx context.Context, performer *models.ElasticsearchPerformer) error {
+	return c.putDocument(ctx, PerformersIndex, performer.ID, performer)
+}
+
+// DeleteVenue removes a venue from the index; a missing document is not an error
+func (c *Client) DeleteVenue(ctx context.Context, venueID uint) error {
+	return c.deleteDocument(ctx, VenuesIndex, venueID)
+}
+
+// DeletePerformer removes a performer from the index; a missing document is
+// not an error
+func (c *Client) DeletePerformer(ctx context.Context, performerID uint) error {
+	return c.deleteDocument(ctx, PerformersIndex, performerID)
+}
+
+// SearchVenues returns the venues matching query
+func (c *Client) SearchVenues(ctx context.Context, query map[string]interface{}) ([]models.ElasticsearchVenue, error) {
+	var venues []models.ElasticsearchVenue
+	if err := c.searchIndex(ctx, VenuesIndex, query, &venues); err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+// SearchPerformers returns the performers matching query
+func (c *Client) SearchPerformers(ctx context.Context, query map[string]interface{}) ([]models.ElasticsearchPerformer, error) {
+	var performers []models.ElasticsearchPerformer
+	if err := c.searchIndex(ctx, PerformersIndex, query, &performers); err != nil {
+		return nil, err
+	}
+	return performers, nil
+}
+
+// BuildVenueQuery matches venues by location; an empty term lists all venues
+func BuildVenueQuery(term string) map[string]interface{} {
+	must := []map[string]interface{}{}
+	if term != "" {
+		must = append(must, map[string]interface{}{
+			"match": map[string]interface{}{"location": term},
+		})
+	}
+	return map[string]interface{}{
+		"query": map[string]interface{}{"bool": map[string]interface{}{"must": must}},
+		"size":  directorySearchSize,
+	}
+}
+
+// BuildPerformerQuery matches performers by name and description, optionally
+// filtered to one genre
+func BuildPerformerQuery(term, genre string) map[string]interface{} {
+	must := []map[string]interface{}{}
+	if term != "" {
+		must = append(must, map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  term,
+				"fields": []string{"name^2", "description"},
+			},
+		})
+	}
+	filter := []map[string]interface{}{}
+	if genre != "" {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"genre": genre},
+		})
+	}
+	return map[string]interface{}{
+		"query": map[string]interface{}{"bool": map[string]interface{}{"must": must, "filter": filter}},
+		"size":  directorySearchSize,
+	}
+}
+
+// createIndexIfMissing creates an index with the given settings and mappings
+// unless it already exists
+func (c *Client) createIndexIfMissing(indexName, mapping string) error {
+	indexURL := fmt.Sprintf("%s/%s", c.baseURL, indexName)
+	resp, err := c.client.Head(indexURL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	req, err := http.NewRequest("PUT", indexURL, strings.NewReader(mapping))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create index %s: %s", indexName, string(body))
+	}
+
+	return nil
+}
+
+// putDocument writes doc under id, replacing any previous version
+func (c *Client) putDocument(ctx context.Context, indexName string, id uint, doc interface{}) error {
+	docJSON, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s document: %w", indexName, err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_doc/%d?refresh=true", c.baseURL, indexName, id)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(docJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to index %s document %d: %s", indexName, id, string(body))
+	}
+
+	return nil
+}
+
+// deleteDocument removes the document with id; a missing one is not an error
+func (c *Client) deleteDocument(ctx context.Context, indexName string, id uint) error {
+	url := fmt.Sprintf("%s/%s/_doc/%d?refresh=true", c.baseURL, indexName, id)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete %s document %d: %s", indexName, id, string(body))
+	}
+
+	return nil
+}
+
+// searchIndex runs query against an index and decodes the hits' sources
+// into dest, which must point to a slice
+func (c *Client) searchIndex(ctx context.Context, indexName string, query map[string]interface{}, dest interface{}) error {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_search", c.baseURL, indexName)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(queryJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("search failed: %s", string(body))
+	}
+
+	var searchResponse struct {
+		Hits struct {
+			Hits []struct {
+				Source json.RawMessage `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	sources := make([]json.RawMessage, len(searchResponse.Hits.Hits))
+	for i, hit := range searchResponse.Hits.Hits {
+		sources[i] = hit.Source
+	}
+	// Re-encode as one array so dest's element type does the decoding
+	raw, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dest)
+}
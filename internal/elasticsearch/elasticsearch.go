@@ -2,16 +2,24 @@ package elasticsearch
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
 )
 
+// ErrEventNotIndexed means the event has no document to update
+var ErrEventNotIndexed = errors.New("event is not indexed")
+
+// ErrVersionConflict means the event's document changed between reading
+// its version and writing it
+var ErrVersionConflict = errors.New("event document was modified concurrently")
+
 type Client struct {
 	baseURL string
 	client  *http.Client
@@ -28,12 +36,28 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to ping Elasticsearch: %w", err)
 	}
 
-	// Create index if it doesn't exist
-	if err := client.CreateIndex(); err != nil {
-		return nil, fmt.Errorf("failed to create index: %w", err)
+	return client, nil
+}
+
+// EnsureIndices creates the events, venues and performers indices if they
+// do not exist
+func (c *Client) EnsureIndices() error {
+	if err := c.CreateIndex(); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
 	}
+	if err := c.CreateVenueIndex(); err != nil {
+		return fmt.Errorf("failed to create venues index: %w", err)
+	}
+	if err := c.CreatePerformerIndex(); err != nil {
+		return fmt.Errorf("failed to create performers index: %w", err)
+	}
+	return nil
+}
 
-	return client, nil
+// IndexMappings returns each index name followed by its mapping, in
+// creation order, so callers can tell when the mappings change
+func IndexMappings() []string {
+	return []string{"events", eventsMapping, VenuesIndex, venuesMapping, PerformersIndex, performersMapping}
 }
 
 func (c *Client) Ping() error {
@@ -50,94 +74,223 @@ func (c *Client) Ping() error {
 	return nil
 }
 
+// eventsMapping is the events index's mapping
+const eventsMapping = `{
+	"mappings": {
+		"properties": {
+			"id": {"type": "integer"},
+			"venueId": {"type": "integer"},
+			"performerId": {"type": "integer"},
+			"name": {"type": "text", "analyzer": "standard"},
+			"description": {"type": "text", "analyzer": "standard"},
+			"date": {"type": "date"},
+			"venue": {"type": "text", "analyzer": "standard"},
+			"performer": {"type": "text", "analyzer": "standard"},
+			"genre": {"type": "keyword"},
+			"location": {"type": "text", "analyzer": "standard"},
+			"currency": {"type": "keyword"},
+			"minPrice": {"type": "float"},
+			"maxPrice": {"type": "float"},
+			"availableTickets": {"type": "integer"},
+			"featured": {"type": "boolean"},
+			"esVersion": {"type": "long"}
+		}
+	}
+}`
+
 func (c *Client) CreateIndex() error {
+	return c.createIndexIfMissing("events", eventsMapping)
+}
+
+// IndexEvent writes an event's document unless the indexed one has a
+// higher ESVersion, in which case it is stale and skipped. The write is
+// conditional on the document not changing since its version was read;
+// when it did, ErrVersionConflict is returned and the caller should
+// rebuild the document from the database before trying again.
+func (c *Client) IndexEvent(ctx context.Context, event *models.ElasticsearchEvent) error {
 	indexName := "events"
 
-	// Check if index exists
-	checkURL := fmt.Sprintf("%s/%s", c.baseURL, indexName)
-	resp, err := c.client.Head(checkURL)
+	current, err := c.eventVersion(ctx, event.ID)
 	if err != nil {
 		return err
 	}
-	resp.Body.Close()
+	if current != nil && current.ESVersion > event.ESVersion {
+		return nil
+	}
 
-	if resp.StatusCode == 200 {
-		return nil // Index already exists
-	}
-
-	// Create index with mapping
-	mapping := `{
-		"mappings": {
-			"properties": {
-				"id": {"type": "integer"},
-				"venueId": {"type": "integer"},
-				"performerId": {"type": "integer"},
-				"name": {"type": "text", "analyzer": "standard"},
-				"description": {"type": "text", "analyzer": "standard"},
-				"date": {"type": "date"},
-				"venue": {"type": "text", "analyzer": "standard"},
-				"performer": {"type": "text", "analyzer": "standard"},
-				"genre": {"type": "keyword"},
-				"location": {"type": "text", "analyzer": "standard"},
-				"minPrice": {"type": "float"},
-				"maxPrice": {"type": "float"},
-				"availableTickets": {"type": "integer"}
-			}
-		}
-	}`
+	// Convert to JSON
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
 
-	createURL := fmt.Sprintf("%s/%s", c.baseURL, indexName)
-	req, err := http.NewRequest("PUT", createURL, strings.NewReader(mapping))
+	url := fmt.Sprintf("%s/%s/_doc/%d?op_type=create&refresh=true", c.baseURL, indexName, event.ID)
+	if current != nil {
+		url = fmt.Sprintf("%s/%s/_doc/%d?if_seq_no=%d&if_primary_term=%d&refresh=true", c.baseURL, indexName, event.ID, current.SeqNo, current.PrimaryTerm)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(eventJSON))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err = c.client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		return ErrVersionConflict
+	}
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create index: %s", string(body))
+		return fmt.Errorf("failed to index event: %s", string(body))
 	}
 
 	return nil
 }
 
-func (c *Client) IndexEvent(event *models.ElasticsearchEvent) error {
-	indexName := "events"
+// documentVersion is where an indexed event document stands, for
+// conditional writes
+type documentVersion struct {
+	SeqNo       int64
+	PrimaryTerm int64
+	ESVersion   int64
+}
 
-	// Convert to JSON
-	eventJSON, err := json.Marshal(event)
+// eventVersion reads the version of an event's document, returning nil if
+// it is not indexed
+func (c *Client) eventVersion(ctx context.Context, eventID uint) (*documentVersion, error) {
+	url := fmt.Sprintf("%s/events/_doc/%d?_source_includes=esVersion", c.baseURL, eventID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/%s/_doc/%d?refresh=true", c.baseURL, indexName, event.ID)
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(eventJSON))
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get event version: %s", string(body))
+	}
+
+	var getResponse struct {
+		Found       bool  `json:"found"`
+		SeqNo       int64 `json:"_seq_no"`
+		PrimaryTerm int64 `json:"_primary_term"`
+		Source      struct {
+			ESVersion int64 `json:"esVersion"`
+		} `json:"_source"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&getResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode event version: %w", err)
+	}
+	if !getResponse.Found {
+		return nil, nil
+	}
+
+	return &documentVersion{
+		SeqNo:       getResponse.SeqNo,
+		PrimaryTerm: getResponse.PrimaryTerm,
+		ESVersion:   getResponse.Source.ESVersion,
+	}, nil
+}
+
+// BulkIndexEvents indexes events with a single _bulk request. Each document
+// is written with its ESVersion as an external version, so one that is
+// older than the indexed document is skipped as stale, like IndexEvent
+// does. It returns the IDs of events that failed individually; the error
+// is only set when the request as a whole failed.
+func (c *Client) BulkIndexEvents(ctx context.Context, events []*models.ElasticsearchEvent) ([]uint, error) {
+	if len(events) == 0 {
+		return nil, nil
 	}
-	req.Header.Set("Content-Type", "application/json")
+
+	indexName := "events"
+
+	// Build the NDJSON body: an action line followed by the document
+	var body bytes.Buffer
+	for _, event := range events {
+		action := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_index":       indexName,
+				"_id":          event.ID,
+				"version":      event.ESVersion,
+				"version_type": "external_gte",
+			},
+		}
+		actionJSON, err := json.Marshal(action)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal bulk action: %w", err)
+		}
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event: %w", err)
+		}
+		body.Write(actionJSON)
+		body.WriteByte('\n')
+		body.Write(eventJSON)
+		body.WriteByte('\n')
+	}
+
+	url := fmt.Sprintf("%s/_bulk?refresh=true", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to index event: %s", string(body))
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("bulk index failed: %s", string(respBody))
 	}
 
-	return nil
+	var bulkResponse struct {
+		Errors bool `json:"errors"`
+		Items  []struct {
+			Index struct {
+				Status int `json:"status"`
+			} `json:"index"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bulkResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+
+	var failed []uint
+	if bulkResponse.Errors {
+		for i, item := range bulkResponse.Items {
+			// A conflict means a newer document is already indexed
+			if item.Index.Status >= 300 && item.Index.Status != http.StatusConflict && i < len(events) {
+				failed = append(failed, events[i].ID)
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+// SearchHit is an event document plus any highlighted fragments, keyed by field
+type SearchHit struct {
+	models.ElasticsearchEvent
+	Highlights map[string][]string `json:"highlights,omitempty"`
 }
 
-func (c *Client) SearchEvents(query map[string]interface{}) ([]models.ElasticsearchEvent, error) {
+func (c *Client) SearchEvents(ctx context.Context, query map[string]interface{}) ([]SearchHit, error) {
 	indexName := "events"
 
 	// Convert query to JSON
@@ -147,7 +300,7 @@ func (c *Client) SearchEvents(query map[string]interface{}) ([]models.Elasticsea
 	}
 
 	url := fmt.Sprintf("%s/%s/_search", c.baseURL, indexName)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(queryJSON))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(queryJSON))
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +321,8 @@ func (c *Client) SearchEvents(query map[string]interface{}) ([]models.Elasticsea
 	var searchResponse struct {
 		Hits struct {
 			Hits []struct {
-				Source models.ElasticsearchEvent `json:"_source"`
+				Source    models.ElasticsearchEvent `json:"_source"`
+				Highlight map[string][]string       `json:"highlight"`
 			} `json:"hits"`
 		} `json:"hits"`
 	}
@@ -177,19 +331,22 @@ func (c *Client) SearchEvents(query map[string]interface{}) ([]models.Elasticsea
 		return nil, fmt.Errorf("failed to decode search response: %w", err)
 	}
 
-	events := make([]models.ElasticsearchEvent, len(searchResponse.Hits.Hits))
+	events := make([]SearchHit, len(searchResponse.Hits.Hits))
 	for i, hit := range searchResponse.Hits.Hits {
-		events[i] = hit.Source
+		events[i] = SearchHit{
+			ElasticsearchEvent: hit.Source,
+			Highlights:         hit.Highlight,
+		}
 	}
 
 	return events, nil
 }
 
-func (c *Client) DeleteEvent(eventID uint) error {
+func (c *Client) DeleteEvent(ctx context.Context, eventID uint) error {
 	indexName := "events"
 
 	url := fmt.Sprintf("%s/%s/_doc/%d?refresh=true", c.baseURL, indexName, eventID)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -200,7 +357,8 @@ func (c *Client) DeleteEvent(eventID uint) error {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
+	// Deleting a document that is already gone is not an error
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to delete event: %s", string(body))
 	}
@@ -208,16 +366,335 @@ func (c *Client) DeleteEvent(eventID uint) error {
 	return nil
 }
 
-func (c *Client) UpdateEvent(event *models.ElasticsearchEvent) error {
-	return c.IndexEvent(event) // Elasticsearch treats update as index
+// CountEvents returns the number of documents in the events index
+func (c *Client) CountEvents(ctx context.Context) (int64, error) {
+	indexName := "events"
+
+	url := fmt.Sprintf("%s/%s/_count", c.baseURL, indexName)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to count events: %s", string(body))
+	}
+
+	var countResponse struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&countResponse); err != nil {
+		return 0, fmt.Errorf("failed to decode count response: %w", err)
+	}
+
+	return countResponse.Count, nil
+}
+
+// GetEvent fetches an indexed event document, returning nil if it is not indexed
+func (c *Client) GetEvent(ctx context.Context, eventID uint) (*models.ElasticsearchEvent, error) {
+	docs, err := c.GetEvents(ctx, []uint{eventID})
+	if err != nil {
+		return nil, err
+	}
+	return docs[eventID], nil
+}
+
+// GetEvents fetches indexed event documents with a single _mget request.
+// Events that are not indexed are absent from the result.
+func (c *Client) GetEvents(ctx context.Context, eventIDs []uint) (map[uint]*models.ElasticsearchEvent, error) {
+	indexName := "events"
+
+	docs := make(map[uint]*models.ElasticsearchEvent, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return docs, nil
+	}
+
+	ids := make([]string, len(eventIDs))
+	for i, id := range eventIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	queryJSON, err := json.Marshal(map[string]interface{}{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_mget", c.baseURL, indexName)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(queryJSON))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get events: %s", string(body))
+	}
+
+	var mgetResponse struct {
+		Docs []struct {
+			Found  bool                      `json:"found"`
+			Source models.ElasticsearchEvent `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&mgetResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode get response: %w", err)
+	}
+
+	for i := range mgetResponse.Docs {
+		doc := &mgetResponse.Docs[i]
+		if doc.Found {
+			docs[doc.Source.ID] = &doc.Source
+		}
+	}
+
+	return docs, nil
+}
+
+// IndexedEventIDs lists the IDs of every document in the events index,
+// scrolling through it without fetching document sources
+func (c *Client) IndexedEventIDs(ctx context.Context) ([]uint, error) {
+	indexName := "events"
+
+	queryJSON, err := json.Marshal(map[string]interface{}{
+		"_source": false,
+		"size":    1000,
+		"sort":    []string{"_doc"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_search?scroll=1m", c.baseURL, indexName)
+	page, err := c.scrollPage(ctx, url, queryJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for len(page.Hits.Hits) > 0 {
+		for _, hit := range page.Hits.Hits {
+			var id uint
+			if _, err := fmt.Sscanf(hit.ID, "%d", &id); err == nil {
+				ids = append(ids, id)
+			}
+		}
+
+		scrollJSON, err := json.Marshal(map[string]interface{}{"scroll": "1m", "scroll_id": page.ScrollID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal scroll request: %w", err)
+		}
+		page, err = c.scrollPage(ctx, fmt.Sprintf("%s/_search/scroll", c.baseURL), scrollJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.clearScroll(ctx, page.ScrollID)
+	return ids, nil
+}
+
+// scrollResponse is one page of a scrolled search
+type scrollResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (c *Client) scrollPage(ctx context.Context, url string, body []byte) (*scrollResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("scroll failed: %s", string(respBody))
+	}
+
+	var page scrollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode scroll response: %w", err)
+	}
+	return &page, nil
+}
+
+// clearScroll frees a scroll context; failures only delay its expiry
+func (c *Client) clearScroll(ctx context.Context, scrollID string) {
+	if scrollID == "" {
+		return
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"scroll_id": scrollID})
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/_search/scroll", c.baseURL), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+func (c *Client) UpdateEvent(ctx context.Context, event *models.ElasticsearchEvent) error {
+	return c.IndexEvent(ctx, event) // Elasticsearch treats update as index
+}
+
+// PartialUpdateEvent merges fields (keyed by document field name) into an
+// indexed event with the _update API. It returns ErrEventNotIndexed if the
+// event has no document.
+func (c *Client) PartialUpdateEvent(ctx context.Context, eventID uint, fields map[string]interface{}) error {
+	indexName := "events"
+
+	updateJSON, err := json.Marshal(map[string]interface{}{"doc": fields})
+	if err != nil {
+		return fmt.Errorf("failed to marshal update: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_update/%d?refresh=true", c.baseURL, indexName, eventID)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(updateJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrEventNotIndexed
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update event: %s", string(body))
+	}
+
+	return nil
+}
+
+// Explanation is Elasticsearch's breakdown of how a document's score was computed
+type Explanation struct {
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details,omitempty"`
 }
 
+// ExplainEvent runs query restricted to a single event with explain enabled
+// and returns the score explanation, or nil if the event does not match
+func (c *Client) ExplainEvent(ctx context.Context, query map[string]interface{}, eventID uint) (*Explanation, error) {
+	indexName := "events"
+
+	// Restrict the query to the one document
+	explainQuery := map[string]interface{}{
+		"explain": true,
+		"size":    1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   []interface{}{query["query"]},
+				"filter": []map[string]interface{}{{"ids": map[string]interface{}{"values": []string{fmt.Sprint(eventID)}}}},
+			},
+		},
+	}
+
+	queryJSON, err := json.Marshal(explainQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_search", c.baseURL, indexName)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(queryJSON))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("explain failed: %s", string(body))
+	}
+
+	var searchResponse struct {
+		Hits struct {
+			Hits []struct {
+				Explanation Explanation `json:"_explanation"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode explain response: %w", err)
+	}
+
+	if len(searchResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return &searchResponse.Hits.Hits[0].Explanation, nil
+}
+
+// highlightFragmentSize caps the length of each highlighted snippet
+const highlightFragmentSize = 150
+
+// SearchParams holds the filters accepted by the search endpoint
+type SearchParams struct {
+	Term        string
+	Location    string
+	EventType   string
+	Date        string
+	VenueID     uint
+	PerformerID uint
+	Highlight   bool
+	Sort        string // SortRelevance or SortFeatured
+}
+
+// Sort orders accepted by BuildSearchQuery
+const (
+	SortRelevance = ""
+	SortFeatured  = "featured" // Featured events first, then by relevance
+)
+
 // BuildSearchQuery constructs an Elasticsearch query from search parameters
-func BuildSearchQuery(term, location, eventType, date string) map[string]interface{} {
+func BuildSearchQuery(params SearchParams) map[string]interface{} {
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
-				"must": []map[string]interface{}{},
+				"must":   []map[string]interface{}{},
+				"filter": []map[string]interface{}{},
 			},
 		},
 		"size": 50,
@@ -225,47 +702,89 @@ func BuildSearchQuery(term, location, eventType, date string) map[string]interfa
 
 	boolQuery := query["query"].(map[string]interface{})["bool"].(map[string]interface{})
 	mustClauses := boolQuery["must"].([]map[string]interface{})
+	filterClauses := boolQuery["filter"].([]map[string]interface{})
 
 	// Text search across name, description, performer, venue
-	if term != "" {
+	if params.Term != "" {
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"multi_match": map[string]interface{}{
-				"query":  term,
+				"query":  params.Term,
 				"fields": []string{"name^2", "description", "performer^1.5", "venue"},
 			},
 		})
 	}
 
 	// Location filter
-	if location != "" {
+	if params.Location != "" {
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"match": map[string]interface{}{
-				"location": location,
+				"location": params.Location,
 			},
 		})
 	}
 
 	// Genre/type filter
-	if eventType != "" {
+	if params.EventType != "" {
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"term": map[string]interface{}{
-				"genre": eventType,
+				"genre": params.EventType,
 			},
 		})
 	}
 
 	// Date filter
-	if date != "" {
+	if params.Date != "" {
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"range": map[string]interface{}{
 				"date": map[string]interface{}{
-					"gte": date,
+					"gte": params.Date,
 				},
 			},
 		})
 	}
 
+	// Venue and performer filters (exact match, no scoring)
+	if params.VenueID != 0 {
+		filterClauses = append(filterClauses, map[string]interface{}{
+			"term": map[string]interface{}{
+				"venueId": params.VenueID,
+			},
+		})
+	}
+
+	if params.PerformerID != 0 {
+		filterClauses = append(filterClauses, map[string]interface{}{
+			"term": map[string]interface{}{
+				"performerId": params.PerformerID,
+			},
+		})
+	}
+
 	boolQuery["must"] = mustClauses
+	boolQuery["filter"] = filterClauses
+
+	if params.Sort == SortFeatured {
+		query["sort"] = []interface{}{
+			map[string]interface{}{"featured": map[string]interface{}{"order": "desc", "unmapped_type": "boolean"}},
+			"_score",
+		}
+	}
+
+	// Highlight matched words; only meaningful for a text search. The html
+	// encoder escapes stored markup so only our <em> tags reach clients.
+	if params.Highlight && params.Term != "" {
+		query["highlight"] = map[string]interface{}{
+			"pre_tags":            []string{"<em>"},
+			"post_tags":           []string{"</em>"},
+			"encoder":             "html",
+			"fragment_size":       highlightFragmentSize,
+			"number_of_fragments": 3,
+			"fields": map[string]interface{}{
+				"name":        map[string]interface{}{"number_of_fragments": 0},
+				"description": map[string]interface{}{},
+			},
+		}
+	}
 
 	return query
 }
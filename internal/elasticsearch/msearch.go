@@ -0,0 +1,89 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// MultiSearch runs one query per index in a single _msearch request, which
+// Elasticsearch executes concurrently, and returns each index's hit sources
+// keyed like queries. A failed search fails the whole call.
+func (c *Client) MultiSearch(ctx context.Context, queries map[string]map[string]interface{}) (map[string][]json.RawMessage, error) {
+	if len(queries) == 0 {
+		return map[string][]json.RawMessage{}, nil
+	}
+
+	// Responses come back in request order
+	indices := make([]string, 0, len(queries))
+	for index := range queries {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, index := range indices {
+		if err := enc.Encode(map[string]string{"index": index}); err != nil {
+			return nil, fmt.Errorf("failed to marshal header for %s: %w", index, err)
+		}
+		if err := enc.Encode(queries[index]); err != nil {
+			return nil, fmt.Errorf("failed to marshal query for %s: %w", index, err)
+		}
+	}
+
+	url := fmt.Sprintf("%s/_msearch", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("multi-search failed: %s", string(body))
+	}
+
+	var msearchResponse struct {
+		Responses []struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+			Hits   struct {
+				Hits []struct {
+					Source json.RawMessage `json:"_source"`
+				} `json:"hits"`
+			} `json:"hits"`
+		} `json:"responses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&msearchResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode multi-search response: %w", err)
+	}
+	if len(msearchResponse.Responses) != len(indices) {
+		return nil, fmt.Errorf("multi-search returned %d responses for %d queries", len(msearchResponse.Responses), len(indices))
+	}
+
+	results := make(map[string][]json.RawMessage, len(indices))
+	for i, index := range indices {
+		response := msearchResponse.Responses[i]
+		if len(response.Error) > 0 || response.Status >= 400 {
+			return nil, fmt.Errorf("search of %s failed: %s", index, string(response.Error))
+		}
+
+		sources := make([]json.RawMessage, len(response.Hits.Hits))
+		for j, hit := range response.Hits.Hits {
+			sources[j] = hit.Source
+		}
+		results[index] = sources
+	}
+	return results, nil
+}
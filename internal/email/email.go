@@ -0,0 +1,32 @@
+// Package email sends transactional emails such as booking confirmations
+package email
+
+import (
+	"context"
+	"log"
+)
+
+// Email is an outgoing message
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers emails
+type EmailSender interface {
+	Send(ctx context.Context, msg Email) error
+}
+
+// LogSender writes emails to the service log instead of delivering them
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+// Send logs the email
+func (s *LogSender) Send(ctx context.Context, msg Email) error {
+	log.Printf("Email to %s: %s", msg.To, msg.Subject)
+	return nil
+}
@@ -0,0 +1,73 @@
+package email
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SentEmail is an email captured by MockSender
+type SentEmail struct {
+	Email
+	SentAt time.Time
+}
+
+// MockSender captures emails instead of sending them, for tests. It is safe
+// for concurrent use.
+type MockSender struct {
+	mu       sync.Mutex
+	sent     []SentEmail
+	failNext error
+}
+
+func NewMockSender() *MockSender {
+	return &MockSender{}
+}
+
+// Send records the email, or returns the error set by FailOnNext
+func (m *MockSender) Send(ctx context.Context, msg Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failNext != nil {
+		err := m.failNext
+		m.failNext = nil
+		return err
+	}
+
+	m.sent = append(m.sent, SentEmail{Email: msg, SentAt: time.Now()})
+	return nil
+}
+
+// SentEmails returns a copy of the captured emails in send order
+func (m *MockSender) SentEmails() []SentEmail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentEmail(nil), m.sent...)
+}
+
+// LastSent returns the most recently captured email, or nil if none
+func (m *MockSender) LastSent() *SentEmail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return nil
+	}
+	last := m.sent[len(m.sent)-1]
+	return &last
+}
+
+// FailOnNext makes the next Send fail with err without capturing the email
+func (m *MockSender) FailOnNext(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failNext = err
+}
+
+// Reset clears captured emails and any pending failure
+func (m *MockSender) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+	m.failNext = nil
+}
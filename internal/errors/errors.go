@@ -0,0 +1,63 @@
+// Package errors defines the error envelope returned by every HTTP endpoint
+// and the machine-readable codes clients can switch on (e.g. for i18n).
+package errors
+
+// Error codes
+const (
+	ErrCodeInvalidRequest      = "INVALID_REQUEST"
+	ErrCodeUnauthorized        = "UNAUTHORIZED"
+	ErrCodeInvalidToken        = "INVALID_TOKEN"
+	ErrCodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	ErrCodeForbidden           = "FORBIDDEN"
+	ErrCodeInvalidMFACode      = "INVALID_MFA_CODE"
+	ErrCodeMFAAlreadyEnabled   = "MFA_ALREADY_ENABLED"
+	ErrCodeMFANotEnrolled      = "MFA_NOT_ENROLLED"
+	ErrCodeUserExists          = "USER_EXISTS"
+	ErrCodeUserNotFound        = "USER_NOT_FOUND"
+	ErrCodeAccountSuspended    = "ACCOUNT_SUSPENDED"
+	ErrCodeEventNotFound       = "EVENT_NOT_FOUND"
+	ErrCodeEventHasBookings    = "EVENT_HAS_BOOKINGS"
+	ErrCodeVenueNotFound       = "VENUE_NOT_FOUND"
+	ErrCodePerformerNotFound   = "PERFORMER_NOT_FOUND"
+	ErrCodeTicketNotFound      = "TICKET_NOT_FOUND"
+	ErrCodeBookingNotFound     = "BOOKING_NOT_FOUND"
+	ErrCodeNotCancellable      = "BOOKING_NOT_CANCELLABLE"
+	ErrCodeRefundNotFound      = "REFUND_NOT_FOUND"
+	ErrCodePaymentNotFound     = "PAYMENT_NOT_FOUND"
+	ErrCodeReceiptNotFound     = "RECEIPT_NOT_FOUND"
+	ErrCodeTicketUnavailable   = "TICKET_UNAVAILABLE"
+	ErrCodeTicketLocked        = "TICKET_LOCKED"
+	ErrCodeLockReleased        = "LOCK_RELEASED"
+	ErrCodeLockingUnavailable  = "LOCKING_UNAVAILABLE"
+	ErrCodeTierSoldOut         = "TIER_SOLD_OUT"
+	ErrCodeNotAdmitted         = "NOT_ADMITTED"
+	ErrCodeReservationExpired  = "RESERVATION_EXPIRED"
+	ErrCodeAlreadyWaitlisted   = "ALREADY_WAITLISTED"
+	ErrCodeNotWaitlisted       = "NOT_WAITLISTED"
+	ErrCodePaymentFailed       = "PAYMENT_FAILED"
+	ErrCodePaymentError        = "PAYMENT_ERROR"
+	ErrCodePaymentNotRetryable = "PAYMENT_NOT_RETRYABLE"
+	ErrCodePaymentInProgress   = "PAYMENT_IN_PROGRESS"
+	ErrCodeRetryLimitReached   = "RETRY_LIMIT_REACHED"
+	ErrCodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	ErrCodeSyncInProgress      = "SYNC_IN_PROGRESS"
+	ErrCodeJobNotFound         = "JOB_NOT_FOUND"
+	ErrCodeInternal            = "INTERNAL_ERROR"
+)
+
+// ErrorResponse is the standard JSON body of every error response
+type ErrorResponse struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewResponse builds an error response; details may be nil
+func NewResponse(code, message string, details map[string]interface{}) ErrorResponse {
+	return ErrorResponse{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+}
@@ -0,0 +1,135 @@
+// Package eventbus passes events between parts of one process, so the code
+// that changes a booking does not call everything that reacts to it.
+// Delivery is in process and best effort: events are lost on restart and
+// dropped when a subscriber falls behind.
+package eventbus
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is how many events a subscriber may have waiting
+const DefaultBufferSize = 256
+
+// Event is one published payload
+type Event struct {
+	Topic       string
+	Payload     interface{}
+	PublishedAt time.Time
+}
+
+// subscription feeds one handler from its own buffered channel, so a slow
+// handler only delays itself
+type subscription struct {
+	handler func(Event)
+	events  chan Event
+}
+
+// Bus delivers published events to the handlers subscribed to their topic.
+// Each handler runs on its own goroutine and sees its topic's events in
+// publish order. Publish never blocks: an event is dropped, with a warning,
+// for any subscriber whose buffer is full.
+type Bus struct {
+	bufferSize int
+
+	mu     sync.RWMutex
+	topics map[string][]*subscription
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// New returns a Bus buffering up to bufferSize events per subscriber
+func New(bufferSize int) *Bus {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &Bus{
+		bufferSize: bufferSize,
+		topics:     make(map[string][]*subscription),
+	}
+}
+
+// Subscribe calls handler for every event later published on topic.
+// Subscribing to a closed bus does nothing.
+func (b *Bus) Subscribe(topic string, handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	sub := &subscription{handler: handler, events: make(chan Event, b.bufferSize)}
+	b.topics[topic] = append(b.topics[topic], sub)
+
+	b.wg.Add(1)
+	go b.work(topic, sub)
+}
+
+// Publish hands payload to every subscriber of topic without waiting for
+// them
+func (b *Bus) Publish(topic string, payload interface{}) {
+	event := Event{Topic: topic, Payload: payload, PublishedAt: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+	for _, sub := range b.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+			log.Printf("Event bus: subscriber to %s is %d events behind, dropping event", topic, b.bufferSize)
+		}
+	}
+}
+
+// Close stops accepting events and waits for subscribers to handle the
+// ones already published, or for ctx to end
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, subs := range b.topics {
+			for _, sub := range subs {
+				close(sub.events)
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) work(topic string, sub *subscription) {
+	defer b.wg.Done()
+	for event := range sub.events {
+		b.deliver(topic, sub, event)
+	}
+}
+
+// deliver runs the handler, keeping a panicking one from killing its
+// worker
+func (b *Bus) deliver(topic string, sub *subscription, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event bus: %s handler panicked: %v", topic, r)
+		}
+	}()
+	sub.handler(event)
+}
@@ -0,0 +1,32 @@
+package eventbus
+
+import "github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+// Topics published by the booking service
+const (
+	// TopicBookingConfirmed carries a BookingChanged once a paid booking is
+	// committed as confirmed
+	TopicBookingConfirmed = "booking.confirmed"
+	// TopicBookingCancelled carries a BookingChanged once a cancellation
+	// is committed
+	TopicBookingCancelled = "booking.cancelled"
+	// TopicTicketAvailable carries a TicketAvailable once a cancelled or
+	// expired booking's ticket is back on sale
+	TopicTicketAvailable = "ticket.available"
+)
+
+// BookingChanged is a booking state transition. PreviousStatus is the
+// status the booking left.
+type BookingChanged struct {
+	BookingID      uint
+	UserID         uint
+	TicketID       uint
+	EventID        uint
+	PreviousStatus models.BookingStatus
+}
+
+// TicketAvailable is a ticket returned to sale
+type TicketAvailable struct {
+	TicketID uint
+	EventID  uint
+}
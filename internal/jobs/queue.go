@@ -0,0 +1,116 @@
+// Package jobs runs work in the background on a fixed pool of goroutines,
+// so request handlers can hand off slow calls without tying up their own.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+var (
+	// ErrQueueFull means every worker is busy and the buffer is full
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrQueueClosed means the queue is shutting down
+	ErrQueueClosed = errors.New("job queue is closed")
+)
+
+// Job is one unit of background work
+type Job struct {
+	ID  string
+	Run func(ctx context.Context) error
+}
+
+// Queue feeds submitted jobs to a pool of workers through a buffered
+// channel. Jobs are not cancelled on shutdown: Stop waits for the queued
+// ones to finish.
+type Queue struct {
+	name string
+	jobs chan Job
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewQueue starts workers goroutines draining a buffer of size jobs
+func NewQueue(name string, workers, size int) *Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	q := &Queue{
+		name: name,
+		jobs: make(chan Job, size),
+	}
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// Submit enqueues a job without blocking
+func (q *Queue) Submit(job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Len reports how many jobs are waiting for a worker
+func (q *Queue) Len() int {
+	return len(q.jobs)
+}
+
+// Stop refuses new jobs and waits for the queued ones to finish, or for ctx
+// to end
+func (q *Queue) Stop(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.run(job)
+	}
+}
+
+// run executes one job, keeping a panicking job from killing its worker
+func (q *Queue) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("%s job %s panicked: %v", q.name, job.ID, r)
+		}
+	}()
+
+	if err := job.Run(context.Background()); err != nil {
+		log.Printf("%s job %s failed: %v", q.name, job.ID, err)
+	}
+}
@@ -0,0 +1,218 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+)
+
+// The client talks to Kafka through the Confluent REST Proxy (v2 API) so
+// services need no native Kafka driver, like the Elasticsearch client.
+
+const (
+	jsonContentType = "application/vnd.kafka.json.v2+json"
+	v2ContentType   = "application/vnd.kafka.v2+json"
+)
+
+// Booking lifecycle transitions published to the booking events topic
+const (
+	BookingReserved  = "Reserved"
+	BookingConfirmed = "Confirmed"
+	BookingCancelled = "Cancelled"
+	BookingExpired   = "Expired"
+)
+
+// BookingEvent is a booking state transition message
+type BookingEvent struct {
+	EventType string          `json:"eventType"`
+	BookingID uint            `json:"bookingId"`
+	UserID    uint            `json:"userId"`
+	TicketID  uint            `json:"ticketId"`
+	Timestamp time.Time       `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// Record is a message read by a Consumer
+type Record struct {
+	Topic     string          `json:"topic"`
+	Key       json.RawMessage `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Partition int             `json:"partition"`
+	Offset    int64           `json:"offset"`
+}
+
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		baseURL: cfg.KafkaRestURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Produce publishes value as JSON to topic. Messages with the same key go to
+// the same partition, keeping their order.
+func (c *Client) Produce(ctx context.Context, topic, key string, value interface{}) error {
+	body := map[string]interface{}{
+		"records": []map[string]interface{}{{"key": key, "value": value}},
+	}
+
+	url := fmt.Sprintf("%s/topics/%s", c.baseURL, topic)
+	resp, err := c.do(ctx, http.MethodPost, url, jsonContentType, body)
+	if err != nil {
+		return fmt.Errorf("failed to produce to %s: %w", topic, err)
+	}
+	defer resp.Body.Close()
+
+	var produceResponse struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&produceResponse); err != nil {
+		return fmt.Errorf("failed to decode produce response: %w", err)
+	}
+	for _, offset := range produceResponse.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("failed to produce to %s: %s", topic, offset.Error)
+		}
+	}
+
+	return nil
+}
+
+// PublishBookingEvent publishes a booking transition keyed by booking ID
+func (c *Client) PublishBookingEvent(ctx context.Context, topic string, event BookingEvent) error {
+	return c.Produce(ctx, topic, strconv.FormatUint(uint64(event.BookingID), 10), event)
+}
+
+// Consumer is a REST Proxy consumer instance subscribed to one topic.
+// Offsets are only committed by Commit.
+type Consumer struct {
+	client      *Client
+	instanceURL string
+}
+
+// NewConsumer creates a consumer instance in group and subscribes it to
+// topic, starting from the earliest uncommitted offset
+func (c *Client) NewConsumer(ctx context.Context, group, name, topic string) (*Consumer, error) {
+	create := map[string]interface{}{
+		"name":               name,
+		"format":             "json",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}
+	resp, err := c.do(ctx, http.MethodPost, fmt.Sprintf("%s/consumers/%s", c.baseURL, group), v2ContentType, create)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var instance struct {
+		BaseURI string `json:"base_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
+		return nil, fmt.Errorf("failed to decode consumer response: %w", err)
+	}
+
+	consumer := &Consumer{client: c, instanceURL: instance.BaseURI}
+
+	subscription := map[string]interface{}{"topics": []string{topic}}
+	resp, err = c.do(ctx, http.MethodPost, consumer.instanceURL+"/subscription", v2ContentType, subscription)
+	if err != nil {
+		consumer.Close(ctx)
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+	resp.Body.Close()
+
+	return consumer, nil
+}
+
+// Poll fetches the next records; an empty result means nothing arrived
+// within the proxy's poll timeout
+func (c *Consumer) Poll(ctx context.Context) ([]Record, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.instanceURL+"/records", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", jsonContentType)
+
+	resp, err := c.client.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var records []Record
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode records: %w", err)
+	}
+	return records, nil
+}
+
+// Commit commits the offsets of every record returned by Poll so far
+func (c *Consumer) Commit(ctx context.Context) error {
+	resp, err := c.client.do(ctx, http.MethodPost, c.instanceURL+"/offsets", v2ContentType, nil)
+	if err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Close deletes the consumer instance so the group rebalances right away
+func (c *Consumer) Close(ctx context.Context) error {
+	resp, err := c.client.do(ctx, http.MethodDelete, c.instanceURL, v2ContentType, nil)
+	if err != nil {
+		return fmt.Errorf("failed to close consumer: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a request with an optional JSON body
+func (c *Client) do(ctx context.Context, method, url, contentType string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyJSON)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return c.send(req)
+}
+
+// send performs a request, turning error statuses into errors
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
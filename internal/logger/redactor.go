@@ -0,0 +1,93 @@
+// Package logger holds helpers for keeping personal data out of logs
+package logger
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the value of every redacted key
+const Redacted = "[REDACTED]"
+
+// LogRedactor replaces the values of sensitive keys in log entries. Keys
+// are compared case-insensitively and without underscores or dashes, and a
+// key ending in a listed one is redacted too, so "token" covers
+// refreshToken and "email" covers user_email.
+type LogRedactor struct {
+	keys []string
+}
+
+// NewLogRedactor redacts the given keys
+func NewLogRedactor(keys []string) *LogRedactor {
+	r := &LogRedactor{keys: make([]string, 0, len(keys))}
+	for _, key := range keys {
+		if key = normalizeKey(key); key != "" {
+			r.keys = append(r.keys, key)
+		}
+	}
+	return r
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(strings.TrimSpace(key)))
+}
+
+// IsRedacted reports whether values under key are redacted
+func (r *LogRedactor) IsRedacted(key string) bool {
+	key = normalizeKey(key)
+	for _, redacted := range r.keys {
+		if strings.HasSuffix(key, redacted) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the values of redacted keys in entry, descending into
+// nested maps and slices. entry is modified in place and returned.
+func (r *LogRedactor) Redact(entry map[string]interface{}) map[string]interface{} {
+	for key, val := range entry {
+		if r.IsRedacted(key) {
+			entry[key] = Redacted
+		} else {
+			entry[key] = r.redactValue(val)
+		}
+	}
+	return entry
+}
+
+func (r *LogRedactor) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return r.Redact(v)
+	case []interface{}:
+		for i, val := range v {
+			v[i] = r.redactValue(val)
+		}
+	}
+	return v
+}
+
+// RedactQuery returns the raw query string with the values of redacted
+// parameters replaced, leaving the rest as sent
+func (r *LogRedactor) RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		rawKey, _, hasValue := strings.Cut(param, "=")
+		if !hasValue {
+			continue
+		}
+		key := rawKey
+		if unescaped, err := url.QueryUnescape(rawKey); err == nil {
+			key = unescaped
+		}
+		if r.IsRedacted(key) {
+			params[i] = rawKey + "=" + Redacted
+		}
+	}
+	return strings.Join(params, "&")
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+)
+
+var defaultKeys = []string{"email", "password", "payment_details", "token", "authorization"}
+
+func TestRedact(t *testing.T) {
+	r := NewLogRedactor(defaultKeys)
+	entry := map[string]interface{}{
+		"email":          "foo@bar.com",
+		"Password":       "secret",
+		"paymentDetails": "pm_card_visa",
+		"refresh_token":  "eyJhbGciOi",
+		"ticketId":       float64(42),
+		"user": map[string]interface{}{
+			"user_email": "foo@bar.com",
+			"name":       "Foo",
+		},
+		"attendees": []interface{}{
+			map[string]interface{}{"Email": "a@b.com", "seat": "A1"},
+		},
+	}
+
+	got := r.Redact(entry)
+	want := map[string]interface{}{
+		"email":          Redacted,
+		"Password":       Redacted,
+		"paymentDetails": Redacted,
+		"refresh_token":  Redacted,
+		"ticketId":       float64(42),
+		"user": map[string]interface{}{
+			"user_email": Redacted,
+			"name":       "Foo",
+		},
+		"attendees": []interface{}{
+			map[string]interface{}{"Email": Redacted, "seat": "A1"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	r := NewLogRedactor(defaultKeys)
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"page=2&q=rock", "page=2&q=rock"},
+		{"token=abc&page=2", "token=[REDACTED]&page=2"},
+		{"user%5Femail=foo%40bar.com&flag", "user%5Femail=[REDACTED]&flag"},
+	}
+	for _, tt := range tests {
+		if got := r.RedactQuery(tt.query); got != tt.want {
+			t.Errorf("RedactQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// RedisCircuitOpen is 1 while the booking service bypasses Redis for ticket locks
+	RedisCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "booking_redis_circuit_open",
+		Help: "Whether the booking service Redis circuit breaker is open (1) or closed (0).",
+	})
+
+	// DegradedReservations counts reservations locked via Postgres advisory locks
+	DegradedReservations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "booking_degraded_reservations_total",
+		Help: "Reservations made with the Postgres advisory lock fallback while Redis was unavailable.",
+	})
+
+	// LockKeepersActive is the number of ticket locks currently being renewed
+	LockKeepersActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "booking_lock_keepers_active",
+		Help: "Ticket locks currently kept alive by a lock keeper.",
+	})
+
+	// LockRenewalFailures counts lock renewals that errored or found the lock gone
+	LockRenewalFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "booking_lock_renewal_failures_total",
+		Help: "Ticket lock renewals that failed or found the lock no longer held.",
+	})
+
+	// BookingFunnelDuration is the time from reservation to each step of the
+	// booking funnel. The reserved step is observed as 0 so that its count is
+	// the number of reservations the other steps convert from.
+	BookingFunnelDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "booking_funnel_duration_seconds",
+		Help:    "Time from reservation to reserved, confirmed, cancelled or expired.",
+		Buckets: []float64{5, 15, 30, 60, 120, 300, 600, 900, 1800, 3600, 7200, 86400},
+	}, []string{"step"})
+)
+
+// Booking funnel steps
+const (
+	FunnelReserved  = "reserved"
+	FunnelConfirmed = "confirmed"
+	FunnelCancelled = "cancelled"
+	FunnelExpired   = "expired"
+)
+
+// ObserveFunnel records that a booking reserved at reservedAt reached step.
+// Bookings from before ReservedAt was recorded are skipped.
+func ObserveFunnel(step string, reservedAt time.Time) {
+	if reservedAt.IsZero() {
+		return
+	}
+	d := time.Duration(0)
+	if step != FunnelReserved {
+		d = time.Since(reservedAt)
+	}
+	BookingFunnelDuration.WithLabelValues(step).Observe(d.Seconds())
+}
+
+// Handler exposes the registered metrics for Prometheus scraping
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLoggedBody caps how much of a request body is logged
+const maxLoggedBody = 64 << 10
+
+// RequestBodyLogger logs request bodies at debug level for troubleshooting.
+// Fields of JSON bodies that redactor covers are redacted; other bodies are
+// only described by their size. The body is restored so handlers can still
+// read it. When enabled is false the middleware does nothing.
+func RequestBodyLogger(logger *slog.Logger, enabled bool, redactor *applog.LogRedactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body.Close()
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			logger.Debug("failed to read request body",
+				"request_id", c.GetHeader("X-Request-ID"),
+				"method", c.Request.Method,
+				"path", c.FullPath(),
+				"error", err)
+			c.Next()
+			return
+		}
+
+		if len(body) > 0 {
+			logger.Debug("request body",
+				"request_id", c.GetHeader("X-Request-ID"),
+				"method", c.Request.Method,
+				"path", c.FullPath(),
+				"body", maskBody(body, redactor))
+		}
+
+		c.Next()
+	}
+}
+
+// maskBody returns a loggable form of body with sensitive fields redacted
+func maskBody(body []byte, redactor *applog.LogRedactor) string {
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "<non-JSON body, " + strconv.Itoa(len(body)) + " bytes>"
+	}
+
+	// Wrapping lets the redactor walk top-level arrays too
+	wrapped := redactor.Redact(map[string]interface{}{"": payload})
+	masked, err := json.Marshal(wrapped[""])
+	if err != nil {
+		return "<unloggable body>"
+	}
+	if len(masked) > maxLoggedBody {
+		return string(masked[:maxLoggedBody]) + "...(truncated)"
+	}
+	return string(masked)
+}
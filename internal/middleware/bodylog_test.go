@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+var redactKeys = []string{"email", "password", "payment_details", "token", "authorization"}
+
+func TestRequestBodyLoggerRedactsJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	body := `{"email": "foo@bar.com", "password": "secret"}`
+	var received string
+	router := gin.New()
+	router.Use(RequestBodyLogger(logger, true, applog.NewLogRedactor(redactKeys)))
+	router.POST("/auth/register", func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		received = string(data)
+		c.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if received != body {
+		t.Errorf("handler read %q, want the original body", received)
+	}
+	logged := logs.String()
+	for _, secret := range []string{"foo@bar.com", "secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("log contains %q: %s", secret, logged)
+		}
+	}
+	if n := strings.Count(logged, applog.Redacted); n != 2 {
+		t.Errorf("log has %d redacted values, want 2: %s", n, logged)
+	}
+}
+
+func TestRequestLoggerRedactsQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &logs
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	router := gin.New()
+	router.Use(RequestLogger(applog.NewLogRedactor(redactKeys)))
+	router.GET("/events", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/events?email=foo%40bar.com&page=2", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	logged := logs.String()
+	if strings.Contains(logged, "foo") {
+		t.Errorf("access log contains the email: %s", logged)
+	}
+	if !strings.Contains(logged, "/events?email=[REDACTED]&page=2") {
+		t.Errorf("access log does not show the redacted query: %s", logged)
+	}
+}
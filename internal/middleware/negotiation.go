@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+)
+
+// MIMEMsgPack is the media type clients use to ask for MessagePack
+const MIMEMsgPack = "application/msgpack"
+
+// msgpackHandle decodes maps with string keys and encodes strings with the
+// str format so that payloads mirror the JSON ones
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	return h
+}()
+
+// ContentNegotiationMiddleware lets clients exchange MessagePack instead of
+// JSON. Request bodies sent as application/msgpack are converted to JSON
+// before handlers bind them. When the client accepts application/msgpack,
+// JSON responses are re-encoded on the way out; everything else, including
+// streamed responses, passes through unchanged.
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if hasMediaType(c.GetHeader("Content-Type"), MIMEMsgPack) && c.Request.Body != nil {
+			body, err := msgpackToJSON(c.Request.Body)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid MessagePack body", nil))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Request.ContentLength = int64(len(body))
+			c.Request.Header.Set("Content-Type", gin.MIMEJSON)
+			c.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+
+		if !acceptsMsgPack(c.GetHeader("Accept")) {
+			c.Next()
+			return
+		}
+
+		w := &msgpackWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		w.finish()
+	}
+}
+
+// msgpackWriter holds back JSON response bodies so they can be re-encoded
+// once the handler is done
+type msgpackWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	buffering bool
+	decided   bool
+}
+
+func (w *msgpackWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.buffering = hasMediaType(w.Header().Get("Content-Type"), gin.MIMEJSON)
+}
+
+func (w *msgpackWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *msgpackWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush passes through for streamed responses; buffered JSON is only
+// written by finish
+func (w *msgpackWriter) Flush() {
+	w.decide()
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *msgpackWriter) Written() bool {
+	return w.ResponseWriter.Written() || w.buf.Len() > 0
+}
+
+func (w *msgpackWriter) Size() int {
+	if w.buffering {
+		return w.buf.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+// finish re-encodes the buffered JSON body, falling back to the original
+// JSON if it cannot be converted
+func (w *msgpackWriter) finish() {
+	if !w.buffering {
+		return
+	}
+
+	header := w.ResponseWriter.Header()
+	header.Del("Content-Length")
+
+	body, err := jsonToMsgpack(w.buf.Bytes())
+	if err != nil {
+		log.Printf("Failed to encode response as MessagePack, sending JSON: %v", err)
+		body = w.buf.Bytes()
+	} else {
+		header.Set("Content-Type", MIMEMsgPack)
+	}
+	header.Add("Vary", "Accept")
+
+	if _, err := w.ResponseWriter.Write(body); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+func msgpackToJSON(r io.Reader) ([]byte, error) {
+	var v interface{}
+	if err := codec.NewDecoder(r, msgpackHandle).Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, msgpackHandle).Encode(normalizeNumbers(v)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// normalizeNumbers keeps integers as integers; decoding JSON into an
+// interface{} would otherwise turn every ID into a float
+func normalizeNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalizeNumbers(e)
+		}
+	}
+	return v
+}
+
+// acceptsMsgPack reports whether MessagePack is listed in the Accept header
+// without being refused by q=0
+func acceptsMsgPack(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != MIMEMsgPack {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(q, 64); err == nil && f == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func hasMediaType(header, want string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	return err == nil && mediaType == want
+}
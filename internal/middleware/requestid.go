@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDMiddleware makes sure every request has an ID: the one in the
+// X-Request-ID header, or a new one. The ID is echoed in the response and
+// stored in the request context, where database logging picks it up. The
+// gateway forwards the header, so one ID follows a request across services.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" {
+			id = uuid.NewString()
+			c.Request.Header.Set(requestid.Header, id)
+		}
+		c.Header(requestid.Header, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	applog "github.com/JonasLeetTheWay/ticketmaster-go/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger logs one line per request in gin's default format, with the
+// values of query parameters redactor covers redacted, so tokens and emails
+// sent in URLs stay out of the access log
+func RequestLogger(redactor *applog.LogRedactor) gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			path := param.Request.URL.Path
+			if query := redactor.RedactQuery(param.Request.URL.RawQuery); query != "" {
+				path += "?" + query
+			}
+			if param.Latency > time.Minute {
+				param.Latency = param.Latency.Truncate(time.Second)
+			}
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				param.StatusCode,
+				param.Latency,
+				param.ClientIP,
+				param.Method,
+				path,
+				param.ErrorMessage,
+			)
+		},
+	})
+}
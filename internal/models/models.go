@@ -1,8 +1,11 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
 	"gorm.io/gorm"
 )
 
@@ -10,7 +13,8 @@ type Venue struct {
 	gorm.Model
 	Location string `gorm:"not null"` // required
 	SeatMap  string
-	Capacity int `gorm:"not null"`
+	Capacity int    `gorm:"not null"`
+	Country  string `gorm:"size:2"` // ISO 3166-1 alpha-2; picks the tax rate on receipts
 }
 
 type Performer struct {
@@ -27,6 +31,35 @@ type Event struct {
 	Name        string
 	Description string
 	Date        time.Time `gorm:"not null"`
+	SoldOut     bool      `gorm:"default:false"` // No tickets left to reserve; not the same as cancelled
+
+	// Capacity is how many attendees the event admits, at most the venue's
+	// capacity; 0 on events created before it existed means the venue's
+	Capacity int `gorm:"not null;default:0"`
+
+	// Currency prices the event's tickets, as a lowercase code from
+	// validation.SupportedCurrencies
+	Currency string `gorm:"size:3;not null;default:'twd'"`
+
+	// Dynamic pricing multiplies ticket prices by PriceSurgeMultiplier once
+	// the share of seats left (per tier, or per event for tickets without
+	// one) drops below PriceSurgeThreshold, e.g. 0.2 for the last 20%
+	DynamicPricingEnabled bool    `gorm:"default:false"`
+	PriceSurgeThreshold   float64 `gorm:"not null;default:0.2"`
+	PriceSurgeMultiplier  float64 `gorm:"not null;default:1.5"`
+
+	// RequiresQueue gates reservations behind the waiting queue; only
+	// admitted users may reserve
+	RequiresQueue bool `gorm:"default:false"`
+
+	// ReservationWindowMinutes overrides how long a reservation holds a
+	// ticket; nil uses the service default
+	ReservationWindowMinutes *int
+
+	// Featured events are pinned to the homepage until FeaturedUntil, or
+	// indefinitely when it is nil
+	Featured      bool `gorm:"default:false"`
+	FeaturedUntil *time.Time
 
 	// Relationships
 	Venue     Venue     `gorm:"foreignKey:VenueID"`
@@ -34,55 +67,464 @@ type Event struct {
 	Tickets   []Ticket  `gorm:"foreignKey:EventID"`
 }
 
+// IsFeatured reports whether the event is featured at the given time
+func (e *Event) IsFeatured(now time.Time) bool {
+	return e.Featured && (e.FeaturedUntil == nil || e.FeaturedUntil.After(now))
+}
+
+// TicketTier groups an event's tickets by price level (VIP, Standard, ...)
+type TicketTier struct {
+	gorm.Model
+	EventID        uint         `gorm:"not null;index"`
+	Name           string       `gorm:"not null"`
+	Price          money.Amount `gorm:"type:bigint;not null"`
+	TotalCount     int          `gorm:"not null"`
+	AvailableCount int          `gorm:"not null"`
+}
+
 type Ticket struct {
 	gorm.Model
-	EventID uint    `gorm:"not null"`
-	Seat    string  `gorm:"not null"`
-	Price   float64 `gorm:"not null"`
-	Status  string  `gorm:"not null;default:'available'"`
+	EventID uint         `gorm:"not null;index:idx_tickets_event_status,priority:1;uniqueIndex:idx_tickets_event_seat,where:deleted_at IS NULL"` // Availability lookups and counts by event and status; seats are unique per event
+	TierID  *uint        `gorm:"index"`
+	Seat    string       `gorm:"not null;uniqueIndex:idx_tickets_event_seat,where:deleted_at IS NULL"`
+	Price   money.Amount `gorm:"type:bigint;not null"`
+	Status  TicketStatus `gorm:"not null;default:'available';index:idx_tickets_event_status,priority:2"`
 	UserID  *uint
+
+	// Relationships
+	Event *Event `gorm:"foreignKey:EventID" json:",omitempty"`
 }
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// User is an account. Emails are unique among live accounts only, so a
+// deleted account's email can register again.
 type User struct {
 	gorm.Model
-	Email    string `gorm:"not null;unique"`
+	Email    string `gorm:"not null;uniqueIndex:idx_users_email_live,where:deleted_at IS NULL"`
 	Password string `json:"-" gorm:"not null"`
 	Name     string `gorm:"not null"`
+	Role     string `gorm:"not null;default:'user'"`
+
+	// TOTPSecret is the encrypted TOTP secret; TOTPEnabled is set once the
+	// user has confirmed enrollment with a valid code
+	TOTPSecret  string `json:"-"`
+	TOTPEnabled bool   `gorm:"not null;default:false"`
+
+	// BannedAt is set while an admin has suspended the account
+	BannedAt *time.Time
+
+	// PerformerID links the account to the performer it manages, granting
+	// access to that performer's stats
+	PerformerID *uint `gorm:"index"`
+}
+
+// UserRefreshToken records an issued refresh token so it can be revoked
+// before it expires. Only the token's hash is stored.
+type UserRefreshToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`
+	SessionID string    `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
 }
 
 type Booking struct {
 	gorm.Model
-	TicketID   uint `gorm:"not null"`
-	UserID     uint `gorm:"not null"`
-	Status     string
+	TicketID   uint          `gorm:"not null;index:idx_bookings_ticket_status,priority:1"` // A ticket's active booking: confirm, cancel, lock recovery
+	UserID     uint          `gorm:"not null;index:idx_bookings_user_status,priority:1"`   // A user's bookings, recommendations, admin filters
+	Status     BookingStatus `gorm:"index:idx_bookings_user_status,priority:2;index:idx_bookings_ticket_status,priority:2"`
 	ReservedAt time.Time
 	ExpiresAt  time.Time
-	PaymentID  uint `gorm:"not null"`
+	PaymentID  string
+
+	// Price is the ticket's price when it was reserved, surges included;
+	// zero for reservations made before prices were locked in
+	Price money.Amount `gorm:"type:bigint;not null;default:0"`
+
+	// AmountPaid is what the customer was charged, fixed at confirmation
+	AmountPaid money.Amount `gorm:"type:bigint;not null;default:0"`
+
+	// Receipt itemizes the charge; it is computed when the booking is
+	// confirmed and not recomputed later
+	Receipt Receipt `gorm:"embedded;embeddedPrefix:receipt_"`
+
+	// ExtensionCount counts how often the reservation was extended, up to
+	// MaxExtensions
+	ExtensionCount int `gorm:"not null;default:0"`
+	MaxExtensions  int `gorm:"not null;default:2"`
+
+	// LockToken proves ownership of the ticket's Redis lock; empty for
+	// reservations made without one
+	LockToken string `json:"-"`
+
+	// Relationships
+	Ticket   Ticket    `gorm:"foreignKey:TicketID"`
+	Payments []Payment `gorm:"foreignKey:BookingID" json:",omitempty"`
+}
+
+// Receipt is the itemized price of a booking. Each line is rounded on its
+// own; Rounding carries the difference between the total, rounded once from
+// the exact amounts, and the sum of the lines. A zero Total means the
+// booking has no receipt yet.
+type Receipt struct {
+	Currency   string
+	FaceValue  money.Amount `gorm:"type:bigint;not null;default:0"`
+	ServiceFee money.Amount `gorm:"type:bigint;not null;default:0"`
+	TaxCountry string
+	TaxRate    float64      // Percent
+	Tax        money.Amount `gorm:"type:bigint;not null;default:0"`
+	Rounding   money.Amount `gorm:"type:bigint;not null;default:0"`
+	Total      money.Amount `gorm:"type:bigint;not null;default:0"`
 }
 
+// ElasticsearchEvent is the denormalized event document stored in the
+// events index. JSON tags must match the index mapping.
 type ElasticsearchEvent struct {
-	gorm.Model
-	VenueID          uint
-	PerformerID      uint
-	Name             string
-	Description      string
-	Date             string
-	Venue            string
-	Performer        string
-	Genre            string
-	Location         string
-	MinPrice         float64
-	MaxPrice         float64
-	AvailableTickets int
+	ID               uint    `json:"id"`
+	VenueID          uint    `json:"venueId"`
+	PerformerID      uint    `json:"performerId"`
+	Name             string  `json:"name"`
+	Description      string  `json:"description"`
+	Date             string  `json:"date"`
+	Venue            string  `json:"venue"`
+	Performer        string  `json:"performer"`
+	Genre            string  `json:"genre"`
+	Location         string  `json:"location"`
+	Currency         string  `json:"currency"` // Of MinPrice and MaxPrice
+	MinPrice         float64 `json:"minPrice"`
+	MaxPrice         float64 `json:"maxPrice"`
+	AvailableTickets int     `json:"availableTickets"`
+	Featured         bool    `json:"featured"`
+
+	// ESVersion is the event's updated_at in UNIX milliseconds. Indexing
+	// never replaces a document with a higher ESVersion.
+	ESVersion int64 `json:"esVersion"`
+}
+
+// ElasticsearchVenue is a venue's document in the venues index
+type ElasticsearchVenue struct {
+	ID       uint   `json:"id"`
+	Location string `json:"location"`
+	Capacity int    `json:"capacity"`
+}
+
+// ElasticsearchPerformer is a performer's document in the performers index
+type ElasticsearchPerformer struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Genre       string `json:"genre"`
+}
+
+// CDCCheckpoint stores the last change processed by the CDC worker for a
+// change stream. (LastUpdatedAt, LastID) is a strict watermark over the
+// stream's rows.
+type CDCCheckpoint struct {
+	Name          string `gorm:"primaryKey"`
+	LastUpdatedAt time.Time
+	LastID        uint
+	UpdatedAt     time.Time
+}
+
+// OutboxEvent is a change written in the same transaction as a mutation and
+// consumed in ID order by the CDC worker
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey"`
+	AggregateType string `gorm:"not null"`
+	AggregateID   uint   `gorm:"not null"`
+	Op            string `gorm:"not null"`
+	Payload       string `gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time
+	ProcessedAt   *time.Time `gorm:"index:idx_outbox_events_unprocessed,where:processed_at IS NULL"`
+	// Error is set on a row the worker could not apply and skipped
+	Error string
+}
+
+// CDCDeadLetter is a search index operation that kept failing after retries.
+// There is at most one per event and operation; operators replay them once
+// Elasticsearch recovers.
+type CDCDeadLetter struct {
+	ID          uint   `gorm:"primaryKey"`
+	EventID     uint   `gorm:"not null;uniqueIndex:idx_cdc_dead_letters_event_op"`
+	Operation   string `gorm:"not null;uniqueIndex:idx_cdc_dead_letters_event_op"`
+	Error       string
+	Attempts    int       `gorm:"not null"`
+	LastTriedAt time.Time `gorm:"not null"`
+	CreatedAt   time.Time
+}
+
+// WaitlistEntry registers a user's interest in an event before its tickets
+// go on sale. Position is the user's place in line for that event; NotifiedAt
+// is set once the entry has been released and the user told to buy.
+type WaitlistEntry struct {
+	ID         uint `gorm:"primaryKey"`
+	EventID    uint `gorm:"not null;uniqueIndex:idx_waitlist_event_user;uniqueIndex:idx_waitlist_event_position"`
+	UserID     uint `gorm:"not null;uniqueIndex:idx_waitlist_event_user"`
+	Position   int  `gorm:"not null;uniqueIndex:idx_waitlist_event_position"`
+	CreatedAt  time.Time
+	NotifiedAt *time.Time
+}
+
+// Payment statuses
+const (
+	PaymentPending   = "pending"
+	PaymentSucceeded = "succeeded"
+	PaymentFailed    = "failed"
+	PaymentRefunded  = "refunded"
+)
+
+// Payment is one attempt to charge for a booking. It is written as pending
+// before the provider is called, so a charge whose booking never got
+// confirmed still leaves a record to reconcile. IntentID is the provider's
+// payment intent ID, empty until the provider answers.
+type Payment struct {
+	ID        uint         `gorm:"primaryKey"`
+	IntentID  string       `gorm:"index"`
+	BookingID uint         `gorm:"not null;index"`
+	UserID    uint         `gorm:"not null;index"`
+	Amount    money.Amount `gorm:"type:bigint"`
+	Currency  string       `gorm:"not null"`
+	Status    string       `gorm:"not null;index"`
+	Provider  string       `gorm:"not null"`
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ProcessedWebhook marks a payment provider webhook event as handled so
+// that redeliveries are acknowledged without being applied twice
+type ProcessedWebhook struct {
+	EventID   string `gorm:"primaryKey"`
+	Provider  string `gorm:"not null"`
+	Type      string `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// Refund statuses. A refund is recorded as requested before the provider
+// is called; the provider's status replaces it, or failed when the call
+// fails.
+const (
+	RefundRequested = "requested"
+	RefundPending   = "pending" // Accepted by the provider, not settled yet
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+)
+
+// Refund records money returned for a cancelled booking or for a charge
+// whose booking could not be confirmed. There is at most one per charge;
+// PaymentID is the charge's payment intent and StripeRefundID the payment
+// provider's refund ID.
+type Refund struct {
+	ID             uint         `gorm:"primaryKey"`
+	BookingID      uint         `gorm:"not null;index"`
+	PaymentID      string       `gorm:"not null;uniqueIndex"`
+	Amount         money.Amount `gorm:"type:bigint"`
+	Currency       string       `gorm:"not null"`
+	Status         string       `gorm:"not null"`
+	StripeRefundID string
+	CreatedAt      time.Time
+}
+
+// BookingEvent is an append-only audit record of a booking state
+// transition, written by the booking consumer from the Kafka topic. The
+// topic position is unique so redelivered messages are stored once.
+type BookingEvent struct {
+	ID         uint   `gorm:"primaryKey"`
+	EventType  string `gorm:"not null"`
+	BookingID  uint   `gorm:"not null;index"`
+	UserID     uint   `gorm:"not null"`
+	TicketID   uint   `gorm:"not null"`
+	Payload    string `gorm:"type:jsonb"`
+	OccurredAt time.Time
+	Topic      string `gorm:"not null;uniqueIndex:idx_booking_events_position"`
+	Partition  int    `gorm:"not null;uniqueIndex:idx_booking_events_position"`
+	Offset     int64  `gorm:"not null;uniqueIndex:idx_booking_events_position"`
+	CreatedAt  time.Time
+}
+
+// MigrationHistory records a one-off data step, such as seeding or
+// creating search indices, so it runs once per database. Checksum is a
+// digest of what the step applied, to spot a step that changed without a
+// new version.
+type MigrationHistory struct {
+	Version   string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+	Checksum  string    `gorm:"not null"`
+}
+
+// All returns every persisted model, parents before the models that
+// reference them
+func All() []interface{} {
+	return []interface{}{
+		&Venue{},
+		&Performer{},
+		&Event{},
+		&TicketTier{},
+		&Ticket{},
+		&User{},
+		&Booking{},
+		&CDCCheckpoint{},
+		&OutboxEvent{},
+		&WaitlistEntry{},
+		&CDCDeadLetter{},
+		&BookingEvent{},
+		&Refund{},
+		&Payment{},
+		&ProcessedWebhook{},
+		&UserRefreshToken{},
+		&MigrationHistory{},
+	}
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-			&Venue{},
-			&Performer{},
-			&Event{},
-			&Ticket{},
-			&User{},
-			&Booking{},
-	)
+	if err := migrateMoneyColumns(db); err != nil {
+		return err
+	}
+	if err := dropReplacedConstraints(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(All()...); err != nil {
+		return err
+	}
+	if err := migrateCurrencyCodes(db); err != nil {
+		return err
+	}
+	if err := createIndexes(db); err != nil {
+		return err
+	}
+	return createStatusChecks(db)
+}
+
+// indexes are created on top of the ones declared in struct tags, for
+// columns that come from gorm.Model and cannot carry a tag. Each serves a
+// hot query; check that query before dropping one.
+var indexes = []struct{ name, table, columns string }{
+	// CDC change scans page through events and tickets in (updated_at, id)
+	// order from the last checkpoint
+	{"idx_events_updated_at_id", "events", "updated_at, id"},
+	{"idx_tickets_updated_at_id", "tickets", "updated_at, id"},
+}
+
+// uniqueIndexes are partial unique indexes whose condition lists values,
+// which a struct tag cannot hold
+var uniqueIndexes = []struct{ name, table, columns, where string }{
+	// A booking has at most one charge in flight or taken, so concurrent
+	// confirms and payment retries cannot both charge
+	{"idx_payments_booking_active", "payments", "booking_id", fmt.Sprintf("status IN ('%s', '%s')", PaymentPending, PaymentSucceeded)},
+}
+
+func createIndexes(db *gorm.DB) error {
+	for _, idx := range indexes {
+		if err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", idx.name, idx.table, idx.columns)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", idx.name, err)
+		}
+	}
+	for _, idx := range uniqueIndexes {
+		if err := db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (%s) WHERE %s", idx.name, idx.table, idx.columns, idx.where)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// statusChecks keep unknown statuses out of the status columns. A
+// constraint is only added when missing, so changing a status set needs a
+// new constraint name.
+var statusChecks = []struct{ table, name, check string }{
+	{"tickets", "chk_tickets_status", statusCheck("status", TicketStatuses)},
+	{"bookings", "chk_bookings_status", statusCheck("status", BookingStatuses)},
+}
+
+func createStatusChecks(db *gorm.DB) error {
+	for _, chk := range statusChecks {
+		if db.Migrator().HasConstraint(chk.table, chk.name) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)", chk.table, chk.name, chk.check)).Error; err != nil {
+			return fmt.Errorf("failed to add constraint %s: %w", chk.name, err)
+		}
+	}
+	return nil
+}
+
+// replacedConstraints were superseded by partial indexes that ignore
+// soft-deleted rows. Both names GORM has given the email constraint are
+// listed.
+var replacedConstraints = []struct{ table, name string }{
+	{"users", "uni_users_email"},
+	{"users", "users_email_key"},
+}
+
+// dropReplacedConstraints runs before AutoMigrate so it does not try to
+// reconcile the old constraints with the new tags
+func dropReplacedConstraints(db *gorm.DB) error {
+	for _, c := range replacedConstraints {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE IF EXISTS %s DROP CONSTRAINT IF EXISTS %s", c.table, c.name)).Error; err != nil {
+			return fmt.Errorf("failed to drop constraint %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// currencyColumns hold currency codes
+var currencyColumns = []struct{ table, column string }{
+	{"events", "currency"},
+	{"bookings", "receipt_currency"},
+	{"payments", "currency"},
+	{"refunds", "currency"},
+}
+
+// replacedCurrencyCodes maps codes the app used before adopting ISO 4217 to
+// the ISO code
+var replacedCurrencyCodes = map[string]string{
+	"ntd": "twd",
+}
+
+// migrateCurrencyCodes rewrites stored non-ISO currency codes, along with
+// the column default that produced them
+func migrateCurrencyCodes(db *gorm.DB) error {
+	for _, col := range currencyColumns {
+		for old, code := range replacedCurrencyCodes {
+			if err := db.Table(col.table).Where(col.column+" = ?", old).Update(col.column, code).Error; err != nil {
+				return fmt.Errorf("failed to migrate %s.%s from %s to %s: %w", col.table, col.column, old, code, err)
+			}
+		}
+	}
+	return nil
+}
+
+// moneyColumns held float amounts before they became integer minor units
+var moneyColumns = []struct{ table, column string }{
+	{"ticket_tiers", "price"},
+	{"tickets", "price"},
+	{"payments", "amount"},
+	{"refunds", "amount"},
+}
+
+// migrateMoneyColumns converts float amount columns to minor units.
+// AutoMigrate would change the type without scaling, so this runs first and
+// skips columns that are already integers.
+func migrateMoneyColumns(db *gorm.DB) error {
+	for _, col := range moneyColumns {
+		var dataType string
+		err := db.Raw("SELECT data_type FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?",
+			col.table, col.column).Scan(&dataType).Error
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s.%s: %w", col.table, col.column, err)
+		}
+		if dataType != "double precision" && dataType != "real" && dataType != "numeric" {
+			continue
+		}
+
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * 100)", col.table, col.column, col.column)).Error; err != nil {
+			return fmt.Errorf("failed to convert %s.%s to minor units: %w", col.table, col.column, err)
+		}
+	}
+	return nil
 }
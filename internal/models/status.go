@@ -0,0 +1,96 @@
+package models
+
+import "fmt"
+
+// TicketStatus is where a ticket is in its sale. It marshals as the plain
+// lowercase string.
+type TicketStatus string
+
+// Ticket statuses
+const (
+	TicketAvailable TicketStatus = "available"
+	TicketReserved  TicketStatus = "reserved"
+	TicketBooked    TicketStatus = "booked"
+)
+
+// ticketTransitions lists the statuses each ticket status may move to: a
+// reservation takes an available ticket, and expiry, cancellation or
+// confirmation end it; cancelling a booked ticket returns it to sale
+var ticketTransitions = map[TicketStatus][]TicketStatus{
+	TicketAvailable: {TicketReserved},
+	TicketReserved:  {TicketAvailable, TicketBooked},
+	TicketBooked:    {TicketAvailable},
+}
+
+// IsValid reports whether s is a known ticket status
+func (s TicketStatus) IsValid() bool {
+	_, ok := ticketTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a ticket may move from s to next
+func (s TicketStatus) CanTransitionTo(next TicketStatus) bool {
+	return contains(ticketTransitions[s], next)
+}
+
+// BookingStatus is where a booking is in its lifecycle. It marshals as the
+// plain lowercase string.
+type BookingStatus string
+
+// Booking statuses. Bookings are never deleted when they expire; rows
+// expired before the status was stored were soft-deleted instead and still
+// read as reserved.
+const (
+	BookingReserved  BookingStatus = "reserved"
+	BookingConfirmed BookingStatus = "confirmed"
+	BookingCancelled BookingStatus = "cancelled"
+	BookingExpired   BookingStatus = "expired"
+)
+
+// bookingTransitions lists the statuses each booking status may move to
+var bookingTransitions = map[BookingStatus][]BookingStatus{
+	BookingReserved:  {BookingConfirmed, BookingCancelled, BookingExpired},
+	BookingConfirmed: {BookingCancelled},
+	BookingCancelled: {},
+	BookingExpired:   {},
+}
+
+// IsValid reports whether s is a known booking status
+func (s BookingStatus) IsValid() bool {
+	_, ok := bookingTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a booking may move from s to next
+func (s BookingStatus) CanTransitionTo(next BookingStatus) bool {
+	return contains(bookingTransitions[s], next)
+}
+
+// TicketStatuses and BookingStatuses list every valid status, for
+// validation and CHECK constraints
+var (
+	TicketStatuses  = []TicketStatus{TicketAvailable, TicketReserved, TicketBooked}
+	BookingStatuses = []BookingStatus{BookingReserved, BookingConfirmed, BookingCancelled, BookingExpired}
+)
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// statusCheck is a CHECK constraint expression allowing only the given
+// statuses in column
+func statusCheck[T ~string](column string, statuses []T) string {
+	check := column + " IN ("
+	for i, status := range statuses {
+		if i > 0 {
+			check += ", "
+		}
+		check += fmt.Sprintf("'%s'", status)
+	}
+	return check + ")"
+}
@@ -0,0 +1,298 @@
+// Package money represents amounts as integer minor units so that sums,
+// refunds and comparisons are exact.
+//
+// Amount is the stored and computed type: hundredths of the currency unit
+// in every currency, with the currency kept beside it (on the event, the
+// payment or the refund). Money pairs an amount with its currency in that
+// currency's own minor units, and is what crosses to the payment provider,
+// so zero-decimal currencies such as JPY never carry cents out of the
+// system.
+//
+// Rounding rules:
+//   - Parsing rejects more than two decimal places rather than guessing.
+//   - Converting a float (legacy data, the search index) rounds half away
+//     from zero, as does MulRatio.
+//   - Converting an Amount to Money in a currency with fewer than two
+//     decimals rounds half away from zero to that currency's minor unit.
+//   - Sums are exact.
+//
+// Both types marshal to JSON as an object holding the exact decimal string
+// and the number older clients read, e.g. {"amount":"15.50","value":15.50};
+// Money adds its currency. Unmarshaling also accepts a bare number or
+// decimal string.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// scale is the number of minor units in one major unit
+const scale = 100
+
+// ErrInvalidAmount is returned for malformed or over-precise amounts
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Amount is a sum of money in hundredths of the currency unit. It is stored
+// as a bigint column.
+type Amount int64
+
+// FromMinor returns the amount of minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat converts a float, rounding half away from zero
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * scale))
+}
+
+// Parse reads a decimal such as "15", "15.5" or "-0.05"
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || len(frac) > 2 {
+		return 0, ErrInvalidAmount
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	units, err := strconv.ParseInt("0"+whole, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	cents, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || strings.ContainsAny(frac, "+-") {
+		return 0, ErrInvalidAmount
+	}
+	if units > (math.MaxInt64-cents)/scale {
+		return 0, ErrInvalidAmount
+	}
+
+	amount := Amount(units*scale + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// Sum adds amounts exactly
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 converts to a float for systems that need one, such as the
+// search index. Never compute with the result.
+func (a Amount) Float64() float64 {
+	return float64(a) / scale
+}
+
+// MulRatio scales the amount by num/den, rounding half away from zero;
+// e.g. a 15% discount is a.MulRatio(15, 100)
+func (a Amount) MulRatio(num, den int64) Amount {
+	product := int64(a) * num
+	quotient, remainder := product/den, product%den
+	if remainder != 0 && 2*abs(remainder) >= abs(den) {
+		if (product < 0) != (den < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return Amount(quotient)
+}
+
+// String formats the amount with two decimals, e.g. "15.50"
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/scale, minor%scale)
+}
+
+// MarshalJSON writes the amount as its decimal string and number, e.g.
+// {"amount":"15.50","value":15.50}
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return marshalDecimal(a.String(), "")
+}
+
+// UnmarshalJSON accepts the object MarshalJSON writes, a JSON number or a
+// decimal string
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var decimal jsonDecimal
+	if err := decimal.decode(data); err != nil {
+		return err
+	}
+	if decimal.null {
+		return nil
+	}
+	return a.parseJSON(decimal.value)
+}
+
+// parseJSON reads a decimal from JSON, which may be in float notation
+func (a *Amount) parseJSON(s string) error {
+	if strings.ContainsAny(s, "eE") {
+		// Exponents only come from float encoders; round like FromFloat
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		*a = FromFloat(f)
+		return nil
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, s)
+	}
+	*a = parsed
+	return nil
+}
+
+// Money is an amount in a currency's own minor units: cents for "usd",
+// whole yen for "jpy". Currency is the lower-case ISO 4217 code.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true,
+	"kmf": true, "krw": true, "mga": true, "pyg": true, "rwf": true,
+	"ugx": true, "vnd": true, "vuv": true, "xaf": true, "xof": true,
+	"xpf": true,
+}
+
+// Decimals returns the number of decimal places of a currency's minor unit
+func Decimals(currency string) int {
+	if zeroDecimalCurrencies[strings.ToLower(currency)] {
+		return 0
+	}
+	return 2
+}
+
+// New converts an amount to Money in currency, rounding half away from
+// zero when the currency has no cents
+func New(amount Amount, currency string) Money {
+	currency = strings.ToLower(currency)
+	if Decimals(currency) == 0 {
+		return Money{Minor: amount.MulRatio(1, scale).Minor(), Currency: currency}
+	}
+	return Money{Minor: amount.Minor(), Currency: currency}
+}
+
+// Amount converts back to hundredths of the currency unit
+func (m Money) Amount() Amount {
+	if Decimals(m.Currency) == 0 {
+		return Amount(m.Minor * scale)
+	}
+	return Amount(m.Minor)
+}
+
+// String formats the amount with the currency's decimals, e.g. "15.50"
+// or "1500" for jpy
+func (m Money) String() string {
+	if Decimals(m.Currency) == 0 {
+		return strconv.FormatInt(m.Minor, 10)
+	}
+	return m.Amount().String()
+}
+
+// MarshalJSON writes the amount as its decimal string and number with its
+// currency, e.g. {"amount":"1500","value":1500,"currency":"jpy"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return marshalDecimal(m.String(), m.Currency)
+}
+
+// UnmarshalJSON accepts the object MarshalJSON writes; a bare number or
+// decimal string leaves the currency empty
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var decimal jsonDecimal
+	if err := decimal.decode(data); err != nil {
+		return err
+	}
+	if decimal.null {
+		return nil
+	}
+
+	var amount Amount
+	if err := amount.parseJSON(decimal.value); err != nil {
+		return err
+	}
+	*m = New(amount, decimal.currency)
+	return nil
+}
+
+func marshalDecimal(decimal, currency string) ([]byte, error) {
+	if currency == "" {
+		return []byte(`{"amount":"` + decimal + `","value":` + decimal + `}`), nil
+	}
+	return []byte(`{"amount":"` + decimal + `","value":` + decimal + `,"currency":` + strconv.Quote(currency) + `}`), nil
+}
+
+// jsonDecimal is an amount read from any of the JSON shapes accepted
+type jsonDecimal struct {
+	value    string
+	currency string
+	null     bool
+}
+
+func (d *jsonDecimal) decode(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	switch {
+	case s == "null":
+		d.null = true
+	case strings.HasPrefix(s, "{"):
+		var object struct {
+			Amount   *string      `json:"amount"`
+			Value    *json.Number `json:"value"`
+			Currency string       `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+		}
+		switch {
+		case object.Amount != nil:
+			d.value = *object.Amount
+		case object.Value != nil:
+			d.value = object.Value.String()
+		default:
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, s)
+		}
+		d.currency = object.Currency
+	default:
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+		d.value = s
+	}
+	return nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
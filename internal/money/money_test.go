@@ -0,0 +1,81 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountJSON(t *testing.T) {
+	data, err := json.Marshal(FromFloat(15.5))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"amount":"15.50","value":15.50}` {
+		t.Errorf("marshaled to %s", data)
+	}
+
+	tests := []struct {
+		json string
+		want Amount
+	}{
+		{`{"amount":"15.50","value":15.50}`, 1550},
+		{`{"value":15.5}`, 1550},
+		{`15.5`, 1550},
+		{`"15.5"`, 1550},
+		{`1.5e1`, 1500},
+		{`"-0.05"`, -5},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+			t.Errorf("Unmarshal(%s) failed: %v", tt.json, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.json, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`{}`, `"15.505"`, `"abc"`} {
+		var got Amount
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Unmarshal(%s) = %s, want an error", bad, got)
+		}
+	}
+}
+
+func TestMoneyMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		currency string
+		want     Money
+		json     string
+	}{
+		{FromFloat(1500.50), "TWD", Money{Minor: 150050, Currency: "twd"}, `{"amount":"1500.50","value":1500.50,"currency":"twd"}`},
+		{FromFloat(1500), "jpy", Money{Minor: 1500, Currency: "jpy"}, `{"amount":"1500","value":1500,"currency":"jpy"}`},
+		{FromFloat(1500.50), "JPY", Money{Minor: 1501, Currency: "jpy"}, `{"amount":"1501","value":1501,"currency":"jpy"}`},
+	}
+	for _, tt := range tests {
+		got := New(tt.amount, tt.currency)
+		if got != tt.want {
+			t.Errorf("New(%s, %s) = %+v, want %+v", tt.amount, tt.currency, got, tt.want)
+		}
+
+		data, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if string(data) != tt.json {
+			t.Errorf("%+v marshaled to %s, want %s", got, data, tt.json)
+		}
+
+		var back Money
+		if err := json.Unmarshal(data, &back); err != nil || back != got {
+			t.Errorf("%s unmarshaled to %+v (%v), want %+v", data, back, err, got)
+		}
+	}
+
+	if got := New(FromFloat(1500), "jpy").Amount(); got != FromFloat(1500) {
+		t.Errorf("1500 yen converted back to %s", got)
+	}
+}
@@ -0,0 +1,25 @@
+// Package notify delivers user-facing notifications
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// Notifier sends a message to a user
+type Notifier interface {
+	Notify(ctx context.Context, userID uint, subject, message string) error
+}
+
+// LogNotifier writes notifications to the service log instead of delivering them
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification
+func (n *LogNotifier) Notify(ctx context.Context, userID uint, subject, message string) error {
+	log.Printf("Notification to user %d: %s - %s", userID, subject, message)
+	return nil
+}
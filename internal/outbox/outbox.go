@@ -0,0 +1,120 @@
+// Package outbox records domain changes in the outbox_events table inside
+// the same transaction as the change itself, so the CDC worker sees every
+// committed mutation (including deletes) exactly in commit order.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// Aggregate types
+const (
+	AggregateEvent   = "event"
+	AggregateTicket  = "ticket"
+	AggregateBooking = "booking"
+)
+
+// Operations
+const (
+	OpUpsert = "upsert"
+	OpDelete = "delete"
+)
+
+// Payload identifies the event whose search document is affected by a change
+type Payload struct {
+	EventID  uint   `json:"eventId"`
+	TicketID uint   `json:"ticketId,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// Record appends an outbox row using tx, which must be the transaction that
+// performs the mutation
+func Record(tx *gorm.DB, aggregateType string, aggregateID uint, op string, payload Payload) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox payload: %w", err)
+	}
+
+	entry := models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Op:            op,
+		Payload:       string(payloadJSON),
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+// RecordEvent records a change to an event row
+func RecordEvent(tx *gorm.DB, eventID uint, op string) error {
+	return Record(tx, AggregateEvent, eventID, op, Payload{EventID: eventID})
+}
+
+// RecordTicket records a change to a ticket row
+func RecordTicket(tx *gorm.DB, ticket *models.Ticket, status string) error {
+	return Record(tx, AggregateTicket, ticket.ID, OpUpsert, Payload{
+		EventID:  ticket.EventID,
+		TicketID: ticket.ID,
+		Status:   status,
+	})
+}
+
+// RecordBooking records a booking state change
+func RecordBooking(tx *gorm.DB, booking *models.Booking, eventID uint) error {
+	return Record(tx, AggregateBooking, booking.ID, OpUpsert, Payload{
+		EventID:  eventID,
+		TicketID: booking.TicketID,
+		Status:   string(booking.Status),
+	})
+}
+
+// DecodePayload parses an outbox row's payload
+func DecodePayload(entry *models.OutboxEvent) (Payload, error) {
+	var payload Payload
+	if err := json.Unmarshal([]byte(entry.Payload), &payload); err != nil {
+		return payload, fmt.Errorf("invalid payload for outbox event %d: %w", entry.ID, err)
+	}
+	return payload, nil
+}
+
+// backfillBatchSize is the number of events seeded per backfill batch
+const backfillBatchSize = 500
+
+// Backfill seeds an upsert outbox row for every existing event, so a CDC
+// worker switching to the outbox re-indexes the whole catalogue once. It
+// returns the number of rows written.
+func Backfill(db *gorm.DB) (int, error) {
+	total := 0
+	var lastID uint
+	for {
+		var eventIDs []uint
+		if err := db.Model(&models.Event{}).Where("id > ?", lastID).
+			Order("id").Limit(backfillBatchSize).Pluck("id", &eventIDs).Error; err != nil {
+			return total, fmt.Errorf("failed to fetch events: %w", err)
+		}
+		if len(eventIDs) == 0 {
+			return total, nil
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, eventID := range eventIDs {
+				if err := RecordEvent(tx, eventID, OpUpsert); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += len(eventIDs)
+		lastID = eventIDs[len(eventIDs)-1]
+	}
+}
@@ -0,0 +1,39 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+)
+
+// Client is a payment processor. A declined charge is reported as a
+// response with Success false; an error means the processor could not be
+// reached or rejected the request itself.
+type Client interface {
+	// Provider names the processor on recorded payments
+	Provider() string
+
+	CreatePaymentIntent(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error)
+	ConfirmPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentResponse, error)
+	// RefundPayment refunds amount of a charge, in the charge's currency.
+	// Calls with the same idempotencyKey make one refund.
+	RefundPayment(ctx context.Context, paymentIntentID string, amount money.Money, idempotencyKey string) (*PaymentResponse, error)
+	GetPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentIntent, error)
+}
+
+// ErrMissingSecretKey is returned when real Stripe is selected without a key
+var ErrMissingSecretKey = errors.New("STRIPE_SECRET_KEY is required when MOCK_STRIPE_ENABLED is false")
+
+// NewClient returns the mock client when MockStripeEnabled is set and the
+// real Stripe client otherwise. It refuses to run real mode without a key.
+func NewClient(cfg *config.Config) (Client, error) {
+	if cfg.MockStripeEnabled {
+		return NewMockStripeClient(cfg), nil
+	}
+	if cfg.StripeSecretKey == "" {
+		return nil, ErrMissingSecretKey
+	}
+	return NewStripeClient(cfg), nil
+}
@@ -3,28 +3,76 @@ package payment
 import (
 	"context"
 	"fmt"
+	"log"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
 )
 
+// ProviderMockStripe names the mock client on recorded payments
+const ProviderMockStripe = "mock_stripe"
+
+// MockLatencyRandom keeps the mock's realistic random delays; it is the
+// default for MOCK_STRIPE_LATENCY
+const MockLatencyRandom = "random"
+
+// MockOutcome forces how the mock answers a charge. The zero value leaves it
+// to the magic amounts and then the configured success rate.
+type MockOutcome string
+
+const (
+	MockOutcomeDefault        MockOutcome = ""
+	MockOutcomeSucceed        MockOutcome = "succeed"
+	MockOutcomeFail           MockOutcome = "fail"
+	MockOutcomeRequiresAction MockOutcome = "requires_action" // Awaits 3D Secure; settles by webhook
+)
+
+// MockOutcomeHeader lets API clients pick a MockOutcome per request while
+// the mock is enabled, e.g. "X-Mock-Payment: fail"
+const MockOutcomeHeader = "X-Mock-Payment"
+
+// Magic amounts: a charge whose cents are one of these gets the matching
+// outcome, e.g. 99.13 always fails
+const (
+	MockFailCents           = 13
+	MockRequiresActionCents = 14
+)
+
+// MockStripeClient simulates Stripe. By default it waits a random 100-600ms
+// and fails at the configured rate; MOCK_STRIPE_LATENCY and MOCK_STRIPE_SEED
+// make it fast and repeatable for tests.
 type MockStripeClient struct {
-	config *config.Config
+	config       *config.Config
+	fixedLatency *time.Duration // nil for random latency
+
+	mu         sync.Mutex // rand.Rand is not safe for concurrent use
+	rng        *rand.Rand
+	currencies map[string]string // Currency of each intent created, for later calls
 }
 
 type PaymentIntent struct {
-	ID       string
-	Amount   float64
-	Currency string
-	Status   string
+	ID     string
+	Amount money.Money
+	Status string
 }
 
 type PaymentRequest struct {
-	Amount   float64
-	Currency string
-	UserID   uint
-	TicketID uint
+	Amount    money.Money
+	UserID    uint
+	TicketID  uint
+	BookingID uint
+
+	// PaymentMethod identifies the customer's card with the processor
+	PaymentMethod string
+
+	// IdempotencyKey makes retrying the same charge safe; empty disables it
+	IdempotencyKey string
+
+	// MockOutcome forces the mock's answer; the real client ignores it
+	MockOutcome MockOutcome
 }
 
 type PaymentResponse struct {
@@ -33,74 +81,183 @@ type PaymentResponse struct {
 	Error         string `json:"omitempty"`
 }
 
+// Pending reports whether the charge is still in progress, e.g. waiting for
+// 3D Secure; its outcome arrives later through a webhook
+func (r *PaymentResponse) Pending() bool {
+	if r.PaymentIntent == nil {
+		return false
+	}
+	switch r.PaymentIntent.Status {
+	case "processing", "requires_action", "requires_capture":
+		return true
+	}
+	return false
+}
+
 func NewMockStripeClient(cfg *config.Config) *MockStripeClient {
-	return &MockStripeClient{config: cfg}
+	seed := cfg.MockStripeSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	client := &MockStripeClient{
+		config:     cfg,
+		rng:        rand.New(rand.NewSource(seed)),
+		currencies: make(map[string]string),
+	}
+	if cfg.MockStripeLatency != "" && cfg.MockStripeLatency != MockLatencyRandom {
+		latency, err := time.ParseDuration(cfg.MockStripeLatency)
+		if err != nil || latency < 0 {
+			log.Printf("Invalid MOCK_STRIPE_LATENCY %q, using random latency", cfg.MockStripeLatency)
+		} else {
+			client.fixedLatency = &latency
+		}
+	}
+	return client
+}
+
+func (c *MockStripeClient) Provider() string {
+	return ProviderMockStripe
+}
+
+// outcome decides how a charge ends: an explicit override, then a magic
+// amount, then the configured success rate. Unknown overrides are ignored.
+func (c *MockStripeClient) outcome(req *PaymentRequest) MockOutcome {
+	switch req.MockOutcome {
+	case MockOutcomeSucceed, MockOutcomeFail, MockOutcomeRequiresAction:
+		return req.MockOutcome
+	}
+	switch req.Amount.Amount().Minor() % 100 {
+	case MockFailCents:
+		return MockOutcomeFail
+	case MockRequiresActionCents:
+		return MockOutcomeRequiresAction
+	}
+	if c.float64() < c.config.MockStripeSuccessRate {
+		return MockOutcomeSucceed
+	}
+	return MockOutcomeFail
+}
+
+// delay simulates network latency: the fixed latency if configured, else a
+// random one in [min, min+spread)
+func (c *MockStripeClient) delay(ctx context.Context, min, spread int) error {
+	latency := time.Duration(c.intn(spread)+min) * time.Millisecond
+	if c.fixedLatency != nil {
+		latency = *c.fixedLatency
+	}
+	if latency == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *MockStripeClient) float64() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64()
+}
+
+func (c *MockStripeClient) intn(n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Intn(n)
+}
+
+func (c *MockStripeClient) rememberCurrency(paymentIntentID, currency string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.currencies[paymentIntentID] = currency
+}
+
+// currencyOf returns the currency an intent was created in, or the default
+// currency for intents this process did not create
+func (c *MockStripeClient) currencyOf(paymentIntentID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if currency, ok := c.currencies[paymentIntentID]; ok {
+		return currency
+	}
+	return c.config.DefaultCurrency
 }
 
 func (c *MockStripeClient) CreatePaymentIntent(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error) {
 	// Simulate network delay
-	time.Sleep(time.Duration(rand.Intn(500)+100) * time.Millisecond)
+	if err := c.delay(ctx, 100, 500); err != nil {
+		return nil, err
+	}
 
 	// Generate mock payment intent ID
-	paymentID := fmt.Sprintf("pi_mock_%d_%d", req.UserID, time.Now().Unix())
-
-	// Determine success based on configured success rate
-	success := rand.Float64() < c.config.MockStripeSuccessRate
+	paymentID := fmt.Sprintf("pi_mock_%d_%d", req.UserID, time.Now().UnixNano())
 
 	var status string
 	var errMsg string
 
-	if success {
+	switch c.outcome(req) {
+	case MockOutcomeSucceed:
 		status = "succeeded"
-	} else {
+	case MockOutcomeRequiresAction:
+		status = "requires_action"
+	default:
 		status = "failed"
 		errMsg = "Mock payment failure - insufficient funds"
 	}
 
 	paymentIntent := &PaymentIntent{
-		ID:       paymentID,
-		Amount:   req.Amount,
-		Currency: req.Currency,
-		Status:   status,
+		ID:     paymentID,
+		Amount: req.Amount,
+		Status: status,
 	}
+	c.rememberCurrency(paymentID, req.Amount.Currency)
 
 	return &PaymentResponse{
 		PaymentIntent: paymentIntent,
-		Success:       success,
+		Success:       status == "succeeded",
 		Error:         errMsg,
 	}, nil
 }
 
 func (c *MockStripeClient) ConfirmPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentResponse, error) {
 	// Simulate network delay
-	time.Sleep(time.Duration(rand.Intn(300)+50) * time.Millisecond)
+	if err := c.delay(ctx, 50, 300); err != nil {
+		return nil, err
+	}
 
 	// For mock purposes, assume confirmation always succeeds if the intent exists
 	// In a real implementation, you'd check the actual payment intent status
 
 	return &PaymentResponse{
 		PaymentIntent: &PaymentIntent{
-			ID:       paymentIntentID,
-			Status:   "succeeded",
-			Currency: "ntd",
+			ID:     paymentIntentID,
+			Status: "succeeded",
+			Amount: money.Money{Currency: c.currencyOf(paymentIntentID)},
 		},
 		Success: true,
 	}, nil
 }
 
-func (c *MockStripeClient) RefundPayment(ctx context.Context, paymentIntentID string, amount float64) (*PaymentResponse, error) {
+func (c *MockStripeClient) RefundPayment(ctx context.Context, paymentIntentID string, amount money.Money, idempotencyKey string) (*PaymentResponse, error) {
 	// Simulate network delay
-	time.Sleep(time.Duration(rand.Intn(400)+100) * time.Millisecond)
+	if err := c.delay(ctx, 100, 400); err != nil {
+		return nil, err
+	}
 
 	// Mock refund - always succeeds
 	refundID := fmt.Sprintf("re_mock_%s_%d", paymentIntentID, time.Now().Unix())
 
 	return &PaymentResponse{
 		PaymentIntent: &PaymentIntent{
-			ID:       refundID,
-			Amount:   amount,
-			Currency: "ntd",
-			Status:   "succeeded",
+			ID:     refundID,
+			Amount: amount,
+			Status: "succeeded",
 		},
 		Success: true,
 	}, nil
@@ -108,12 +265,16 @@ func (c *MockStripeClient) RefundPayment(ctx context.Context, paymentIntentID st
 
 func (c *MockStripeClient) GetPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentIntent, error) {
 	// Simulate network delay
-	time.Sleep(time.Duration(rand.Intn(200)+50) * time.Millisecond)
+	if err := c.delay(ctx, 50, 200); err != nil {
+		return nil, err
+	}
 
 	// Mock implementation - return a successful payment intent
 	return &PaymentIntent{
-		ID:       paymentIntentID,
-		Status:   "succeeded",
-		Currency: "ntd",
+		ID:     paymentIntentID,
+		Status: "succeeded",
+		Amount: money.Money{Currency: c.currencyOf(paymentIntentID)},
 	}, nil
 }
+
+var _ Client = (*MockStripeClient)(nil)
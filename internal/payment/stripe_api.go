@@ -0,0 +1,219 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"github.com/stripe/stripe-go/v82"
+)
+
+// ProviderStripe names the real Stripe client on recorded payments
+const ProviderStripe = "stripe"
+
+// stripeTimeout bounds one Stripe API call when the context has no deadline
+const stripeTimeout = 30 * time.Second
+
+// StripeClient talks to the Stripe API through the official stripe-go
+// library
+type StripeClient struct {
+	client *stripe.Client
+}
+
+// Error is an error object returned by Stripe, by the API or in a webhook.
+// StatusCode is zero when Stripe sent no error object.
+type Error struct {
+	StatusCode  int    `json:"-"`
+	Type        string `json:"type"`
+	Code        string `json:"code"`
+	DeclineCode string `json:"decline_code"`
+	Message     string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("stripe %s (%s): %s", e.Type, e.Code, e.Message)
+	}
+	return fmt.Sprintf("stripe %s: %s", e.Type, e.Message)
+}
+
+// AppCode maps the Stripe error type to the API's error codes
+func (e *Error) AppCode() string {
+	switch e.Type {
+	case "card_error":
+		return apperrors.ErrCodePaymentFailed
+	case "api_error", "rate_limit_error", "api_connection_error":
+		return apperrors.ErrCodeUpstreamUnavailable
+	default:
+		// invalid_request_error, idempotency_error and authentication
+		// failures are our fault, not the customer's
+		return apperrors.ErrCodePaymentError
+	}
+}
+
+func NewStripeClient(cfg *config.Config) *StripeClient {
+	backends := stripe.NewBackendsWithConfig(&stripe.BackendConfig{
+		HTTPClient: &http.Client{Timeout: stripeTimeout},
+		URL:        stripe.String(strings.TrimRight(cfg.StripeAPIURL, "/")),
+		// Payment jobs retry with their own idempotency keys
+		MaxNetworkRetries: stripe.Int64(0),
+		// Callers log the errors they act on
+		LeveledLogger: &stripe.LeveledLogger{Level: stripe.LevelNull},
+	})
+	return &StripeClient{client: stripe.NewClient(cfg.StripeSecretKey, stripe.WithBackends(backends))}
+}
+
+func (c *StripeClient) Provider() string {
+	return ProviderStripe
+}
+
+// CreatePaymentIntent creates and confirms a PaymentIntent in one call.
+// Declines come back as an unsuccessful response, not an error.
+func (c *StripeClient) CreatePaymentIntent(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error) {
+	params := &stripe.PaymentIntentCreateParams{
+		Amount:        stripe.Int64(req.Amount.Minor),
+		Currency:      stripe.String(req.Amount.Currency),
+		Confirm:       stripe.Bool(true),
+		PaymentMethod: stripe.String(req.PaymentMethod),
+		AutomaticPaymentMethods: &stripe.PaymentIntentCreateAutomaticPaymentMethodsParams{
+			Enabled:        stripe.Bool(true),
+			AllowRedirects: stripe.String(string(stripe.PaymentIntentAutomaticPaymentMethodsAllowRedirectsNever)),
+		},
+	}
+	params.AddMetadata("user_id", strconv.FormatUint(uint64(req.UserID), 10))
+	params.AddMetadata("ticket_id", strconv.FormatUint(uint64(req.TicketID), 10))
+	if req.BookingID != 0 {
+		params.AddMetadata("booking_id", strconv.FormatUint(uint64(req.BookingID), 10))
+	}
+	if req.IdempotencyKey != "" {
+		params.SetIdempotencyKey(req.IdempotencyKey)
+	}
+
+	intent, err := c.client.V1PaymentIntents.Create(ctx, params)
+	err = toError(ctx, err)
+	if declined, ok := asCardError(err); ok {
+		return &PaymentResponse{Success: false, Error: declined.Message}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return intentResponse(intent), nil
+}
+
+func (c *StripeClient) ConfirmPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentResponse, error) {
+	intent, err := c.client.V1PaymentIntents.Confirm(ctx, paymentIntentID, nil)
+	err = toError(ctx, err)
+	if declined, ok := asCardError(err); ok {
+		return &PaymentResponse{
+			PaymentIntent: &PaymentIntent{ID: paymentIntentID, Status: "failed"},
+			Success:       false,
+			Error:         declined.Message,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return intentResponse(intent), nil
+}
+
+// RefundPayment refunds amount of a charge, which must be in the charge's
+// currency. Only a refund Stripe reports succeeded is a success: a pending
+// one settles later, and the charge.refunded webhook records it.
+func (c *StripeClient) RefundPayment(ctx context.Context, paymentIntentID string, amount money.Money, idempotencyKey string) (*PaymentResponse, error) {
+	params := &stripe.RefundCreateParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+		Amount:        stripe.Int64(amount.Minor),
+	}
+	if idempotencyKey != "" {
+		params.SetIdempotencyKey(idempotencyKey)
+	}
+
+	refund, err := c.client.V1Refunds.Create(ctx, params)
+	if err := toError(ctx, err); err != nil {
+		return nil, err
+	}
+
+	return &PaymentResponse{
+		PaymentIntent: &PaymentIntent{
+			ID:     refund.ID,
+			Amount: money.Money{Minor: refund.Amount, Currency: string(refund.Currency)},
+			Status: string(refund.Status),
+		},
+		Success: refund.Status == stripe.RefundStatusSucceeded,
+	}, nil
+}
+
+func (c *StripeClient) GetPaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentIntent, error) {
+	intent, err := c.client.V1PaymentIntents.Retrieve(ctx, paymentIntentID, nil)
+	if err := toError(ctx, err); err != nil {
+		return nil, err
+	}
+	return intentResponse(intent).PaymentIntent, nil
+}
+
+// toError converts an error from stripe-go to an *Error, or to the
+// context's error once ctx is done
+func toError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		// The request never got an answer, or the answer was not JSON
+		return &Error{Type: "api_connection_error", Message: err.Error()}
+	}
+	converted := &Error{
+		StatusCode:  stripeErr.HTTPStatusCode,
+		Type:        string(stripeErr.Type),
+		Code:        string(stripeErr.Code),
+		DeclineCode: string(stripeErr.DeclineCode),
+		Message:     stripeErr.Msg,
+	}
+	if stripeErr.HTTPStatusCode == http.StatusTooManyRequests {
+		converted.Type = "rate_limit_error"
+	}
+	return converted
+}
+
+func asCardError(err error) (*Error, bool) {
+	stripeErr, ok := err.(*Error)
+	if !ok || stripeErr.Type != "card_error" {
+		return nil, false
+	}
+	return stripeErr, true
+}
+
+func intentResponse(intent *stripe.PaymentIntent) *PaymentResponse {
+	resp := &PaymentResponse{
+		PaymentIntent: &PaymentIntent{
+			ID:     intent.ID,
+			Amount: money.Money{Minor: intent.Amount, Currency: string(intent.Currency)},
+			Status: string(intent.Status),
+		},
+		Success: intent.Status == stripe.PaymentIntentStatusSucceeded,
+	}
+	if !resp.Success {
+		if intent.LastPaymentError != nil {
+			resp.Error = intent.LastPaymentError.Msg
+		} else {
+			resp.Error = "payment intent is " + string(intent.Status)
+		}
+	}
+	return resp
+}
+
+var _ Client = (*StripeClient)(nil)
@@ -0,0 +1,285 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+)
+
+// stripeRequest is a request the fake Stripe API received
+type stripeRequest struct {
+	method         string
+	path           string
+	form           url.Values
+	secretKey      string
+	idempotencyKey string
+}
+
+// fakeStripe records requests and answers each with handler
+type fakeStripe struct {
+	mu       sync.Mutex
+	requests []stripeRequest
+}
+
+func newFakeStripe(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*fakeStripe, *StripeClient) {
+	t.Helper()
+	fake := &fakeStripe{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, stripeRequest{
+			method:         r.Method,
+			path:           r.URL.Path,
+			form:           r.PostForm,
+			secretKey:      key,
+			idempotencyKey: r.Header.Get("Idempotency-Key"),
+		})
+		fake.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, NewStripeClient(&config.Config{StripeAPIURL: server.URL + "/", StripeSecretKey: "sk_test_123"})
+}
+
+func (f *fakeStripe) last() stripeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[len(f.requests)-1]
+}
+
+func respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func TestStripeCreatePaymentIntent(t *testing.T) {
+	fake, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, map[string]interface{}{
+			"id": "pi_123", "amount": 150050, "currency": "twd", "status": "succeeded",
+		})
+	})
+
+	resp, err := client.CreatePaymentIntent(context.Background(), &PaymentRequest{
+		Amount:         money.New(money.FromFloat(1500.50), "TWD"),
+		UserID:         7,
+		TicketID:       42,
+		BookingID:      9,
+		PaymentMethod:  "pm_card_visa",
+		IdempotencyKey: "booking-9-attempt-1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentIntent failed: %v", err)
+	}
+	if !resp.Success || resp.PaymentIntent.ID != "pi_123" || resp.PaymentIntent.Amount != money.New(money.FromFloat(1500.50), "twd") {
+		t.Errorf("got %+v (intent %+v), want a successful pi_123 for 1500.50", resp, resp.PaymentIntent)
+	}
+
+	req := fake.last()
+	if req.method != http.MethodPost || req.path != "/v1/payment_intents" {
+		t.Errorf("sent %s %s", req.method, req.path)
+	}
+	if req.secretKey != "sk_test_123" || req.idempotencyKey != "booking-9-attempt-1" {
+		t.Errorf("sent key %q and idempotency key %q", req.secretKey, req.idempotencyKey)
+	}
+	want := map[string]string{
+		"amount":               "150050",
+		"currency":             "twd",
+		"confirm":              "true",
+		"payment_method":       "pm_card_visa",
+		"metadata[user_id]":    "7",
+		"metadata[ticket_id]":  "42",
+		"metadata[booking_id]": "9",
+	}
+	for field, value := range want {
+		if got := req.form.Get(field); got != value {
+			t.Errorf("form field %s is %q, want %q", field, got, value)
+		}
+	}
+}
+
+func TestStripeZeroDecimalCurrency(t *testing.T) {
+	fake, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, map[string]interface{}{
+			"id": "pi_123", "amount": 1500, "currency": "jpy", "status": "succeeded",
+		})
+	})
+
+	resp, err := client.CreatePaymentIntent(context.Background(), &PaymentRequest{
+		Amount: money.New(money.FromFloat(1500), "jpy"), PaymentMethod: "pm_card_visa",
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentIntent failed: %v", err)
+	}
+	if got := fake.last().form.Get("amount"); got != "1500" {
+		t.Errorf("sent amount %s for 1500 yen, want 1500", got)
+	}
+	if resp.PaymentIntent.Amount.Amount() != money.FromFloat(1500) {
+		t.Errorf("response amount %s, want 1500", resp.PaymentIntent.Amount)
+	}
+}
+
+func TestStripeDeclineIsNotAnError(t *testing.T) {
+	_, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusPaymentRequired, map[string]interface{}{"error": map[string]interface{}{
+			"type": "card_error", "code": "card_declined", "decline_code": "insufficient_funds", "message": "Your card has insufficient funds.",
+		}})
+	})
+
+	resp, err := client.CreatePaymentIntent(context.Background(), &PaymentRequest{
+		Amount: money.New(money.FromFloat(100), "twd"), PaymentMethod: "pm_card_chargeDeclined",
+	})
+	if err != nil {
+		t.Fatalf("a decline returned an error: %v", err)
+	}
+	if resp.Success || resp.Error != "Your card has insufficient funds." {
+		t.Errorf("got %+v, want an unsuccessful response with Stripe's message", resp)
+	}
+}
+
+func TestStripeErrorMapping(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantType   string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "invalid request",
+			status:     http.StatusBadRequest,
+			body:       `{"error":{"type":"invalid_request_error","message":"No such payment_intent"}}`,
+			wantType:   "invalid_request_error",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   apperrors.ErrCodePaymentError,
+		},
+		{
+			name:       "rate limited",
+			status:     http.StatusTooManyRequests,
+			body:       `{"error":{"type":"invalid_request_error","message":"Too many requests"}}`,
+			wantType:   "rate_limit_error",
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   apperrors.ErrCodeUpstreamUnavailable,
+		},
+		{
+			name:       "outage without an error body",
+			status:     http.StatusBadGateway,
+			body:       `<html>bad gateway</html>`,
+			wantType:   "api_connection_error",
+			wantStatus: 0,
+			wantCode:   apperrors.ErrCodeUpstreamUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.GetPaymentIntent(context.Background(), "pi_123")
+			var stripeErr *Error
+			if !errors.As(err, &stripeErr) {
+				t.Fatalf("got %v, want a Stripe error", err)
+			}
+			if stripeErr.Type != tt.wantType || stripeErr.StatusCode != tt.wantStatus {
+				t.Errorf("got type %q and status %d, want %q and %d", stripeErr.Type, stripeErr.StatusCode, tt.wantType, tt.wantStatus)
+			}
+			if code := stripeErr.AppCode(); code != tt.wantCode {
+				t.Errorf("mapped to %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestStripeRefundUsesChargeCurrency(t *testing.T) {
+	fake, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/refunds":
+			respond(w, http.StatusOK, map[string]interface{}{"id": "re_1", "amount": 1000, "currency": "jpy", "status": "succeeded"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	resp, err := client.RefundPayment(context.Background(), "pi_123", money.New(money.FromFloat(1000), "jpy"), "refund-9")
+	if err != nil {
+		t.Fatalf("RefundPayment failed: %v", err)
+	}
+	if !resp.Success || resp.PaymentIntent.ID != "re_1" || resp.PaymentIntent.Amount.Amount() != money.FromFloat(1000) {
+		t.Errorf("got %+v (refund %+v)", resp, resp.PaymentIntent)
+	}
+
+	req := fake.last()
+	if req.path != "/v1/refunds" || req.form.Get("payment_intent") != "pi_123" || req.form.Get("amount") != "1000" {
+		t.Errorf("sent %s with form %v", req.path, req.form)
+	}
+	if req.idempotencyKey != "refund-9" {
+		t.Errorf("sent idempotency key %q, want refund-9", req.idempotencyKey)
+	}
+}
+
+func TestStripePendingRefundIsNotSuccess(t *testing.T) {
+	_, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, map[string]interface{}{"id": "re_1", "amount": 1000, "currency": "twd", "status": "pending"})
+	})
+
+	resp, err := client.RefundPayment(context.Background(), "pi_123", money.New(money.FromFloat(10), "twd"), "refund-9")
+	if err != nil {
+		t.Fatalf("RefundPayment failed: %v", err)
+	}
+	if resp.Success || resp.PaymentIntent.Status != "pending" {
+		t.Errorf("got %+v (refund %+v), want an unsettled pending refund", resp, resp.PaymentIntent)
+	}
+}
+
+func TestStripeHonoursContextCancellation(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	_, client := newFakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetPaymentIntent(ctx, "pi_123")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call returned %v after its context expired", elapsed)
+	}
+}
+
+func TestNewClientRequiresSecretKeyForStripe(t *testing.T) {
+	if _, err := NewClient(&config.Config{}); !errors.Is(err, ErrMissingSecretKey) {
+		t.Errorf("real mode without a key returned %v, want ErrMissingSecretKey", err)
+	}
+
+	client, err := NewClient(&config.Config{StripeSecretKey: "sk_test_123"})
+	if err != nil || client.Provider() != ProviderStripe {
+		t.Errorf("real mode with a key returned %v, err %v", client, err)
+	}
+	client, err = NewClient(&config.Config{MockStripeEnabled: true, MockStripeLatency: "0s"})
+	if err != nil || client.Provider() == ProviderStripe {
+		t.Errorf("mock mode returned %v, err %v", client, err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package pricing computes ticket prices with demand-based surges. Both the
+// event service, which quotes current prices, and the booking service,
+// which locks the price in at reservation, use it so they always agree.
+package pricing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"gorm.io/gorm"
+)
+
+// multiplierScale is the precision of surge multipliers: 1.5 is applied as
+// 15000/10000
+const multiplierScale = 10000
+
+// Apply returns base, multiplied by the event's surge multiplier when
+// dynamic pricing is on and the share of seats left is below the threshold
+func Apply(event *models.Event, base money.Amount, available, total int64) money.Amount {
+	if !Surging(event, available, total) {
+		return base
+	}
+	return base.MulRatio(int64(math.Round(event.PriceSurgeMultiplier*multiplierScale)), multiplierScale)
+}
+
+// Surging reports whether the event's prices are surged at the given
+// availability
+func Surging(event *models.Event, available, total int64) bool {
+	if event == nil || !event.DynamicPricingEnabled || total <= 0 {
+		return false
+	}
+	return float64(available)/float64(total) < event.PriceSurgeThreshold
+}
+
+// Availability counts the seats left and in total of a tier, or of the
+// whole event when tierID is 0
+func Availability(db *gorm.DB, eventID, tierID uint) (available, total int64, err error) {
+	if tierID != 0 {
+		var tier models.TicketTier
+		if err := db.Select("total_count", "available_count").First(&tier, "id = ? AND event_id = ?", tierID, eventID).Error; err != nil {
+			return 0, 0, err
+		}
+		return int64(tier.AvailableCount), int64(tier.TotalCount), nil
+	}
+
+	if err := db.Model(&models.Ticket{}).Where("event_id = ?", eventID).Count(&total).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count tickets: %w", err)
+	}
+	if err := db.Model(&models.Ticket{}).Where("event_id = ? AND status = ?", eventID, models.TicketAvailable).Count(&available).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count available tickets: %w", err)
+	}
+	return available, total, nil
+}
+
+// TicketPrice is what the ticket costs right now. The ticket must be loaded
+// with its Event.
+func TicketPrice(db *gorm.DB, ticket *models.Ticket) (money.Amount, error) {
+	if ticket.Event == nil || !ticket.Event.DynamicPricingEnabled {
+		return ticket.Price, nil
+	}
+
+	var tierID uint
+	if ticket.TierID != nil {
+		tierID = *ticket.TierID
+	}
+	available, total, err := Availability(db, ticket.EventID, tierID)
+	if err != nil {
+		return 0, err
+	}
+	return Apply(ticket.Event, ticket.Price, available, total), nil
+}
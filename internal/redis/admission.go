@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// grantAdmissionScript stores a user's admission token and records it in the
+// event's set of outstanding admissions, scored by expiry
+var grantAdmissionScript = redis.NewScript(`
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+redis.call("ZADD", KEYS[2], ARGV[3], ARGV[4])
+return 1
+`)
+
+// revokeAdmissionScript removes a user's admission token and its entry in
+// the outstanding admissions
+var revokeAdmissionScript = redis.NewScript(`
+redis.call("DEL", KEYS[1])
+redis.call("ZREM", KEYS[2], ARGV[1])
+return 1
+`)
+
+func admissionKey(eventID, userID uint) string {
+	return fmt.Sprintf("admission:%d:%d", eventID, userID)
+}
+
+func admittedKey(eventID uint) string {
+	return fmt.Sprintf("admitted:%d", eventID)
+}
+
+// SignAdmissionToken creates an opaque admission token for a user, signed
+// with key so that it cannot be forged or moved to another user or event
+func SignAdmissionToken(key []byte, eventID, userID uint) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate admission token: %w", err)
+	}
+	return hex.EncodeToString(nonce) + "." + admissionMAC(key, eventID, userID, nonce), nil
+}
+
+// VerifyAdmissionToken reports whether token was signed with key for the
+// user and event
+func VerifyAdmissionToken(key []byte, eventID, userID uint, token string) bool {
+	nonceHex, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	nonce, err := hex.DecodeString(nonceHex)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(mac), []byte(admissionMAC(key, eventID, userID, nonce)))
+}
+
+func admissionMAC(key []byte, eventID, userID uint, nonce []byte) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%d:%d:%x", eventID, userID, nonce)
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// GrantAdmission lets a user past the waiting queue of an event for ttl and
+// returns their admission token. The admission is revoked when it is used
+// or when it expires.
+func (c *Client) GrantAdmission(ctx context.Context, eventID uint, userID uint, ttl time.Duration) (string, error) {
+	token, err := SignAdmissionToken(c.signingKey, eventID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(ttl).UnixMilli()
+	err = grantAdmissionScript.Run(ctx, c.rdb,
+		[]string{admissionKey(eventID, userID), admittedKey(eventID)},
+		token, ttl.Milliseconds(), expiresAt, strconv.FormatUint(uint64(userID), 10),
+	).Err()
+	if err != nil {
+		return "", fmt.Errorf("failed to grant admission: %w", err)
+	}
+	return token, nil
+}
+
+// CheckAdmission reports whether a user currently holds an admission for
+// the event. A stored token that does not verify for the user and event
+// does not admit them.
+func (c *Client) CheckAdmission(ctx context.Context, eventID uint, userID uint) (bool, error) {
+	token, err := c.rdb.Get(ctx, admissionKey(eventID, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check admission: %w", err)
+	}
+	return VerifyAdmissionToken(c.signingKey, eventID, userID, token), nil
+}
+
+// RevokeAdmission removes a user's admission, typically once it was used
+func (c *Client) RevokeAdmission(ctx context.Context, eventID uint, userID uint) error {
+	err := revokeAdmissionScript.Run(ctx, c.rdb,
+		[]string{admissionKey(eventID, userID), admittedKey(eventID)},
+		strconv.FormatUint(uint64(userID), 10),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to revoke admission: %w", err)
+	}
+	return nil
+}
+
+// ActiveAdmissions counts the unexpired, unused admissions of an event
+func (c *Client) ActiveAdmissions(ctx context.Context, eventID uint) (int64, error) {
+	key := admittedKey(eventID)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := c.rdb.ZRemRangeByScore(ctx, key, "-inf", now).Err(); err != nil {
+		return 0, fmt.Errorf("failed to prune admissions: %w", err)
+	}
+	n, err := c.rdb.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count admissions: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// cacheKey namespaces a cache key with the configured prefix so several
+// environments can share one Redis instance
+func (c *Client) cacheKey(key string) string {
+	if c.keyPrefix == "" {
+		return "cache:" + key
+	}
+	return c.keyPrefix + ":cache:" + key
+}
+
+// GetJSON decodes the cached value of key into dest. It returns false on a
+// cache miss.
+func (c *Client) GetJSON(ctx context.Context, key string, dest any) (bool, error) {
+	data, err := c.rdb.Get(ctx, c.cacheKey(key)).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// SetJSON caches val as JSON under key for ttl
+func (c *Client) SetJSON(ctx context.Context, key string, val any, ttl time.Duration) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for cache: %w", key, err)
+	}
+	if err := c.rdb.Set(ctx, c.cacheKey(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Delete removes cached keys
+func (c *Client) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cacheKeys := make([]string, len(keys))
+	for i, key := range keys {
+		cacheKeys[i] = c.cacheKey(key)
+	}
+	if err := c.rdb.Del(ctx, cacheKeys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cache keys: %w", err)
+	}
+	return nil
+}
+
+// Remember decodes the cached value of key into dest, computing it with fn
+// and caching it for ttl on a miss. Concurrent misses for the same key in
+// this process share one call to fn. A failing cache is treated as a miss
+// so callers still get a value while Redis is down.
+func (c *Client) Remember(ctx context.Context, key string, ttl time.Duration, dest any, fn func(ctx context.Context) (any, error)) error {
+	hit, err := c.GetJSON(ctx, key, dest)
+	if err != nil {
+		log.Printf("Cache read for %s failed, recomputing: %v", key, err)
+	}
+	if hit {
+		return nil
+	}
+
+	data, err, _ := c.flight.Do(key, func() (interface{}, error) {
+		val, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s for cache: %w", key, err)
+		}
+		if err := c.rdb.Set(ctx, c.cacheKey(key), data, ttl).Err(); err != nil {
+			log.Printf("Failed to cache %s: %v", key, err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data.([]byte), dest); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+type cachedEvent struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONCacheRoundTrip(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	var got cachedEvent
+	if hit, err := client.GetJSON(ctx, "event:1", &got); err != nil || hit {
+		t.Fatalf("empty cache returned hit %v, err %v", hit, err)
+	}
+
+	want := cachedEvent{ID: 1, Name: "Spring Concert"}
+	if err := client.SetJSON(ctx, "event:1", want, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	if hit, err := client.GetJSON(ctx, "event:1", &got); err != nil || !hit {
+		t.Fatalf("cached value returned hit %v, err %v", hit, err)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	server.FastForward(2 * time.Minute)
+	if hit, _ := client.GetJSON(ctx, "event:1", &got); hit {
+		t.Error("value still cached after its TTL")
+	}
+
+	if err := client.SetJSON(ctx, "event:2", want, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	if err := client.Delete(ctx, "event:2"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if hit, _ := client.GetJSON(ctx, "event:2", &got); hit {
+		t.Error("value still cached after Delete")
+	}
+}
+
+func TestJSONCacheKeyPrefix(t *testing.T) {
+	server := miniredis.RunT(t)
+	staging := connect(t, server, "staging")
+	production := connect(t, server, "production")
+	ctx := context.Background()
+
+	if err := staging.SetJSON(ctx, "event:1", cachedEvent{ID: 1, Name: "staging"}, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	if err := production.SetJSON(ctx, "event:1", cachedEvent{ID: 1, Name: "production"}, time.Minute); err != nil {
+		t.Fatalf("SetJSON failed: %v", err)
+	}
+	if !server.Exists("staging:cache:event:1") || !server.Exists("production:cache:event:1") {
+		t.Fatalf("cache keys are not namespaced: %v", server.Keys())
+	}
+
+	if err := production.Delete(ctx, "event:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	var got cachedEvent
+	if hit, err := staging.GetJSON(ctx, "event:1", &got); err != nil || !hit || got.Name != "staging" {
+		t.Fatalf("another environment's Delete reached staging: hit %v, value %+v, err %v", hit, got, err)
+	}
+}
+
+func TestRememberComputesConcurrentMissesOnce(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	compute := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return cachedEvent{ID: 1, Name: "Spring Concert"}, nil
+	}
+
+	const callers = 20
+	results := make([]cachedEvent, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = client.Remember(ctx, "event:1", time.Minute, &results[i], compute)
+		}()
+	}
+	// Let every caller miss and join the flight before it lands
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("value computed %d times for %d concurrent misses, want 1", n, callers)
+	}
+	for i := range results {
+		if errs[i] != nil || results[i].Name != "Spring Concert" {
+			t.Fatalf("caller %d got %+v, err %v", i, results[i], errs[i])
+		}
+	}
+
+	// Later calls are served from the cache
+	var cached cachedEvent
+	if err := client.Remember(ctx, "event:1", time.Minute, &cached, compute); err != nil {
+		t.Fatalf("Remember failed: %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("cached value recomputed (%d calls)", n)
+	}
+}
+
+func TestRememberDoesNotCacheErrors(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	failure := errors.New("database down")
+	var dest cachedEvent
+	err := client.Remember(ctx, "event:1", time.Minute, &dest, func(ctx context.Context) (any, error) {
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("got %v, want the compute error", err)
+	}
+	if server.Exists("cache:event:1") {
+		t.Fatal("a failed computation was cached")
+	}
+}
+
+func TestRememberComputesWhileRedisIsDown(t *testing.T) {
+	client, server := newTestClient(t)
+	server.Close()
+
+	var dest cachedEvent
+	err := client.Remember(context.Background(), "event:1", time.Minute, &dest, func(ctx context.Context) (any, error) {
+		return cachedEvent{ID: 1, Name: "Spring Concert"}, nil
+	})
+	if err != nil || dest.Name != "Spring Concert" {
+		t.Fatalf("Remember with Redis down returned %+v, err %v", dest, err)
+	}
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// EventCounters are an event's live ticket counts. Reserved tickets are
+// the ones neither available nor sold.
+type EventCounters struct {
+	Total     int
+	Available int
+	Sold      int
+}
+
+// EventCountersTTL is how long counters live before they are recounted
+// from the database, which heals drift from an adjustment that failed
+const EventCountersTTL = time.Hour
+
+func eventCountersKey(eventID uint) string {
+	return fmt.Sprintf("event_capacity:%d", eventID)
+}
+
+// SetEventCounters replaces an event's counters, typically with counts just
+// read from Postgres. They expire after EventCountersTTL.
+func (c *Client) SetEventCounters(ctx context.Context, eventID uint, counters EventCounters) error {
+	key := eventCountersKey(eventID)
+	pipe := c.rdb.TxPipeline()
+	pipe.HSet(ctx, key, "total", counters.Total, "available", counters.Available, "sold", counters.Sold)
+	pipe.Expire(ctx, key, EventCountersTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set event counters: %w", err)
+	}
+	return nil
+}
+
+// adjustEventCountersScript only touches initialized counters; a missing
+// key is re-seeded from the database on the next read
+var adjustEventCountersScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HINCRBY", KEYS[1], "total", ARGV[1])
+redis.call("HINCRBY", KEYS[1], "available", ARGV[2])
+redis.call("HINCRBY", KEYS[1], "sold", ARGV[3])
+return 1
+`)
+
+// AdjustEventCounters adds delta to an event's counters if they are
+// initialized
+func (c *Client) AdjustEventCounters(ctx context.Context, eventID uint, delta EventCounters) error {
+	err := adjustEventCountersScript.Run(ctx, c.rdb, []string{eventCountersKey(eventID)},
+		delta.Total, delta.Available, delta.Sold).Err()
+	if err != nil {
+		return fmt.Errorf("failed to adjust event counters: %w", err)
+	}
+	return nil
+}
+
+// GetEventCounters reads an event's counters; ok is false when they have
+// not been initialized
+func (c *Client) GetEventCounters(ctx context.Context, eventID uint) (EventCounters, bool, error) {
+	fields, err := c.rdb.HGetAll(ctx, eventCountersKey(eventID)).Result()
+	if err != nil {
+		return EventCounters{}, false, fmt.Errorf("failed to read event counters: %w", err)
+	}
+	if len(fields) == 0 {
+		return EventCounters{}, false, nil
+	}
+
+	var counters EventCounters
+	for field, dest := range map[string]*int{"total": &counters.Total, "available": &counters.Available, "sold": &counters.Sold} {
+		if *dest, err = strconv.Atoi(fields[field]); err != nil {
+			return EventCounters{}, false, fmt.Errorf("malformed event counter %s: %w", field, err)
+		}
+	}
+	return counters, true, nil
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// contentionKey is a sorted set of ticket IDs scored by lock conflicts
+const contentionKey = "lock_contention"
+
+// contentionWindow is how long conflict counts survive without new
+// conflicts; each conflict extends it
+const contentionWindow = 24 * time.Hour
+
+// ContendedTicket is a ticket and how often locking it hit a conflict
+type ContendedTicket struct {
+	TicketID  uint  `json:"ticketId"`
+	Conflicts int64 `json:"conflicts"`
+}
+
+// recordContention counts a lock conflict on a ticket. It only costs a
+// round trip when two users collide, and a failure is only logged.
+func (c *Client) recordContention(ctx context.Context, ticketID uint) {
+	pipe := c.rdb.Pipeline()
+	pipe.ZIncrBy(ctx, contentionKey, 1, strconv.FormatUint(uint64(ticketID), 10))
+	pipe.Expire(ctx, contentionKey, contentionWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to record lock contention on ticket %d: %v", ticketID, err)
+	}
+}
+
+// TopContendedTickets returns the limit tickets with the most lock
+// conflicts, most contended first
+func (c *Client) TopContendedTickets(ctx context.Context, limit int) ([]ContendedTicket, error) {
+	entries, err := c.rdb.ZRevRangeWithScores(ctx, contentionKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock contention: %w", err)
+	}
+
+	tickets := make([]ContendedTicket, 0, len(entries))
+	for _, entry := range entries {
+		member, _ := entry.Member.(string)
+		ticketID, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		tickets = append(tickets, ContendedTicket{TicketID: uint(ticketID), Conflicts: int64(entry.Score)})
+	}
+	return tickets, nil
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// expiredKeysChannel carries the names of expired keys in DB 0
+const expiredKeysChannel = "__keyevent@0__:expired"
+
+// ticketLockPrefix starts every ticket lock key; the ticket ID follows it
+const ticketLockPrefix = "ticket_lock:"
+
+// EnableExpiryNotifications turns on keyevent notifications for expired
+// keys, keeping any notification classes that are already enabled
+func (c *Client) EnableExpiryNotifications(ctx context.Context) error {
+	current, err := c.rdb.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return fmt.Errorf("failed to read keyspace notification config: %w", err)
+	}
+
+	flags := ""
+	if len(current) == 2 {
+		flags, _ = current[1].(string)
+	}
+	for _, flag := range "Ex" {
+		// "A" already includes expired events
+		if flag == 'x' && strings.ContainsRune(flags, 'A') {
+			continue
+		}
+		if !strings.ContainsRune(flags, flag) {
+			flags += string(flag)
+		}
+	}
+
+	if err := c.rdb.ConfigSet(ctx, "notify-keyspace-events", flags).Err(); err != nil {
+		return fmt.Errorf("failed to enable keyspace notifications: %w", err)
+	}
+	return nil
+}
+
+// ListenTicketLockExpiry calls onExpired with the ticket ID of every ticket
+// lock that expires, until ctx is done. Notifications must be enabled, either
+// with EnableExpiryNotifications or in the server config. Expiry events are
+// delivered at most once; locks that expire while nobody listens are missed.
+func (c *Client) ListenTicketLockExpiry(ctx context.Context, onExpired func(ticketID uint)) error {
+	pubsub := c.rdb.Subscribe(ctx, expiredKeysChannel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to expired keys: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			if !strings.HasPrefix(message.Payload, ticketLockPrefix) {
+				continue
+			}
+			ticketID, err := strconv.ParseUint(strings.TrimPrefix(message.Payload, ticketLockPrefix), 10, 32)
+			if err != nil {
+				log.Printf("Ignoring expired key %q: %v", message.Payload, err)
+				continue
+			}
+			onExpired(uint(ticketID))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// pingTimeout bounds the startup ping and each health probe
+const pingTimeout = 5 * time.Second
+
+// HealthProbeInterval is how often services probe Redis
+const HealthProbeInterval = 10 * time.Second
+
+// HealthProber pings Redis in the background for a service's health
+// endpoint. Like database.Readiness, a failure counts until two intervals
+// pass without another one. go-redis replaces broken pool connections on
+// use, so probes that succeed again mean the client has reconnected.
+type HealthProber struct {
+	store    Store
+	service  string
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastErr  error
+	failedAt time.Time
+}
+
+func NewHealthProber(store Store, service string, interval time.Duration) *HealthProber {
+	return &HealthProber{store: store, service: service, interval: interval}
+}
+
+// Watch pings Redis every interval until ctx is done, logging and recording
+// every failure
+func (p *HealthProber) Watch(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := p.store.Ping(pingCtx)
+		cancel()
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		err = fmt.Errorf("redis ping failed: %w", err)
+		log.Printf("level=error service=%s component=redis msg=%q error=%q", p.service, "health check failed", err.Error())
+
+		p.mu.Lock()
+		p.lastErr = err
+		p.failedAt = time.Now()
+		p.mu.Unlock()
+	}
+}
+
+// Err returns the latest probe failure while it still counts
+func (p *HealthProber) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lastErr == nil || time.Since(p.failedAt) > 2*p.interval {
+		return nil
+	}
+	return p.lastErr
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+)
+
+func TestNewClientFailsFastWhenRedisIsUnreachable(t *testing.T) {
+	// A port with nothing listening refuses the dial
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	// A server that accepts connections but never answers hangs the ping
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer silent.Close()
+	go func() {
+		for {
+			conn, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	tests := []struct {
+		name string
+		addr string
+	}{
+		{name: "connection refused", addr: closedAddr},
+		{name: "no reply", addr: silent.Addr().String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, port, _ := net.SplitHostPort(tt.addr)
+			cfg := &config.Config{
+				RedisHost:            host,
+				RedisPort:            port,
+				RedisDialTimeout:     200 * time.Millisecond,
+				RedisReadTimeout:     200 * time.Millisecond,
+				RedisWriteTimeout:    200 * time.Millisecond,
+				RedisMaxRetries:      1,
+				RedisMinRetryBackoff: 10 * time.Millisecond,
+				RedisMaxRetryBackoff: 50 * time.Millisecond,
+			}
+
+			start := time.Now()
+			client, err := NewClient(cfg)
+			elapsed := time.Since(start)
+			if err == nil {
+				client.Close()
+				t.Fatal("NewClient succeeded against an unreachable Redis")
+			}
+			// Two attempts at the read timeout plus backoff, well short of
+			// the startup ping timeout
+			if elapsed > 2*time.Second {
+				t.Errorf("NewClient took %v to fail, want under 2s", elapsed)
+			}
+		})
+	}
+}
+
+func TestHealthProberReportsFailureUntilRecovery(t *testing.T) {
+	client, server := newTestClient(t)
+	const interval = 20 * time.Millisecond
+	prober := NewHealthProber(client, "test", interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go prober.Watch(ctx)
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	waitFor(t, "probe failure to be reported", func() bool { return prober.Err() != nil })
+
+	server.SetError("")
+	waitFor(t, "probe failure to clear", func() bool { return prober.Err() == nil })
+}
+
+// waitFor polls cond until it holds, failing the test after a second
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,787 @@
+// Package inmem implements redis.Store in process memory, with key
+// expiry, for unit tests and single-process development without a Redis
+// server. State is not shared between processes, so it cannot coordinate
+// replicas or separate services.
+package inmem
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"golang.org/x/sync/singleflight"
+)
+
+// sweepInterval is how often expired keys are removed and expiry listeners
+// notified; reads also ignore expired keys in between
+const sweepInterval = 100 * time.Millisecond
+
+type entry struct {
+	value     string
+	expiresAt time.Time // zero means no expiry
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Store is an in-memory redis.Store
+type Store struct {
+	mu          sync.Mutex
+	values      map[string]*entry
+	queues      map[uint]map[string]float64
+	contention  map[uint]int64
+	popularity  map[uint]float64
+	history     map[uint][]redis.SearchHistoryEntry
+	subscribers map[chan redis.ChangeMessage]struct{}
+	listeners   map[int]func(ticketID uint)
+	nextID      int
+	signingKey  []byte
+	flight      singleflight.Group
+
+	stop chan struct{}
+	once sync.Once
+}
+
+var _ redis.Store = (*Store)(nil)
+
+// New creates an empty store and starts its expiry sweeper; Close stops it
+func New() *Store {
+	signingKey := make([]byte, 32)
+	rand.Read(signingKey)
+
+	s := &Store{
+		values:      make(map[string]*entry),
+		queues:      make(map[uint]map[string]float64),
+		contention:  make(map[uint]int64),
+		popularity:  make(map[uint]float64),
+		history:     make(map[uint][]redis.SearchHistoryEntry),
+		subscribers: make(map[chan redis.ChangeMessage]struct{}),
+		listeners:   make(map[int]func(ticketID uint)),
+		signingKey:  signingKey,
+		stop:        make(chan struct{}),
+	}
+	go s.sweep()
+	return s
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+// sweep drops expired keys and tells expiry listeners about ticket locks
+func (s *Store) sweep() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		var expiredLocks []uint
+		s.mu.Lock()
+		for key, e := range s.values {
+			if !e.expired(now) {
+				continue
+			}
+			delete(s.values, key)
+			if id, ok := strings.CutPrefix(key, "ticket_lock:"); ok {
+				if ticketID, err := strconv.ParseUint(id, 10, 32); err == nil {
+					expiredLocks = append(expiredLocks, uint(ticketID))
+				}
+			}
+		}
+		listeners := make([]func(uint), 0, len(s.listeners))
+		for _, fn := range s.listeners {
+			listeners = append(listeners, fn)
+		}
+		s.mu.Unlock()
+
+		for _, ticketID := range expiredLocks {
+			for _, fn := range listeners {
+				fn(ticketID)
+			}
+		}
+	}
+}
+
+// get returns a live entry; callers hold mu
+func (s *Store) get(key string) (*entry, bool) {
+	e, ok := s.values[key]
+	if !ok || e.expired(time.Now()) {
+		return nil, false
+	}
+	return e, true
+}
+
+// set stores a value; a ttl of zero means no expiry. Callers hold mu.
+func (s *Store) set(key, value string, ttl time.Duration) {
+	e := &entry{value: value}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	s.values[key] = e
+}
+
+func lockKey(ticketID uint) string {
+	return fmt.Sprintf("ticket_lock:%d", ticketID)
+}
+
+func (s *Store) LockTicket(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error) {
+	token, err := redis.NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(lockKey(ticketID)); ok {
+		s.contention[ticketID]++
+		return "", fmt.Errorf("ticket %d: %w", ticketID, redis.ErrTicketLocked)
+	}
+	s.set(lockKey(ticketID), token, ttl)
+	return token, nil
+}
+
+// LockTicketInMemory is LockTicket; every lock here is in memory
+func (s *Store) LockTicketInMemory(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error) {
+	return s.LockTicket(ctx, ticketID, userID, ttl)
+}
+
+func (s *Store) UnlockTicket(ctx context.Context, ticketID uint, token string) error {
+	if token == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.get(lockKey(ticketID)); ok && e.value == token {
+		delete(s.values, lockKey(ticketID))
+	}
+	return nil
+}
+
+func (s *Store) LockTickets(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error) {
+	if len(ticketIDs) == 0 {
+		return "", nil
+	}
+	token, err := redis.NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var contended []uint
+	for _, ticketID := range ticketIDs {
+		if _, ok := s.get(lockKey(ticketID)); ok {
+			contended = append(contended, ticketID)
+			s.contention[ticketID]++
+		}
+	}
+	if len(contended) > 0 {
+		return "", &redis.ContendedTicketsError{TicketIDs: contended}
+	}
+	for _, ticketID := range ticketIDs {
+		s.set(lockKey(ticketID), token, ttl)
+	}
+	return token, nil
+}
+
+// LockTicketsInMemory is LockTickets; every lock here is in memory
+func (s *Store) LockTicketsInMemory(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error) {
+	return s.LockTickets(ctx, ticketIDs, userID, ttl)
+}
+
+func (s *Store) UnlockTickets(ctx context.Context, ticketIDs []uint, token string) error {
+	if token == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ticketID := range ticketIDs {
+		if e, ok := s.get(lockKey(ticketID)); ok && e.value == token {
+			delete(s.values, lockKey(ticketID))
+		}
+	}
+	return nil
+}
+
+func (s *Store) IsTicketLocked(ctx context.Context, ticketID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.get(lockKey(ticketID))
+	return ok, nil
+}
+
+func (s *Store) GetTicketLockOwner(ctx context.Context, ticketID uint) (uint, error) {
+	token, err := s.TicketLockToken(ctx, ticketID)
+	if err != nil {
+		return 0, err
+	}
+	if token == "" {
+		return 0, redis.ErrLockNotFound
+	}
+	return redis.LockTokenOwner(token)
+}
+
+func (s *Store) TicketLockToken(ctx context.Context, ticketID uint) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.get(lockKey(ticketID)); ok {
+		return e.value, nil
+	}
+	return "", nil
+}
+
+func (s *Store) ExtendTicketLock(ctx context.Context, ticketID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.get(lockKey(ticketID)); ok {
+		e.expiresAt = time.Now().Add(redis.TicketLockTTL)
+	}
+	return nil
+}
+
+func (s *Store) RenewTicketLock(ctx context.Context, ticketID uint, token string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.get(lockKey(ticketID))
+	if !ok || e.value != token {
+		return false, nil
+	}
+	if expiresAt := time.Now().Add(ttl); e.expiresAt.Before(expiresAt) {
+		e.expiresAt = expiresAt
+	}
+	return true, nil
+}
+
+func (s *Store) TopContendedTickets(ctx context.Context, limit int) ([]redis.ContendedTicket, error) {
+	s.mu.Lock()
+	tickets := make([]redis.ContendedTicket, 0, len(s.contention))
+	for ticketID, conflicts := range s.contention {
+		tickets = append(tickets, redis.ContendedTicket{TicketID: ticketID, Conflicts: conflicts})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(tickets, func(i, j int) bool {
+		if tickets[i].Conflicts != tickets[j].Conflicts {
+			return tickets[i].Conflicts > tickets[j].Conflicts
+		}
+		return tickets[i].TicketID < tickets[j].TicketID
+	})
+	if len(tickets) > limit {
+		tickets = tickets[:limit]
+	}
+	return tickets, nil
+}
+
+// LockBackendMode always reports memory
+func (s *Store) LockBackendMode() string {
+	return redis.LockBackendMemory
+}
+
+// EnableExpiryNotifications is a no-op; expiry listeners always fire
+func (s *Store) EnableExpiryNotifications(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) ListenTicketLockExpiry(ctx context.Context, onExpired func(ticketID uint)) error {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = onExpired
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	delete(s.listeners, id)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Store) ReserveTierSlot(ctx context.Context, tierID uint, available int) (bool, error) {
+	key := fmt.Sprintf("tier_available:%d", tierID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remaining := available
+	if e, ok := s.get(key); ok {
+		remaining, _ = strconv.Atoi(e.value)
+	}
+	if remaining <= 0 {
+		s.set(key, strconv.Itoa(remaining), 0)
+		return false, nil
+	}
+	s.set(key, strconv.Itoa(remaining-1), 0)
+	return true, nil
+}
+
+func (s *Store) ReleaseTierSlot(ctx context.Context, tierID uint) error {
+	key := fmt.Sprintf("tier_available:%d", tierID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Only increment an initialized counter, like the Redis implementation
+	if e, ok := s.get(key); ok {
+		remaining, _ := strconv.Atoi(e.value)
+		e.value = strconv.Itoa(remaining + 1)
+	}
+	return nil
+}
+
+func (s *Store) ResetTierSlots(ctx context.Context, tierID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, fmt.Sprintf("tier_available:%d", tierID))
+	return nil
+}
+
+func eventCountersKey(eventID uint) string {
+	return fmt.Sprintf("event_capacity:%d", eventID)
+}
+
+func (s *Store) SetEventCounters(ctx context.Context, eventID uint, counters redis.EventCounters) error {
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(eventCountersKey(eventID), string(data), redis.EventCountersTTL)
+	return nil
+}
+
+func (s *Store) AdjustEventCounters(ctx context.Context, eventID uint, delta redis.EventCounters) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Only adjust initialized counters, like the Redis implementation
+	e, ok := s.get(eventCountersKey(eventID))
+	if !ok {
+		return nil
+	}
+	var counters redis.EventCounters
+	if err := json.Unmarshal([]byte(e.value), &counters); err != nil {
+		return err
+	}
+	counters.Total += delta.Total
+	counters.Available += delta.Available
+	counters.Sold += delta.Sold
+	data, err := json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	e.value = string(data)
+	return nil
+}
+
+func (s *Store) GetEventCounters(ctx context.Context, eventID uint) (redis.EventCounters, bool, error) {
+	s.mu.Lock()
+	e, ok := s.get(eventCountersKey(eventID))
+	s.mu.Unlock()
+	if !ok {
+		return redis.EventCounters{}, false, nil
+	}
+	var counters redis.EventCounters
+	if err := json.Unmarshal([]byte(e.value), &counters); err != nil {
+		return redis.EventCounters{}, false, err
+	}
+	return counters, true, nil
+}
+
+func (s *Store) IncrEventScore(ctx context.Context, eventID uint, delta float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.popularity[eventID] += delta
+	return nil
+}
+
+func (s *Store) GetTopEvents(ctx context.Context, limit int) ([]uint, error) {
+	s.mu.Lock()
+	eventIDs := make([]uint, 0, len(s.popularity))
+	scores := make(map[uint]float64, len(s.popularity))
+	for eventID, score := range s.popularity {
+		eventIDs = append(eventIDs, eventID)
+		scores[eventID] = score
+	}
+	s.mu.Unlock()
+
+	// Ties go to the higher ID, as with ZREVRANGE
+	sort.Slice(eventIDs, func(i, j int) bool {
+		if scores[eventIDs[i]] != scores[eventIDs[j]] {
+			return scores[eventIDs[i]] > scores[eventIDs[j]]
+		}
+		return eventIDs[i] > eventIDs[j]
+	})
+	if len(eventIDs) > limit {
+		eventIDs = eventIDs[:limit]
+	}
+	return eventIDs, nil
+}
+
+func queueMember(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
+
+// queueOrder returns the members of a queue by join time; callers hold mu
+func (s *Store) queueOrder(eventID uint) []string {
+	queue := s.queues[eventID]
+	members := make([]string, 0, len(queue))
+	for member := range queue {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if queue[members[i]] != queue[members[j]] {
+			return queue[members[i]] < queue[members[j]]
+		}
+		return members[i] < members[j]
+	})
+	return members
+}
+
+func (s *Store) AddToWaitingQueue(ctx context.Context, eventID uint, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queues[eventID] == nil {
+		s.queues[eventID] = make(map[string]float64)
+	}
+	if _, ok := s.queues[eventID][queueMember(userID)]; !ok {
+		s.queues[eventID][queueMember(userID)] = float64(time.Now().UnixMilli())
+	}
+	return nil
+}
+
+func (s *Store) GetWaitingQueuePosition(ctx context.Context, eventID uint, userID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, member := range s.queueOrder(eventID) {
+		if member == queueMember(userID) {
+			return int64(i), nil
+		}
+	}
+	return -1, redis.ErrNotInQueue
+}
+
+func (s *Store) RemoveFromWaitingQueue(ctx context.Context, eventID uint, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.queues[eventID], queueMember(userID))
+	return nil
+}
+
+func (s *Store) QueueLength(ctx context.Context, eventID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.queues[eventID])), nil
+}
+
+func (s *Store) ProcessWaitingQueue(ctx context.Context, eventID uint) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.queueOrder(eventID)
+	if len(order) == 0 {
+		return 0, redis.ErrQueueEmpty
+	}
+	delete(s.queues[eventID], order[0])
+
+	userID, err := strconv.ParseUint(order[0], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID in queue: %w", err)
+	}
+	return uint(userID), nil
+}
+
+func admissionKey(eventID, userID uint) string {
+	return fmt.Sprintf("admission:%d:%d", eventID, userID)
+}
+
+func (s *Store) GrantAdmission(ctx context.Context, eventID uint, userID uint, ttl time.Duration) (string, error) {
+	token, err := redis.SignAdmissionToken(s.signingKey, eventID, userID)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(admissionKey(eventID, userID), token, ttl)
+	return token, nil
+}
+
+func (s *Store) CheckAdmission(ctx context.Context, eventID uint, userID uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.get(admissionKey(eventID, userID))
+	return ok && redis.VerifyAdmissionToken(s.signingKey, eventID, userID, e.value), nil
+}
+
+func (s *Store) RevokeAdmission(ctx context.Context, eventID uint, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, admissionKey(eventID, userID))
+	return nil
+}
+
+func (s *Store) ActiveAdmissions(ctx context.Context, eventID uint) (int64, error) {
+	prefix := fmt.Sprintf("admission:%d:", eventID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for key := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			if _, ok := s.get(key); ok {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func cacheKey(key string) string {
+	return "cache:" + key
+}
+
+func (s *Store) GetJSON(ctx context.Context, key string, dest any) (bool, error) {
+	s.mu.Lock()
+	e, ok := s.get(cacheKey(key))
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(e.value), dest); err != nil {
+		return false, fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func (s *Store) SetJSON(ctx context.Context, key string, val any, ttl time.Duration) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for cache: %w", key, err)
+	}
+	s.mu.Lock()
+	s.set(cacheKey(key), string(data), ttl)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.values, cacheKey(key))
+	}
+	return nil
+}
+
+func (s *Store) Remember(ctx context.Context, key string, ttl time.Duration, dest any, fn func(ctx context.Context) (any, error)) error {
+	if hit, _ := s.GetJSON(ctx, key, dest); hit {
+		return nil
+	}
+
+	data, err, _ := s.flight.Do(key, func() (interface{}, error) {
+		val, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s for cache: %w", key, err)
+		}
+		s.mu.Lock()
+		s.set(cacheKey(key), string(data), ttl)
+		s.mu.Unlock()
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data.([]byte), dest); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return nil
+}
+
+func ticketStatusKey(ticketID uint) string {
+	return fmt.Sprintf("ticket_status:%d", ticketID)
+}
+
+func (s *Store) GetTicketStatuses(ctx context.Context, ticketIDs []uint) (map[uint]redis.TicketStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make(map[uint]redis.TicketStatus, len(ticketIDs))
+	for _, ticketID := range ticketIDs {
+		e, ok := s.get(ticketStatusKey(ticketID))
+		if !ok {
+			continue
+		}
+		var status redis.TicketStatus
+		if err := json.Unmarshal([]byte(e.value), &status); err == nil {
+			statuses[status.ID] = status
+		}
+	}
+	return statuses, nil
+}
+
+func (s *Store) SetTicketStatuses(ctx context.Context, statuses []redis.TicketStatus, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, status := range statuses {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ticket status: %w", err)
+		}
+		s.set(ticketStatusKey(status.ID), string(data), ttl)
+	}
+	return nil
+}
+
+func (s *Store) InvalidateTicketStatuses(ctx context.Context, ticketIDs ...uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ticketID := range ticketIDs {
+		delete(s.values, ticketStatusKey(ticketID))
+	}
+	return nil
+}
+
+// PublishChange delivers the message to current subscribers, dropping it
+// for any subscriber that is not keeping up
+func (s *Store) PublishChange(ctx context.Context, msg redis.ChangeMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- msg:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *Store) SubscribeChanges(ctx context.Context) <-chan redis.ChangeMessage {
+	changes := make(chan redis.ChangeMessage, 100)
+
+	s.mu.Lock()
+	s.subscribers[changes] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.subscribers, changes)
+		s.mu.Unlock()
+		close(changes)
+	}()
+
+	return changes
+}
+
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+func (s *Store) SetSession(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(sessionKey(sessionID), string(data), ttl)
+	return nil
+}
+
+func (s *Store) GetSession(ctx context.Context, sessionID string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.get(sessionKey(sessionID))
+	if !ok {
+		return nil, redis.ErrSessionNotFound
+	}
+	return []byte(e.value), nil
+}
+
+func (s *Store) DeleteSession(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, sessionKey(sessionID))
+	return nil
+}
+
+func (s *Store) PushSearchHistory(ctx context.Context, userID uint, query string, resultCount int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := []redis.SearchHistoryEntry{{Query: query, ResultCount: resultCount, SearchedAt: time.Now()}}
+	for _, e := range s.history[userID] {
+		if e.Query != query && len(entries) < redis.SearchHistorySize {
+			entries = append(entries, e)
+		}
+	}
+	s.history[userID] = entries
+	return nil
+}
+
+func (s *Store) GetSearchHistory(ctx context.Context, userID uint) ([]redis.SearchHistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]redis.SearchHistoryEntry{}, s.history[userID]...), nil
+}
+
+func (s *Store) ClearSearchHistory(ctx context.Context, userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.history, userID)
+	return nil
+}
+
+func (s *Store) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+	s.set(key, holder, ttl)
+	return true, nil
+}
+
+func (s *Store) RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.get(key)
+	if !ok || e.value != holder {
+		return false, nil
+	}
+	e.expiresAt = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (s *Store) ReleaseLease(ctx context.Context, key, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.get(key); ok && e.value == holder {
+		delete(s.values, key)
+	}
+	return nil
+}
+
+func (s *Store) LeaseHolder(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.get(key); ok {
+		return e.value, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/metrics"
+)
+
+// ErrTooManyKeepers means the keeper is already renewing its maximum number
+// of locks
+var ErrTooManyKeepers = errors.New("too many ticket locks being kept")
+
+// LockKeeper renews ticket locks while a long operation, such as a payment,
+// still needs them. Renewal only succeeds while the lock holds the caller's
+// token, so a lock that expired and was taken by someone else is never
+// extended.
+type LockKeeper struct {
+	client Store
+	ttl    time.Duration
+	slots  chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewLockKeeper creates a keeper that renews locks to ttl and runs at most
+// max renewals at once
+func NewLockKeeper(client Store, ttl time.Duration, max int) *LockKeeper {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LockKeeper{
+		client: client,
+		ttl:    ttl,
+		slots:  make(chan struct{}, max),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Keep renews the ticket lock every TTL/3 until ctx is done, the lock is
+// lost or the keeper is stopped. It returns ErrTooManyKeepers instead of
+// starting when the keeper is full.
+func (k *LockKeeper) Keep(ctx context.Context, ticketID uint, token string) error {
+	select {
+	case k.slots <- struct{}{}:
+	default:
+		return ErrTooManyKeepers
+	}
+
+	k.wg.Add(1)
+	metrics.LockKeepersActive.Inc()
+	go func() {
+		defer func() {
+			metrics.LockKeepersActive.Dec()
+			<-k.slots
+			k.wg.Done()
+		}()
+
+		ticker := time.NewTicker(k.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-k.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			renewed, err := k.client.RenewTicketLock(k.ctx, ticketID, token, k.ttl)
+			if ctx.Err() != nil || k.ctx.Err() != nil {
+				// The holder finished (and may have released the lock) meanwhile
+				return
+			}
+			if err != nil {
+				metrics.LockRenewalFailures.Inc()
+				log.Printf("Failed to renew lock on ticket %d: %v", ticketID, err)
+				continue
+			}
+			if !renewed {
+				metrics.LockRenewalFailures.Inc()
+				log.Printf("Lock on ticket %d was lost before it could be renewed", ticketID)
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends every renewal and waits for them to finish
+func (k *LockKeeper) Stop() {
+	k.cancel()
+	k.wg.Wait()
+}
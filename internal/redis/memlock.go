@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Lock backends reported by LockBackendMode
+const (
+	LockBackendRedis  = "redis"
+	LockBackendMemory = "memory"
+)
+
+// InMemoryLocker holds ticket locks in process while Redis is unreachable,
+// with the same set-if-absent and expiry semantics as the Redis keys.
+//
+// The locks are not shared with other replicas: two replicas in fallback
+// can lock the same ticket. The conditional ticket status update in the
+// reservation transaction remains the guard against double booking.
+//
+// The booking service only falls back to it while its Redis circuit breaker
+// is closed; once the breaker opens, reservations take Postgres advisory
+// locks instead and stop waiting on Redis.
+type InMemoryLocker struct {
+	locks sync.Map // ticket ID -> memLock
+}
+
+type memLock struct {
+	token     string
+	expiresAt time.Time
+}
+
+func (l memLock) live(now time.Time) bool {
+	return now.Before(l.expiresAt)
+}
+
+// NewInMemoryLocker returns an empty locker
+func NewInMemoryLocker() *InMemoryLocker {
+	return &InMemoryLocker{}
+}
+
+// Lock sets the lock to token for ttl unless a live lock exists
+func (l *InMemoryLocker) Lock(ticketID uint, token string, ttl time.Duration) bool {
+	next := memLock{token: token, expiresAt: time.Now().Add(ttl)}
+	for {
+		current, loaded := l.locks.LoadOrStore(ticketID, next)
+		if !loaded {
+			return true
+		}
+		if current.(memLock).live(time.Now()) {
+			return false
+		}
+		// Replace the expired lock unless someone else just did
+		if l.locks.CompareAndSwap(ticketID, current, next) {
+			return true
+		}
+	}
+}
+
+// LockAll locks every ticket with token for ttl, or none of them. It
+// returns the tickets that already had a live lock, empty on success. A
+// concurrent Lock may briefly see part of a group that is then rolled back.
+func (l *InMemoryLocker) LockAll(ticketIDs []uint, token string, ttl time.Duration) []uint {
+	var locked, contended []uint
+	for _, ticketID := range ticketIDs {
+		if l.Lock(ticketID, token, ttl) {
+			locked = append(locked, ticketID)
+		} else {
+			contended = append(contended, ticketID)
+		}
+	}
+	if len(contended) > 0 {
+		for _, ticketID := range locked {
+			l.Unlock(ticketID, token)
+		}
+	}
+	return contended
+}
+
+// Unlock deletes the lock if it still holds token
+func (l *InMemoryLocker) Unlock(ticketID uint, token string) bool {
+	current, ok := l.locks.Load(ticketID)
+	if !ok || current.(memLock).token != token {
+		return false
+	}
+	return l.locks.CompareAndDelete(ticketID, current)
+}
+
+// Token returns the token of the live lock on the ticket, or ""
+func (l *InMemoryLocker) Token(ticketID uint) string {
+	current, ok := l.locks.Load(ticketID)
+	if !ok {
+		return ""
+	}
+	lock := current.(memLock)
+	if !lock.live(time.Now()) {
+		l.locks.CompareAndDelete(ticketID, current)
+		return ""
+	}
+	return lock.token
+}
+
+// Renew extends a live lock held with token to at least ttl from now
+func (l *InMemoryLocker) Renew(ticketID uint, token string, ttl time.Duration) bool {
+	for {
+		current, ok := l.locks.Load(ticketID)
+		if !ok {
+			return false
+		}
+		lock := current.(memLock)
+		if lock.token != token || !lock.live(time.Now()) {
+			return false
+		}
+		expiresAt := time.Now().Add(ttl)
+		if !expiresAt.After(lock.expiresAt) {
+			return true
+		}
+		if l.locks.CompareAndSwap(ticketID, current, memLock{token: token, expiresAt: expiresAt}) {
+			return true
+		}
+	}
+}
+
+// isConnectionError reports whether err means Redis could not be reached,
+// as opposed to Redis answering with an error
+func isConnectionError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, redis.ErrClosed)
+}
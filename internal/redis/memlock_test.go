@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryLockerSetIfAbsent(t *testing.T) {
+	locker := NewInMemoryLocker()
+
+	if !locker.Lock(1, "a", time.Minute) {
+		t.Fatal("failed to lock a free ticket")
+	}
+	if locker.Lock(1, "b", time.Minute) {
+		t.Fatal("locked a ticket that is already held")
+	}
+	if locker.Unlock(1, "b") {
+		t.Fatal("unlocked with another holder's token")
+	}
+	if got := locker.Token(1); got != "a" {
+		t.Fatalf("lock holds %q, want a", got)
+	}
+	if !locker.Unlock(1, "a") || locker.Token(1) != "" {
+		t.Fatal("holder failed to unlock")
+	}
+}
+
+func TestInMemoryLockerExpiry(t *testing.T) {
+	locker := NewInMemoryLocker()
+
+	locker.Lock(1, "a", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if locker.Token(1) != "" {
+		t.Fatal("expired lock is still reported")
+	}
+	if locker.Renew(1, "a", time.Minute) {
+		t.Fatal("renewed an expired lock")
+	}
+	if !locker.Lock(1, "b", time.Minute) {
+		t.Fatal("failed to take over an expired lock")
+	}
+	if !locker.Renew(1, "b", 2*time.Minute) || locker.Renew(1, "a", time.Minute) {
+		t.Fatal("renew did not follow the holder's token")
+	}
+}
+
+func TestInMemoryLockerConcurrentLock(t *testing.T) {
+	locker := NewInMemoryLocker()
+
+	var winners atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if locker.Lock(1, fmt.Sprint(i), time.Minute) {
+				winners.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := winners.Load(); n != 1 {
+		t.Fatalf("%d goroutines locked the same ticket, want 1", n)
+	}
+}
+
+func TestLockTicketFallsBackToMemory(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.Close()
+	if _, err := client.LockTicket(ctx, 1, 1, time.Minute); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v locking with Redis down, want ErrUnavailable", err)
+	}
+	token, err := client.LockTicketInMemory(ctx, 1, 1, time.Minute)
+	if err != nil {
+		t.Fatalf("in-memory lock with Redis down failed: %v", err)
+	}
+	if mode := client.LockBackendMode(); mode != LockBackendMemory {
+		t.Errorf("backend is %s with Redis down, want %s", mode, LockBackendMemory)
+	}
+	if _, err := client.LockTicket(ctx, 1, 2, time.Minute); !errors.Is(err, ErrTicketLocked) {
+		t.Fatalf("second user locked a ticket held in memory: %v", err)
+	}
+	if _, err := client.LockTicketInMemory(ctx, 1, 2, time.Minute); !errors.Is(err, ErrTicketLocked) {
+		t.Fatalf("second user locked a ticket held in memory: %v", err)
+	}
+
+	// Once Redis is back the lock taken during the outage still holds
+	if err := server.Restart(); err != nil {
+		t.Fatalf("failed to restart miniredis: %v", err)
+	}
+	if _, err := client.LockTicket(ctx, 1, 2, time.Minute); !errors.Is(err, ErrTicketLocked) {
+		t.Fatalf("second user locked the ticket in Redis over the in-memory lock: %v", err)
+	}
+	if _, err := client.LockTicket(ctx, 2, 2, time.Minute); err != nil {
+		t.Fatalf("lock after recovery failed: %v", err)
+	}
+	if mode := client.LockBackendMode(); mode != LockBackendRedis {
+		t.Errorf("backend is %s after recovery, want %s", mode, LockBackendRedis)
+	}
+
+	if err := client.UnlockTicket(ctx, 1, token); err != nil {
+		t.Fatalf("unlock of the in-memory lock failed: %v", err)
+	}
+	if _, err := client.LockTicket(ctx, 1, 2, time.Minute); err != nil {
+		t.Fatalf("ticket still locked after the in-memory lock was released: %v", err)
+	}
+}
+
+func TestLockTicketsHonoursMemoryLocks(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.Close()
+	if _, err := client.LockTickets(ctx, []uint{1, 2}, 1, time.Minute); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("got %v locking a group with Redis down, want ErrUnavailable", err)
+	}
+	if _, err := client.LockTicketInMemory(ctx, 2, 1, time.Minute); err != nil {
+		t.Fatalf("in-memory lock failed: %v", err)
+	}
+
+	// The group fails as a whole and leaves its free ticket unlocked
+	_, err := client.LockTicketsInMemory(ctx, []uint{1, 2}, 2, time.Minute)
+	var contended *ContendedTicketsError
+	if !errors.As(err, &contended) || len(contended.TicketIDs) != 1 || contended.TicketIDs[0] != 2 {
+		t.Fatalf("got %v locking a group over an in-memory lock, want ticket 2 contended", err)
+	}
+	token, err := client.LockTicketsInMemory(ctx, []uint{1, 3}, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("in-memory group lock failed: %v", err)
+	}
+
+	// Back on Redis, the locks taken during the outage still hold
+	if err := server.Restart(); err != nil {
+		t.Fatalf("failed to restart miniredis: %v", err)
+	}
+	_, err = client.LockTickets(ctx, []uint{2, 4}, 3, time.Minute)
+	if !errors.As(err, &contended) || len(contended.TicketIDs) != 1 || contended.TicketIDs[0] != 2 {
+		t.Fatalf("got %v locking a group over an in-memory lock, want ticket 2 contended", err)
+	}
+	if err := client.UnlockTickets(ctx, []uint{1, 3}, token); err != nil {
+		t.Fatalf("unlock of the in-memory group failed: %v", err)
+	}
+	if _, err := client.LockTickets(ctx, []uint{1, 3}, 3, time.Minute); err != nil {
+		t.Fatalf("group still locked after the in-memory locks were released: %v", err)
+	}
+}
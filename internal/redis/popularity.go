@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// popularityKey is a sorted set of event IDs scored by interest
+const popularityKey = "events:popularity"
+
+// How much each kind of interest adds to an event's popularity score
+const (
+	PopularitySearchHit    = 1.0
+	PopularityReservation  = 5.0
+	PopularityConfirmation = 10.0
+)
+
+// IncrEventScore adds delta to an event's popularity score
+func (c *Client) IncrEventScore(ctx context.Context, eventID uint, delta float64) error {
+	if err := c.rdb.ZIncrBy(ctx, popularityKey, delta, strconv.FormatUint(uint64(eventID), 10)).Err(); err != nil {
+		return fmt.Errorf("failed to increment event score: %w", err)
+	}
+	return nil
+}
+
+// GetTopEvents returns the IDs of the limit most popular events, most
+// popular first
+func (c *Client) GetTopEvents(ctx context.Context, limit int) ([]uint, error) {
+	members, err := c.rdb.ZRevRange(ctx, popularityKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event popularity: %w", err)
+	}
+
+	eventIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		eventID, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		eventIDs = append(eventIDs, uint(eventID))
+	}
+	return eventIDs, nil
+}
@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	// ErrNotInQueue means the user is not waiting in the queue
+	ErrNotInQueue = errors.New("user is not in the waiting queue")
+
+	// ErrQueueEmpty means nobody is waiting in the queue
+	ErrQueueEmpty = errors.New("waiting queue is empty")
+)
+
+// migrateWaitingQueueScript converts a queue stored by the old list
+// implementation into a sorted set. List entries were LPUSHed, so the
+// oldest is at the tail; they get scores just below ARGV[1] in join order,
+// keeping them ahead of anyone who joins after the migration. Duplicates
+// keep their earliest position.
+var migrateWaitingQueueScript = redis.NewScript(`
+if redis.call("TYPE", KEYS[1]).ok ~= "list" then
+	return 0
+end
+local members = redis.call("LRANGE", KEYS[1], 0, -1)
+redis.call("DEL", KEYS[1])
+local now = tonumber(ARGV[1])
+local count = #members
+for i = count, 1, -1 do
+	redis.call("ZADD", KEYS[1], "NX", now - i, members[i])
+end
+return count
+`)
+
+// waitingQueueKey returns the queue key of an event, migrating a queue left
+// in the old list format first
+func (c *Client) waitingQueueKey(ctx context.Context, eventID uint) (string, error) {
+	key := fmt.Sprintf("waiting_queue:%d", eventID)
+	if err := migrateWaitingQueueScript.Run(ctx, c.rdb, []string{key}, time.Now().UnixMilli()).Err(); err != nil {
+		return "", fmt.Errorf("failed to migrate waiting queue: %w", err)
+	}
+	return key, nil
+}
+
+// AddToWaitingQueue adds a user to the virtual waiting queue, ordered by
+// join time. Joining again keeps the user's original place.
+func (c *Client) AddToWaitingQueue(ctx context.Context, eventID uint, userID uint) error {
+	key, err := c.waitingQueueKey(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	member := &redis.Z{Score: float64(time.Now().UnixMilli()), Member: strconv.FormatUint(uint64(userID), 10)}
+	if err := c.rdb.ZAddNX(ctx, key, member).Err(); err != nil {
+		return fmt.Errorf("failed to join waiting queue: %w", err)
+	}
+	return nil
+}
+
+// GetWaitingQueuePosition returns the user's zero-based position in the
+// waiting queue, or ErrNotInQueue
+func (c *Client) GetWaitingQueuePosition(ctx context.Context, eventID uint, userID uint) (int64, error) {
+	key, err := c.waitingQueueKey(ctx, eventID)
+	if err != nil {
+		return -1, err
+	}
+
+	position, err := c.rdb.ZRank(ctx, key, strconv.FormatUint(uint64(userID), 10)).Result()
+	if err == redis.Nil {
+		return -1, ErrNotInQueue
+	}
+	if err != nil {
+		return -1, fmt.Errorf("failed to read queue position: %w", err)
+	}
+	return position, nil
+}
+
+// RemoveFromWaitingQueue takes a user out of the waiting queue; removing a
+// user who is not waiting is not an error
+func (c *Client) RemoveFromWaitingQueue(ctx context.Context, eventID uint, userID uint) error {
+	key, err := c.waitingQueueKey(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.rdb.ZRem(ctx, key, strconv.FormatUint(uint64(userID), 10)).Err(); err != nil {
+		return fmt.Errorf("failed to leave waiting queue: %w", err)
+	}
+	return nil
+}
+
+// QueueLength returns how many users are waiting for the event
+func (c *Client) QueueLength(ctx context.Context, eventID uint) (int64, error) {
+	key, err := c.waitingQueueKey(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	length, err := c.rdb.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read queue length: %w", err)
+	}
+	c.metrics.QueueLength(eventID, length)
+	return length, nil
+}
+
+// ProcessWaitingQueue atomically removes and returns the user who has waited
+// longest, or ErrQueueEmpty
+func (c *Client) ProcessWaitingQueue(ctx context.Context, eventID uint) (uint, error) {
+	key, err := c.waitingQueueKey(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+
+	popped, err := c.rdb.ZPopMin(ctx, key, 1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to pop waiting queue: %w", err)
+	}
+	if len(popped) == 0 {
+		return 0, ErrQueueEmpty
+	}
+	// Scores are join times in Unix milliseconds
+	c.metrics.QueueWait(time.Since(time.UnixMilli(int64(popped[0].Score))))
+
+	member, _ := popped[0].Member.(string)
+	userID, err := strconv.ParseUint(member, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID in queue: %w", err)
+	}
+	return uint(userID), nil
+}
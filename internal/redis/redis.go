@@ -2,57 +2,377 @@ package redis
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/metrics"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
 	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
 )
 
+var (
+	// ErrTicketLocked means another user holds the ticket lock
+	ErrTicketLocked = errors.New("ticket is already locked")
+
+	// ErrLockNotFound means the ticket has no active lock
+	ErrLockNotFound = errors.New("ticket lock not found")
+
+	// ErrUnavailable means Redis could not be reached, as opposed to
+	// answering with an error
+	ErrUnavailable = errors.New("redis is unreachable")
+)
+
+// TicketLockTTL is the default reservation window: how long a ticket lock
+// lives unless renewed
+const TicketLockTTL = 10 * time.Minute
+
 type Client struct {
-	rdb *redis.Client
+	rdb        *redis.Client
+	keyPrefix  string
+	signingKey []byte               // Signs admission tokens
+	metrics    *metrics.LockMetrics // nil records nothing
+	flight     singleflight.Group
+
+	// fallback holds the locks LockTicketInMemory takes while Redis is
+	// unreachable; memoryMode is set while the last lock attempt used it
+	fallback   *InMemoryLocker
+	memoryMode atomic.Bool
 }
 
-func NewClient(cfg *config.Config) *Client {
+// NewClient connects to Redis and pings it, so services fail fast at
+// startup instead of on their first request. A zero pool size keeps the
+// go-redis default.
+func NewClient(cfg *config.Config) (*Client, error) {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
-		Password: cfg.RedisPassword,
-		DB:       0,
+		Addr:            fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort),
+		Password:        cfg.RedisPassword,
+		DB:              0,
+		PoolSize:        cfg.RedisPoolSize,
+		MinIdleConns:    cfg.RedisMinIdleConns,
+		DialTimeout:     cfg.RedisDialTimeout,
+		ReadTimeout:     cfg.RedisReadTimeout,
+		WriteTimeout:    cfg.RedisWriteTimeout,
+		MaxRetries:      cfg.RedisMaxRetries,
+		MinRetryBackoff: cfg.RedisMinRetryBackoff,
+		MaxRetryBackoff: cfg.RedisMaxRetryBackoff,
 	})
 
-	return &Client{rdb: rdb}
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", rdb.Options().Addr, err)
+	}
+
+	return &Client{
+		rdb:        rdb,
+		keyPrefix:  cfg.RedisKeyPrefix,
+		signingKey: []byte(cfg.AdmissionSigningKey),
+		fallback:   NewInMemoryLocker(),
+	}, nil
+}
+
+// UseMetrics reports lock and queue activity to m
+func (c *Client) UseMetrics(m *metrics.LockMetrics) {
+	c.metrics = m
 }
 
 func (c *Client) Ping(ctx context.Context) error {
 	return c.rdb.Ping(ctx).Err()
 }
 
-// LockTicket reserves a ticket for 10 minutes
-func (c *Client) LockTicket(ctx context.Context, ticketID uint, userID uint) error {
+// NewLockToken creates a lock token for a user. It starts with the user ID
+// and ends with the time the lock was taken, in Unix milliseconds.
+func NewLockToken(userID uint) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return fmt.Sprintf("%d:%s:%d", userID, hex.EncodeToString(random), time.Now().UnixMilli()), nil
+}
+
+// lockHeldFor returns how long ago the lock with token was taken; tokens
+// issued before they carried a timestamp report false
+func lockHeldFor(token string) (time.Duration, bool) {
+	parts := strings.Split(token, ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	takenAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Since(time.UnixMilli(takenAt)), true
+}
+
+// LockTicket reserves a ticket for ttl. It returns the lock token the holder
+// must present to UnlockTicket; the token starts with the user ID. When
+// Redis cannot be reached the error wraps ErrUnavailable, and the caller
+// may take the lock through LockTicketInMemory instead.
+func (c *Client) LockTicket(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error) {
 	key := fmt.Sprintf("ticket_lock:%d", ticketID)
-	value := fmt.Sprintf("%d", userID)
+
+	token, err := NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	// A lock taken in process during an outage still holds the ticket
+	if c.fallback.Token(ticketID) != "" {
+		c.metrics.LockAttempt(metrics.LockConflict, 1)
+		return "", fmt.Errorf("ticket %d: %w", ticketID, ErrTicketLocked)
+	}
 
 	// Try to set the lock with expiration
-	result := c.rdb.SetNX(ctx, key, value, 10*time.Minute)
+	result := c.rdb.SetNX(ctx, key, token, ttl)
 	if result.Err() != nil {
-		return fmt.Errorf("failed to lock ticket: %w", result.Err())
+		c.metrics.LockAttempt(metrics.LockError, 1)
+		return "", lockError("failed to lock ticket", result.Err())
 	}
+	c.memoryMode.Store(false)
 
 	if !result.Val() {
-		return fmt.Errorf("ticket %d is already locked", ticketID)
+		c.metrics.LockAttempt(metrics.LockConflict, 1)
+		c.recordContention(ctx, ticketID)
+		return "", fmt.Errorf("ticket %d: %w", ticketID, ErrTicketLocked)
 	}
 
-	return nil
+	c.metrics.LockAttempt(metrics.LockAcquired, 1)
+	return token, nil
+}
+
+// lockError wraps a failed lock attempt, marking it ErrUnavailable when
+// Redis could not be reached
+func lockError(msg string, err error) error {
+	if isConnectionError(err) {
+		return fmt.Errorf("%s: %w: %w", msg, ErrUnavailable, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// LockTicketInMemory takes the ticket lock in process, for callers that
+// found Redis unreachable. UnlockTicket, RenewTicketLock and the Redis lock
+// calls all honour it until it expires or is released.
+func (c *Client) LockTicketInMemory(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error) {
+	token, err := NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	c.memoryMode.Store(true)
+	if !c.fallback.Lock(ticketID, token, ttl) {
+		c.metrics.LockAttempt(metrics.LockConflict, 1)
+		return "", fmt.Errorf("ticket %d: %w", ticketID, ErrTicketLocked)
+	}
+	c.metrics.LockAttempt(metrics.LockAcquired, 1)
+	return token, nil
+}
+
+// LockBackendMode reports where ticket locks are being taken: "redis", or
+// "memory" while Redis is unreachable
+func (c *Client) LockBackendMode() string {
+	if c.memoryMode.Load() {
+		return LockBackendMemory
+	}
+	return LockBackendRedis
 }
 
-// UnlockTicket releases a ticket lock
-func (c *Client) UnlockTicket(ctx context.Context, ticketID uint) error {
+// unlockTicketScript deletes a ticket lock only if it still holds the token
+var unlockTicketScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// UnlockTicket releases a ticket lock if it is still held with token. A lock
+// that expired and was taken by someone else is left alone.
+func (c *Client) UnlockTicket(ctx context.Context, ticketID uint, token string) error {
+	if token == "" {
+		return nil
+	}
+	if c.fallback.Unlock(ticketID, token) {
+		if held, ok := lockHeldFor(token); ok {
+			c.metrics.LockHeld(held)
+		}
+		return nil
+	}
 	key := fmt.Sprintf("ticket_lock:%d", ticketID)
-	return c.rdb.Del(ctx, key).Err()
+	released, err := unlockTicketScript.Run(ctx, c.rdb, []string{key}, token).Int64()
+	if err != nil {
+		return err
+	}
+	if held, ok := lockHeldFor(token); ok && released > 0 {
+		c.metrics.LockHeld(held)
+	}
+	return nil
+}
+
+// ContendedTicketsError reports the tickets of a multi-ticket lock that were
+// already held by someone else. It matches ErrTicketLocked with errors.Is.
+type ContendedTicketsError struct {
+	TicketIDs []uint
+}
+
+func (e *ContendedTicketsError) Error() string {
+	return fmt.Sprintf("tickets %v: %v", e.TicketIDs, ErrTicketLocked)
+}
+
+func (e *ContendedTicketsError) Unwrap() error {
+	return ErrTicketLocked
+}
+
+// lockTicketsScript sets every key to the token with the TTL only if none
+// of them exist. It returns the (1-based) positions of the taken keys, or an
+// empty list once all are set.
+var lockTicketsScript = redis.NewScript(`
+local taken = {}
+for i, key in ipairs(KEYS) do
+	if redis.call("EXISTS", key) == 1 then
+		table.insert(taken, i)
+	end
+end
+if #taken > 0 then
+	return taken
+end
+for _, key in ipairs(KEYS) do
+	redis.call("SET", key, ARGV[1], "PX", ARGV[2])
+end
+return taken
+`)
+
+// unlockTicketsScript deletes the keys that still hold the token
+var unlockTicketsScript = redis.NewScript(`
+local released = 0
+for _, key in ipairs(KEYS) do
+	if redis.call("GET", key) == ARGV[1] then
+		released = released + redis.call("DEL", key)
+	end
+end
+return released
+`)
+
+// LockTickets locks a group of tickets all-or-nothing, with one token and
+// TTL for the whole group. If any ticket is already locked, in Redis or in
+// process, nothing is set and a *ContendedTicketsError lists the taken
+// tickets. The returned token releases the group through UnlockTickets.
+// When Redis cannot be reached the error wraps ErrUnavailable, and the
+// caller may lock the group through LockTicketsInMemory instead.
+func (c *Client) LockTickets(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error) {
+	if len(ticketIDs) == 0 {
+		return "", nil
+	}
+
+	token, err := NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	// Locks taken in process during an outage still hold their tickets
+	var held []uint
+	for _, ticketID := range ticketIDs {
+		if c.fallback.Token(ticketID) != "" {
+			held = append(held, ticketID)
+		}
+	}
+	if len(held) > 0 {
+		c.metrics.LockAttempt(metrics.LockConflict, len(ticketIDs))
+		return "", &ContendedTicketsError{TicketIDs: held}
+	}
+
+	keys := ticketLockKeys(ticketIDs)
+	taken, err := lockTicketsScript.Run(ctx, c.rdb, keys, token, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		c.metrics.LockAttempt(metrics.LockError, len(ticketIDs))
+		return "", lockError("failed to lock tickets", err)
+	}
+	c.memoryMode.Store(false)
+
+	if len(taken) > 0 {
+		contended := make([]uint, len(taken))
+		for i, position := range taken {
+			contended[i] = ticketIDs[position-1]
+			c.recordContention(ctx, contended[i])
+		}
+		c.metrics.LockAttempt(metrics.LockConflict, len(ticketIDs))
+		return "", &ContendedTicketsError{TicketIDs: contended}
+	}
+
+	c.metrics.LockAttempt(metrics.LockAcquired, len(ticketIDs))
+	return token, nil
+}
+
+// LockTicketsInMemory locks a group of tickets all-or-nothing in process,
+// for callers that found Redis unreachable
+func (c *Client) LockTicketsInMemory(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error) {
+	if len(ticketIDs) == 0 {
+		return "", nil
+	}
+
+	token, err := NewLockToken(userID)
+	if err != nil {
+		return "", err
+	}
+
+	c.memoryMode.Store(true)
+	if contended := c.fallback.LockAll(ticketIDs, token, ttl); len(contended) > 0 {
+		c.metrics.LockAttempt(metrics.LockConflict, len(ticketIDs))
+		return "", &ContendedTicketsError{TicketIDs: contended}
+	}
+	c.metrics.LockAttempt(metrics.LockAcquired, len(ticketIDs))
+	return token, nil
+}
+
+// UnlockTickets releases the tickets of a group lock that still hold token
+func (c *Client) UnlockTickets(ctx context.Context, ticketIDs []uint, token string) error {
+	if len(ticketIDs) == 0 || token == "" {
+		return nil
+	}
+
+	// A group locked in process is released without Redis
+	var released int64
+	for _, ticketID := range ticketIDs {
+		if c.fallback.Unlock(ticketID, token) {
+			released++
+		}
+	}
+	if released == 0 {
+		var err error
+		released, err = unlockTicketsScript.Run(ctx, c.rdb, ticketLockKeys(ticketIDs), token).Int64()
+		if err != nil {
+			return fmt.Errorf("failed to unlock tickets: %w", err)
+		}
+	}
+	if held, ok := lockHeldFor(token); ok {
+		for i := int64(0); i < released; i++ {
+			c.metrics.LockHeld(held)
+		}
+	}
+	return nil
+}
+
+func ticketLockKeys(ticketIDs []uint) []string {
+	keys := make([]string, len(ticketIDs))
+	for i, ticketID := range ticketIDs {
+		keys[i] = fmt.Sprintf("ticket_lock:%d", ticketID)
+	}
+	return keys
 }
 
 // IsTicketLocked checks if a ticket is currently locked
 func (c *Client) IsTicketLocked(ctx context.Context, ticketID uint) (bool, error) {
+	if c.fallback.Token(ticketID) != "" {
+		return true, nil
+	}
 	key := fmt.Sprintf("ticket_lock:%d", ticketID)
 	result := c.rdb.Exists(ctx, key)
 	if result.Err() != nil {
@@ -63,59 +383,338 @@ func (c *Client) IsTicketLocked(ctx context.Context, ticketID uint) (bool, error
 
 // GetTicketLockOwner returns the user ID who locked the ticket
 func (c *Client) GetTicketLockOwner(ctx context.Context, ticketID uint) (uint, error) {
-	key := fmt.Sprintf("ticket_lock:%d", ticketID)
-	result := c.rdb.Get(ctx, key)
-	if result.Err() != nil {
-		return 0, result.Err()
+	token, err := c.TicketLockToken(ctx, ticketID)
+	if err != nil {
+		return 0, err
+	}
+	if token == "" {
+		return 0, ErrLockNotFound
 	}
+	return LockTokenOwner(token)
+}
 
+// LockTokenOwner returns the ID of the user a lock token was issued to
+func LockTokenOwner(token string) (uint, error) {
 	var userID uint
-	if _, err := fmt.Sscanf(result.Val(), "%d", &userID); err != nil {
+	if _, err := fmt.Sscanf(token, "%d", &userID); err != nil {
 		return 0, fmt.Errorf("invalid user ID in lock: %w", err)
 	}
-
 	return userID, nil
 }
 
-// ExtendTicketLock extends the lock expiration by 10 minutes
+// ExtendTicketLock resets the lock expiration to TicketLockTTL
 func (c *Client) ExtendTicketLock(ctx context.Context, ticketID uint) error {
 	key := fmt.Sprintf("ticket_lock:%d", ticketID)
-	return c.rdb.Expire(ctx, key, 10*time.Minute).Err()
+	return c.rdb.Expire(ctx, key, TicketLockTTL).Err()
 }
 
-// AddToWaitingQueue adds a user to the virtual waiting queue
-func (c *Client) AddToWaitingQueue(ctx context.Context, eventID uint, userID uint) error {
-	key := fmt.Sprintf("waiting_queue:%d", eventID)
-	return c.rdb.LPush(ctx, key, fmt.Sprintf("%d", userID)).Err()
+// TicketLockToken returns the token the ticket is locked with, or "" if it
+// is not locked
+func (c *Client) TicketLockToken(ctx context.Context, ticketID uint) (string, error) {
+	if token := c.fallback.Token(ticketID); token != "" {
+		return token, nil
+	}
+	key := fmt.Sprintf("ticket_lock:%d", ticketID)
+	token, err := c.rdb.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read ticket lock: %w", err)
+	}
+	return token, nil
 }
 
-// GetWaitingQueuePosition returns the position of a user in the waiting queue
-func (c *Client) GetWaitingQueuePosition(ctx context.Context, eventID uint, userID uint) (int64, error) {
-	key := fmt.Sprintf("waiting_queue:%d", eventID)
-	result := c.rdb.LPos(ctx, key, fmt.Sprintf("%d", userID), redis.LPosArgs{})
-	if result.Err() != nil {
-		return -1, result.Err()
+// renewTicketLockScript extends a ticket lock held with the token to at
+// least ARGV[2] milliseconds, never shortening a longer reservation window
+var renewTicketLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if redis.call("PTTL", KEYS[1]) < tonumber(ARGV[2]) then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// RenewTicketLock extends the lock to live at least ttl longer, returning
+// false if the lock is no longer held with token
+func (c *Client) RenewTicketLock(ctx context.Context, ticketID uint, token string, ttl time.Duration) (bool, error) {
+	if c.fallback.Renew(ticketID, token, ttl) {
+		return true, nil
 	}
-	return result.Val(), nil
+	key := fmt.Sprintf("ticket_lock:%d", ticketID)
+	renewed, err := renewTicketLockScript.Run(ctx, c.rdb, []string{key}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew ticket lock: %w", err)
+	}
+	return renewed == 1, nil
 }
 
-// ProcessWaitingQueue processes the next user in the waiting queue
-func (c *Client) ProcessWaitingQueue(ctx context.Context, eventID uint) (uint, error) {
-	key := fmt.Sprintf("waiting_queue:%d", eventID)
-	result := c.rdb.RPop(ctx, key)
-	if result.Err() != nil {
-		return 0, result.Err()
+// reserveTierSlotScript seeds the tier counter from the database value on
+// first use, then decrements it only while slots remain
+var reserveTierSlotScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+if tonumber(redis.call("GET", KEYS[1])) <= 0 then
+	return -1
+end
+return redis.call("DECR", KEYS[1])
+`)
+
+// ReserveTierSlot atomically claims one ticket from a tier's flash-sale
+// counter. It returns false when the tier is sold out.
+func (c *Client) ReserveTierSlot(ctx context.Context, tierID uint, available int) (bool, error) {
+	key := fmt.Sprintf("tier_available:%d", tierID)
+	remaining, err := reserveTierSlotScript.Run(ctx, c.rdb, []string{key}, available).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve tier slot: %w", err)
 	}
+	return remaining >= 0, nil
+}
 
-	var userID uint
-	if _, err := fmt.Sscanf(result.Val(), "%d", &userID); err != nil {
-		return 0, fmt.Errorf("invalid user ID in queue: %w", err)
+// ReleaseTierSlot returns a ticket to a tier's flash-sale counter
+func (c *Client) ReleaseTierSlot(ctx context.Context, tierID uint) error {
+	key := fmt.Sprintf("tier_available:%d", tierID)
+	// Only increment an initialized counter; a missing key is re-seeded from the database
+	if c.rdb.Exists(ctx, key).Val() == 0 {
+		return nil
 	}
+	return c.rdb.Incr(ctx, key).Err()
+}
 
-	return userID, nil
+// ResetTierSlots drops a tier's flash-sale counter so the next reservation
+// re-seeds it from the database, e.g. after reservations were taken
+// without Redis
+func (c *Client) ResetTierSlots(ctx context.Context, tierID uint) error {
+	return c.rdb.Del(ctx, fmt.Sprintf("tier_available:%d", tierID)).Err()
 }
 
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	return c.rdb.Close()
 }
+
+// ChangesChannel is the pub/sub channel services publish event changes on
+// so the CDC service can re-index without waiting for its next poll
+const ChangesChannel = "cdc:events"
+
+// ChangeMessage announces that an event's search document is stale
+type ChangeMessage struct {
+	EventID uint   `json:"eventId"`
+	Op      string `json:"op"`
+}
+
+// PublishChange publishes a change notification on ChangesChannel
+func (c *Client) PublishChange(ctx context.Context, msg ChangeMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change message: %w", err)
+	}
+	if err := c.rdb.Publish(ctx, ChangesChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish change: %w", err)
+	}
+	return nil
+}
+
+// SubscribeChanges delivers change notifications until ctx is done.
+// Malformed messages are skipped. The channel is closed when the
+// subscription ends.
+func (c *Client) SubscribeChanges(ctx context.Context) <-chan ChangeMessage {
+	pubsub := c.rdb.Subscribe(ctx, ChangesChannel)
+	changes := make(chan ChangeMessage)
+
+	go func() {
+		defer close(changes)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var change ChangeMessage
+				if err := json.Unmarshal([]byte(message.Payload), &change); err != nil || change.EventID == 0 {
+					continue
+				}
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return changes
+}
+
+// TicketStatus is the cached availability of one ticket
+type TicketStatus struct {
+	ID      uint         `json:"id"`
+	EventID uint         `json:"eventId"`
+	Seat    string       `json:"seat"`
+	Status  string       `json:"status"`
+	Price   money.Amount `json:"price"`
+}
+
+// GetTicketStatuses returns the cached statuses of the given tickets in one
+// round trip. Tickets missing from the cache are absent from the map.
+func (c *Client) GetTicketStatuses(ctx context.Context, ticketIDs []uint) (map[uint]TicketStatus, error) {
+	if len(ticketIDs) == 0 {
+		return map[uint]TicketStatus{}, nil
+	}
+
+	values, err := c.rdb.MGet(ctx, ticketStatusKeys(ticketIDs)...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket statuses: %w", err)
+	}
+
+	statuses := make(map[uint]TicketStatus, len(values))
+	for _, value := range values {
+		encoded, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var status TicketStatus
+		if err := json.Unmarshal([]byte(encoded), &status); err != nil {
+			continue
+		}
+		statuses[status.ID] = status
+	}
+	return statuses, nil
+}
+
+// SetTicketStatuses caches ticket statuses with a TTL in one pipelined round trip
+func (c *Client) SetTicketStatuses(ctx context.Context, statuses []TicketStatus, ttl time.Duration) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, status := range statuses {
+			encoded, err := json.Marshal(status)
+			if err != nil {
+				return fmt.Errorf("failed to marshal ticket status: %w", err)
+			}
+			pipe.Set(ctx, fmt.Sprintf("ticket_status:%d", status.ID), encoded, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cache ticket statuses: %w", err)
+	}
+	return nil
+}
+
+// InvalidateTicketStatuses drops cached statuses after tickets change
+func (c *Client) InvalidateTicketStatuses(ctx context.Context, ticketIDs ...uint) error {
+	if len(ticketIDs) == 0 {
+		return nil
+	}
+	if err := c.rdb.Del(ctx, ticketStatusKeys(ticketIDs)...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate ticket statuses: %w", err)
+	}
+	return nil
+}
+
+func ticketStatusKeys(ticketIDs []uint) []string {
+	keys := make([]string, len(ticketIDs))
+	for i, ticketID := range ticketIDs {
+		keys[i] = fmt.Sprintf("ticket_status:%d", ticketID)
+	}
+	return keys
+}
+
+// ErrSessionNotFound means the session expired or was deleted
+var ErrSessionNotFound = errors.New("session not found")
+
+// SetSession stores an encoded session under its ID
+func (c *Client) SetSession(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error {
+	key := fmt.Sprintf("session:%s", sessionID)
+	if err := c.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// GetSession returns an encoded session, or ErrSessionNotFound
+func (c *Client) GetSession(ctx context.Context, sessionID string) ([]byte, error) {
+	key := fmt.Sprintf("session:%s", sessionID)
+	data, err := c.rdb.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	return data, nil
+}
+
+// DeleteSession removes a session; deleting a missing session is not an error
+func (c *Client) DeleteSession(ctx context.Context, sessionID string) error {
+	key := fmt.Sprintf("session:%s", sessionID)
+	if err := c.rdb.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// renewLeaseScript extends a lease only while it is still held by the caller
+var renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaseScript deletes a lease only while it is still held by the caller
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLease takes the lease at key for holder if nobody holds it
+func (c *Client) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	acquired, err := c.rdb.SetNX(ctx, key, holder, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	return acquired, nil
+}
+
+// RenewLease resets the lease TTL, returning false if holder no longer holds it
+func (c *Client) RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, c.rdb, []string{key}, holder, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return renewed == 1, nil
+}
+
+// ReleaseLease gives up the lease if holder still holds it
+func (c *Client) ReleaseLease(ctx context.Context, key, holder string) error {
+	if err := releaseLeaseScript.Run(ctx, c.rdb, []string{key}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// LeaseHolder returns the current holder of the lease, or "" if it is free
+func (c *Client) LeaseHolder(ctx context.Context, key string) (string, error) {
+	holder, err := c.rdb.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read lease: %w", err)
+	}
+	return holder, nil
+}
@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestClient connects a Client to a fresh miniredis server
+func newTestClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	return connect(t, server, ""), server
+}
+
+// connect returns a Client on server that namespaces its keys with prefix
+func connect(t *testing.T, server *miniredis.Miniredis, prefix string) *Client {
+	t.Helper()
+	host, port, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("failed to parse miniredis address: %v", err)
+	}
+
+	client, err := NewClient(&config.Config{RedisHost: host, RedisPort: port, RedisKeyPrefix: prefix})
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestStaleUnlockLeavesNewLockInPlace(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	const ticketID, userA, userB = 7, 1, 2
+
+	staleToken, err := client.LockTicket(ctx, ticketID, userA, time.Minute)
+	if err != nil {
+		t.Fatalf("user A failed to lock: %v", err)
+	}
+
+	// User A's reservation lapses and user B takes the ticket
+	server.FastForward(2 * time.Minute)
+	freshToken, err := client.LockTicket(ctx, ticketID, userB, time.Minute)
+	if err != nil {
+		t.Fatalf("user B failed to lock after expiry: %v", err)
+	}
+
+	if err := client.UnlockTicket(ctx, ticketID, staleToken); err != nil {
+		t.Fatalf("stale unlock failed: %v", err)
+	}
+	owner, err := client.GetTicketLockOwner(ctx, ticketID)
+	if err != nil {
+		t.Fatalf("user B's lock is gone after user A's stale unlock: %v", err)
+	}
+	if owner != userB {
+		t.Fatalf("ticket is held by user %d, want %d", owner, userB)
+	}
+	if _, err := client.LockTicket(ctx, ticketID, userA, time.Minute); !errors.Is(err, ErrTicketLocked) {
+		t.Fatalf("user A relocked a ticket user B holds: %v", err)
+	}
+
+	// The owner's token still releases it
+	if err := client.UnlockTicket(ctx, ticketID, freshToken); err != nil {
+		t.Fatalf("user B failed to unlock: %v", err)
+	}
+	if locked, err := client.IsTicketLocked(ctx, ticketID); err != nil || locked {
+		t.Fatalf("ticket still locked after its owner unlocked it (err %v)", err)
+	}
+}
+
+func TestLockTicketsRaceOverOverlappingSeats(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	groups := [][]uint{{1, 2, 3}, {3, 4, 5}}
+
+	for round := 0; round < 50; round++ {
+		server.FlushAll()
+
+		start := make(chan struct{})
+		tokens := make([]string, len(groups))
+		errs := make([]error, len(groups))
+		var wg sync.WaitGroup
+		for i, group := range groups {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				tokens[i], errs[i] = client.LockTickets(ctx, group, uint(i+1), time.Minute)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		winner, loser := 0, 1
+		if errs[0] != nil {
+			winner, loser = 1, 0
+		}
+		if errs[winner] != nil {
+			t.Fatalf("round %d: both groups failed: %v, %v", round, errs[0], errs[1])
+		}
+		var contended *ContendedTicketsError
+		if !errors.As(errs[loser], &contended) || !errors.Is(errs[loser], ErrTicketLocked) {
+			t.Fatalf("round %d: both groups locked seat 3 (loser error %v)", round, errs[loser])
+		}
+		if len(contended.TicketIDs) != 1 || contended.TicketIDs[0] != 3 {
+			t.Fatalf("round %d: contended tickets %v, want [3]", round, contended.TicketIDs)
+		}
+
+		// The loser set nothing, so its other seats are still free
+		for _, ticketID := range groups[loser] {
+			owner, err := client.GetTicketLockOwner(ctx, ticketID)
+			if ticketID == 3 {
+				if err != nil || owner != uint(winner+1) {
+					t.Fatalf("round %d: seat 3 held by %d (err %v), want %d", round, owner, err, winner+1)
+				}
+				continue
+			}
+			if !errors.Is(err, ErrLockNotFound) {
+				t.Fatalf("round %d: loser left seat %d locked by %d", round, ticketID, owner)
+			}
+		}
+
+		if err := client.UnlockTickets(ctx, groups[winner], tokens[winner]); err != nil {
+			t.Fatalf("round %d: unlock failed: %v", round, err)
+		}
+		if _, err := client.LockTickets(ctx, groups[loser], uint(loser+1), time.Minute); err != nil {
+			t.Fatalf("round %d: loser could not lock after the winner released: %v", round, err)
+		}
+	}
+}
+
+func TestAdmissionTokenIsSignedForUserAndEvent(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+	const eventID, userID = 3, 1
+
+	token, err := client.GrantAdmission(ctx, eventID, userID, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to grant admission: %v", err)
+	}
+	if !VerifyAdmissionToken(client.signingKey, eventID, userID, token) {
+		t.Fatal("granted token does not verify")
+	}
+	if VerifyAdmissionToken(client.signingKey, eventID, userID+1, token) || VerifyAdmissionToken(client.signingKey, eventID+1, userID, token) {
+		t.Fatal("token verifies for another user or event")
+	}
+	if VerifyAdmissionToken([]byte("other-key"), eventID, userID, token) {
+		t.Fatal("token verifies with another key")
+	}
+	if admitted, err := client.CheckAdmission(ctx, eventID, userID); err != nil || !admitted {
+		t.Fatalf("admitted %v (err %v) after the grant, want true", admitted, err)
+	}
+
+	// A token copied from another user, or planted without the key, does
+	// not admit
+	server.Set(admissionKey(eventID, userID+1), token)
+	server.Set(admissionKey(eventID, userID+2), "1")
+	for _, other := range []uint{userID + 1, userID + 2} {
+		if admitted, err := client.CheckAdmission(ctx, eventID, other); err != nil || admitted {
+			t.Errorf("user %d admitted %v (err %v) with a forged token, want false", other, admitted, err)
+		}
+	}
+
+	if err := client.RevokeAdmission(ctx, eventID, userID); err != nil {
+		t.Fatalf("failed to revoke admission: %v", err)
+	}
+	if admitted, _ := client.CheckAdmission(ctx, eventID, userID); admitted {
+		t.Error("admission survived revocation")
+	}
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SearchHistorySize is how many distinct searches are kept per user
+const SearchHistorySize = 10
+
+// SearchHistoryEntry is one search a user ran, newest first in the history
+type SearchHistoryEntry struct {
+	Query       string    `json:"query"`
+	ResultCount int       `json:"resultCount"`
+	SearchedAt  time.Time `json:"searchedAt"`
+}
+
+func searchHistoryKey(userID uint) string {
+	return fmt.Sprintf("search_history:%d", userID)
+}
+
+// pushSearchHistoryScript drops earlier entries for the same query, pushes
+// the new entry and trims the list, so the history holds distinct queries
+var pushSearchHistoryScript = redis.NewScript(`
+for _, item in ipairs(redis.call("LRANGE", KEYS[1], 0, -1)) do
+	local ok, decoded = pcall(cjson.decode, item)
+	if ok and decoded.query == ARGV[1] then
+		redis.call("LREM", KEYS[1], 0, item)
+	end
+end
+redis.call("LPUSH", KEYS[1], ARGV[2])
+redis.call("LTRIM", KEYS[1], 0, tonumber(ARGV[3]) - 1)
+return 1
+`)
+
+// PushSearchHistory records a search at the head of the user's history
+func (c *Client) PushSearchHistory(ctx context.Context, userID uint, query string, resultCount int) error {
+	item, err := json.Marshal(SearchHistoryEntry{Query: query, ResultCount: resultCount, SearchedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	if err := pushSearchHistoryScript.Run(ctx, c.rdb, []string{searchHistoryKey(userID)}, query, item, SearchHistorySize).Err(); err != nil {
+		return fmt.Errorf("failed to push search history: %w", err)
+	}
+	return nil
+}
+
+// GetSearchHistory returns the user's recent searches, newest first
+func (c *Client) GetSearchHistory(ctx context.Context, userID uint) ([]SearchHistoryEntry, error) {
+	items, err := c.rdb.LRange(ctx, searchHistoryKey(userID), 0, SearchHistorySize-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search history: %w", err)
+	}
+
+	entries := make([]SearchHistoryEntry, 0, len(items))
+	for _, item := range items {
+		var entry SearchHistoryEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ClearSearchHistory deletes the user's search history
+func (c *Client) ClearSearchHistory(ctx context.Context, userID uint) error {
+	if err := c.rdb.Del(ctx, searchHistoryKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear search history: %w", err)
+	}
+	return nil
+}
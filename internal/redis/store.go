@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the Redis-backed state the services share: ticket locks, tier
+// and event counters, event popularity, the waiting queue and admissions, caches, change notifications,
+// sessions, search history and leases. Client implements it against Redis;
+// inmem.Store implements it in process for tests and single-process
+// development.
+type Store interface {
+	Ping(ctx context.Context) error
+	Close() error
+
+	// Ticket locks
+	LockTicket(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error)
+	UnlockTicket(ctx context.Context, ticketID uint, token string) error
+	LockTicketInMemory(ctx context.Context, ticketID uint, userID uint, ttl time.Duration) (string, error)
+	LockTickets(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error)
+	LockTicketsInMemory(ctx context.Context, ticketIDs []uint, userID uint, ttl time.Duration) (string, error)
+	UnlockTickets(ctx context.Context, ticketIDs []uint, token string) error
+	IsTicketLocked(ctx context.Context, ticketID uint) (bool, error)
+	GetTicketLockOwner(ctx context.Context, ticketID uint) (uint, error)
+	TicketLockToken(ctx context.Context, ticketID uint) (string, error)
+	ExtendTicketLock(ctx context.Context, ticketID uint) error
+	RenewTicketLock(ctx context.Context, ticketID uint, token string, ttl time.Duration) (bool, error)
+	TopContendedTickets(ctx context.Context, limit int) ([]ContendedTicket, error)
+	LockBackendMode() string
+	EnableExpiryNotifications(ctx context.Context) error
+	ListenTicketLockExpiry(ctx context.Context, onExpired func(ticketID uint)) error
+
+	// Flash-sale tier counters
+	ReserveTierSlot(ctx context.Context, tierID uint, available int) (bool, error)
+	ReleaseTierSlot(ctx context.Context, tierID uint) error
+	ResetTierSlots(ctx context.Context, tierID uint) error
+
+	// Event capacity counters
+	SetEventCounters(ctx context.Context, eventID uint, counters EventCounters) error
+	AdjustEventCounters(ctx context.Context, eventID uint, delta EventCounters) error
+	GetEventCounters(ctx context.Context, eventID uint) (EventCounters, bool, error)
+
+	// Event popularity
+	IncrEventScore(ctx context.Context, eventID uint, delta float64) error
+	GetTopEvents(ctx context.Context, limit int) ([]uint, error)
+
+	// Waiting queue
+	AddToWaitingQueue(ctx context.Context, eventID uint, userID uint) error
+	GetWaitingQueuePosition(ctx context.Context, eventID uint, userID uint) (int64, error)
+	RemoveFromWaitingQueue(ctx context.Context, eventID uint, userID uint) error
+	QueueLength(ctx context.Context, eventID uint) (int64, error)
+	ProcessWaitingQueue(ctx context.Context, eventID uint) (uint, error)
+
+	// Admissions past the waiting queue
+	GrantAdmission(ctx context.Context, eventID uint, userID uint, ttl time.Duration) (string, error)
+	CheckAdmission(ctx context.Context, eventID uint, userID uint) (bool, error)
+	RevokeAdmission(ctx context.Context, eventID uint, userID uint) error
+	ActiveAdmissions(ctx context.Context, eventID uint) (int64, error)
+
+	// Caches
+	GetJSON(ctx context.Context, key string, dest any) (bool, error)
+	SetJSON(ctx context.Context, key string, val any, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Remember(ctx context.Context, key string, ttl time.Duration, dest any, fn func(ctx context.Context) (any, error)) error
+	GetTicketStatuses(ctx context.Context, ticketIDs []uint) (map[uint]TicketStatus, error)
+	SetTicketStatuses(ctx context.Context, statuses []TicketStatus, ttl time.Duration) error
+	InvalidateTicketStatuses(ctx context.Context, ticketIDs ...uint) error
+
+	// Change notifications for the CDC service
+	PublishChange(ctx context.Context, msg ChangeMessage) error
+	SubscribeChanges(ctx context.Context) <-chan ChangeMessage
+
+	// Sessions
+	SetSession(ctx context.Context, sessionID string, data []byte, ttl time.Duration) error
+	GetSession(ctx context.Context, sessionID string) ([]byte, error)
+	DeleteSession(ctx context.Context, sessionID string) error
+
+	// Search history
+	PushSearchHistory(ctx context.Context, userID uint, query string, resultCount int) error
+	GetSearchHistory(ctx context.Context, userID uint) ([]SearchHistoryEntry, error)
+	ClearSearchHistory(ctx context.Context, userID uint) error
+
+	// Leases
+	AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	RenewLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, key, holder string) error
+	LeaseHolder(ctx context.Context, key string) (string, error)
+}
+
+var _ Store = (*Client)(nil)
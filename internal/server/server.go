@@ -0,0 +1,85 @@
+// Package server builds the HTTP servers the services listen with
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// SetMode puts gin in release mode in production and test mode in tests,
+// leaving debug output on for development. Call it before building the
+// router, since route registration logs in debug mode.
+func SetMode(cfg *config.Config) {
+	switch {
+	case cfg.IsProduction():
+		gin.SetMode(gin.ReleaseMode)
+	case cfg.AppEnv == config.EnvTest:
+		gin.SetMode(gin.TestMode)
+	default:
+		gin.SetMode(gin.DebugMode)
+	}
+}
+
+// New returns a server for handler on port. With HTTP2Enabled it speaks
+// HTTP/2 over TLS when a certificate is configured and h2c (HTTP/2 without
+// TLS) otherwise; HTTP/1.1 clients are served as before either way.
+func New(cfg *config.Config, port string, handler http.Handler) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: handler,
+	}
+
+	if !cfg.HTTP2Enabled {
+		// net/http negotiates HTTP/2 over TLS on its own; keep it off
+		srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return srv, nil
+	}
+
+	h2 := &http2.Server{}
+	if useTLS(cfg) {
+		if err := http2.ConfigureServer(srv, h2); err != nil {
+			return nil, fmt.Errorf("failed to configure HTTP/2: %w", err)
+		}
+		return srv, nil
+	}
+
+	srv.Handler = h2c.NewHandler(handler, h2)
+	return srv, nil
+}
+
+// ListenAndServe serves over TLS when a certificate is configured
+func ListenAndServe(cfg *config.Config, srv *http.Server) error {
+	if useTLS(cfg) {
+		return srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+	return srv.ListenAndServe()
+}
+
+// Transport returns the transport for calls between services: h2c when
+// HTTP/2 is enabled without TLS, since plain-HTTP URLs would otherwise be
+// fetched over HTTP/1.1, and the default transport otherwise
+func Transport(cfg *config.Config) http.RoundTripper {
+	if !cfg.HTTP2Enabled || useTLS(cfg) {
+		return http.DefaultTransport
+	}
+
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+func useTLS(cfg *config.Config) bool {
+	return cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// serve starts a server built by New on a free port and returns its URL
+func serve(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/proto", func(c *gin.Context) {
+		c.String(http.StatusOK, c.Request.Proto)
+	})
+
+	srv, err := New(cfg, "0", router)
+	if err != nil {
+		t.Fatalf("failed to build server: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+	return "http://" + ln.Addr().String()
+}
+
+// proto fetches the protocol the server saw the request arrive over
+func proto(t *testing.T, transport http.RoundTripper, url string) string {
+	t.Helper()
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(url + "/proto")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	return string(body)
+}
+
+func TestH2CRequest(t *testing.T) {
+	cfg := &config.Config{HTTP2Enabled: true}
+	url := serve(t, cfg)
+
+	if got := proto(t, Transport(cfg), url); got != "HTTP/2.0" {
+		t.Errorf("h2c request arrived over %s, want HTTP/2.0", got)
+	}
+	// HTTP/1.1 clients are served as before
+	if got := proto(t, http.DefaultTransport, url); got != "HTTP/1.1" {
+		t.Errorf("HTTP/1.1 request arrived over %s", got)
+	}
+}
+
+func TestHTTP2Disabled(t *testing.T) {
+	cfg := &config.Config{}
+	url := serve(t, cfg)
+
+	if Transport(cfg) != http.DefaultTransport {
+		t.Error("Transport speaks h2c with HTTP/2 disabled")
+	}
+	if got := proto(t, Transport(cfg), url); got != "HTTP/1.1" {
+		t.Errorf("request arrived over %s, want HTTP/1.1", got)
+	}
+}
@@ -0,0 +1,256 @@
+package booking
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultEventBookingsPageSize = 50
+	maxEventBookingsPageSize     = 200
+
+	mimeCSV = "text/csv"
+
+	// bookingStatusSQL is a booking's status as listed: reservations that
+	// expired before the status was stored were soft-deleted and keep the
+	// reserved status in their row
+	bookingStatusSQL = "CASE WHEN b.deleted_at IS NOT NULL THEN 'expired' ELSE b.status END"
+
+	// listedBookingSQL leaves out expired and deleted bookings unless they
+	// are asked for
+	listedBookingSQL = "b.deleted_at IS NULL AND b.status <> 'expired'"
+)
+
+// EventBooking is one row of an event's booking list. Price is what the
+// booking was reserved at, AmountPaid what was charged for it.
+type EventBooking struct {
+	BookingID  uint         `json:"bookingId"`
+	Status     string       `json:"status"`
+	UserID     uint         `json:"userId"`
+	UserName   string       `json:"userName"`
+	UserEmail  string       `json:"userEmail"`
+	Seat       string       `json:"seat"`
+	Tier       *string      `json:"tier"`
+	Price      money.Amount `json:"price"`
+	AmountPaid money.Amount `json:"amountPaid"`
+	BookedAt   time.Time    `json:"bookedAt"`
+}
+
+// ListEventBookings lists who booked an event, with counts per status
+// (admin only). Clients asking for text/csv get every matching booking as a
+// CSV download instead of a JSON page. With includeDeleted=true deleted
+// events can be audited and expired bookings are listed too;
+// status=expired lists only those.
+func (s *Service) ListEventBookings(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("eventId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	status := c.Query("status")
+	if status != "" && !models.BookingStatus(status).IsValid() {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "status must be reserved, confirmed, cancelled or expired", nil))
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+	includeDeleted := c.Query("includeDeleted") == "true"
+
+	events := s.db
+	if includeDeleted {
+		events = events.Unscoped()
+	}
+	var event models.Event
+	if err := events.Select("id").First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	summary, err := s.eventBookingSummary(c.Request.Context(), event.ID, includeDeleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count bookings", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// One query joining bookings, tickets, users and events, rather than a
+	// preload per relation
+	query := s.db.WithContext(c.Request.Context()).Table("bookings b").
+		Joins("JOIN tickets t ON t.id = b.ticket_id").
+		Joins("JOIN events e ON e.id = t.event_id").
+		Joins("JOIN users u ON u.id = b.user_id").
+		Joins("LEFT JOIN ticket_tiers tt ON tt.id = t.tier_id").
+		Where("e.id = ?", event.ID)
+	if !includeDeleted && status != string(models.BookingExpired) {
+		query = query.Where(listedBookingSQL)
+	}
+	if status != "" {
+		query = query.Where(bookingStatusSQL+" = ?", status)
+	}
+
+	selectRows := func(q *gorm.DB) *gorm.DB {
+		return q.Select("b.id AS booking_id, " + bookingStatusSQL + " AS status, u.id AS user_id, u.name AS user_name, u.email AS user_email, " +
+			"t.seat, tt.name AS tier, b.price, b.amount_paid, b.created_at AS booked_at").
+			Order("b.created_at").Order("b.id")
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, mimeCSV) == mimeCSV {
+		var bookings []EventBooking
+		if err := selectRows(query).Scan(&bookings).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch bookings", gin.H{"reason": err.Error()}))
+			return
+		}
+		writeEventBookingsCSV(c, event.ID, summary, bookings)
+		return
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count bookings", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	bookings := []EventBooking{}
+	if err := selectRows(query).Offset((page - 1) * pageSize).Limit(pageSize).Scan(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch bookings", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":  event.ID,
+		"summary":  summary,
+		"bookings": bookings,
+		"count":    len(bookings),
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// eventBookingSummary counts an event's bookings by status, including
+// deleted ones when includeDeleted is set
+func (s *Service) eventBookingSummary(ctx context.Context, eventID uint, includeDeleted bool) (gin.H, error) {
+	var counts []struct {
+		Status models.BookingStatus
+		Count  int64
+	}
+	query := s.db.WithContext(ctx).Table("bookings b").
+		Joins("JOIN tickets t ON t.id = b.ticket_id").
+		Where("t.event_id = ?", eventID)
+	if !includeDeleted {
+		query = query.Where(listedBookingSQL)
+	}
+	err := query.Select(bookingStatusSQL + " AS status, COUNT(*) AS count").
+		Group(bookingStatusSQL).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := gin.H{"totalConfirmed": int64(0), "totalReserved": int64(0), "totalCancelled": int64(0)}
+	if includeDeleted {
+		summary["totalExpired"] = int64(0)
+	}
+	for _, row := range counts {
+		switch row.Status {
+		case models.BookingConfirmed:
+			summary["totalConfirmed"] = row.Count
+		case models.BookingReserved:
+			summary["totalReserved"] = row.Count
+		case models.BookingCancelled:
+			summary["totalCancelled"] = row.Count
+		case models.BookingExpired:
+			summary["totalExpired"] = row.Count
+		}
+	}
+	return summary, nil
+}
+
+// writeEventBookingsCSV sends the bookings as a CSV attachment, with the
+// summary in response headers
+func writeEventBookingsCSV(c *gin.Context, eventID uint, summary gin.H, bookings []EventBooking) {
+	c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d-bookings.csv"`, eventID))
+	c.Header("X-Total-Confirmed", fmt.Sprint(summary["totalConfirmed"]))
+	c.Header("X-Total-Reserved", fmt.Sprint(summary["totalReserved"]))
+	c.Header("X-Total-Cancelled", fmt.Sprint(summary["totalCancelled"]))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"booking_id", "status", "user_id", "user_name", "user_email", "seat", "tier", "price", "amount_paid", "booked_at"})
+	for _, b := range bookings {
+		tier := ""
+		if b.Tier != nil {
+			tier = *b.Tier
+		}
+		w.Write([]string{
+			strconv.FormatUint(uint64(b.BookingID), 10),
+			b.Status,
+			strconv.FormatUint(uint64(b.UserID), 10),
+			csvText(b.UserName),
+			csvText(b.UserEmail),
+			csvText(b.Seat),
+			csvText(tier),
+			b.Price.String(),
+			b.AmountPaid.String(),
+			b.BookedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+// csvText keeps a spreadsheet from running a user-supplied cell as a
+// formula, by prefixing cells that would start one with a quote
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// parsePagination reads the page (1-based) and page_size query parameters
+func parsePagination(c *gin.Context) (int, int, error) {
+	page := 1
+	if value := c.Query("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+		page = parsed
+	}
+
+	pageSize := defaultEventBookingsPageSize
+	if value := c.Query("page_size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxEventBookingsPageSize {
+			return 0, 0, fmt.Errorf("page_size must be between 1 and %d", maxEventBookingsPageSize)
+		}
+		pageSize = parsed
+	}
+
+	return page, pageSize, nil
+}
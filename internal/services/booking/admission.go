@@ -0,0 +1,123 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// admissionTimeout bounds one admission check or dispatcher run
+const admissionTimeout = 5 * time.Second
+
+// requireAdmission gates reservations for events that require the waiting
+// queue. A user without an admission joins the queue and gets 403 with
+// their position; retrying keeps their place. The gate needs Redis, so it
+// fails closed while the circuit breaker is open. Returns false when a
+// response was written.
+func (s *Service) requireAdmission(c *gin.Context, event *models.Event, userID uint) bool {
+	if event == nil || !event.RequiresQueue {
+		return true
+	}
+
+	if s.breaker.IsOpen() {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Waiting queue is temporarily unavailable", nil))
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), admissionTimeout)
+	defer cancel()
+
+	admitted, err := s.redisClient.CheckAdmission(ctx, event.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Waiting queue is temporarily unavailable", gin.H{"reason": err.Error()}))
+		return false
+	}
+	if admitted {
+		return true
+	}
+
+	if err := s.redisClient.AddToWaitingQueue(ctx, event.ID, userID); err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Waiting queue is temporarily unavailable", gin.H{"reason": err.Error()}))
+		return false
+	}
+	s.dispatchAdmissions(c.Request.Context(), event.ID)
+
+	position, err := s.redisClient.GetWaitingQueuePosition(ctx, event.ID, userID)
+	if errors.Is(err, redis.ErrNotInQueue) {
+		// Admitted by the dispatcher in the meantime; the next attempt goes through
+		position = -1
+	} else if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Waiting queue is temporarily unavailable", gin.H{"reason": err.Error()}))
+		return false
+	}
+
+	c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeNotAdmitted, "Waiting in the queue for this event", gin.H{"position": position + 1}))
+	return false
+}
+
+// revokeAdmission uses up a user's admission once they reserved a ticket
+func (s *Service) revokeAdmission(ctx context.Context, event *models.Event, userID uint) {
+	if event == nil || !event.RequiresQueue {
+		return
+	}
+	if err := s.redisClient.RevokeAdmission(context.WithoutCancel(ctx), event.ID, userID); err != nil {
+		log.Printf("Failed to revoke admission of user %d to event %d: %v", userID, event.ID, err)
+	}
+}
+
+// dispatchAdmissions admits queued users in the background until the event
+// has AdmissionWindow outstanding admissions. It runs whenever someone joins
+// the queue and whenever a reservation completes or expires. Runs are
+// serialized within the process; replicas racing each other may briefly
+// admit a few users more than the window.
+func (s *Service) dispatchAdmissions(ctx context.Context, eventID uint) {
+	if s.breaker.IsOpen() {
+		return
+	}
+
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		s.admissionMu.Lock()
+		defer s.admissionMu.Unlock()
+
+		ctx, cancel := context.WithTimeout(detached, admissionTimeout)
+		defer cancel()
+
+		queued, err := s.redisClient.QueueLength(ctx, eventID)
+		if err != nil || queued == 0 {
+			return
+		}
+
+		active, err := s.redisClient.ActiveAdmissions(ctx, eventID)
+		if err != nil {
+			log.Printf("Failed to count admissions for event %d: %v", eventID, err)
+			return
+		}
+
+		for n := int64(s.config.AdmissionWindow) - active; n > 0; n-- {
+			userID, err := s.redisClient.ProcessWaitingQueue(ctx, eventID)
+			if errors.Is(err, redis.ErrQueueEmpty) {
+				return
+			}
+			if err != nil {
+				log.Printf("Failed to take the next user from the queue of event %d: %v", eventID, err)
+				return
+			}
+
+			if _, err := s.redisClient.GrantAdmission(ctx, eventID, userID, s.config.AdmissionTTL); err != nil {
+				log.Printf("Failed to admit user %d to event %d: %v", userID, eventID, err)
+				// Requeue the user rather than lose them; they rejoin at the back
+				s.redisClient.AddToWaitingQueue(ctx, eventID, userID)
+				return
+			}
+		}
+	}()
+}
@@ -2,34 +2,247 @@ package booking
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/email"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/eventbus"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/jobs"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/kafka"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/metrics"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/pricing"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/store"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+const (
+	// expiryCheckInterval is how often StartExpiryWorker releases expired reservations
+	expiryCheckInterval = time.Minute
+
+	// txMaxAttempts bounds deadlock retries of the confirm and cancel transactions
+	txMaxAttempts = 3
+
+	// bookingEventTimeout bounds publishing one booking event to Kafka
+	bookingEventTimeout = 5 * time.Second
+
+	// bookingEventPartitions is the number of publishers booking events are
+	// spread over; one booking's events always go through the same one
+	bookingEventPartitions = 8
+
+	// bookingEventBuffer is how many events each publisher holds
+	bookingEventBuffer = 256
+)
+
 type Service struct {
 	db            *gorm.DB
-	redisClient   *redis.Client
-	paymentClient *payment.MockStripeClient
+	tickets       store.TicketStore
+	bookings      store.BookingStore
+	users         store.UserStore
+	redisClient   redis.Store
+	paymentClient payment.Client
 	config        *config.Config
+	breaker       *RedisCircuitBreaker
+	txRunner      *txRunner
+	lockKeeper    *redis.LockKeeper
+	dbHealth      *database.Readiness
+	redisHealth   *redis.HealthProber
+	emailSender   email.EmailSender
+	notifier      notify.Notifier
+	kafkaClient   *kafka.Client // nil when booking events are disabled
+	bus           *eventbus.Bus // Booking changes for the in-process subscribers
+	admissionMu   sync.Mutex    // Serializes admission dispatcher runs
+	paymentJobs   *jobs.Queue   // Charges confirmed bookings off the request path
+	bookingEvents []*jobs.Queue // Single-worker publishers to Kafka, by booking ID
+	staleTiers    sync.Map      // Tier IDs whose Redis counter missed degraded reservations
 }
 
-func NewService(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *Service {
-	return &Service{
+func NewService(db *gorm.DB, redisClient redis.Store, cfg *config.Config, paymentClient payment.Client, emailSender email.EmailSender, notifier notify.Notifier, kafkaClient *kafka.Client) *Service {
+	s := &Service{
 		db:            db,
+		tickets:       store.NewTicketStore(db),
+		bookings:      store.NewBookingStore(db),
+		users:         store.NewUserStore(db),
 		redisClient:   redisClient,
-		paymentClient: payment.NewMockStripeClient(cfg),
+		paymentClient: paymentClient,
 		config:        cfg,
+		breaker:       NewRedisCircuitBreaker(),
+		lockKeeper:    redis.NewLockKeeper(redisClient, redis.TicketLockTTL, cfg.LockKeeperMax),
+		dbHealth:      database.NewReadiness("booking-service", database.HealthCheckInterval),
+		redisHealth:   redis.NewHealthProber(redisClient, "booking-service", redis.HealthProbeInterval),
+		emailSender:   emailSender,
+		notifier:      notifier,
+		kafkaClient:   kafkaClient,
+		bus:           eventbus.New(eventbus.DefaultBufferSize),
+		paymentJobs:   jobs.NewQueue("payment", cfg.PaymentWorkers, cfg.PaymentQueueSize),
 	}
+	if kafkaClient != nil {
+		s.bookingEvents = make([]*jobs.Queue, bookingEventPartitions)
+		for i := range s.bookingEvents {
+			s.bookingEvents[i] = jobs.NewQueue("booking event", 1, bookingEventBuffer)
+		}
+	}
+	s.txRunner = &txRunner{db: db, redisClient: redisClient, breaker: s.breaker, maxAttempts: txMaxAttempts}
+	s.subscribe()
+	return s
+}
+
+// Shutdown stops renewing the locks of in-flight confirmations and waits
+// for the booking events already queued to be published
+func (s *Service) Shutdown(ctx context.Context) {
+	if err := s.paymentJobs.Stop(ctx); err != nil {
+		log.Printf("Payment jobs still running at shutdown: %v", err)
+	}
+	s.lockKeeper.Stop()
+	if err := s.bus.Close(ctx); err != nil {
+		log.Printf("Event bus subscribers still running at shutdown: %v", err)
+	}
+	for _, queue := range s.bookingEvents {
+		if err := queue.Stop(ctx); err != nil {
+			log.Printf("Booking events still unpublished at shutdown: %v", err)
+			return
+		}
+	}
+}
+
+// StartRedisProbe closes the Redis circuit breaker once Redis recovers
+func (s *Service) StartRedisProbe(ctx context.Context) {
+	s.breaker.Probe(ctx, s.redisClient.Ping)
+}
+
+// StartExpiryWorker periodically releases reservations past their expiry and
+// clears the sold-out flag of events whose tickets return to sale
+func (s *Service) StartExpiryWorker(ctx context.Context) {
+	ticker := time.NewTicker(expiryCheckInterval)
+	defer ticker.Stop()
+
+	log.Println("Reservation expiry worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Reservation expiry worker stopped")
+			return
+		case <-ticker.C:
+			if err := s.releaseExpiredReservations(ctx); err != nil {
+				log.Printf("Reservation expiry error: %v", err)
+			}
+		}
+	}
+}
+
+// StartLockExpiryListener expires reservations as soon as their Redis lock
+// expires instead of waiting for the expiry worker. Managed Redis may not
+// allow enabling keyspace events from here, in which case they have to be
+// enabled in the server config.
+func (s *Service) StartLockExpiryListener(ctx context.Context) {
+	if err := s.redisClient.EnableExpiryNotifications(ctx); err != nil {
+		log.Printf("Could not enable Redis expiry notifications, relying on server config: %v", err)
+	}
+
+	log.Println("Lock expiry listener started")
+	if err := s.redisClient.ListenTicketLockExpiry(ctx, func(ticketID uint) { s.handleLockExpired(ctx, ticketID) }); err != nil {
+		log.Printf("Lock expiry listener error: %v", err)
+		return
+	}
+	log.Println("Lock expiry listener stopped")
+}
+
+// handleLockExpired expires the reservation whose lock just lapsed. Expiry
+// events do not carry the old value, so the reservation is looked up by
+// ticket and skipped if its lock has since been taken again with its own
+// token.
+func (s *Service) handleLockExpired(ctx context.Context, ticketID uint) {
+	var booking models.Booking
+	err := s.db.WithContext(ctx).Preload("Ticket").
+		Where("ticket_id = ? AND status = ? AND lock_token <> ''", ticketID, models.BookingReserved).
+		First(&booking).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Printf("Failed to look up reservation for expired lock on ticket %d: %v", ticketID, err)
+		}
+		return
+	}
+
+	token, err := s.redisClient.TicketLockToken(ctx, ticketID)
+	if err != nil {
+		log.Printf("Failed to check lock on ticket %d: %v", ticketID, err)
+		return
+	}
+	if token == booking.LockToken {
+		return
+	}
+
+	if err := s.expireReservation(ctx, &booking); err != nil {
+		log.Printf("Failed to release booking %d after its lock expired: %v", booking.ID, err)
+		return
+	}
+	log.Printf("Released booking %d after its lock on ticket %d expired", booking.ID, ticketID)
+}
+
+// releaseExpiredReservations expires every overdue reservation; expiring one
+// also re-checks the sold-out status of its event
+func (s *Service) releaseExpiredReservations(ctx context.Context) error {
+	var bookings []models.Booking
+	if err := s.db.WithContext(ctx).Preload("Ticket").
+		Where("status = ? AND expires_at < ?", models.BookingReserved, time.Now()).
+		Find(&bookings).Error; err != nil {
+		return fmt.Errorf("failed to fetch expired reservations: %w", err)
+	}
+
+	for i := range bookings {
+		if err := s.expireReservation(ctx, &bookings[i]); err != nil {
+			log.Printf("Failed to release expired booking %d: %v", bookings[i].ID, err)
+		}
+	}
+
+	if len(bookings) > 0 {
+		log.Printf("Released %d expired reservations", len(bookings))
+	}
+	return nil
+}
+
+// CheckAndMarkSoldOut sets the event's SoldOut flag when it has no available
+// tickets left, and clears it once tickets return to sale
+func (s *Service) CheckAndMarkSoldOut(ctx context.Context, eventID uint) error {
+	return checkAndMarkSoldOut(s.db.WithContext(ctx), eventID)
+}
+
+// checkAndMarkSoldOut is CheckAndMarkSoldOut on an explicit handle, so it can
+// run inside a caller's transaction
+func checkAndMarkSoldOut(db *gorm.DB, eventID uint) error {
+	var remaining int64
+	if err := db.Model(&models.Ticket{}).
+		Where("event_id = ? AND status = ?", eventID, models.TicketAvailable).
+		Count(&remaining).Error; err != nil {
+		return fmt.Errorf("failed to count available tickets: %w", err)
+	}
+
+	soldOut := remaining == 0
+	if err := db.Model(&models.Event{}).
+		Where("id = ? AND sold_out <> ?", eventID, soldOut).
+		Update("sold_out", soldOut).Error; err != nil {
+		return fmt.Errorf("failed to update sold-out status: %w", err)
+	}
+	return nil
 }
 
 func (s *Service) SetupRoutes(r *gin.Engine) {
@@ -37,32 +250,36 @@ func (s *Service) SetupRoutes(r *gin.Engine) {
 	r.PUT("/booking/confirm", s.ConfirmBooking)
 	r.DELETE("/booking/cancel/:id", s.CancelBooking)
 	r.GET("/booking/user/:userId", s.GetUserBookings)
+	r.GET("/booking/:id/refund", s.GetBookingRefund)
+	r.GET("/booking/:id/payment", s.GetBookingPayment)
+	r.GET("/booking/:id/receipt", s.GetBookingReceipt)
+	r.GET("/booking/:id/payment-status", s.GetPaymentStatus)
+	r.POST("/booking/:id/retry-payment", s.RetryPayment)
+	r.GET("/admin/payments/unreconciled", s.ListUnreconciledPayments)
+	r.GET("/admin/bookings/by-event/:eventId", s.ListEventBookings)
+	r.POST("/webhooks/stripe", s.HandleStripeWebhook)
+	r.GET("/admin/locks/contended", s.GetContendedTickets)
 	r.GET("/health", s.HealthCheck)
+	r.GET("/metrics", metrics.Handler())
 }
 
 func (s *Service) ReserveTicket(c *gin.Context) {
 	// Extract and validate JWT token
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Authorization header required",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
 		return
 	}
 
 	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid authorization header",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
 		return
 	}
 
 	claims, err := auth.ValidateToken(s.config, tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid token",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
 		return
 	}
 
@@ -70,70 +287,142 @@ func (s *Service) ReserveTicket(c *gin.Context) {
 	var req struct {
 		TicketID uint `json:"ticketId" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
-	// Check if ticket exists and is available
-	var ticket models.Ticket
-	result := s.db.Preload("Event").First(&ticket, req.TicketID)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Ticket not found",
-			})
+	// Check if ticket exists and is available, on the primary so a replica's
+	// stale status cannot offer a ticket that was just taken
+	ticket, err := s.tickets.FindForReservation(c.Request.Context(), req.TicketID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeTicketNotFound, "Ticket not found", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch ticket",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch ticket", nil))
 		return
 	}
 
-	if ticket.Status != "available" {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": "Ticket is not available",
-		})
+	if ticket.Status != models.TicketAvailable {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTicketUnavailable, "Ticket is not available", nil))
+		return
+	}
+
+	// Only admitted users may reserve tickets for events behind the queue
+	if !s.requireAdmission(c, ticket.Event, claims.UserID) {
+		return
+	}
+
+	// Lock in the current price, surge included
+	price, err := pricing.TicketPrice(s.db.WithContext(c.Request.Context()), ticket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to price ticket", nil))
+		return
+	}
+
+	// Fall back to Postgres advisory locks while Redis is unavailable
+	if s.breaker.IsOpen() {
+		s.reserveWithAdvisoryLock(c, ticket, claims.UserID, price)
 		return
 	}
 
 	// Try to lock the ticket in Redis
-	if err := s.redisClient.LockTicket(context.Background(), req.TicketID, claims.UserID); err != nil {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": "Ticket is currently being processed by another user",
-		})
+	window := s.reservationWindow(ticket.Event)
+	lockToken, err := s.redisClient.LockTicket(c.Request.Context(), req.TicketID, claims.UserID, window)
+	if err != nil && !errors.Is(err, redis.ErrTicketLocked) {
+		s.breaker.RecordFailure(err)
+		if s.breaker.IsOpen() {
+			s.reserveWithAdvisoryLock(c, ticket, claims.UserID, price)
+			return
+		}
+		// Until the breaker opens, a brief outage is bridged in process
+		if errors.Is(err, redis.ErrUnavailable) {
+			lockToken, err = s.redisClient.LockTicketInMemory(c.Request.Context(), req.TicketID, claims.UserID, window)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, redis.ErrTicketLocked) {
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTicketLocked, "Ticket is currently being processed by another user", nil))
+			return
+		}
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeLockingUnavailable, "Ticket locking is temporarily unavailable", nil))
 		return
 	}
 
-	// Create booking record
+	// Claim a slot from the tier's flash-sale counter
+	if ticket.TierID != nil {
+		tier, err := s.tickets.FindTier(c.Request.Context(), *ticket.TierID)
+		if err != nil {
+			s.redisClient.UnlockTicket(context.WithoutCancel(c.Request.Context()), req.TicketID, lockToken)
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch ticket tier", nil))
+			return
+		}
+
+		// Reservations taken without Redis left the counter behind the
+		// database; drop it so it is re-seeded
+		if _, stale := s.staleTiers.LoadAndDelete(tier.ID); stale {
+			if err := s.redisClient.ResetTierSlots(c.Request.Context(), tier.ID); err != nil {
+				s.staleTiers.Store(tier.ID, true)
+				log.Printf("Failed to reset the counter of tier %d: %v", tier.ID, err)
+			}
+		}
+
+		reserved, err := s.redisClient.ReserveTierSlot(c.Request.Context(), tier.ID, tier.AvailableCount)
+		if err != nil {
+			s.redisClient.UnlockTicket(context.WithoutCancel(c.Request.Context()), req.TicketID, lockToken)
+			s.breaker.RecordFailure(err)
+			c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeLockingUnavailable, "Ticket locking is temporarily unavailable", nil))
+			return
+		}
+		if !reserved {
+			s.redisClient.UnlockTicket(context.WithoutCancel(c.Request.Context()), req.TicketID, lockToken)
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTierSoldOut, "Ticket tier is sold out", nil))
+			return
+		}
+	}
+
+	// Create booking record and mark the ticket reserved
 	booking := models.Booking{
 		TicketID:   req.TicketID,
 		UserID:     claims.UserID,
-		Status:     "reserved",
+		Status:     models.BookingReserved,
 		ReservedAt: time.Now(),
-		ExpiresAt:  time.Now().Add(10 * time.Minute), // 10 minute reservation window
+		ExpiresAt:  time.Now().Add(window),
+		Price:      price,
+		LockToken:  lockToken,
 	}
 
-	if err := s.db.Create(&booking).Error; err != nil {
+	if err := s.bookings.Reserve(c.Request.Context(), &booking, ticket); err != nil {
 		// Release the lock if database operation fails
-		s.redisClient.UnlockTicket(context.Background(), req.TicketID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create booking",
-		})
+		s.redisClient.UnlockTicket(context.WithoutCancel(c.Request.Context()), req.TicketID, lockToken)
+		if ticket.TierID != nil {
+			s.releaseTierSlot(c.Request.Context(), *ticket.TierID)
+		}
+		if errors.Is(err, store.ErrTicketContended) {
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTicketUnavailable, "Ticket is not available", nil))
+			return
+		}
+		if errors.Is(err, store.ErrTierSoldOut) {
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTierSoldOut, "Ticket tier is sold out", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create booking", nil))
 		return
 	}
-
-	// Update ticket status
-	s.db.Model(&ticket).Update("status", "reserved")
+	s.invalidateTicketStatus(c.Request.Context(), ticket.ID)
+	s.adjustEventCounters(c.Request.Context(), ticket.EventID, redis.EventCounters{Available: -1})
+	s.scoreEvent(c.Request.Context(), ticket.EventID, redis.PopularityReservation)
+	s.publishChange(c.Request.Context(), ticket.EventID)
+	s.revokeAdmission(c.Request.Context(), ticket.Event, claims.UserID)
+	metrics.ObserveFunnel(metrics.FunnelReserved, booking.ReservedAt)
+	s.publishBookingEvent(c.Request.Context(), kafka.BookingReserved, &booking, gin.H{"eventId": ticket.EventID, "expiresAt": booking.ExpiresAt})
 
 	c.JSON(http.StatusOK, gin.H{
 		"bookingId": booking.ID,
 		"ticketId":  req.TicketID,
 		"expiresAt": booking.ExpiresAt,
+		"price":     booking.Price,
+		"currency":  s.eventCurrency(ticket.Event),
 		"message":   "Ticket reserved successfully",
 	})
 }
@@ -142,25 +431,19 @@ func (s *Service) ConfirmBooking(c *gin.Context) {
 	// Extract and validate JWT token
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Authorization header required",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
 		return
 	}
 
 	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid authorization header",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
 		return
 	}
 
 	claims, err := auth.ValidateToken(s.config, tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid token",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
 		return
 	}
 
@@ -169,124 +452,93 @@ func (s *Service) ConfirmBooking(c *gin.Context) {
 		TicketID       uint   `json:"ticketId" binding:"required"`
 		PaymentDetails string `json:"paymentDetails" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
-	// Check if booking exists and belongs to user
+	// Check if booking exists and belongs to user, on the primary so a
+	// reservation made a moment ago is found
 	var booking models.Booking
-	result := s.db.Preload("Ticket").Preload("Ticket.Event").First(&booking, "ticket_id = ? AND user_id = ? AND status = ?", req.TicketID, claims.UserID, "reserved")
+	result := database.Primary(s.db.WithContext(c.Request.Context())).Preload("Ticket").Preload("Ticket.Event").First(&booking, "ticket_id = ? AND user_id = ? AND status = ?", req.TicketID, claims.UserID, models.BookingReserved)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No active reservation found for this ticket",
-			})
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this ticket", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch booking",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
 		return
 	}
 
 	// Check if reservation has expired
 	if time.Now().After(booking.ExpiresAt) {
 		// Clean up expired booking
-		s.db.Delete(&booking)
-		s.redisClient.UnlockTicket(context.Background(), req.TicketID)
-		s.db.Model(&models.Ticket{ID: req.TicketID}).Update("status", "available")
+		if err := s.expireReservation(c.Request.Context(), &booking); err != nil {
+			if errors.Is(err, errBookingNotReserved) {
+				c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this ticket", nil))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to release expired reservation", nil))
+			return
+		}
 
-		c.JSON(http.StatusGone, gin.H{
-			"error": "Reservation has expired",
-		})
+		c.JSON(http.StatusGone, apperrors.NewResponse(apperrors.ErrCodeReservationExpired, "Reservation has expired", nil))
 		return
 	}
 
 	// Verify the ticket is still locked by this user
-	lockOwner, err := s.redisClient.GetTicketLockOwner(context.Background(), req.TicketID)
-	if err != nil || lockOwner != claims.UserID {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": "Ticket lock has been released",
-		})
+	if !s.checkReservationLock(c, req.TicketID, claims.UserID) {
 		return
 	}
 
-	// Process payment
-	paymentReq := &payment.PaymentRequest{
-		Amount:   booking.Ticket.Price,
-		Currency: "ntd",
-		UserID:   claims.UserID,
-		TicketID: req.TicketID,
-	}
-
-	paymentResp, err := s.paymentClient.CreatePaymentIntent(context.Background(), paymentReq)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Payment processing failed",
-		})
+	// Fix the price with fees and tax before charging
+	if err := s.ensureReceipt(c.Request.Context(), &booking); err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to price booking", nil))
 		return
 	}
 
-	if !paymentResp.Success {
-		c.JSON(http.StatusPaymentRequired, gin.H{
-			"error":   "Payment failed",
-			"details": paymentResp.Error,
-		})
+	// Record the payment before charging so that a crash between the charge
+	// and the booking update still leaves a trace. A repeated confirm finds
+	// the first one's payment and charges nothing.
+	statusURL := fmt.Sprintf("/booking/%d/payment-status", booking.ID)
+	paymentRecord, attempt, err := s.beginPayment(c.Request.Context(), &booking, claims.UserID, 0)
+	switch {
+	case errors.Is(err, errPaymentInProgress):
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodePaymentInProgress, "A payment for this booking is already in progress", gin.H{"statusUrl": statusURL}))
 		return
-	}
-
-	// Start database transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// Update booking status
-	if err := tx.Model(&booking).Updates(map[string]interface{}{
-		"status":     "confirmed",
-		"payment_id": paymentResp.PaymentIntent.ID,
-	}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to confirm booking",
-		})
+	case errors.Is(err, errBookingNotReserved):
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this ticket", nil))
 		return
-	}
-
-	// Update ticket status and assign to user
-	if err := tx.Model(&booking.Ticket).Updates(map[string]interface{}{
-		"status":  "booked",
-		"user_id": claims.UserID,
-	}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update ticket",
-		})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to record payment", nil))
 		return
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to confirm booking",
-		})
+	// Charge in the background; the client polls for the outcome
+	job := &paymentJob{
+		booking:       booking,
+		record:        *paymentRecord,
+		attempt:       attempt,
+		email:         claims.Email,
+		paymentMethod: req.PaymentDetails,
+	}
+	if s.config.MockStripeEnabled {
+		job.mockOutcome = payment.MockOutcome(c.GetHeader(payment.MockOutcomeHeader))
+	}
+	status, err := s.submitPaymentJob(c.Request.Context(), job)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Too many payments in progress, try again shortly", nil))
 		return
 	}
 
-	// Release Redis lock
-	s.redisClient.UnlockTicket(context.Background(), req.TicketID)
-
-	c.JSON(http.StatusOK, gin.H{
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{
 		"bookingId": booking.ID,
 		"ticketId":  req.TicketID,
-		"paymentId": paymentResp.PaymentIntent.ID,
-		"message":   "Booking confirmed successfully",
+		"jobId":     status.JobID,
+		"status":    status.Status,
+		"statusUrl": statusURL,
+		"receipt":   receiptResponse(&booking),
+		"message":   "Payment is processing",
 	})
 }
 
@@ -294,159 +546,610 @@ func (s *Service) CancelBooking(c *gin.Context) {
 	bookingIDStr := c.Param("id")
 	bookingID, err := strconv.ParseUint(bookingIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid booking ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
 		return
 	}
 
 	// Extract and validate JWT token
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Authorization header required",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
 		return
 	}
 
 	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid authorization header",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
 		return
 	}
 
 	claims, err := auth.ValidateToken(s.config, tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid token",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
 		return
 	}
 
-	// Check if booking exists and belongs to user
+	// Check if booking exists and belongs to user; admins may cancel anyone's.
+	// Read on the primary: a replica may not show a confirmation yet, and the
+	// refund is decided by the status read here.
+	query := database.Primary(s.db.WithContext(c.Request.Context())).Preload("Ticket").Where("id = ?", uint(bookingID))
+	if !claims.IsAdmin() {
+		query = query.Where("user_id = ?", claims.UserID)
+	}
 	var booking models.Booking
-	result := s.db.Preload("Ticket").First(&booking, "id = ? AND user_id = ?", uint(bookingID), claims.UserID)
+	result := query.First(&booking)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Booking not found",
-			})
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "Booking not found", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch booking",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
 		return
 	}
 
-	// Start database transaction
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	// A cancelled booking is not returned to sale again, so the seat cannot
+	// be taken from whoever holds it now. Cancelling it again only retries a
+	// refund that did not go through.
+	if booking.Status == models.BookingCancelled {
+		refund, err := s.retryRefund(context.WithoutCancel(c.Request.Context()), booking.ID)
+		switch {
+		case err != nil:
+			log.Printf("Failed to refund booking %d: %v", booking.ID, err)
+			c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodePaymentError, "Booking is cancelled, but the refund failed", gin.H{"refund": refund}))
+		case refund != nil:
+			c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled successfully", "refund": refund})
+		default:
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeNotCancellable, "Booking is already cancelled", nil))
 		}
-	}()
-
-	// Update booking status
-	if err := tx.Model(&booking).Update("status", "cancelled").Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to cancel booking",
-		})
 		return
 	}
 
-	// Update ticket status back to available
-	if err := tx.Model(&booking.Ticket).Updates(map[string]interface{}{
-		"status":  "available",
-		"user_id": nil,
-	}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update ticket",
-		})
+	if !booking.Status.CanTransitionTo(models.BookingCancelled) {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeNotCancellable, "Booking cannot be cancelled", gin.H{"status": booking.Status}))
 		return
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to cancel booking",
+	// Captured before the update, which overwrites booking.Status
+	previousStatus := booking.Status
+	wasReserved := booking.Status == models.BookingReserved
+
+	// A paid booking is refunded only once the cancellation has committed,
+	// so no money moves for a cancellation that fails. The refund is
+	// recorded as requested in the same transaction and stays owed even if
+	// the provider is never reached.
+	var refund *models.Refund
+	if booking.Status == models.BookingConfirmed && booking.PaymentID != "" {
+		// Bookings confirmed before AmountPaid existed fall back to the price
+		amount := booking.AmountPaid
+		if amount == 0 {
+			amount = booking.Ticket.Price
+		}
+		refund = &models.Refund{
+			BookingID: booking.ID,
+			PaymentID: booking.PaymentID,
+			Amount:    amount,
+			Currency:  chargeCurrency(&booking),
+			Status:    models.RefundRequested,
+		}
+	}
+
+	// The ticket the booking holds: reserved for a reservation, booked for
+	// a confirmed booking
+	heldStatus := models.TicketReserved
+	if previousStatus == models.BookingConfirmed {
+		heldStatus = models.TicketBooked
+	}
+
+	// Return the ticket in a transaction, retried on deadlock, and release
+	// the ticket's lock once it commits
+	var releaseTier bool
+	err = s.txRunner.RunWithTicketLock(c.Request.Context(), booking.TicketID, booking.UserID, func(tx *gorm.DB) error {
+		releaseTier = false
+
+		// Claim the booking; of two concurrent cancels only one gets here,
+		// so only one refunds
+		result := tx.Model(&booking).Where("status = ?", previousStatus).Update("status", models.BookingCancelled)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update booking: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errBookingNotCancellable
+		}
+
+		// Return the ticket to sale while the booking still holds it
+		result = tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", booking.TicketID, heldStatus).Updates(map[string]interface{}{
+			"status":  models.TicketAvailable,
+			"user_id": nil,
 		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update ticket: %w", result.Error)
+		}
+
+		// Return the ticket to its tier along with it
+		if result.RowsAffected > 0 && booking.Ticket.TierID != nil {
+			if err := tx.Model(&models.TicketTier{}).Where("id = ?", *booking.Ticket.TierID).
+				Update("available_count", gorm.Expr("available_count + 1")).Error; err != nil {
+				return fmt.Errorf("failed to update ticket tier: %w", err)
+			}
+			releaseTier = true
+		}
+
+		// The returned ticket puts a sold-out event back on sale
+		if err := checkAndMarkSoldOut(tx, booking.Ticket.EventID); err != nil {
+			return err
+		}
+
+		if refund != nil {
+			if err := tx.Create(refund).Error; err != nil {
+				return fmt.Errorf("failed to record refund: %w", err)
+			}
+		}
+
+		return outbox.RecordBooking(tx, &booking, booking.Ticket.EventID)
+	})
+	if errors.Is(err, errBookingNotCancellable) {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeNotCancellable, "Booking was changed by another request", nil))
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to cancel booking %d: %v", booking.ID, err)
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to cancel booking", nil))
 		return
 	}
 
-	// Release Redis lock if it exists
-	s.redisClient.UnlockTicket(context.Background(), booking.TicketID)
+	if releaseTier {
+		s.releaseTierSlot(c.Request.Context(), *booking.Ticket.TierID)
+	}
+	s.invalidateTicketStatus(c.Request.Context(), booking.TicketID)
+	s.bus.Publish(eventbus.TopicBookingCancelled, bookingChanged(&booking, previousStatus))
+	s.bus.Publish(eventbus.TopicTicketAvailable, eventbus.TicketAvailable{TicketID: booking.TicketID, EventID: booking.Ticket.EventID})
+	s.dispatchAdmissions(c.Request.Context(), booking.Ticket.EventID)
+	// Confirmed bookings already left the funnel; cancelling them is not a drop-off
+	if wasReserved {
+		metrics.ObserveFunnel(metrics.FunnelCancelled, booking.ReservedAt)
+	}
+	s.publishBookingEvent(c.Request.Context(), kafka.BookingCancelled, &booking, gin.H{"eventId": booking.Ticket.EventID})
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Booking cancelled successfully",
-	})
+	}
+	if refund != nil {
+		// The cancellation is committed, so the refund is asked for even if
+		// the client disconnects
+		if err := s.refundBooking(context.WithoutCancel(c.Request.Context()), refund); err != nil {
+			log.Printf("Failed to refund booking %d: %v", booking.ID, err)
+			c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodePaymentError, "Booking cancelled, but the refund failed", gin.H{"refund": refund}))
+			return
+		}
+		response["refund"] = refund
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBookingRefund returns the refund of one of the caller's bookings
+func (s *Service) GetBookingRefund(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
+		return
+	}
+
+	// Extract and validate JWT token
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	// Other users' bookings are reported as not found
+	var booking models.Booking
+	if err := s.db.WithContext(c.Request.Context()).First(&booking, "id = ? AND user_id = ?", uint(bookingID), claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "Booking not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
+		return
+	}
+
+	var refund models.Refund
+	if err := s.db.WithContext(c.Request.Context()).Where("booking_id = ?", booking.ID).Order("id DESC").First(&refund).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeRefundNotFound, "No refund for this booking", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch refund", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
 }
 
 func (s *Service) GetUserBookings(c *gin.Context) {
 	userIDStr := c.Param("userId")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid user ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid user ID", nil))
 		return
 	}
 
 	// Extract and validate JWT token
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Authorization header required",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
 		return
 	}
 
 	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid authorization header",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
 		return
 	}
 
 	claims, err := auth.ValidateToken(s.config, tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid token",
-		})
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
 		return
 	}
 
-	// Verify user can only access their own bookings
-	if claims.UserID != uint(userID) {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Access denied",
-		})
+	// Verify user can only access their own bookings; admins see anyone's
+	if claims.UserID != uint(userID) && !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Access denied", nil))
 		return
 	}
 
-	var bookings []models.Booking
-	result := s.db.Preload("Ticket").Preload("Ticket.Event").Preload("Ticket.Event.Venue").Preload("Ticket.Event.Performer").Find(&bookings, "user_id = ?", uint(userID))
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch bookings",
-		})
+	bookings, err := s.bookings.ListByUser(c.Request.Context(), uint(userID), store.Page{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch bookings", nil))
 		return
 	}
 
+	// Give reservations about to expire a little longer
+	s.extendExpiringReservations(c.Request.Context(), bookings)
+
 	c.JSON(http.StatusOK, gin.H{
 		"bookings": bookings,
 		"count":    len(bookings),
 	})
 }
 
+// reserveWithAdvisoryLock reserves a ticket in degraded mode, serializing
+// competing requests with a transaction-scoped Postgres advisory lock instead
+// of the Redis lock. The conditional status update keeps the seat exclusive.
+func (s *Service) reserveWithAdvisoryLock(c *gin.Context, ticket *models.Ticket, userID uint, price money.Amount) {
+	booking := models.Booking{
+		TicketID:   ticket.ID,
+		UserID:     userID,
+		Status:     models.BookingReserved,
+		ReservedAt: time.Now(),
+		ExpiresAt:  time.Now().Add(s.reservationWindow(ticket.Event)),
+		Price:      price,
+	}
+	err := s.bookings.ReserveWithAdvisoryLock(c.Request.Context(), &booking, ticket)
+
+	switch {
+	case errors.Is(err, store.ErrTicketContended):
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTicketLocked, "Ticket is currently being processed by another user", nil))
+		return
+	case errors.Is(err, store.ErrTierSoldOut):
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeTierSoldOut, "Ticket tier is sold out", nil))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create booking", nil))
+		return
+	}
+
+	metrics.DegradedReservations.Inc()
+	if ticket.TierID != nil {
+		s.staleTiers.Store(*ticket.TierID, true)
+	}
+	s.invalidateTicketStatus(c.Request.Context(), ticket.ID)
+	s.adjustEventCounters(c.Request.Context(), ticket.EventID, redis.EventCounters{Available: -1})
+	s.scoreEvent(c.Request.Context(), ticket.EventID, redis.PopularityReservation)
+	s.publishChange(c.Request.Context(), ticket.EventID)
+	metrics.ObserveFunnel(metrics.FunnelReserved, booking.ReservedAt)
+	s.publishBookingEvent(c.Request.Context(), kafka.BookingReserved, &booking, gin.H{"eventId": ticket.EventID, "expiresAt": booking.ExpiresAt, "degraded": true})
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookingId": booking.ID,
+		"ticketId":  ticket.ID,
+		"expiresAt": booking.ExpiresAt,
+		"price":     booking.Price,
+		"currency":  s.eventCurrency(ticket.Event),
+		"message":   "Ticket reserved successfully",
+	})
+}
+
+// errBookingNotReserved means the booking left the reserved state before it
+// could be confirmed
+var errBookingNotReserved = errors.New("booking is no longer reserved")
+
+// errBookingNotCancellable means the booking changed status before it
+// could be cancelled
+var errBookingNotCancellable = errors.New("booking can no longer be cancelled")
+
+// finalizeBooking confirms a paid reservation in a transaction, retried on
+// deadlock: the booking, its ticket, the event's sold-out flag and the
+// outbox change. The ticket's lock is released once it commits. The
+// booking must be loaded with its Ticket.
+func (s *Service) finalizeBooking(ctx context.Context, booking *models.Booking, paymentID string) error {
+	if !booking.Status.CanTransitionTo(models.BookingConfirmed) {
+		return errBookingNotReserved
+	}
+	err := s.txRunner.RunWithTicketLock(ctx, booking.TicketID, booking.UserID, func(tx *gorm.DB) error {
+		// Update booking status unless a concurrent confirm or release won
+		result := tx.Model(booking).Where("status = ?", models.BookingReserved).Updates(map[string]interface{}{
+			"status":      models.BookingConfirmed,
+			"payment_id":  paymentID,
+			"amount_paid": chargeAmount(booking),
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update booking: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errBookingNotReserved
+		}
+
+		// Update ticket status and assign to user; a reserved booking's
+		// ticket is reserved for it
+		result = tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", booking.TicketID, models.TicketReserved).Updates(map[string]interface{}{
+			"status":  models.TicketBooked,
+			"user_id": booking.UserID,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to update ticket: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errBookingNotReserved
+		}
+		booking.Ticket.Status = models.TicketBooked
+		booking.Ticket.UserID = &booking.UserID
+
+		// Mark the event sold out if this was its last ticket
+		if err := checkAndMarkSoldOut(tx, booking.Ticket.EventID); err != nil {
+			return err
+		}
+
+		return outbox.RecordBooking(tx, booking, booking.Ticket.EventID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.bus.Publish(eventbus.TopicBookingConfirmed, bookingChanged(booking, models.BookingReserved))
+	s.scoreEvent(ctx, booking.Ticket.EventID, redis.PopularityConfirmation)
+	metrics.ObserveFunnel(metrics.FunnelConfirmed, booking.ReservedAt)
+	return nil
+}
+
+// expireReservation removes an expired reservation and returns its ticket to
+// sale: the ticket, its tier count, the event's sold-out flag and the outbox
+// change are written in one transaction, then the Redis lock and tier counter
+// are released. The booking must be loaded with its Ticket. It returns
+// errBookingNotReserved when the booking is no longer reserved.
+func (s *Service) expireReservation(ctx context.Context, booking *models.Booking) error {
+	if !booking.Status.CanTransitionTo(models.BookingExpired) {
+		return errBookingNotReserved
+	}
+	err := database.WithTransaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		// Only a booking still reserved expires; one confirmed or cancelled
+		// since it was read keeps its ticket
+		result := tx.Model(booking).Where("status = ?", models.BookingReserved).Update("status", models.BookingExpired)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errBookingNotReserved
+		}
+
+		result = tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", booking.TicketID, models.TicketReserved).
+			Update("status", models.TicketAvailable)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 && booking.Ticket.TierID != nil {
+			if err := tx.Model(&models.TicketTier{}).Where("id = ?", *booking.Ticket.TierID).
+				Update("available_count", gorm.Expr("available_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		if err := checkAndMarkSoldOut(tx, booking.Ticket.EventID); err != nil {
+			return err
+		}
+		return outbox.RecordBooking(tx, booking, booking.Ticket.EventID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.redisClient.UnlockTicket(context.WithoutCancel(ctx), booking.TicketID, booking.LockToken)
+	if booking.Ticket.TierID != nil {
+		s.releaseTierSlot(ctx, *booking.Ticket.TierID)
+	}
+	metrics.ObserveFunnel(metrics.FunnelExpired, booking.ReservedAt)
+	s.invalidateTicketStatus(ctx, booking.TicketID)
+	s.bus.Publish(eventbus.TopicTicketAvailable, eventbus.TicketAvailable{TicketID: booking.TicketID, EventID: booking.Ticket.EventID})
+	s.dispatchAdmissions(ctx, booking.Ticket.EventID)
+	s.publishBookingEvent(ctx, kafka.BookingExpired, booking, gin.H{"eventId": booking.Ticket.EventID, "expiredAt": booking.ExpiresAt})
+	return nil
+}
+
+// releaseTierSlot returns a ticket to its tier's Redis counter. A counter
+// that cannot be updated is re-seeded from the database on next use.
+func (s *Service) releaseTierSlot(ctx context.Context, tierID uint) {
+	if err := s.redisClient.ReleaseTierSlot(context.WithoutCancel(ctx), tierID); err != nil {
+		s.staleTiers.Store(tierID, true)
+	}
+}
+
+// reservationWindow is how long a reservation for the event holds its
+// ticket: the event's override if it has one, else the configured default
+func (s *Service) reservationWindow(event *models.Event) time.Duration {
+	if event != nil && event.ReservationWindowMinutes != nil && *event.ReservationWindowMinutes > 0 {
+		return time.Duration(*event.ReservationWindowMinutes) * time.Minute
+	}
+	return time.Duration(s.config.ReservationWindowMinutes) * time.Minute
+}
+
+// sendConfirmationEmail emails the booking details to the user. The booking
+// is already confirmed, so a failure is only logged.
+func (s *Service) sendConfirmationEmail(ctx context.Context, to string, booking *models.Booking, paymentID string) {
+	eventName := "your event"
+	if booking.Ticket.Event != nil {
+		eventName = booking.Ticket.Event.Name
+	}
+
+	msg := email.Email{
+		To:      to,
+		Subject: fmt.Sprintf("Booking confirmed: %s", eventName),
+		Body: fmt.Sprintf("Your booking %d for %s is confirmed.\nSeat: %s\n%s\nPayment: %s",
+			booking.ID, eventName, booking.Ticket.Seat, receiptLines(booking), paymentID),
+	}
+	if err := s.emailSender.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send confirmation email for booking %d: %v", booking.ID, err)
+	}
+}
+
+// The helpers below follow a committed change. They keep ctx's values but
+// not its cancellation, so a client that disconnects after the commit
+// still gets its caches and subscribers updated.
+
+// invalidateTicketStatus drops the ticket's cached seat availability so the
+// seat picker sees the change before the cache entry expires
+func (s *Service) invalidateTicketStatus(ctx context.Context, ticketID uint) {
+	// While the circuit is open the entry is left to expire on its own
+	if s.breaker.IsOpen() {
+		return
+	}
+	if err := s.redisClient.InvalidateTicketStatuses(context.WithoutCancel(ctx), ticketID); err != nil {
+		log.Printf("Failed to invalidate cached status of ticket %d: %v", ticketID, err)
+	}
+}
+
+// adjustEventCounters keeps the event's capacity counters in step with a
+// booking change. Failures are only logged; GET /events/:id/capacity
+// recounts from Postgres once the counters are gone.
+func (s *Service) adjustEventCounters(ctx context.Context, eventID uint, delta redis.EventCounters) {
+	if err := s.redisClient.AdjustEventCounters(context.WithoutCancel(ctx), eventID, delta); err != nil {
+		log.Printf("Failed to adjust capacity counters of event %d: %v", eventID, err)
+	}
+}
+
+// scoreEvent raises the event's popularity score for GET /events/trending.
+// Failures are only logged.
+func (s *Service) scoreEvent(ctx context.Context, eventID uint, delta float64) {
+	if s.breaker.IsOpen() {
+		return
+	}
+	if err := s.redisClient.IncrEventScore(context.WithoutCancel(ctx), eventID, delta); err != nil {
+		log.Printf("Failed to score event %d: %v", eventID, err)
+	}
+}
+
+// publishChange tells the CDC service that an event's availability changed
+// when pub/sub sync is enabled. Failures are only logged; the periodic sync
+// picks the change up anyway.
+func (s *Service) publishChange(ctx context.Context, eventID uint) {
+	if !s.config.CDCPubSubEnabled {
+		return
+	}
+	msg := redis.ChangeMessage{EventID: eventID, Op: outbox.OpUpsert}
+	if err := s.redisClient.PublishChange(context.WithoutCancel(ctx), msg); err != nil {
+		log.Printf("Failed to publish change for event %d: %v", eventID, err)
+	}
+}
+
+// publishBookingEvent records a state transition on the booking events topic
+// in the background. The transition is already committed, so a failure is
+// only logged.
+func (s *Service) publishBookingEvent(ctx context.Context, eventType string, booking *models.Booking, payload gin.H) {
+	if s.kafkaClient == nil {
+		return
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to marshal %s event for booking %d: %v", eventType, booking.ID, err)
+		return
+	}
+
+	event := kafka.BookingEvent{
+		EventType: eventType,
+		BookingID: booking.ID,
+		UserID:    booking.UserID,
+		TicketID:  booking.TicketID,
+		Timestamp: time.Now(),
+		Payload:   payloadJSON,
+	}
+
+	// A booking's events are published one at a time by the same worker, so
+	// consumers never see its confirmation before its reservation
+	queue := s.bookingEvents[booking.ID%uint(len(s.bookingEvents))]
+	err = queue.Submit(jobs.Job{
+		ID: fmt.Sprintf("%s-%d", eventType, booking.ID),
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, bookingEventTimeout)
+			defer cancel()
+			return s.kafkaClient.PublishBookingEvent(ctx, s.config.BookingEventsTopic, event)
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to publish %s event for booking %d: %v", eventType, booking.ID, err)
+	}
+}
+
 func (s *Service) HealthCheck(c *gin.Context) {
+	if err := s.dbHealth.Err(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "NOT_READY",
+			"service":  "booking-service",
+			"database": err.Error(),
+		})
+		return
+	}
+
+	// Reservations fall back to advisory locks without Redis, so an outage
+	// degrades the service instead of taking it out of rotation
+	if err := s.redisHealth.Err(); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "degraded",
+			"service":     "booking-service",
+			"redis":       err.Error(),
+			"lockBackend": s.lockBackendMode(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "booking-service",
+		"status":      "healthy",
+		"service":     "booking-service",
+		"lockBackend": s.lockBackendMode(),
 	})
 }
+
+// lockBackendMode reports where reservations take their locks: the Redis
+// client's backend, or "advisory" while the circuit breaker is open
+func (s *Service) lockBackendMode() string {
+	if s.breaker.IsOpen() {
+		return lockBackendAdvisory
+	}
+	return s.redisClient.LockBackendMode()
+}
+
+// WatchRedis probes Redis until ctx is done; the health check reports
+// degraded while probes fail
+func (s *Service) WatchRedis(ctx context.Context) {
+	s.redisHealth.Watch(ctx)
+}
+
+// WatchDatabase pings the database until ctx is done; the health check
+// reports NOT_READY while pings fail
+func (s *Service) WatchDatabase(ctx context.Context) {
+	s.dbHealth.Watch(ctx, s.db)
+}
@@ -0,0 +1,387 @@
+package booking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/email"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// testEnv is a booking service on sqlite and the in-memory Redis store,
+// with charges that always succeed at once
+type testEnv struct {
+	service *Service
+	router  *gin.Engine
+	db      *gorm.DB
+	redis   *inmem.Store
+	emails  *email.MockSender
+	config  *config.Config
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		JWTSecret:                "test-secret",
+		JWTExpiry:                time.Hour,
+		ReservationWindowMinutes: 10,
+		LockKeeperMax:            10,
+		PaymentWorkers:           1,
+		PaymentQueueSize:         10,
+		PaymentRetryLimit:        3,
+		MockStripeEnabled:        true,
+		MockStripeSuccessRate:    1,
+		MockStripeLatency:        "0s",
+		DefaultCurrency:          "twd",
+	}
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig()
+	db := testutil.NewDB(t)
+	store := inmem.New()
+	t.Cleanup(func() { store.Close() })
+	emails := email.NewMockSender()
+
+	s := NewService(db, store, cfg, payment.NewMockStripeClient(cfg), emails, notify.NewLogNotifier(), nil)
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+
+	router := gin.New()
+	s.SetupRoutes(router)
+	return &testEnv{service: s, router: router, db: db, redis: store, emails: emails, config: cfg}
+}
+
+// seedTicket creates an event with one available ticket and returns the
+// ticket with its Event loaded
+func (env *testEnv) seedTicket(t *testing.T) *models.Ticket {
+	t.Helper()
+	venue := models.Venue{Location: "Taipei Arena", Capacity: 100}
+	performer := models.Performer{Name: "Band"}
+	if err := env.db.Create(&venue).Error; err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+	if err := env.db.Create(&performer).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+	event := models.Event{
+		VenueID:     venue.ID,
+		PerformerID: performer.ID,
+		Name:        "Spring Concert",
+		Date:        time.Now().Add(30 * 24 * time.Hour),
+		Currency:    "twd",
+	}
+	if err := env.db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	ticket := models.Ticket{EventID: event.ID, Seat: "A1", Price: money.FromFloat(1000), Status: models.TicketAvailable}
+	if err := env.db.Create(&ticket).Error; err != nil {
+		t.Fatalf("failed to create ticket: %v", err)
+	}
+	ticket.Event = &event
+	return &ticket
+}
+
+// seedUser creates a user and returns it with an access token
+func (env *testEnv) seedUser(t *testing.T, emailAddr string) (*models.User, string) {
+	t.Helper()
+	user := models.User{Email: emailAddr, Password: "hash", Name: strings.Split(emailAddr, "@")[0], Role: models.RoleUser}
+	if err := env.db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	token, err := auth.GenerateToken(env.config, user.ID, user.Email, user.Role, "")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	return &user, token
+}
+
+// do sends a JSON request through the service's routes
+func (env *testEnv) do(t *testing.T, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// reserve reserves the ticket for the token's user and returns the booking ID
+func (env *testEnv) reserve(t *testing.T, token string, ticketID uint) uint {
+	t.Helper()
+	rec := env.do(t, http.MethodPost, "/booking/reserve", token, gin.H{"ticketId": ticketID})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reserve returned %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		BookingID uint `json:"bookingId"`
+	}
+	decode(t, rec, &resp)
+	return resp.BookingID
+}
+
+// drainPayments waits for the queued charges to finish
+func (env *testEnv) drainPayments(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := env.service.paymentJobs.Stop(ctx); err != nil {
+		t.Fatalf("payment jobs did not finish: %v", err)
+	}
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, dest interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), dest); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body, err)
+	}
+}
+
+func TestConfirmBookingSendsConfirmationEmail(t *testing.T) {
+	env := newTestEnv(t)
+	ticket := env.seedTicket(t)
+	user, token := env.seedUser(t, "alice@example.com")
+
+	bookingID := env.reserve(t, token, ticket.ID)
+	rec := env.do(t, http.MethodPut, "/booking/confirm", token, gin.H{"ticketId": ticket.ID, "paymentDetails": "pm_card_visa"})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("confirm returned %d: %s", rec.Code, rec.Body)
+	}
+	env.drainPayments(t)
+
+	var booking models.Booking
+	if err := env.db.First(&booking, bookingID).Error; err != nil {
+		t.Fatalf("failed to load booking: %v", err)
+	}
+	if booking.Status != models.BookingConfirmed {
+		t.Fatalf("booking is %s, want confirmed", booking.Status)
+	}
+
+	sent := env.emails.SentEmails()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sent))
+	}
+	msg := sent[0]
+	if msg.To != user.Email {
+		t.Errorf("email sent to %q, want %q", msg.To, user.Email)
+	}
+	if want := "Booking confirmed: " + ticket.Event.Name; msg.Subject != want {
+		t.Errorf("email subject %q, want %q", msg.Subject, want)
+	}
+	if want := fmt.Sprintf("Your booking %d ", bookingID); !strings.Contains(msg.Body, want) {
+		t.Errorf("email body %q does not name booking %d", msg.Body, bookingID)
+	}
+}
+
+// pendingRefundClient accepts refunds without settling them, as Stripe
+// does for some payment methods
+type pendingRefundClient struct{ *payment.MockStripeClient }
+
+func (pendingRefundClient) RefundPayment(ctx context.Context, paymentIntentID string, amount money.Money, idempotencyKey string) (*payment.PaymentResponse, error) {
+	return &payment.PaymentResponse{
+		PaymentIntent: &payment.PaymentIntent{ID: "re_1", Amount: amount, Status: models.RefundPending},
+	}, nil
+}
+
+func TestPendingRefundSettlesOnChargeRefunded(t *testing.T) {
+	ctx := context.Background()
+	env := newTestEnv(t)
+	env.service.paymentClient = pendingRefundClient{payment.NewMockStripeClient(env.config)}
+	ticket := env.seedTicket(t)
+	user, token := env.seedUser(t, "alice@example.com")
+	bookingID := env.reserve(t, token, ticket.ID)
+
+	record := models.Payment{IntentID: "pi_1", BookingID: bookingID, UserID: user.ID, Amount: money.FromFloat(1000), Currency: "twd", Status: models.PaymentSucceeded, Provider: "mock"}
+	if err := env.db.Create(&record).Error; err != nil {
+		t.Fatalf("failed to create payment: %v", err)
+	}
+
+	if err := env.service.refundUnconfirmedCharge(ctx, &record, record.IntentID); err != nil {
+		t.Fatalf("pending refund returned %v", err)
+	}
+	var refund models.Refund
+	env.db.First(&refund, "payment_id = ?", record.IntentID)
+	if refund.Status != models.RefundPending || refund.StripeRefundID != "re_1" {
+		t.Fatalf("refund is %s (%q), want pending re_1", refund.Status, refund.StripeRefundID)
+	}
+	env.db.First(&record, record.ID)
+	if record.Status != models.PaymentSucceeded {
+		t.Fatalf("payment is %s before the refund settled, want succeeded", record.Status)
+	}
+
+	if err := env.service.handleChargeRefunded(ctx, &payment.WebhookObject{ID: "ch_1", PaymentIntent: record.IntentID}); err != nil {
+		t.Fatalf("charge.refunded failed: %v", err)
+	}
+	env.db.First(&refund, refund.ID)
+	env.db.First(&record, record.ID)
+	if refund.Status != models.RefundSucceeded || record.Status != models.PaymentRefunded {
+		t.Errorf("refund is %s and payment %s after charge.refunded, want succeeded and refunded", refund.Status, record.Status)
+	}
+}
+
+// expectError fails the test unless the response has the wanted status and
+// error code
+func expectError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	var resp apperrors.ErrorResponse
+	decode(t, rec, &resp)
+	if resp.Code != code {
+		t.Fatalf("got error code %q, want %q", resp.Code, code)
+	}
+}
+
+func TestReserveTicket(t *testing.T) {
+	env := newTestEnv(t)
+	ticket := env.seedTicket(t)
+	alice, aliceToken := env.seedUser(t, "alice@example.com")
+	_, bobToken := env.seedUser(t, "bob@example.com")
+
+	bookingID := env.reserve(t, aliceToken, ticket.ID)
+
+	var booking models.Booking
+	if err := env.db.Preload("Ticket").First(&booking, bookingID).Error; err != nil {
+		t.Fatalf("failed to load booking: %v", err)
+	}
+	if booking.Status != models.BookingReserved || booking.UserID != alice.ID {
+		t.Errorf("booking is %s for user %d, want reserved for %d", booking.Status, booking.UserID, alice.ID)
+	}
+	if booking.Ticket.Status != models.TicketReserved {
+		t.Errorf("ticket is %s, want reserved", booking.Ticket.Status)
+	}
+	if owner, err := env.redis.GetTicketLockOwner(context.Background(), ticket.ID); err != nil || owner != alice.ID {
+		t.Errorf("ticket lock held by %d (err %v), want %d", owner, err, alice.ID)
+	}
+
+	rec := env.do(t, http.MethodPost, "/booking/reserve", bobToken, gin.H{"ticketId": ticket.ID})
+	expectError(t, rec, http.StatusConflict, apperrors.ErrCodeTicketUnavailable)
+}
+
+func TestReserveTicketErrors(t *testing.T) {
+	env := newTestEnv(t)
+	ticket := env.seedTicket(t)
+	_, token := env.seedUser(t, "alice@example.com")
+
+	rec := env.do(t, http.MethodPost, "/booking/reserve", "", gin.H{"ticketId": ticket.ID})
+	expectError(t, rec, http.StatusUnauthorized, apperrors.ErrCodeUnauthorized)
+
+	rec = env.do(t, http.MethodPost, "/booking/reserve", token, gin.H{"ticketId": ticket.ID + 100})
+	expectError(t, rec, http.StatusNotFound, apperrors.ErrCodeTicketNotFound)
+
+	// Another user is mid-reservation and holds the lock
+	if _, err := env.redis.LockTicket(context.Background(), ticket.ID, 99, time.Minute); err != nil {
+		t.Fatalf("failed to lock ticket: %v", err)
+	}
+	rec = env.do(t, http.MethodPost, "/booking/reserve", token, gin.H{"ticketId": ticket.ID})
+	expectError(t, rec, http.StatusConflict, apperrors.ErrCodeTicketLocked)
+
+	var count int64
+	env.db.Model(&models.Booking{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d bookings created by failed reservations", count)
+	}
+}
+
+func TestConfirmBookingErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no reservation", func(t *testing.T) {
+		env := newTestEnv(t)
+		ticket := env.seedTicket(t)
+		_, token := env.seedUser(t, "alice@example.com")
+
+		rec := env.do(t, http.MethodPut, "/booking/confirm", token, gin.H{"ticketId": ticket.ID, "paymentDetails": "pm_card_visa"})
+		expectError(t, rec, http.StatusNotFound, apperrors.ErrCodeBookingNotFound)
+	})
+
+	t.Run("expired reservation", func(t *testing.T) {
+		env := newTestEnv(t)
+		ticket := env.seedTicket(t)
+		_, token := env.seedUser(t, "alice@example.com")
+		bookingID := env.reserve(t, token, ticket.ID)
+		if err := env.db.Model(&models.Booking{}).Where("id = ?", bookingID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+			t.Fatalf("failed to backdate reservation: %v", err)
+		}
+
+		rec := env.do(t, http.MethodPut, "/booking/confirm", token, gin.H{"ticketId": ticket.ID, "paymentDetails": "pm_card_visa"})
+		expectError(t, rec, http.StatusGone, apperrors.ErrCodeReservationExpired)
+
+		var reloaded models.Ticket
+		env.db.First(&reloaded, ticket.ID)
+		if reloaded.Status != models.TicketAvailable {
+			t.Errorf("ticket is %s after its reservation expired, want available", reloaded.Status)
+		}
+		if locked, _ := env.redis.IsTicketLocked(ctx, ticket.ID); locked {
+			t.Error("expired reservation kept its ticket lock")
+		}
+
+		var expired models.Booking
+		if err := env.db.First(&expired, bookingID).Error; err != nil {
+			t.Fatalf("expired booking is gone: %v", err)
+		}
+		if expired.Status != models.BookingExpired {
+			t.Errorf("booking is %s after it expired, want expired", expired.Status)
+		}
+	})
+
+	t.Run("lock taken by another user", func(t *testing.T) {
+		env := newTestEnv(t)
+		ticket := env.seedTicket(t)
+		_, token := env.seedUser(t, "alice@example.com")
+		bookingID := env.reserve(t, token, ticket.ID)
+
+		var booking models.Booking
+		env.db.First(&booking, bookingID)
+		if err := env.redis.UnlockTicket(ctx, ticket.ID, booking.LockToken); err != nil {
+			t.Fatalf("failed to release lock: %v", err)
+		}
+		if _, err := env.redis.LockTicket(ctx, ticket.ID, 99, time.Minute); err != nil {
+			t.Fatalf("failed to lock ticket: %v", err)
+		}
+
+		rec := env.do(t, http.MethodPut, "/booking/confirm", token, gin.H{"ticketId": ticket.ID, "paymentDetails": "pm_card_visa"})
+		expectError(t, rec, http.StatusConflict, apperrors.ErrCodeLockReleased)
+
+		var count int64
+		env.db.Model(&models.Payment{}).Count(&count)
+		if count != 0 {
+			t.Errorf("%d payments recorded for a booking that lost its lock", count)
+		}
+	})
+}
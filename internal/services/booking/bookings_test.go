@@ -0,0 +1,111 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	memstore "github.com/JonasLeetTheWay/ticketmaster-go/internal/store/inmem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newStoreService returns a booking service backed by the in-memory stores
+// alone, for handlers that do not touch the database directly
+func newStoreService(t *testing.T) (*Service, *memstore.Store, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	rows := memstore.New()
+	s := &Service{
+		tickets:  rows.Tickets(),
+		bookings: rows.Bookings(),
+		users:    rows.Users(),
+		config:   testConfig(),
+	}
+
+	router := gin.New()
+	router.GET("/booking/user/:userId", s.GetUserBookings)
+	return s, rows, router
+}
+
+// reserveInStore books a new ticket of event for the user in rows
+func reserveInStore(t *testing.T, rows *memstore.Store, eventID, userID uint, seat string) uint {
+	t.Helper()
+	ticket := models.Ticket{EventID: eventID, Seat: seat, Price: money.FromFloat(1000)}
+	ticket.ID = rows.AddTicket(ticket)
+	booking := models.Booking{
+		TicketID:   ticket.ID,
+		UserID:     userID,
+		Status:     models.BookingReserved,
+		ReservedAt: time.Now(),
+		ExpiresAt:  time.Now().Add(10 * time.Minute),
+		Price:      ticket.Price,
+	}
+	if err := rows.Bookings().Reserve(context.Background(), &booking, &ticket); err != nil {
+		t.Fatalf("failed to reserve %s: %v", seat, err)
+	}
+	return booking.ID
+}
+
+func TestGetUserBookingsFromStore(t *testing.T) {
+	s, rows, router := newStoreService(t)
+	event := rows.AddEvent(models.Event{Name: "Spring Concert", Date: time.Now().Add(24 * time.Hour)})
+	alice := rows.AddUser(models.User{Email: "alice@example.com", Role: models.RoleUser})
+	bob := rows.AddUser(models.User{Email: "bob@example.com", Role: models.RoleUser})
+	first := reserveInStore(t, rows, event, alice, "A1")
+	second := reserveInStore(t, rows, event, alice, "A2")
+	reserveInStore(t, rows, event, bob, "A3")
+
+	token := func(userID uint, role string) string {
+		token, err := auth.GenerateToken(s.config, userID, "user@example.com", role, "")
+		if err != nil {
+			t.Fatalf("failed to issue token: %v", err)
+		}
+		return token
+	}
+	get := func(userID uint, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/booking/user/%d", userID), nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(alice, token(alice, models.RoleUser))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Bookings []models.Booking `json:"bookings"`
+		Count    int              `json:"count"`
+	}
+	decode(t, rec, &resp)
+	if resp.Count != 2 || len(resp.Bookings) != 2 || resp.Bookings[0].ID != first || resp.Bookings[1].ID != second {
+		t.Fatalf("got %d bookings %+v, want alice's %d and %d", resp.Count, resp.Bookings, first, second)
+	}
+	if ticket := resp.Bookings[0].Ticket; ticket.Seat != "A1" || ticket.Event == nil || ticket.Event.Name != "Spring Concert" {
+		t.Errorf("booking ticket is %+v, want seat A1 with its event", ticket)
+	}
+
+	expectError(t, get(alice, token(bob, models.RoleUser)), http.StatusForbidden, apperrors.ErrCodeForbidden)
+	expectError(t, get(alice, ""), http.StatusUnauthorized, apperrors.ErrCodeUnauthorized)
+
+	rec = get(alice, token(bob, models.RoleAdmin))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("admin got status %d: %s", rec.Code, rec.Body)
+	}
+	decode(t, rec, &resp)
+	if resp.Count != 2 {
+		t.Errorf("admin saw %d of alice's bookings, want 2", resp.Count)
+	}
+}
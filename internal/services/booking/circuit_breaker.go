@@ -0,0 +1,99 @@
+package booking
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/metrics"
+)
+
+const (
+	// circuitFailureThreshold Redis errors within circuitFailureWindow open the circuit
+	circuitFailureThreshold = 5
+	circuitFailureWindow    = 30 * time.Second
+
+	// circuitProbeInterval is how often Redis is pinged while the circuit is open
+	circuitProbeInterval = 10 * time.Second
+
+	// lockBackendAdvisory is the lock backend the health check reports
+	// while the circuit is open
+	lockBackendAdvisory = "advisory"
+)
+
+// RedisCircuitBreaker tracks Redis failures so the booking service can fall
+// back to Postgres advisory locks while Redis is unavailable
+type RedisCircuitBreaker struct {
+	mu       sync.Mutex
+	failures []time.Time
+	open     bool
+}
+
+func NewRedisCircuitBreaker() *RedisCircuitBreaker {
+	metrics.RedisCircuitOpen.Set(0)
+	return &RedisCircuitBreaker{}
+}
+
+// IsOpen reports whether Redis should be bypassed
+func (b *RedisCircuitBreaker) IsOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// RecordFailure registers a Redis error and opens the circuit once the
+// threshold is reached within the failure window
+func (b *RedisCircuitBreaker) RecordFailure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	recent := b.failures[:0]
+	for _, t := range b.failures {
+		if now.Sub(t) < circuitFailureWindow {
+			recent = append(recent, t)
+		}
+	}
+	b.failures = append(recent, now)
+
+	if !b.open && len(b.failures) >= circuitFailureThreshold {
+		b.open = true
+		metrics.RedisCircuitOpen.Set(1)
+		log.Printf("WARNING: Redis circuit opened after %d errors in %s (last: %v); using Postgres advisory locks",
+			len(b.failures), circuitFailureWindow, err)
+	}
+}
+
+// close resumes normal Redis operation
+func (b *RedisCircuitBreaker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.open {
+		log.Println("Redis recovered, circuit closed")
+	}
+	b.open = false
+	b.failures = nil
+	metrics.RedisCircuitOpen.Set(0)
+}
+
+// Probe pings Redis while the circuit is open and closes it on recovery
+func (b *RedisCircuitBreaker) Probe(ctx context.Context, ping func(ctx context.Context) error) {
+	ticker := time.NewTicker(circuitProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !b.IsOpen() {
+				continue
+			}
+			if err := ping(ctx); err == nil {
+				b.close()
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package booking
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/email"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedisDialFailuresOpenBreakerToAdvisoryLocks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := testConfig()
+	server := miniredis.RunT(t)
+	host, port, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatalf("failed to parse miniredis address: %v", err)
+	}
+	cfg.RedisHost, cfg.RedisPort, cfg.RedisMaxRetries = host, port, -1
+	client, err := redis.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	db := testutil.NewDB(t)
+	s := NewService(db, client, cfg, payment.NewMockStripeClient(cfg), email.NewMockSender(), notify.NewLogNotifier(), nil)
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	router := gin.New()
+	s.SetupRoutes(router)
+	env := &testEnv{service: s, router: router, db: db, config: cfg}
+	_, token := env.seedUser(t, "alice@example.com")
+
+	server.Close()
+	for i := 1; i <= circuitFailureThreshold; i++ {
+		ticket := env.seedTicket(t)
+		rec := env.do(t, http.MethodPost, "/booking/reserve", token, gin.H{"ticketId": ticket.ID})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("reservation %d got status %d with Redis down: %s", i, rec.Code, rec.Body)
+		}
+
+		var booking models.Booking
+		if err := db.Where("ticket_id = ?", ticket.ID).First(&booking).Error; err != nil {
+			t.Fatalf("failed to load booking %d: %v", i, err)
+		}
+		if i < circuitFailureThreshold {
+			// Dial failures below the threshold are bridged in process
+			if s.breaker.IsOpen() || booking.LockToken == "" {
+				t.Fatalf("reservation %d: breaker open %v, lock token %q; want an in-memory lock", i, s.breaker.IsOpen(), booking.LockToken)
+			}
+			continue
+		}
+		if !s.breaker.IsOpen() || booking.LockToken != "" {
+			t.Fatalf("reservation %d: breaker open %v, lock token %q; want the advisory lock", i, s.breaker.IsOpen(), booking.LockToken)
+		}
+	}
+	if mode := s.lockBackendMode(); mode != lockBackendAdvisory {
+		t.Errorf("lock backend is %s with the breaker open, want %s", mode, lockBackendAdvisory)
+	}
+}
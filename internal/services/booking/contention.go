@@ -0,0 +1,45 @@
+package booking
+
+import (
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultContendedLimit = 20
+	maxContendedLimit     = 100
+)
+
+// GetContendedTickets lists the tickets whose locks collide most often, to
+// spot bots targeting seats (admin only). Counts cover the last day of
+// conflicts.
+func (s *Service) GetContendedTickets(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	limit := defaultContendedLimit
+	if value := c.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxContendedLimit {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "limit must be between 1 and 100", nil))
+			return
+		}
+	}
+
+	tickets, err := s.redisClient.TopContendedTickets(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Failed to read lock contention", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tickets": tickets,
+		"count":   len(tickets),
+	})
+}
@@ -0,0 +1,70 @@
+package booking
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// extensionThreshold is how close to expiry a reservation must be for
+	// viewing it to extend it
+	extensionThreshold = 2 * time.Minute
+
+	// extensionStep is how much one extension adds to a reservation
+	extensionStep = 5 * time.Minute
+)
+
+// extendExpiringReservations gives reservations about to expire another
+// extensionStep, up to each booking's MaxExtensions. A user viewing their
+// bookings this close to expiry is most likely on their way to confirm.
+// The ticket lock is renewed first; a reservation whose lock is gone is
+// left to expire. The bookings are updated in place.
+func (s *Service) extendExpiringReservations(ctx context.Context, bookings []models.Booking) {
+	now := time.Now()
+	for i := range bookings {
+		booking := &bookings[i]
+		if booking.Status != models.BookingReserved || booking.ExtensionCount >= booking.MaxExtensions ||
+			!booking.ExpiresAt.After(now) || booking.ExpiresAt.After(now.Add(extensionThreshold)) {
+			continue
+		}
+
+		expiresAt := booking.ExpiresAt.Add(extensionStep)
+
+		// Reservations made while the circuit was open have no lock to renew
+		if booking.LockToken != "" {
+			if s.breaker.IsOpen() {
+				continue
+			}
+			renewed, err := s.redisClient.RenewTicketLock(ctx, booking.TicketID, booking.LockToken, time.Until(expiresAt))
+			if err != nil {
+				s.breaker.RecordFailure(err)
+				log.Printf("Failed to extend lock of booking %d: %v", booking.ID, err)
+				continue
+			}
+			if !renewed {
+				continue
+			}
+		}
+
+		// Conditional on the expiry read, so two concurrent views extend once
+		result := s.db.WithContext(ctx).Model(&models.Booking{}).
+			Where("id = ? AND status = ? AND extension_count < max_extensions AND expires_at = ?", booking.ID, models.BookingReserved, booking.ExpiresAt).
+			Updates(map[string]interface{}{
+				"expires_at":      expiresAt,
+				"extension_count": gorm.Expr("extension_count + 1"),
+			})
+		if result.Error != nil {
+			log.Printf("Failed to extend booking %d: %v", booking.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 1 {
+			booking.ExpiresAt = expiresAt
+			booking.ExtensionCount++
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/jobs"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/kafka"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Payment job states
+const (
+	PaymentJobQueued     = "queued"
+	PaymentJobProcessing = "processing"
+	PaymentJobSucceeded  = "succeeded"
+	PaymentJobFailed     = "failed"
+
+	// PaymentJobAwaitingConfirmation means the charge needs 3D Secure or
+	// settles later; the webhook confirms the booking
+	PaymentJobAwaitingConfirmation = "awaiting_confirmation"
+)
+
+// paymentJobTTL is how long a payment job's status stays in Redis
+const paymentJobTTL = 24 * time.Hour
+
+// PaymentJobStatus is the progress of a background charge, kept in Redis
+// for GET /booking/:id/payment-status
+type PaymentJobStatus struct {
+	JobID     string    `json:"jobId"`
+	BookingID uint      `json:"bookingId"`
+	Status    string    `json:"status"`
+	PaymentID string    `json:"paymentId,omitempty"`
+	ErrorCode string    `json:"errorCode,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// paymentJob charges for a reservation and confirms it. The booking must be
+// loaded with its Ticket and the Ticket's Event.
+type paymentJob struct {
+	booking       models.Booking
+	record        models.Payment
+	attempt       int64 // Of the booking's charges, from beginPayment
+	email         string
+	paymentMethod string
+	mockOutcome   payment.MockOutcome
+}
+
+// paymentJobID names the job charging a payment record
+func paymentJobID(paymentID uint) string {
+	return fmt.Sprintf("payment-%d", paymentID)
+}
+
+func paymentJobKey(jobID string) string {
+	return "payment_job:" + jobID
+}
+
+// submitPaymentJob queues the charge. A payment that cannot be queued is
+// marked failed, since it was never attempted.
+func (s *Service) submitPaymentJob(ctx context.Context, job *paymentJob) (*PaymentJobStatus, error) {
+	status := &PaymentJobStatus{
+		JobID:     paymentJobID(job.record.ID),
+		BookingID: job.booking.ID,
+		Status:    PaymentJobQueued,
+	}
+	s.savePaymentJobStatus(ctx, status)
+
+	err := s.paymentJobs.Submit(jobs.Job{
+		ID: status.JobID,
+		Run: func(ctx context.Context) error {
+			return s.runPaymentJob(ctx, job, status)
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to queue payment for booking %d: %v", job.booking.ID, err)
+		s.abandonPayment(ctx, &job.record, err.Error())
+		status.Status = PaymentJobFailed
+		status.ErrorCode = apperrors.ErrCodeUpstreamUnavailable
+		status.Error = err.Error()
+		s.savePaymentJobStatus(ctx, status)
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// runPaymentJob charges the customer and confirms the booking, recording
+// each step in the job status
+func (s *Service) runPaymentJob(ctx context.Context, job *paymentJob, status *PaymentJobStatus) error {
+	booking := &job.booking
+
+	status.Status = PaymentJobProcessing
+	s.savePaymentJobStatus(ctx, status)
+
+	// The reservation may have expired or been cancelled while the job was
+	// queued; charging it then would take money for a seat it no longer holds
+	if err := s.checkStillReserved(ctx, booking.ID); err != nil {
+		s.abandonPayment(ctx, &job.record, err.Error())
+		code := apperrors.ErrCodeInternal
+		if errors.Is(err, errBookingNotReserved) {
+			code = apperrors.ErrCodeReservationExpired
+		}
+		s.failPaymentJob(ctx, status, code, "Reservation is no longer active")
+		return fmt.Errorf("not charging booking %d: %w", booking.ID, err)
+	}
+
+	// Keep the lock alive while the payment is in flight so a slow charge
+	// cannot outlive it
+	keepCtx, stopKeeping := context.WithCancel(ctx)
+	defer stopKeeping()
+	if !s.breaker.IsOpen() && booking.LockToken != "" {
+		if err := s.lockKeeper.Keep(keepCtx, booking.TicketID, booking.LockToken); err != nil {
+			log.Printf("Not renewing lock on ticket %d during payment: %v", booking.TicketID, err)
+		}
+	}
+
+	paymentReq := &payment.PaymentRequest{
+		Amount:         money.New(job.record.Amount, job.record.Currency),
+		UserID:         booking.UserID,
+		TicketID:       booking.TicketID,
+		BookingID:      booking.ID,
+		PaymentMethod:  job.paymentMethod,
+		IdempotencyKey: paymentIdempotencyKey(booking.ID, job.attempt),
+		MockOutcome:    job.mockOutcome,
+	}
+
+	paymentResp, err := s.paymentClient.CreatePaymentIntent(ctx, paymentReq)
+	if err != nil {
+		// The charge may or may not have gone through; the payment stays
+		// pending for reconciliation
+		code := apperrors.ErrCodePaymentError
+		var stripeErr *payment.Error
+		if errors.As(err, &stripeErr) {
+			code = stripeErr.AppCode()
+		}
+		s.failPaymentJob(ctx, status, code, "Payment processing failed")
+		return fmt.Errorf("failed to charge booking %d: %w", booking.ID, err)
+	}
+
+	// The charge went through, so its outcome is recorded even if the job is
+	// cancelled from here on
+	ctx = context.WithoutCancel(ctx)
+	s.recordPaymentResult(ctx, &job.record, paymentResp)
+	if paymentResp.PaymentIntent != nil {
+		status.PaymentID = paymentResp.PaymentIntent.ID
+	}
+
+	// Charges needing 3D Secure or settling later are confirmed by webhook
+	if paymentResp.Pending() {
+		status.Status = PaymentJobAwaitingConfirmation
+		s.savePaymentJobStatus(ctx, status)
+		return nil
+	}
+
+	if !paymentResp.Success {
+		s.failPaymentJob(ctx, status, apperrors.ErrCodePaymentFailed, paymentResp.Error)
+		if err := s.releaseAfterFailedPayment(ctx, booking); err != nil {
+			return fmt.Errorf("failed to release booking %d after declined payment: %w", booking.ID, err)
+		}
+		return nil
+	}
+
+	// The lock is released once the confirmation commits, or kept with
+	// what is left of its TTL if it fails
+	stopKeeping()
+	if err := s.finalizeBooking(ctx, booking, paymentResp.PaymentIntent.ID); err != nil {
+		if errors.Is(err, errBookingNotReserved) {
+			// The payment webhook may have confirmed the booking first
+			var current models.Booking
+			if database.Primary(s.db.WithContext(ctx)).Select("status").First(&current, booking.ID).Error == nil && current.Status == models.BookingConfirmed {
+				status.Status = PaymentJobSucceeded
+				s.savePaymentJobStatus(ctx, status)
+				return nil
+			}
+
+			// Otherwise the reservation ended while the charge was in
+			// flight, and the customer gets the money back
+			s.failPaymentJob(ctx, status, apperrors.ErrCodeReservationExpired, "Reservation ended before the payment went through; the charge is refunded")
+			if err := s.refundUnconfirmedCharge(ctx, &job.record, paymentResp.PaymentIntent.ID); err != nil {
+				return fmt.Errorf("failed to refund booking %d after its reservation ended: %w", booking.ID, err)
+			}
+			return nil
+		}
+		s.failPaymentJob(ctx, status, apperrors.ErrCodeInternal, "Failed to confirm booking")
+		return fmt.Errorf("failed to confirm booking %d: %w", booking.ID, err)
+	}
+
+	s.invalidateTicketStatus(ctx, booking.TicketID)
+	s.dispatchAdmissions(ctx, booking.Ticket.EventID)
+	s.publishBookingEvent(ctx, kafka.BookingConfirmed, booking, gin.H{"eventId": booking.Ticket.EventID, "paymentId": paymentResp.PaymentIntent.ID})
+	s.sendConfirmationEmail(ctx, job.email, booking, paymentResp.PaymentIntent.ID)
+
+	status.Status = PaymentJobSucceeded
+	s.savePaymentJobStatus(ctx, status)
+	return nil
+}
+
+// checkStillReserved fails with errBookingNotReserved unless the booking
+// is reserved and its reservation has not expired, reading the primary
+func (s *Service) checkStillReserved(ctx context.Context, bookingID uint) error {
+	var current models.Booking
+	err := database.Primary(s.db.WithContext(ctx)).Select("status", "expires_at").First(&current, bookingID).Error
+	if err == gorm.ErrRecordNotFound {
+		return errBookingNotReserved
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch booking: %w", err)
+	}
+	if current.Status != models.BookingReserved || time.Now().After(current.ExpiresAt) {
+		return errBookingNotReserved
+	}
+	return nil
+}
+
+// abandonPayment marks a payment that was never sent to the provider
+// failed, so it neither blocks a retry nor shows up in reconciliation
+func (s *Service) abandonPayment(ctx context.Context, record *models.Payment, reason string) {
+	if err := s.db.WithContext(context.WithoutCancel(ctx)).Model(record).Where("status = ?", models.PaymentPending).Updates(map[string]interface{}{
+		"status": models.PaymentFailed,
+		"error":  reason,
+	}).Error; err != nil {
+		log.Printf("Failed to mark payment %d for booking %d failed: %v", record.ID, record.BookingID, err)
+	}
+}
+
+func (s *Service) failPaymentJob(ctx context.Context, status *PaymentJobStatus, code, message string) {
+	status.Status = PaymentJobFailed
+	status.ErrorCode = code
+	status.Error = message
+	s.savePaymentJobStatus(ctx, status)
+}
+
+// savePaymentJobStatus stores the job status. It is best effort: without
+// Redis the status endpoint falls back to the booking and payment rows.
+// The status is saved even when ctx is cancelled, so it never lags behind
+// the job.
+func (s *Service) savePaymentJobStatus(ctx context.Context, status *PaymentJobStatus) {
+	if s.breaker.IsOpen() {
+		return
+	}
+	status.UpdatedAt = time.Now()
+	if err := s.redisClient.SetJSON(context.WithoutCancel(ctx), paymentJobKey(status.JobID), status, paymentJobTTL); err != nil {
+		log.Printf("Failed to save status of payment job %s: %v", status.JobID, err)
+	}
+}
+
+// GetPaymentStatus reports the progress of the latest charge for one of the
+// caller's bookings, for polling after ConfirmBooking returns 202
+func (s *Service) GetPaymentStatus(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
+		return
+	}
+
+	// Extract and validate JWT token
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	// Reservations expired before the status was stored are soft-deleted but
+	// still have a status to report
+	var booking models.Booking
+	if err := s.db.WithContext(c.Request.Context()).Unscoped().First(&booking, "id = ? AND user_id = ?", uint(bookingID), claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "Booking not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
+		return
+	}
+	if booking.DeletedAt.Valid {
+		booking.Status = models.BookingExpired
+	}
+
+	response := gin.H{
+		"bookingId":     booking.ID,
+		"bookingStatus": booking.Status,
+		"paymentId":     booking.PaymentID,
+	}
+
+	var record models.Payment
+	err = s.db.WithContext(c.Request.Context()).Where("booking_id = ?", booking.ID).Order("id DESC").First(&record).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch payment", nil))
+		return
+	}
+	if err == nil {
+		response["paymentStatus"] = record.Status
+		if record.Status == models.PaymentFailed && booking.Status == models.BookingReserved {
+			if attempts, err := s.paymentAttempts(c.Request.Context(), booking.ID); err == nil {
+				response["remainingRetries"] = s.retriesRemaining(attempts)
+			}
+		}
+
+		var status PaymentJobStatus
+		if !s.breaker.IsOpen() {
+			found, err := s.redisClient.GetJSON(c.Request.Context(), paymentJobKey(paymentJobID(record.ID)), &status)
+			if err != nil {
+				log.Printf("Failed to read status of payment job for booking %d: %v", booking.ID, err)
+			} else if found {
+				response["job"] = status
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,216 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errPaymentInProgress means the booking already has a pending or
+// succeeded payment, so charging it again could take the money twice
+var errPaymentInProgress = errors.New("booking already has a payment in progress")
+
+// errRetryLimitReached means the booking has used all its payment retries
+var errRetryLimitReached = errors.New("no payment retries left")
+
+// beginPayment records a pending payment for a reserved booking and
+// returns it with its attempt number, counting from 1. The booking's row
+// is locked while its payments are checked, so of two concurrent confirms
+// or retries only one gets past a pending or succeeded payment; the others
+// fail with errPaymentInProgress. The partial unique index on
+// payments(booking_id) backs this up. A booking that is no longer reserved
+// fails with errBookingNotReserved, and one that already made maxAttempts
+// charges with errRetryLimitReached; zero means no limit. The booking must
+// carry its Receipt.
+func (s *Service) beginPayment(ctx context.Context, booking *models.Booking, userID uint, maxAttempts int64) (*models.Payment, int64, error) {
+	record := models.Payment{
+		BookingID: booking.ID,
+		UserID:    userID,
+		Amount:    booking.Receipt.Total,
+		Currency:  booking.Receipt.Currency,
+		Status:    models.PaymentPending,
+		Provider:  s.paymentClient.Provider(),
+	}
+	var attempts int64
+	err := database.WithTransaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		var current models.Booking
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			First(&current, "id = ? AND status = ?", booking.ID, models.BookingReserved).Error
+		if err == gorm.ErrRecordNotFound {
+			return errBookingNotReserved
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock booking: %w", err)
+		}
+
+		var active int64
+		if err := tx.Model(&models.Payment{}).
+			Where("booking_id = ? AND status IN ?", booking.ID, []string{models.PaymentPending, models.PaymentSucceeded}).
+			Count(&active).Error; err != nil {
+			return fmt.Errorf("failed to check payments: %w", err)
+		}
+		if active > 0 {
+			return errPaymentInProgress
+		}
+
+		if err := tx.Model(&models.Payment{}).Where("booking_id = ?", booking.ID).Count(&attempts).Error; err != nil {
+			return fmt.Errorf("failed to count payment attempts: %w", err)
+		}
+		if maxAttempts > 0 && attempts >= maxAttempts {
+			return errRetryLimitReached
+		}
+		attempts++
+
+		if err := tx.Create(&record).Error; err != nil {
+			if database.IsUniqueViolation(err) {
+				return errPaymentInProgress
+			}
+			return fmt.Errorf("failed to record payment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &record, attempts, nil
+}
+
+// paymentIdempotencyKey keys a booking's nth charge with the payment
+// provider. The key depends on the booking rather than the payment row, so
+// a charge that somehow gets submitted twice is still taken once, while a
+// retry after a failure gets a fresh intent.
+func paymentIdempotencyKey(bookingID uint, attempt int64) string {
+	return fmt.Sprintf("booking-%d-charge-%d", bookingID, attempt)
+}
+
+// recordPaymentResult stores the provider's answer on a pending payment.
+// A failure is only logged: the payment stays pending and shows up in
+// reconciliation.
+func (s *Service) recordPaymentResult(ctx context.Context, record *models.Payment, resp *payment.PaymentResponse) {
+	status := models.PaymentFailed
+	switch {
+	case resp.Success:
+		status = models.PaymentSucceeded
+	case resp.Pending():
+		status = models.PaymentPending
+	}
+
+	updates := map[string]interface{}{
+		"status": status,
+		"error":  resp.Error,
+	}
+	if resp.PaymentIntent != nil {
+		updates["intent_id"] = resp.PaymentIntent.ID
+	}
+	if err := s.db.WithContext(ctx).Model(record).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record result of payment %d for booking %d: %v", record.ID, record.BookingID, err)
+	}
+}
+
+// GetBookingPayment returns the latest payment attempt of one of the
+// caller's bookings
+func (s *Service) GetBookingPayment(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
+		return
+	}
+
+	// Extract and validate JWT token
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	// Other users' bookings are reported as not found
+	var booking models.Booking
+	if err := s.db.WithContext(c.Request.Context()).First(&booking, "id = ? AND user_id = ?", uint(bookingID), claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "Booking not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
+		return
+	}
+
+	var record models.Payment
+	if err := s.db.WithContext(c.Request.Context()).Where("booking_id = ?", booking.ID).Order("id DESC").First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodePaymentNotFound, "No payment for this booking", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch payment", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// ListUnreconciledPayments lists payments that succeeded or never got an
+// answer but have no confirmed booking and no succeeded refund: money that
+// may have been taken without a seat to show for it (admin only). A refund
+// that was only requested, or failed, still leaves the money taken.
+func (s *Service) ListUnreconciledPayments(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	var payments []models.Payment
+	err := s.db.WithContext(c.Request.Context()).Where("payments.status NOT IN ?", []string{models.PaymentFailed, models.PaymentRefunded}).
+		Where("NOT EXISTS (SELECT 1 FROM bookings b WHERE b.id = payments.booking_id AND b.status = ? AND b.deleted_at IS NULL)", models.BookingConfirmed).
+		Where("NOT EXISTS (SELECT 1 FROM refunds r WHERE r.payment_id = payments.intent_id AND payments.intent_id <> '' AND r.status = ?)", models.RefundSucceeded).
+		Order("payments.id").
+		Find(&payments).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch payments", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payments": payments,
+		"count":    len(payments),
+	})
+}
+
+// requireAdmin validates the bearer token and checks for the admin role,
+// writing the error response and returning false otherwise
+func (s *Service) requireAdmin(c *gin.Context) bool {
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return false
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return false
+	}
+
+	if !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,225 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// legacyCurrency is what every charge was made in before events had a
+// currency
+const legacyCurrency = "twd"
+
+// buildReceipt prices a ticket with the configured service fee and the tax
+// rate of the venue's country. The fee and tax are computed exactly and
+// rounded half away from zero line by line; the total is rounded once from
+// the exact sum, and any difference to the rounded lines goes to Rounding.
+// The flat fee is taken to be in the ticket's currency.
+func (s *Service) buildReceipt(faceValue money.Amount, currency, country string) models.Receipt {
+	country = strings.ToUpper(country)
+	taxRate, ok := s.config.TaxRates[country]
+	if !ok {
+		taxRate = s.config.DefaultTaxRate
+	}
+
+	face := new(big.Rat).SetInt64(faceValue.Minor())
+
+	// Service fee: a percentage of face value plus a flat amount
+	fee := new(big.Rat).Mul(face, percent(s.config.ServiceFeePercent))
+	fee.Add(fee, new(big.Rat).SetInt64(money.FromFloat(s.config.ServiceFeeFlat).Minor()))
+
+	// Tax applies to the face value and the fee
+	taxable := new(big.Rat).Add(face, fee)
+	tax := new(big.Rat).Mul(taxable, percent(taxRate))
+
+	receipt := models.Receipt{
+		Currency:   currency,
+		FaceValue:  faceValue,
+		ServiceFee: roundMinor(fee),
+		TaxCountry: country,
+		TaxRate:    taxRate,
+		Tax:        roundMinor(tax),
+		Total:      roundMinor(new(big.Rat).Add(taxable, tax)),
+	}
+	receipt.Rounding = receipt.Total - money.Sum(receipt.FaceValue, receipt.ServiceFee, receipt.Tax)
+	return receipt
+}
+
+// percent converts a percentage such as 8.875 to an exact ratio. The float
+// is read back through its shortest decimal form, so 0.1 means one tenth
+// rather than its binary approximation.
+func percent(p float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(p, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r.Quo(r, big.NewRat(100, 1))
+}
+
+// roundMinor rounds an exact number of minor units half away from zero
+func roundMinor(r *big.Rat) money.Amount {
+	quo, rem := new(big.Int).QuoRem(new(big.Int).Abs(r.Num()), r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return money.FromMinor(quo.Int64())
+}
+
+// ensureReceipt computes and stores the booking's receipt unless it already
+// has one, so retries and later fee changes charge what was first quoted.
+// The booking must be loaded with its Ticket and the Ticket's Event.
+func (s *Service) ensureReceipt(ctx context.Context, booking *models.Booking) error {
+	if booking.Receipt.Total != 0 {
+		return nil
+	}
+
+	var country string
+	if booking.Ticket.Event != nil {
+		if err := s.db.WithContext(ctx).Model(&models.Venue{}).Where("id = ?", booking.Ticket.Event.VenueID).
+			Pluck("country", &country).Error; err != nil {
+			return err
+		}
+	}
+
+	currency := s.eventCurrency(booking.Ticket.Event)
+
+	// Reservations lock in the price; older ones pay the ticket's list price
+	faceValue := booking.Price
+	if faceValue == 0 {
+		faceValue = booking.Ticket.Price
+	}
+
+	receipt := s.buildReceipt(faceValue, currency, country)
+	if err := s.db.WithContext(ctx).Model(booking).Updates(map[string]interface{}{
+		"receipt_currency":    receipt.Currency,
+		"receipt_face_value":  receipt.FaceValue,
+		"receipt_service_fee": receipt.ServiceFee,
+		"receipt_tax_country": receipt.TaxCountry,
+		"receipt_tax_rate":    receipt.TaxRate,
+		"receipt_tax":         receipt.Tax,
+		"receipt_rounding":    receipt.Rounding,
+		"receipt_total":       receipt.Total,
+	}).Error; err != nil {
+		return err
+	}
+	booking.Receipt = receipt
+	return nil
+}
+
+// chargeAmount is what a booking is charged: its receipt total, or the
+// ticket price for reservations confirmed before receipts existed
+func chargeAmount(booking *models.Booking) money.Amount {
+	if booking.Receipt.Total != 0 {
+		return booking.Receipt.Total
+	}
+	return booking.Ticket.Price
+}
+
+// eventCurrency is the currency the event's tickets are priced in
+func (s *Service) eventCurrency(event *models.Event) string {
+	if event != nil && event.Currency != "" {
+		return event.Currency
+	}
+	return s.config.DefaultCurrency
+}
+
+// chargeCurrency is the currency a booking was charged in
+func chargeCurrency(booking *models.Booking) string {
+	if booking.Receipt.Currency != "" {
+		return booking.Receipt.Currency
+	}
+	return legacyCurrency
+}
+
+// receiptResponse is the API shape of a receipt
+func receiptResponse(booking *models.Booking) gin.H {
+	receipt := booking.Receipt
+	if receipt.Total == 0 {
+		// Confirmed before receipts existed: the whole charge was face value
+		receipt = models.Receipt{FaceValue: booking.AmountPaid, Total: booking.AmountPaid}
+	}
+	return gin.H{
+		"bookingId":  booking.ID,
+		"faceValue":  receipt.FaceValue,
+		"serviceFee": receipt.ServiceFee,
+		"taxCountry": receipt.TaxCountry,
+		"taxRate":    receipt.TaxRate,
+		"tax":        receipt.Tax,
+		"rounding":   receipt.Rounding,
+		"total":      receipt.Total,
+		"currency":   chargeCurrency(booking),
+	}
+}
+
+// GetBookingReceipt returns the itemized receipt of one of the caller's
+// confirmed bookings
+func (s *Service) GetBookingReceipt(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
+		return
+	}
+
+	// Extract and validate JWT token
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	var booking models.Booking
+	if err := s.db.WithContext(c.Request.Context()).First(&booking, "id = ? AND user_id = ?", uint(bookingID), claims.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "Booking not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
+		return
+	}
+
+	// Cancelled bookings keep their receipt for the refund paperwork
+	if booking.Status == models.BookingReserved || (booking.Receipt.Total == 0 && booking.AmountPaid == 0) {
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeReceiptNotFound, "Booking has no receipt until it is confirmed", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, receiptResponse(&booking))
+}
+
+// receiptLines formats the receipt for the confirmation email
+func receiptLines(booking *models.Booking) string {
+	receipt := booking.Receipt
+	if receipt.Total == 0 {
+		return fmt.Sprintf("Price: %s", chargeAmount(booking))
+	}
+	lines := []string{
+		fmt.Sprintf("Face value: %s", receipt.FaceValue),
+		fmt.Sprintf("Service fee: %s", receipt.ServiceFee),
+		fmt.Sprintf("Tax (%s%%): %s", strconv.FormatFloat(receipt.TaxRate, 'f', -1, 64), receipt.Tax),
+	}
+	if receipt.Rounding != 0 {
+		lines = append(lines, fmt.Sprintf("Rounding: %s", receipt.Rounding))
+	}
+	lines = append(lines, fmt.Sprintf("Total: %s %s", receipt.Total, strings.ToUpper(receipt.Currency)))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// refundBooking asks the payment provider for a recorded refund and stores
+// the answer on the refund row. A pending refund is stored as pending; the
+// charge.refunded webhook settles it. A failed call marks the refund failed
+// and is returned; the refund then shows up in reconciliation.
+func (s *Service) refundBooking(ctx context.Context, refund *models.Refund) error {
+	resp, err := s.paymentClient.RefundPayment(ctx, refund.PaymentID, money.New(refund.Amount, refund.Currency), refundIdempotencyKey(refund.BookingID))
+	if err == nil && !resp.Success && resp.PaymentIntent.Status != models.RefundPending {
+		err = fmt.Errorf("refund was %s", resp.PaymentIntent.Status)
+	}
+
+	updates := map[string]interface{}{"status": models.RefundFailed}
+	if err == nil {
+		updates = map[string]interface{}{
+			"status":           resp.PaymentIntent.Status,
+			"amount":           resp.PaymentIntent.Amount.Amount(),
+			"stripe_refund_id": resp.PaymentIntent.ID,
+		}
+		refund.Amount = resp.PaymentIntent.Amount.Amount()
+		refund.StripeRefundID = resp.PaymentIntent.ID
+	}
+	refund.Status = updates["status"].(string)
+
+	if dbErr := s.db.WithContext(ctx).Model(refund).Updates(updates).Error; dbErr != nil {
+		log.Printf("Failed to record refund %d of booking %d as %s: %v", refund.ID, refund.BookingID, refund.Status, dbErr)
+	}
+	return err
+}
+
+// refundIdempotencyKey keys a booking's refund with the payment provider.
+// A booking has at most one succeeded charge, so repeated or concurrent
+// refund calls for it, such as a retry after a lost response, refund once.
+func refundIdempotencyKey(bookingID uint) string {
+	return fmt.Sprintf("booking-%d-refund", bookingID)
+}
+
+// retryRefund asks again for a cancelled booking's refund that was never
+// answered or failed. It returns nil when the booking has no such refund.
+func (s *Service) retryRefund(ctx context.Context, bookingID uint) (*models.Refund, error) {
+	var refund models.Refund
+	err := database.Primary(s.db.WithContext(ctx)).
+		Where("booking_id = ? AND status IN ?", bookingID, []string{models.RefundRequested, models.RefundFailed}).
+		First(&refund).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch refund: %w", err)
+	}
+	return &refund, s.refundBooking(ctx, &refund)
+}
+
+// refundUnconfirmedCharge returns a charge that went through after its
+// reservation had ended, so the booking could not be confirmed. The charge
+// is refunded once: whoever records its refund first, the payment job or
+// the webhook, makes the call.
+func (s *Service) refundUnconfirmedCharge(ctx context.Context, record *models.Payment, intentID string) error {
+	refund := models.Refund{
+		BookingID: record.BookingID,
+		PaymentID: intentID,
+		Amount:    record.Amount,
+		Currency:  record.Currency,
+		Status:    models.RefundRequested,
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&refund)
+	if result.Error != nil {
+		return fmt.Errorf("failed to record refund: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	log.Printf("Refunding payment %s: booking %d ended before the charge went through", intentID, record.BookingID)
+	return s.refundBooking(ctx, &refund)
+}
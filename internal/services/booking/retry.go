@@ -0,0 +1,235 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RetryPayment charges a reservation again after its last payment failed.
+// Each retry creates a new payment intent; once the booking has used
+// PaymentRetryLimit retries, a failed charge releases the reservation.
+func (s *Service) RetryPayment(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid booking ID", nil))
+		return
+	}
+
+	// Extract and validate JWT token
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	var req struct {
+		PaymentDetails string `json:"paymentDetails" binding:"required"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	// Read on the primary, where a confirm or failed charge a moment ago is
+	// already visible
+	var booking models.Booking
+	result := database.Primary(s.db.WithContext(c.Request.Context())).Preload("Ticket").Preload("Ticket.Event").First(&booking, "id = ? AND user_id = ? AND status = ?", uint(bookingID), claims.UserID, models.BookingReserved)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this booking", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch booking", nil))
+		return
+	}
+
+	if time.Now().After(booking.ExpiresAt) {
+		if err := s.expireReservation(c.Request.Context(), &booking); err != nil {
+			if errors.Is(err, errBookingNotReserved) {
+				c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this booking", nil))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to release expired reservation", nil))
+			return
+		}
+		c.JSON(http.StatusGone, apperrors.NewResponse(apperrors.ErrCodeReservationExpired, "Reservation has expired", nil))
+		return
+	}
+
+	if !s.checkReservationLock(c, booking.TicketID, claims.UserID) {
+		return
+	}
+
+	// Only a failed charge may be retried; one in flight or awaiting 3D
+	// Secure is still being decided. beginPayment checks this again with
+	// the booking locked, in case a concurrent retry gets there first.
+	var last models.Payment
+	if err := database.Primary(s.db.WithContext(c.Request.Context())).Where("booking_id = ?", booking.ID).Order("id DESC").First(&last).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodePaymentNotRetryable, "Booking has no payment to retry", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch payment", nil))
+		return
+	}
+	if last.Status != models.PaymentFailed {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodePaymentNotRetryable, "Only a failed payment can be retried", gin.H{"paymentStatus": last.Status}))
+		return
+	}
+
+	attempts, err := s.paymentAttempts(c.Request.Context(), booking.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count payment attempts", nil))
+		return
+	}
+	if s.retriesRemaining(attempts) == 0 {
+		s.releaseAfterRetryLimit(c, &booking)
+		return
+	}
+
+	// Retries charge the total quoted on the first attempt
+	if err := s.ensureReceipt(c.Request.Context(), &booking); err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to price booking", nil))
+		return
+	}
+
+	// The first charge and PaymentRetryLimit retries
+	maxAttempts := int64(s.config.PaymentRetryLimit) + 1
+	paymentRecord, attempt, err := s.beginPayment(c.Request.Context(), &booking, claims.UserID, maxAttempts)
+	switch {
+	case errors.Is(err, errPaymentInProgress):
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodePaymentNotRetryable, "A payment for this booking is already in progress", nil))
+		return
+	case errors.Is(err, errRetryLimitReached):
+		s.releaseAfterRetryLimit(c, &booking)
+		return
+	case errors.Is(err, errBookingNotReserved):
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this booking", nil))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to record payment", nil))
+		return
+	}
+	remaining := s.retriesRemaining(attempt - 1)
+
+	// The job's idempotency key names the new attempt, so Stripe creates a
+	// fresh intent instead of replaying the failed one
+	job := &paymentJob{
+		booking:       booking,
+		record:        *paymentRecord,
+		attempt:       attempt,
+		email:         claims.Email,
+		paymentMethod: req.PaymentDetails,
+	}
+	if s.config.MockStripeEnabled {
+		job.mockOutcome = payment.MockOutcome(c.GetHeader(payment.MockOutcomeHeader))
+	}
+	status, err := s.submitPaymentJob(c.Request.Context(), job)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Too many payments in progress, try again shortly", nil))
+		return
+	}
+
+	statusURL := fmt.Sprintf("/booking/%d/payment-status", booking.ID)
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{
+		"bookingId":        booking.ID,
+		"ticketId":         booking.TicketID,
+		"jobId":            status.JobID,
+		"status":           status.Status,
+		"statusUrl":        statusURL,
+		"remainingRetries": remaining - 1,
+		"message":          "Payment is processing",
+	})
+}
+
+// releaseAfterRetryLimit releases a reservation whose payment retries are
+// used up. It is normally released when the last retry fails; this
+// catches up if that did not happen.
+func (s *Service) releaseAfterRetryLimit(c *gin.Context, booking *models.Booking) {
+	if err := s.expireReservation(c.Request.Context(), booking); err != nil {
+		if errors.Is(err, errBookingNotReserved) {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeBookingNotFound, "No active reservation found for this booking", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to release reservation", nil))
+		return
+	}
+	c.JSON(http.StatusGone, apperrors.NewResponse(apperrors.ErrCodeRetryLimitReached, "No payment retries left; the reservation has been released", gin.H{"remainingRetries": 0}))
+}
+
+// checkReservationLock verifies the ticket is still locked by the user. A
+// missing lock is tolerated: reservations made while the Redis circuit was
+// open have none, and the reserved booking and ticket rows still guard the
+// seat. Returns false when a response was written.
+func (s *Service) checkReservationLock(c *gin.Context, ticketID, userID uint) bool {
+	if s.breaker.IsOpen() {
+		return true
+	}
+	lockOwner, err := s.redisClient.GetTicketLockOwner(c.Request.Context(), ticketID)
+	if err != nil && !errors.Is(err, redis.ErrLockNotFound) {
+		s.breaker.RecordFailure(err)
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeLockingUnavailable, "Ticket locking is temporarily unavailable", nil))
+		return false
+	}
+	if err == nil && lockOwner != userID {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeLockReleased, "Ticket lock has been released", nil))
+		return false
+	}
+	return true
+}
+
+// paymentAttempts counts the charges made for a booking, the first one
+// included
+func (s *Service) paymentAttempts(ctx context.Context, bookingID uint) (int64, error) {
+	var attempts int64
+	err := database.Primary(s.db.WithContext(ctx)).Model(&models.Payment{}).Where("booking_id = ?", bookingID).Count(&attempts).Error
+	return attempts, err
+}
+
+// retriesRemaining is how many more charges a booking with the given
+// number of attempts may make
+func (s *Service) retriesRemaining(attempts int64) int {
+	used := int(attempts) - 1
+	if used < 0 {
+		used = 0
+	}
+	if remaining := s.config.PaymentRetryLimit - used; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// releaseAfterFailedPayment releases the reservation once its payment
+// retries are used up. Until then the reservation stays held so the user
+// can retry. The booking must be loaded with its Ticket.
+func (s *Service) releaseAfterFailedPayment(ctx context.Context, booking *models.Booking) error {
+	attempts, err := s.paymentAttempts(ctx, booking.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count payment attempts: %w", err)
+	}
+	if s.retriesRemaining(attempts) > 0 {
+		return nil
+	}
+	return s.expireReservation(ctx, booking)
+}
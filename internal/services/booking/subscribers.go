@@ -0,0 +1,81 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/eventbus"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+)
+
+// subscribe registers what reacts to committed booking changes: the event
+// capacity counters, the CDC change notification and the user's
+// notification. They run on the bus's goroutines, after the response.
+func (s *Service) subscribe() {
+	s.bus.Subscribe(eventbus.TopicBookingConfirmed, s.trackCapacity)
+	s.bus.Subscribe(eventbus.TopicBookingCancelled, s.trackCapacity)
+	s.bus.Subscribe(eventbus.TopicTicketAvailable, s.trackCapacity)
+
+	s.bus.Subscribe(eventbus.TopicBookingConfirmed, s.triggerCDCSync)
+	s.bus.Subscribe(eventbus.TopicTicketAvailable, s.triggerCDCSync)
+
+	s.bus.Subscribe(eventbus.TopicBookingConfirmed, s.notifyUser)
+	s.bus.Subscribe(eventbus.TopicBookingCancelled, s.notifyUser)
+}
+
+// bookingChanged describes booking's move out of previous
+func bookingChanged(booking *models.Booking, previous models.BookingStatus) eventbus.BookingChanged {
+	return eventbus.BookingChanged{
+		BookingID:      booking.ID,
+		UserID:         booking.UserID,
+		TicketID:       booking.TicketID,
+		EventID:        booking.Ticket.EventID,
+		PreviousStatus: previous,
+	}
+}
+
+// trackCapacity keeps the event's capacity counters in step: a
+// confirmation sells a ticket, cancelling a confirmed booking unsells it,
+// and a ticket back on sale is available again
+func (s *Service) trackCapacity(event eventbus.Event) {
+	switch payload := event.Payload.(type) {
+	case eventbus.BookingChanged:
+		switch {
+		case event.Topic == eventbus.TopicBookingConfirmed:
+			s.adjustEventCounters(context.Background(), payload.EventID, redis.EventCounters{Sold: 1})
+		case payload.PreviousStatus == models.BookingConfirmed:
+			s.adjustEventCounters(context.Background(), payload.EventID, redis.EventCounters{Sold: -1})
+		}
+	case eventbus.TicketAvailable:
+		s.adjustEventCounters(context.Background(), payload.EventID, redis.EventCounters{Available: 1})
+	}
+}
+
+// triggerCDCSync tells the CDC service the event's availability changed
+func (s *Service) triggerCDCSync(event eventbus.Event) {
+	switch payload := event.Payload.(type) {
+	case eventbus.BookingChanged:
+		s.publishChange(context.Background(), payload.EventID)
+	case eventbus.TicketAvailable:
+		s.publishChange(context.Background(), payload.EventID)
+	}
+}
+
+// notifyUser tells the booking's owner it was confirmed or cancelled.
+// Failures are only logged.
+func (s *Service) notifyUser(event eventbus.Event) {
+	payload, ok := event.Payload.(eventbus.BookingChanged)
+	if !ok {
+		return
+	}
+
+	subject, message := "Booking confirmed", fmt.Sprintf("Your booking #%d is confirmed.", payload.BookingID)
+	if event.Topic == eventbus.TopicBookingCancelled {
+		subject, message = "Booking cancelled", fmt.Sprintf("Your booking #%d has been cancelled.", payload.BookingID)
+	}
+	if err := s.notifier.Notify(context.Background(), payload.UserID, subject, message); err != nil {
+		log.Printf("Failed to notify user %d about booking %d: %v", payload.UserID, payload.BookingID, err)
+	}
+}
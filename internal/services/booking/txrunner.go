@@ -0,0 +1,86 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"gorm.io/gorm"
+)
+
+// txLockTTL bounds a lock RunWithTicketLock takes itself, so one left
+// behind by a failed transaction does not hold the ticket for a whole
+// reservation window
+const txLockTTL = 30 * time.Second
+
+// txRunner runs booking transactions under the ticket's Redis lock, so
+// the order of commit and unlock is decided in one place
+type txRunner struct {
+	db          *gorm.DB
+	redisClient redis.Store
+	breaker     *RedisCircuitBreaker
+	maxAttempts int
+}
+
+// RunWithTicketLock runs fn in a transaction, retried on deadlock, on
+// behalf of userID, holding userID's lock on the ticket. A missing lock
+// (expired, or never taken in degraded mode) is taken for the
+// transaction. The lock is released only once fn has committed. When fn
+// fails, the commit fails or fn panics, the lock is left with its
+// remaining TTL, so the user still holds the seat and can retry.
+//
+// fn must update the booking and ticket rows conditionally on their
+// current status, so the rows rather than the lock decide the outcome.
+// Another user's lock therefore does not stop fn: it runs without the
+// lock, which is left to its owner. The same goes for the Redis circuit
+// being open or Redis failing.
+func (r *txRunner) RunWithTicketLock(ctx context.Context, ticketID, userID uint, fn func(tx *gorm.DB) error) error {
+	token := r.holdLock(ctx, ticketID, userID)
+
+	if err := database.RunWithRetry(ctx, r.db, fn, r.maxAttempts); err != nil {
+		return err
+	}
+
+	if token != "" {
+		if err := r.redisClient.UnlockTicket(context.WithoutCancel(ctx), ticketID, token); err != nil {
+			log.Printf("Failed to release lock on ticket %d after commit: %v", ticketID, err)
+		}
+	}
+	return nil
+}
+
+// holdLock returns the token of userID's lock on the ticket, taking the
+// lock if nobody holds it. It returns "" when fn runs without a lock.
+func (r *txRunner) holdLock(ctx context.Context, ticketID, userID uint) string {
+	if r.breaker.IsOpen() {
+		return ""
+	}
+
+	token, err := r.redisClient.TicketLockToken(ctx, ticketID)
+	if err != nil {
+		r.breaker.RecordFailure(err)
+		log.Printf("Running transaction on ticket %d without its lock: %v", ticketID, err)
+		return ""
+	}
+	if token != "" {
+		if owner, err := redis.LockTokenOwner(token); err != nil || owner != userID {
+			log.Printf("Running transaction on ticket %d without its lock: held by another user", ticketID)
+			return ""
+		}
+		return token
+	}
+
+	token, err = r.redisClient.LockTicket(ctx, ticketID, userID, txLockTTL)
+	if errors.Is(err, redis.ErrTicketLocked) {
+		return ""
+	}
+	if err != nil {
+		r.breaker.RecordFailure(err)
+		log.Printf("Running transaction on ticket %d without its lock: %v", ticketID, err)
+		return ""
+	}
+	return token
+}
@@ -0,0 +1,146 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// lockedTicket seeds a ticket locked by a new user and returns the ticket,
+// the user's ID and the lock token
+func (env *testEnv) lockedTicket(t *testing.T) (*models.Ticket, uint, string) {
+	t.Helper()
+	ticket := env.seedTicket(t)
+	user, _ := env.seedUser(t, "alice@example.com")
+	token, err := env.redis.LockTicket(context.Background(), ticket.ID, user.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to lock ticket: %v", err)
+	}
+	return ticket, user.ID, token
+}
+
+// bookTicket is a transaction body that marks the ticket booked
+func bookTicket(ticketID uint) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", ticketID, models.TicketAvailable).
+			Update("status", models.TicketBooked).Error
+	}
+}
+
+// expectOutcome checks the ticket's status and the token its lock holds
+func (env *testEnv) expectOutcome(t *testing.T, ticketID uint, status models.TicketStatus, token string) {
+	t.Helper()
+	var ticket models.Ticket
+	if err := env.db.First(&ticket, ticketID).Error; err != nil {
+		t.Fatalf("failed to load ticket: %v", err)
+	}
+	if ticket.Status != status {
+		t.Errorf("ticket is %s, want %s", ticket.Status, status)
+	}
+	held, err := env.redis.TicketLockToken(context.Background(), ticketID)
+	if err != nil {
+		t.Fatalf("failed to read lock: %v", err)
+	}
+	if held != token {
+		t.Errorf("lock holds %q, want %q", held, token)
+	}
+}
+
+func TestRunWithTicketLockReleasesAfterCommit(t *testing.T) {
+	env := newTestEnv(t)
+	ticket, userID, _ := env.lockedTicket(t)
+
+	if err := env.service.txRunner.RunWithTicketLock(context.Background(), ticket.ID, userID, bookTicket(ticket.ID)); err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+	env.expectOutcome(t, ticket.ID, models.TicketBooked, "")
+}
+
+func TestRunWithTicketLockKeepsLockWhenFnFails(t *testing.T) {
+	env := newTestEnv(t)
+	ticket, userID, token := env.lockedTicket(t)
+
+	declined := errors.New("card declined")
+	err := env.service.txRunner.RunWithTicketLock(context.Background(), ticket.ID, userID, func(tx *gorm.DB) error {
+		if err := bookTicket(ticket.ID)(tx); err != nil {
+			return err
+		}
+		return declined
+	})
+	if !errors.Is(err, declined) {
+		t.Fatalf("got %v, want fn's error", err)
+	}
+	env.expectOutcome(t, ticket.ID, models.TicketAvailable, token)
+}
+
+func TestRunWithTicketLockKeepsLockWhenCommitFails(t *testing.T) {
+	env := newTestEnv(t)
+	ticket, userID, token := env.lockedTicket(t)
+
+	// A deferred foreign key check passes every statement and fails the
+	// commit, as a deferred constraint would in Postgres
+	err := env.service.txRunner.RunWithTicketLock(context.Background(), ticket.ID, userID, func(tx *gorm.DB) error {
+		if err := tx.Exec("PRAGMA defer_foreign_keys = ON").Error; err != nil {
+			return err
+		}
+		if err := bookTicket(ticket.ID)(tx); err != nil {
+			return err
+		}
+		orphan := models.Booking{TicketID: ticket.ID + 100, UserID: userID, Status: models.BookingConfirmed, ExpiresAt: time.Now()}
+		return tx.Create(&orphan).Error
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to commit") {
+		t.Fatalf("got %v, want a commit failure", err)
+	}
+	env.expectOutcome(t, ticket.ID, models.TicketAvailable, token)
+
+	var bookings int64
+	env.db.Model(&models.Booking{}).Count(&bookings)
+	if bookings != 0 {
+		t.Errorf("%d bookings survived the failed commit", bookings)
+	}
+}
+
+func TestRunWithTicketLockKeepsLockWhenFnPanics(t *testing.T) {
+	env := newTestEnv(t)
+	ticket, userID, token := env.lockedTicket(t)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("recovered %v, want fn's panic re-raised", r)
+			}
+		}()
+		env.service.txRunner.RunWithTicketLock(context.Background(), ticket.ID, userID, func(tx *gorm.DB) error {
+			if err := bookTicket(ticket.ID)(tx); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	env.expectOutcome(t, ticket.ID, models.TicketAvailable, token)
+}
+
+func TestRunWithTicketLockTakesMissingLock(t *testing.T) {
+	env := newTestEnv(t)
+	ticket := env.seedTicket(t)
+	user, _ := env.seedUser(t, "alice@example.com")
+
+	// The lock taken for the transaction is left to its short TTL when
+	// the transaction fails
+	err := env.service.txRunner.RunWithTicketLock(context.Background(), ticket.ID, user.ID, func(tx *gorm.DB) error {
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("transaction succeeded")
+	}
+	if owner, err := env.redis.GetTicketLockOwner(context.Background(), ticket.ID); err != nil || owner != user.ID {
+		t.Fatalf("lock held by %d (err %v), want %d", owner, err, user.ID)
+	}
+}
@@ -0,0 +1,240 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/kafka"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxWebhookBytes caps the size of a webhook payload
+const maxWebhookBytes = 1 << 20
+
+// HandleStripeWebhook applies asynchronous payment outcomes. The request
+// is authenticated by its signature, not a token. Each event is applied at
+// most once. Unknown types are acknowledged so Stripe stops retrying them.
+// Errors return 500 so that Stripe redelivers the event.
+func (s *Service) HandleStripeWebhook(c *gin.Context) {
+	if s.config.StripeWebhookSecret == "" {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeInternal, "Webhooks are not configured", nil))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Failed to read webhook", nil))
+		return
+	}
+
+	event, err := payment.ParseWebhook(body, c.GetHeader(payment.SignatureHeader), s.config.StripeWebhookSecret)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid webhook", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var processed models.ProcessedWebhook
+	err = s.db.WithContext(c.Request.Context()).First(&processed, "event_id = ?", event.ID).Error
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"received": true, "duplicate": true})
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to check webhook", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if err := s.applyWebhookEvent(c.Request.Context(), event); err != nil {
+		log.Printf("Failed to handle webhook %s (%s): %v", event.ID, event.Type, err)
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to handle webhook", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// The handlers are idempotent, so two deliveries racing past the check
+	// above do no harm
+	if err := s.db.WithContext(c.Request.Context()).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedWebhook{
+		EventID:  event.ID,
+		Provider: payment.ProviderStripe,
+		Type:     event.Type,
+	}).Error; err != nil {
+		log.Printf("Failed to record webhook %s as processed: %v", event.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+func (s *Service) applyWebhookEvent(ctx context.Context, event *payment.WebhookEvent) error {
+	var object payment.WebhookObject
+	switch event.Type {
+	case payment.EventPaymentIntentSucceeded, payment.EventPaymentIntentFailed, payment.EventChargeRefunded:
+		if err := json.Unmarshal(event.Data.Object, &object); err != nil {
+			return fmt.Errorf("failed to decode %s object: %w", event.Type, err)
+		}
+	default:
+		return nil
+	}
+
+	switch event.Type {
+	case payment.EventPaymentIntentSucceeded:
+		return s.handlePaymentSucceeded(ctx, &object)
+	case payment.EventPaymentIntentFailed:
+		return s.handlePaymentFailed(ctx, &object)
+	default:
+		return s.handleChargeRefunded(ctx, &object)
+	}
+}
+
+// handlePaymentSucceeded confirms the booking the payment intent is for,
+// if it is still reserved. A booking that expired or was cancelled
+// meanwhile is left alone and the charge is refunded.
+func (s *Service) handlePaymentSucceeded(ctx context.Context, intent *payment.WebhookObject) error {
+	record, err := s.findWebhookPayment(ctx, intent)
+	if err != nil || record == nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Model(record).Updates(map[string]interface{}{
+		"intent_id": intent.ID,
+		"status":    models.PaymentSucceeded,
+		"error":     "",
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	var booking models.Booking
+	err = database.Primary(s.db.WithContext(ctx)).Preload("Ticket").Preload("Ticket.Event").First(&booking, "id = ? AND status = ?", record.BookingID, models.BookingReserved).Error
+	if err == gorm.ErrRecordNotFound {
+		return s.refundUnlessConfirmed(ctx, record, intent.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch booking: %w", err)
+	}
+
+	err = s.finalizeBooking(ctx, &booking, intent.ID)
+	if errors.Is(err, errBookingNotReserved) {
+		return s.refundUnlessConfirmed(ctx, record, intent.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.invalidateTicketStatus(ctx, booking.TicketID)
+	s.dispatchAdmissions(ctx, booking.Ticket.EventID)
+	s.publishBookingEvent(ctx, kafka.BookingConfirmed, &booking, gin.H{"eventId": booking.Ticket.EventID, "paymentId": intent.ID})
+
+	user, err := s.users.FindByID(ctx, booking.UserID)
+	if err != nil {
+		log.Printf("Failed to look up user %d to email booking %d: %v", booking.UserID, booking.ID, err)
+		return nil
+	}
+	s.sendConfirmationEmail(ctx, user.Email, &booking, intent.ID)
+	return nil
+}
+
+// refundUnlessConfirmed refunds a succeeded charge unless its booking is
+// confirmed, e.g. by the payment job
+func (s *Service) refundUnlessConfirmed(ctx context.Context, record *models.Payment, intentID string) error {
+	var current models.Booking
+	err := database.Primary(s.db.WithContext(ctx)).Unscoped().Select("status", "deleted_at").First(&current, record.BookingID).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to fetch booking: %w", err)
+	}
+	if err == nil && current.Status == models.BookingConfirmed && !current.DeletedAt.Valid {
+		return nil
+	}
+	return s.refundUnconfirmedCharge(ctx, record, intentID)
+}
+
+// handlePaymentFailed marks the payment failed and releases the
+// reservation it was for once its payment retries are used up
+func (s *Service) handlePaymentFailed(ctx context.Context, intent *payment.WebhookObject) error {
+	record, err := s.findWebhookPayment(ctx, intent)
+	if err != nil || record == nil {
+		return err
+	}
+
+	reason := "payment failed"
+	if intent.LastPaymentError != nil && intent.LastPaymentError.Message != "" {
+		reason = intent.LastPaymentError.Message
+	}
+	if err := s.db.WithContext(ctx).Model(record).Where("status <> ?", models.PaymentSucceeded).Updates(map[string]interface{}{
+		"intent_id": intent.ID,
+		"status":    models.PaymentFailed,
+		"error":     reason,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+
+	var booking models.Booking
+	err = database.Primary(s.db.WithContext(ctx)).Preload("Ticket").First(&booking, "id = ? AND status = ?", record.BookingID, models.BookingReserved).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch booking: %w", err)
+	}
+
+	return s.releaseAfterFailedPayment(ctx, &booking)
+}
+
+// handleChargeRefunded marks the charge's payment refunded and settles its
+// pending refunds
+func (s *Service) handleChargeRefunded(ctx context.Context, charge *payment.WebhookObject) error {
+	if charge.PaymentIntent == "" {
+		return nil
+	}
+	return database.WithTransaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Payment{}).Where("intent_id = ?", charge.PaymentIntent).
+			Update("status", models.PaymentRefunded).Error; err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		if err := tx.Model(&models.Refund{}).Where("payment_id = ? AND status = ?", charge.PaymentIntent, models.RefundPending).
+			Update("status", models.RefundSucceeded).Error; err != nil {
+			return fmt.Errorf("failed to update refunds: %w", err)
+		}
+		return nil
+	})
+}
+
+// findWebhookPayment finds the payment an intent belongs to: by intent ID,
+// or through the booking_id metadata for an intent whose creation never
+// got an answer. Returns nil when the payment is not ours.
+func (s *Service) findWebhookPayment(ctx context.Context, intent *payment.WebhookObject) (*models.Payment, error) {
+	var record models.Payment
+	err := s.db.WithContext(ctx).Where("intent_id = ?", intent.ID).Order("id DESC").First(&record).Error
+	if err == nil {
+		return &record, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to fetch payment: %w", err)
+	}
+
+	bookingID, convErr := strconv.ParseUint(intent.Metadata["booking_id"], 10, 32)
+	if convErr != nil {
+		log.Printf("Ignoring webhook for unknown payment intent %s", intent.ID)
+		return nil, nil
+	}
+
+	err = s.db.WithContext(ctx).Where("booking_id = ? AND status = ?", uint(bookingID), models.PaymentPending).Order("id DESC").First(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		log.Printf("Ignoring webhook for payment intent %s: booking %d has no pending payment", intent.ID, bookingID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment: %w", err)
+	}
+	return &record, nil
+}
@@ -2,37 +2,136 @@ package cdc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/store"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+const (
+	// maxStatusErrors caps how many recent sync errors are kept in SyncStatus
+	maxStatusErrors = 10
+
+	// cdcBatchSize is the number of changed events indexed per batch
+	cdcBatchSize = 100
+
+	// cdcTicketBatchSize is the number of changed tickets scanned per batch
+	cdcTicketBatchSize = 1000
+
+	// cdcCommitLag keeps the newest changes for the next pass, giving
+	// in-flight transactions time to commit
+	cdcCommitLag = 5 * time.Second
+
+	// Change stream names in cdc_checkpoints
+	eventsCheckpoint        = "events"
+	ticketsCheckpoint       = "tickets"
+	deletedEventsCheckpoint = "deleted_events"
+)
+
 type Service struct {
 	db           *gorm.DB
+	events       store.EventStore
+	tickets      store.TicketStore
 	searchClient *elasticsearch.Client
 	config       *config.Config
+	status       atomic.Pointer[SyncStatus]
+	startedAt    time.Time
+	dbHealth     *database.Readiness
+
+	// Leader election; redis is nil when no Redis features are enabled
+	redis      redis.Store
+	instance   LeaderInfo
+	leaseValue string
+	leader     atomic.Bool
+
+	jobsMu    sync.Mutex
+	jobs      map[string]*SyncJob
+	activeJob *SyncJob
+}
+
+// SyncStatus reports the state of the periodic CDC sync
+type SyncStatus struct {
+	LastSyncTime           time.Time `json:"lastSyncTime"`
+	LastSuccessfulSyncTime time.Time `json:"lastSuccessfulSyncTime"`
+	LastSyncCount          int       `json:"lastSyncCount"`
+	TotalSynced            int64     `json:"totalSynced"`
+	ConsecutiveFailures    int       `json:"consecutiveFailures"`
+	Errors                 []string  `json:"errors"`
+	IsRunning              bool      `json:"isRunning"`
+	ReindexInProgress      bool      `json:"reindexInProgress"`
+}
+
+// Watermark is the position of a change stream checkpoint
+type Watermark struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	ID        uint      `json:"id"`
+}
+
+// StatusReport is the sync status plus the persisted checkpoints and the
+// backlog of changes the worker has not indexed yet
+type StatusReport struct {
+	SyncStatus
+	Watermarks     map[string]Watermark `json:"watermarks"`
+	PendingChanges int64                `json:"pendingChanges"`
+	DeadLetters    int64                `json:"deadLetters"`
+	LagSeconds     float64              `json:"lagSeconds"`
+	Leader         LeaderStatus         `json:"leader"`
 }
 
-func NewService(db *gorm.DB, searchClient *elasticsearch.Client, cfg *config.Config) *Service {
-	return &Service{
+func NewService(db *gorm.DB, searchClient *elasticsearch.Client, cfg *config.Config, redisClient redis.Store) *Service {
+	s := &Service{
 		db:           db,
+		events:       store.NewEventStore(db),
+		tickets:      store.NewTicketStore(db),
 		searchClient: searchClient,
 		config:       cfg,
+		startedAt:    time.Now(),
+		dbHealth:     database.NewReadiness("cdc-service", database.HealthCheckInterval),
+		redis:        redisClient,
+		instance:     newInstance(cfg.CDCAdvertiseURL),
+		jobs:         make(map[string]*SyncJob),
 	}
+	s.status.Store(&SyncStatus{Errors: []string{}})
+
+	leaseValue, _ := json.Marshal(s.instance)
+	s.leaseValue = string(leaseValue)
+	// Without election every replica acts as leader
+	s.leader.Store(!cfg.CDCLeaderElection)
+	return s
 }
 
 func (s *Service) SetupRoutes(r *gin.Engine) {
-	r.POST("/cdc/sync-event/:id", s.SyncEvent)
-	r.POST("/cdc/sync-all", s.SyncAllEvents)
+	// Mutations need the internal token
+	internal := auth.InternalAuthMiddleware(s.config)
+
+	r.POST("/cdc/sync-event/:id", internal, s.leaderOnly(), s.SyncEvent)
+	r.POST("/cdc/sync-all", internal, s.leaderOnly(), s.SyncAllEvents)
+	r.POST("/cdc/sync", internal, s.leaderOnly(), s.SyncScoped)
+	r.POST("/cdc/sync-tickets", internal, s.leaderOnly(), s.SyncTickets)
+	r.GET("/cdc/jobs", s.leaderOnly(), s.ListSyncJobs)
+	r.GET("/cdc/jobs/:id", s.leaderOnly(), s.GetSyncJob)
+	r.DELETE("/cdc/jobs/:id", internal, s.leaderOnly(), s.CancelSyncJob)
+	r.GET("/cdc/status", s.GetSyncStatus)
+	r.GET("/cdc/dead-letters", s.ListDeadLetters)
+	r.POST("/cdc/dead-letters/retry", internal, s.leaderOnly(), s.RetryDeadLetters)
 	r.GET("/health", s.HealthCheck)
 }
 
@@ -40,17 +139,23 @@ func (s *Service) SyncEvent(c *gin.Context) {
 	eventIDStr := c.Param("id")
 	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid event ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
 		return
 	}
 
-	if err := s.syncEventByID(context.Background(), uint(eventID)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to sync event",
-			"details": err.Error(),
-		})
+	// Report how the document differs without writing anything
+	if c.Query("dryRun") == "true" {
+		diff, err := s.diffEvent(c.Request.Context(), uint(eventID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to diff event", gin.H{"reason": err.Error()}))
+			return
+		}
+		c.JSON(http.StatusOK, diff)
+		return
+	}
+
+	if err := s.syncEventByID(c.Request.Context(), uint(eventID)); err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to sync event", gin.H{"reason": err.Error()}))
 		return
 	}
 
@@ -59,35 +164,146 @@ func (s *Service) SyncEvent(c *gin.Context) {
 	})
 }
 
-func (s *Service) SyncAllEvents(c *gin.Context) {
-	if err := s.syncAllEvents(context.Background()); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to sync all events",
-			"details": err.Error(),
-		})
+func (s *Service) GetSyncStatus(c *gin.Context) {
+	report, err := s.Report(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to load sync status", gin.H{"reason": err.Error()}))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "All events synced successfully",
-	})
+	c.JSON(http.StatusOK, report)
 }
 
 func (s *Service) HealthCheck(c *gin.Context) {
+	if err := s.dbHealth.Err(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "NOT_READY",
+			"service":  "cdc-service",
+			"database": err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "healthy",
 		"service": "cdc-service",
 	})
 }
 
-// syncEventByID syncs a specific event to Elasticsearch
+// WatchDatabase pings the database until ctx is done; the health check
+// reports NOT_READY while pings fail
+func (s *Service) WatchDatabase(ctx context.Context) {
+	s.dbHealth.Watch(ctx, s.db)
+}
+
+// Status returns a snapshot of the current sync status
+func (s *Service) Status() SyncStatus {
+	status := *s.status.Load()
+	status.Errors = append([]string(nil), status.Errors...)
+	return status
+}
+
+// Report combines the in-memory status with the checkpoint table. Pending
+// changes are unprocessed outbox rows plus event, ticket and deleted-event
+// rows past their watermarks. Lag is the time since the last successful pass
+// (or since startup) while changes are pending, and zero otherwise.
+func (s *Service) Report(ctx context.Context) (*StatusReport, error) {
+	report := &StatusReport{
+		SyncStatus: s.Status(),
+		Watermarks: make(map[string]Watermark),
+		Leader:     s.leaderStatus(ctx),
+	}
+
+	db := s.db.WithContext(ctx)
+	var checkpoints []models.CDCCheckpoint
+	if err := db.Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	watermarks := make(map[string]*models.CDCCheckpoint)
+	for i := range checkpoints {
+		checkpoint := &checkpoints[i]
+		watermarks[checkpoint.Name] = checkpoint
+		report.Watermarks[checkpoint.Name] = Watermark{UpdatedAt: checkpoint.LastUpdatedAt, ID: checkpoint.LastID}
+	}
+	// position returns a checkpoint's watermark as query arguments
+	position := func(name string) []interface{} {
+		if checkpoint, ok := watermarks[name]; ok {
+			return []interface{}{checkpoint.LastUpdatedAt, checkpoint.LastID}
+		}
+		return []interface{}{time.Time{}, uint(0)}
+	}
+
+	counts := []struct {
+		name  string
+		query *gorm.DB
+	}{
+		{"outbox", db.Model(&models.OutboxEvent{}).Where("processed_at IS NULL")},
+		{"events", db.Model(&models.Event{}).Where("(updated_at, id) > (?, ?)", position(eventsCheckpoint)...)},
+		{"tickets", db.Model(&models.Ticket{}).Where("(updated_at, id) > (?, ?)", position(ticketsCheckpoint)...)},
+		{"deleted events", db.Unscoped().Model(&models.Event{}).
+			Where("deleted_at IS NOT NULL").
+			Where("(deleted_at, id) > (?, ?)", position(deletedEventsCheckpoint)...)},
+	}
+	for _, count := range counts {
+		var pending int64
+		if err := count.query.Count(&pending).Error; err != nil {
+			return nil, fmt.Errorf("failed to count pending %s: %w", count.name, err)
+		}
+		report.PendingChanges += pending
+	}
+
+	if err := db.Model(&models.CDCDeadLetter{}).Count(&report.DeadLetters).Error; err != nil {
+		return nil, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+
+	if report.PendingChanges > 0 {
+		since := report.LastSuccessfulSyncTime
+		if since.IsZero() {
+			since = s.startedAt
+		}
+		report.LagSeconds = time.Since(since).Seconds()
+	}
+
+	return report, nil
+}
+
+// updateStatus applies fn to a copy of the current status and swaps it in
+func (s *Service) updateStatus(fn func(status *SyncStatus)) {
+	for {
+		current := s.status.Load()
+		next := *current
+		next.Errors = append([]string(nil), current.Errors...)
+		fn(&next)
+		if len(next.Errors) > maxStatusErrors {
+			next.Errors = next.Errors[len(next.Errors)-maxStatusErrors:]
+		}
+		if s.status.CompareAndSwap(current, &next) {
+			return
+		}
+	}
+}
+
+// syncEventByID syncs a specific event to Elasticsearch. When another
+// writer indexed the event in between, it is read and indexed once more.
 func (s *Service) syncEventByID(ctx context.Context, eventID uint) error {
+	err := s.indexEventFromDB(ctx, eventID)
+	if errors.Is(err, elasticsearch.ErrVersionConflict) {
+		log.Printf("Event %d was indexed concurrently, re-reading it", eventID)
+		err = s.indexEventFromDB(ctx, eventID)
+	}
+	return err
+}
+
+// indexEventFromDB indexes the event as the database has it, or removes it
+// from Elasticsearch if it was deleted
+func (s *Service) indexEventFromDB(ctx context.Context, eventID uint) error {
 	var event models.Event
-	result := s.db.Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, eventID)
+	result := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, eventID)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			// Event was deleted, remove from Elasticsearch
-			return s.searchClient.DeleteEvent(eventID)
+			return s.searchClient.DeleteEvent(ctx, eventID)
 		}
 		return fmt.Errorf("failed to fetch event: %w", result.Error)
 	}
@@ -96,26 +312,7 @@ func (s *Service) syncEventByID(ctx context.Context, eventID uint) error {
 	esEvent := s.convertToElasticsearchEvent(&event)
 
 	// Index in Elasticsearch
-	return s.searchClient.IndexEvent(esEvent)
-}
-
-// syncAllEvents syncs all events to Elasticsearch
-func (s *Service) syncAllEvents(ctx context.Context) error {
-	var events []models.Event
-	result := s.db.Preload("Venue").Preload("Performer").Preload("Tickets").Find(&events)
-	if result.Error != nil {
-		return fmt.Errorf("failed to fetch events: %w", result.Error)
-	}
-
-	for _, event := range events {
-		esEvent := s.convertToElasticsearchEvent(&event)
-		if err := s.searchClient.IndexEvent(esEvent); err != nil {
-			log.Printf("Failed to sync event %d: %v", event.ID, err)
-			continue
-		}
-	}
-
-	return nil
+	return s.searchClient.IndexEvent(ctx, esEvent)
 }
 
 // convertToElasticsearchEvent converts a database event to Elasticsearch document
@@ -131,6 +328,9 @@ func (s *Service) convertToElasticsearchEvent(event *models.Event) *models.Elast
 		Performer:   event.Performer.Name,
 		Genre:       event.Performer.Genre,
 		Location:    event.Venue.Location,
+		Currency:    event.Currency,
+		Featured:    event.IsFeatured(time.Now()),
+		ESVersion:   event.UpdatedAt.UnixMilli(),
 	}
 
 	// Calculate price range and available tickets
@@ -146,62 +346,459 @@ func (s *Service) convertToElasticsearchEvent(event *models.Event) *models.Elast
 			if ticket.Price > maxPrice {
 				maxPrice = ticket.Price
 			}
-			if ticket.Status == "available" {
+			if ticket.Status == models.TicketAvailable {
 				availableCount++
 			}
 		}
 
-		esEvent.MinPrice = minPrice
-		esEvent.MaxPrice = maxPrice
+		esEvent.MinPrice = minPrice.Float64()
+		esEvent.MaxPrice = maxPrice.Float64()
 		esEvent.AvailableTickets = availableCount
 	}
 
 	return esEvent
 }
 
-// StartCDCWorker starts a background worker that periodically syncs changes
+// InitialSync reconciles the search index with Postgres before the worker
+// starts, running a full sync when the document counts drift apart by more
+// than the configured threshold (changes may have been missed while down).
+func (s *Service) InitialSync(ctx context.Context) error {
+	esCount, err := s.searchClient.CountEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count indexed events: %w", err)
+	}
+
+	var dbCount int64
+	if err := s.db.WithContext(ctx).Model(&models.Event{}).Count(&dbCount).Error; err != nil {
+		return fmt.Errorf("failed to count events: %w", err)
+	}
+
+	drift := countDrift(esCount, dbCount)
+	if drift <= s.config.CDCSyncDriftThreshold {
+		log.Printf("Initial sync skipped: index has %d of %d events (drift %.2f%%)", esCount, dbCount, drift*100)
+		return nil
+	}
+
+	log.Printf("Initial sync starting: index has %d of %d events (drift %.2f%%)", esCount, dbCount, drift*100)
+	job, jobCtx, err := s.startFullSync()
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, job.cancel)
+	defer stop()
+	if err := s.runFullSync(jobCtx, job); err != nil {
+		return err
+	}
+
+	syncedCount, err := s.searchClient.CountEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count indexed events: %w", err)
+	}
+	log.Printf("Initial sync completed: index has %d of %d events (drift %.2f%% -> %.2f%%)",
+		syncedCount, dbCount, drift*100, countDrift(syncedCount, dbCount)*100)
+
+	return nil
+}
+
+// countDrift returns the relative difference between the indexed and
+// database event counts
+func countDrift(esCount, dbCount int64) float64 {
+	diff := esCount - dbCount
+	if diff < 0 {
+		diff = -diff
+	}
+	if dbCount == 0 {
+		if diff == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(diff) / float64(dbCount)
+}
+
+// StartCDCWorker starts a background worker that periodically syncs changes.
+// Ticks are skipped while this replica is not the leader. Cancelling ctx
+// stops the worker after the tick in progress, which runs to completion so
+// no batch is abandoned half-indexed.
 func (s *Service) StartCDCWorker(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second) // Sync every 30 seconds
 	defer ticker.Stop()
 
 	log.Println("CDC worker started")
+	s.updateStatus(func(status *SyncStatus) { status.IsRunning = true })
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("CDC worker stopped")
+			s.updateStatus(func(status *SyncStatus) { status.IsRunning = false })
 			return
 		case <-ticker.C:
-			if err := s.syncRecentChanges(ctx); err != nil {
-				log.Printf("CDC sync error: %v", err)
+			if !s.IsLeader() {
+				continue
 			}
+			s.runTick(context.WithoutCancel(ctx))
 		}
 	}
 }
 
-// syncRecentChanges syncs events that have been modified recently
+// runTick runs one worker pass: lapsed features, the outbox, then the
+// change scans, then a few dead letters
+func (s *Service) runTick(ctx context.Context) {
+	if err := s.expireFeatured(ctx); err != nil {
+		log.Printf("CDC featured expiry error: %v", err)
+	}
+	if err := s.processOutbox(ctx); err != nil {
+		log.Printf("CDC outbox error: %v", err)
+	}
+	if err := s.syncRecentChanges(ctx); err != nil {
+		log.Printf("CDC sync error: %v", err)
+	}
+	if _, _, err := s.replayDeadLetters(ctx, deadLetterReplayLimit); err != nil {
+		log.Printf("CDC dead letter replay error: %v", err)
+	}
+}
+
+// processOutbox consumes unprocessed outbox rows in insertion order, indexing
+// or deleting the affected events, and marks them processed. Each event is
+// synced once per batch from its current database state. Processing stops at
+// the first failure so later rows are never applied ahead of earlier ones;
+// rows whose payload cannot be decoded are marked failed and skipped.
+func (s *Service) processOutbox(ctx context.Context) error {
+	synced := 0
+	var processErr error
+
+	for processErr == nil {
+		var entries []models.OutboxEvent
+		result := s.db.WithContext(ctx).Where("processed_at IS NULL").
+			Order("id").Limit(cdcBatchSize).Find(&entries)
+		if result.Error != nil {
+			processErr = fmt.Errorf("failed to fetch outbox events: %w", result.Error)
+			break
+		}
+
+		handled := make(map[uint]bool)
+		var processedIDs []uint
+		for _, entry := range entries {
+			eventID := entry.AggregateID
+			if entry.AggregateType != outbox.AggregateEvent {
+				payload, err := outbox.DecodePayload(&entry)
+				if err != nil {
+					// Retrying cannot fix the payload, so the row would block
+					// the outbox forever
+					if err := s.skipOutboxEntry(ctx, &entry, err); err != nil {
+						processErr = err
+						break
+					}
+					continue
+				}
+				eventID = payload.EventID
+			}
+
+			if !handled[eventID] {
+				var err error
+				if entry.AggregateType == outbox.AggregateEvent && entry.Op == outbox.OpDelete {
+					err = s.withRetry(ctx, eventID, opDelete, func() error {
+						return s.searchClient.DeleteEvent(ctx, eventID)
+					})
+				} else {
+					err = s.withRetry(ctx, eventID, opIndex, func() error {
+						return s.syncEventByID(ctx, eventID)
+					})
+				}
+				if err != nil {
+					processErr = fmt.Errorf("failed to process outbox event %d for event %d: %w", entry.ID, eventID, err)
+					break
+				}
+				handled[eventID] = true
+				synced++
+			}
+			processedIDs = append(processedIDs, entry.ID)
+		}
+
+		if len(processedIDs) > 0 {
+			if err := s.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id IN ?", processedIDs).
+				Update("processed_at", time.Now()).Error; err != nil {
+				processErr = fmt.Errorf("failed to mark outbox events processed: %w", err)
+			}
+		}
+
+		if len(entries) < cdcBatchSize {
+			break
+		}
+	}
+
+	if synced > 0 {
+		log.Printf("Synced %d events from the outbox", synced)
+	}
+
+	var syncErrors []string
+	if processErr != nil {
+		syncErrors = append(syncErrors, processErr.Error())
+	}
+	s.recordSync(synced, syncErrors)
+	return processErr
+}
+
+// skipOutboxEntry marks an outbox row that cannot be applied processed,
+// keeping the error on it for operators
+func (s *Service) skipOutboxEntry(ctx context.Context, entry *models.OutboxEvent, cause error) error {
+	log.Printf("Skipping outbox event %d: %v", entry.ID, cause)
+	if err := s.db.WithContext(ctx).Model(entry).Updates(map[string]interface{}{
+		"processed_at": time.Now(),
+		"error":        cause.Error(),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox event %d failed: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// syncRecentChanges indexes events whose rows or tickets changed since the
+// stored checkpoints. Ticket changes (reservations, bookings) don't touch the
+// event row but do change availability and price ranges in the index.
+// Changed venues and performers are synced to their own indices.
 func (s *Service) syncRecentChanges(ctx context.Context) error {
-	// Get events modified in the last 5 minutes
-	cutoff := time.Now().Add(-5 * time.Minute)
+	// Leave recent rows for the next pass so transactions that commit out of
+	// updated_at order are not skipped
+	upperBound := time.Now().Add(-cdcCommitLag)
 
-	var events []models.Event
-	result := s.db.Preload("Venue").Preload("Performer").Preload("Tickets").
-		Where("updated_at > ?", cutoff).Find(&events)
-	if result.Error != nil {
-		return fmt.Errorf("failed to fetch recent events: %w", result.Error)
+	var syncErrors []string
+	eventsSynced, eventsErr := s.syncChangedEvents(ctx, upperBound)
+	if eventsErr != nil {
+		syncErrors = append(syncErrors, eventsErr.Error())
+	}
+
+	ticketEventsSynced, ticketsErr := s.syncChangedTickets(ctx, upperBound)
+	if ticketsErr != nil {
+		syncErrors = append(syncErrors, ticketsErr.Error())
+	}
+
+	eventsDeleted, deletesErr := s.syncDeletedEvents(ctx, upperBound)
+	if deletesErr != nil {
+		syncErrors = append(syncErrors, deletesErr.Error())
+	}
+
+	synced := eventsSynced + ticketEventsSynced + eventsDeleted
+	if synced > 0 {
+		log.Printf("Synced %d changed events (%d from ticket changes, %d deleted)", synced, ticketEventsSynced, eventsDeleted)
+	}
+
+	venuesSynced, venuesErr := s.syncChangedVenues(ctx, upperBound)
+	if venuesErr != nil {
+		syncErrors = append(syncErrors, venuesErr.Error())
+	}
+
+	performersSynced, performersErr := s.syncChangedPerformers(ctx, upperBound)
+	if performersErr != nil {
+		syncErrors = append(syncErrors, performersErr.Error())
+	}
+
+	if venuesSynced+performersSynced > 0 {
+		log.Printf("Synced %d changed venues and %d changed performers", venuesSynced, performersSynced)
+	}
+
+	s.recordSync(synced+venuesSynced+performersSynced, syncErrors)
+	for _, err := range []error{eventsErr, ticketsErr, deletesErr, venuesErr, performersErr} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncChangedEvents indexes events changed since the events checkpoint, in
+// (updated_at, id) order and in batches. The checkpoint only advances past
+// events that were indexed successfully, so a failed pass resumes where it
+// stopped on the next tick or after a restart.
+func (s *Service) syncChangedEvents(ctx context.Context, upperBound time.Time) (int, error) {
+	checkpoint, err := s.loadCheckpoint(ctx, eventsCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for {
+		events, err := s.events.ChangedSince(ctx, checkpointCursor(checkpoint), upperBound, cdcBatchSize)
+		if err != nil {
+			return synced, fmt.Errorf("failed to fetch changed events: %w", err)
+		}
+
+		var indexErr error
+		for _, event := range events {
+			esEvent := s.convertToElasticsearchEvent(&event)
+			err := s.withRetry(ctx, event.ID, opIndex, func() error {
+				err := s.searchClient.IndexEvent(ctx, esEvent)
+				if errors.Is(err, elasticsearch.ErrVersionConflict) {
+					// The batch's copy may be stale; index the current row instead
+					log.Printf("Event %d was indexed concurrently, re-reading it", event.ID)
+					return s.indexEventFromDB(ctx, event.ID)
+				}
+				return err
+			})
+			if err != nil {
+				indexErr = fmt.Errorf("failed to sync event %d: %w", event.ID, err)
+				break
+			}
+			checkpoint.LastUpdatedAt = event.UpdatedAt
+			checkpoint.LastID = event.ID
+			synced++
+		}
+
+		if err := s.saveCheckpoint(ctx, checkpoint); err != nil {
+			return synced, err
+		}
+
+		if indexErr != nil {
+			return synced, indexErr
+		}
+
+		if len(events) < cdcBatchSize {
+			return synced, nil
+		}
+	}
+}
+
+// syncChangedTickets re-indexes the parent events of tickets changed since
+// the tickets checkpoint. Each batch's event IDs are deduplicated, and the
+// checkpoint advances only once the whole batch has been indexed.
+func (s *Service) syncChangedTickets(ctx context.Context, upperBound time.Time) (int, error) {
+	checkpoint, err := s.loadCheckpoint(ctx, ticketsCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for {
+		changes, err := s.tickets.ChangedSince(ctx, checkpointCursor(checkpoint), upperBound, cdcTicketBatchSize)
+		if err != nil {
+			return synced, fmt.Errorf("failed to fetch changed tickets: %w", err)
+		}
+
+		seen := make(map[uint]bool)
+		for _, change := range changes {
+			if seen[change.EventID] {
+				continue
+			}
+			seen[change.EventID] = true
+
+			eventID := change.EventID
+			err := s.withRetry(ctx, eventID, opIndex, func() error {
+				return s.syncEventByID(ctx, eventID)
+			})
+			if err != nil {
+				return synced, fmt.Errorf("failed to sync event %d for ticket changes: %w", eventID, err)
+			}
+			synced++
+		}
+
+		if len(changes) > 0 {
+			last := changes[len(changes)-1]
+			checkpoint.LastUpdatedAt = last.UpdatedAt
+			checkpoint.LastID = last.ID
+			if err := s.saveCheckpoint(ctx, checkpoint); err != nil {
+				return synced, err
+			}
+		}
+
+		if len(changes) < cdcTicketBatchSize {
+			return synced, nil
+		}
 	}
+}
+
+// syncDeletedEvents removes soft-deleted events from the index. Deleting only
+// sets deleted_at, so these rows are invisible to the updated_at scan; they
+// are tracked with their own (deleted_at, id) checkpoint.
+func (s *Service) syncDeletedEvents(ctx context.Context, upperBound time.Time) (int, error) {
+	checkpoint, err := s.loadCheckpoint(ctx, deletedEventsCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for {
+		var events []struct {
+			ID        uint
+			DeletedAt time.Time
+		}
+		result := s.db.WithContext(ctx).Unscoped().Model(&models.Event{}).Select("id, deleted_at").
+			Where("deleted_at IS NOT NULL").
+			Where("(deleted_at, id) > (?, ?)", checkpoint.LastUpdatedAt, checkpoint.LastID).
+			Where("deleted_at <= ?", upperBound).
+			Order("deleted_at, id").Limit(cdcBatchSize).Scan(&events)
+		if result.Error != nil {
+			return deleted, fmt.Errorf("failed to fetch deleted events: %w", result.Error)
+		}
+
+		var deleteErr error
+		for _, event := range events {
+			eventID := event.ID
+			err := s.withRetry(ctx, eventID, opDelete, func() error {
+				return s.searchClient.DeleteEvent(ctx, eventID)
+			})
+			if err != nil {
+				deleteErr = fmt.Errorf("failed to remove deleted event %d: %w", event.ID, err)
+				break
+			}
+			checkpoint.LastUpdatedAt = event.DeletedAt
+			checkpoint.LastID = event.ID
+			deleted++
+		}
+
+		if err := s.saveCheckpoint(ctx, checkpoint); err != nil {
+			return deleted, err
+		}
 
-	for _, event := range events {
-		esEvent := s.convertToElasticsearchEvent(&event)
-		if err := s.searchClient.IndexEvent(esEvent); err != nil {
-			log.Printf("Failed to sync recent event %d: %v", event.ID, err)
-			continue
+		if deleteErr != nil {
+			return deleted, deleteErr
+		}
+
+		if len(events) < cdcBatchSize {
+			return deleted, nil
 		}
 	}
+}
 
-	if len(events) > 0 {
-		log.Printf("Synced %d recent events", len(events))
+// checkpointCursor is the scan position a checkpoint stores
+func checkpointCursor(checkpoint *models.CDCCheckpoint) store.Cursor {
+	return store.Cursor{UpdatedAt: checkpoint.LastUpdatedAt, ID: checkpoint.LastID}
+}
+
+// loadCheckpoint returns the stored watermark for a change stream, creating
+// an empty one (which replays every row) on first use
+func (s *Service) loadCheckpoint(ctx context.Context, name string) (*models.CDCCheckpoint, error) {
+	checkpoint := models.CDCCheckpoint{Name: name}
+	if err := s.db.WithContext(ctx).FirstOrCreate(&checkpoint, models.CDCCheckpoint{Name: name}).Error; err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint %q: %w", name, err)
 	}
+	return &checkpoint, nil
+}
 
+// saveCheckpoint persists the watermark of a change stream
+func (s *Service) saveCheckpoint(ctx context.Context, checkpoint *models.CDCCheckpoint) error {
+	if err := s.db.WithContext(ctx).Save(checkpoint).Error; err != nil {
+		return fmt.Errorf("failed to save checkpoint %q: %w", checkpoint.Name, err)
+	}
 	return nil
 }
+
+// recordSync stores the outcome of a sync pass in the status. Only a pass
+// without errors moves LastSyncTime, so monitoring that alerts on its age
+// also fires while every pass fails.
+func (s *Service) recordSync(synced int, syncErrors []string) {
+	now := time.Now()
+	s.updateStatus(func(status *SyncStatus) {
+		status.LastSyncCount = synced
+		status.TotalSynced += int64(synced)
+		if len(syncErrors) == 0 {
+			status.LastSyncTime = now
+			status.LastSuccessfulSyncTime = now
+			status.ConsecutiveFailures = 0
+		} else {
+			status.ConsecutiveFailures++
+		}
+		for _, msg := range syncErrors {
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", now.Format(time.RFC3339), msg))
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// seedEvents creates count events last updated before the commit lag, one
+// millisecond apart so the change scan orders them by ID
+func seedEvents(t *testing.T, db *gorm.DB, count int) []models.Event {
+	t.Helper()
+	venue := models.Venue{Location: "Taipei Arena", Capacity: 1000}
+	performer := models.Performer{Name: "Band", Genre: "rock"}
+	if err := db.Create(&venue).Error; err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+	if err := db.Create(&performer).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	events := make([]models.Event, count)
+	for i := range events {
+		events[i] = models.Event{
+			VenueID:     venue.ID,
+			PerformerID: performer.ID,
+			Name:        fmt.Sprintf("Event %d", i+1),
+			Date:        base.Add(30 * 24 * time.Hour),
+			Currency:    "twd",
+		}
+		events[i].UpdatedAt = base.Add(time.Duration(i) * time.Millisecond)
+	}
+	if err := db.CreateInBatches(&events, 100).Error; err != nil {
+		t.Fatalf("failed to create events: %v", err)
+	}
+	return events
+}
+
+func newTestService(t *testing.T, db *gorm.DB) (*Service, *testutil.SearchServer) {
+	t.Helper()
+	search, client := testutil.NewSearchServer(t)
+	return NewService(db, client, &config.Config{}, nil), search
+}
+
+func TestSyncChangedEventsResumesAfterKillMidBatch(t *testing.T) {
+	db := testutil.NewDB(t)
+	events := seedEvents(t, db, cdcBatchSize+50)
+	killAt := events[cdcBatchSize+20].ID
+
+	s, search := newTestService(t, db)
+	ctx, kill := context.WithCancel(context.Background())
+	defer kill()
+	killed := false
+	search.OnIndex = func(id string) error {
+		if !killed && id == strconv.FormatUint(uint64(killAt), 10) {
+			killed = true
+			kill()
+			return errors.New("worker killed")
+		}
+		return nil
+	}
+
+	if _, err := s.syncChangedEvents(ctx, time.Now().Add(-cdcCommitLag)); err == nil {
+		t.Fatal("expected the killed pass to fail")
+	}
+
+	// Only the first, completed batch is checkpointed
+	var checkpoint models.CDCCheckpoint
+	if err := db.First(&checkpoint, "name = ?", eventsCheckpoint).Error; err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if want := events[cdcBatchSize-1].ID; checkpoint.LastID != want {
+		t.Fatalf("checkpoint at event %d after the kill, want %d", checkpoint.LastID, want)
+	}
+
+	// A restarted worker shares nothing with the killed one but the database
+	// and the index
+	restarted := NewService(db, s.searchClient, s.config, nil)
+	synced, err := restarted.syncChangedEvents(context.Background(), time.Now().Add(-cdcCommitLag))
+	if err != nil {
+		t.Fatalf("resumed pass failed: %v", err)
+	}
+	if synced != len(events)-cdcBatchSize {
+		t.Errorf("resumed pass synced %d events, want %d", synced, len(events)-cdcBatchSize)
+	}
+
+	for _, event := range events {
+		var doc models.ElasticsearchEvent
+		if !search.Document("events", event.ID, &doc) {
+			t.Errorf("event %d was skipped", event.ID)
+		}
+	}
+
+	if err := db.First(&checkpoint, "name = ?", eventsCheckpoint).Error; err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if want := events[len(events)-1].ID; checkpoint.LastID != want {
+		t.Errorf("checkpoint at event %d after resuming, want %d", checkpoint.LastID, want)
+	}
+}
+
+// seedTickets adds count available tickets to an event, last updated before
+// the commit lag
+func seedTickets(t *testing.T, db *gorm.DB, event models.Event, count int) []models.Ticket {
+	t.Helper()
+	tickets := make([]models.Ticket, count)
+	for i := range tickets {
+		tickets[i] = models.Ticket{
+			EventID: event.ID,
+			Seat:    fmt.Sprintf("A%d", i+1),
+			Price:   money.FromFloat(50),
+			Status:  models.TicketAvailable,
+		}
+		tickets[i].UpdatedAt = event.UpdatedAt
+	}
+	if err := db.Create(&tickets).Error; err != nil {
+		t.Fatalf("failed to create tickets: %v", err)
+	}
+	return tickets
+}
+
+func TestSyncPassRefreshesAvailabilityAfterConfirm(t *testing.T) {
+	db := testutil.NewDB(t)
+	event := seedEvents(t, db, 1)[0]
+	tickets := seedTickets(t, db, event, 3)
+
+	s, search := newTestService(t, db)
+	ctx := context.Background()
+	if err := s.syncRecentChanges(ctx); err != nil {
+		t.Fatalf("initial pass failed: %v", err)
+	}
+	var doc models.ElasticsearchEvent
+	if !search.Document("events", event.ID, &doc) || doc.AvailableTickets != 3 {
+		t.Fatalf("indexed availableTickets = %d, want 3", doc.AvailableTickets)
+	}
+
+	// Confirming books the ticket without touching the event row; the
+	// change committed before the commit lag
+	if err := db.Model(&tickets[0]).UpdateColumns(map[string]interface{}{
+		"status":     models.TicketBooked,
+		"updated_at": time.Now().Add(-2 * cdcCommitLag),
+	}).Error; err != nil {
+		t.Fatalf("failed to book ticket: %v", err)
+	}
+
+	if err := s.syncRecentChanges(ctx); err != nil {
+		t.Fatalf("sync pass failed: %v", err)
+	}
+	search.Document("events", event.ID, &doc)
+	if doc.AvailableTickets != 2 {
+		t.Errorf("indexed availableTickets = %d after confirming, want 2", doc.AvailableTickets)
+	}
+}
+
+func TestWorkerPassRemovesDeletedEventFromSearch(t *testing.T) {
+	db := testutil.NewDB(t)
+	events := seedEvents(t, db, 2)
+
+	s, search := newTestService(t, db)
+	ctx := context.Background()
+	s.runTick(ctx)
+	if indexed := search.IDs("events"); len(indexed) != 2 {
+		t.Fatalf("first pass indexed %v, want both events", indexed)
+	}
+
+	// Deleted without an outbox row, as a direct database delete would be
+	if err := db.Delete(&events[0]).Error; err != nil {
+		t.Fatalf("failed to delete event: %v", err)
+	}
+	if err := db.Unscoped().Model(&events[0]).UpdateColumn("deleted_at", time.Now().Add(-2*cdcCommitLag)).Error; err != nil {
+		t.Fatalf("failed to backdate deletion: %v", err)
+	}
+
+	s.runTick(ctx)
+
+	hits, err := s.searchClient.SearchEvents(ctx, map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ID != events[1].ID {
+		ids := make([]uint, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.ID
+		}
+		t.Errorf("search returned events %v, want only %d", ids, events[1].ID)
+	}
+}
@@ -0,0 +1,164 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// cdcRetryMaxAttempts bounds the attempts of one index operation before
+	// it is dead-lettered
+	cdcRetryMaxAttempts = 3
+
+	// cdcRetryBaseDelay is the backoff after the first failed attempt; it
+	// doubles with each further attempt
+	cdcRetryBaseDelay = 500 * time.Millisecond
+
+	// deadLetterReplayLimit caps how many dead letters the worker replays per tick
+	deadLetterReplayLimit = 20
+
+	// Index operations recorded in cdc_dead_letters
+	opIndex  = "index"
+	opDelete = "delete"
+)
+
+func (s *Service) ListDeadLetters(c *gin.Context) {
+	var deadLetters []models.CDCDeadLetter
+	if err := s.db.WithContext(c.Request.Context()).Order("last_tried_at").Find(&deadLetters).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch dead letters", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deadLetters": deadLetters,
+		"count":       len(deadLetters),
+	})
+}
+
+func (s *Service) RetryDeadLetters(c *gin.Context) {
+	succeeded, failed, err := s.replayDeadLetters(c.Request.Context(), -1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to replay dead letters", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"succeeded": succeeded,
+		"failed":    failed,
+	})
+}
+
+// withRetry runs an index operation for an event, retrying with exponential
+// backoff. When every attempt fails the failure is stored as a dead letter
+// and nil is returned, so one bad document does not hold up the change
+// stream. An error is returned only if the context ends or the dead letter
+// cannot be stored.
+func (s *Service) withRetry(ctx context.Context, eventID uint, operation string, fn func() error) error {
+	err := retry(ctx, fn)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	log.Printf("Dead-lettering %s of event %d after %d attempts: %v", operation, eventID, cdcRetryMaxAttempts, err)
+	return s.storeDeadLetter(ctx, eventID, operation, err, cdcRetryMaxAttempts)
+}
+
+// retry runs fn up to cdcRetryMaxAttempts times with exponential backoff,
+// returning the last error
+func retry(ctx context.Context, fn func() error) error {
+	var err error
+	delay := cdcRetryBaseDelay
+	for attempt := 1; attempt <= cdcRetryMaxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == cdcRetryMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return err
+}
+
+// storeDeadLetter records a failed operation, adding to the attempts of an
+// existing dead letter for the same event and operation
+func (s *Service) storeDeadLetter(ctx context.Context, eventID uint, operation string, cause error, attempts int) error {
+	deadLetter := models.CDCDeadLetter{
+		EventID:     eventID,
+		Operation:   operation,
+		Error:       cause.Error(),
+		Attempts:    attempts,
+		LastTriedAt: time.Now(),
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "event_id"}, {Name: "operation"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"error":         gorm.Expr("excluded.error"),
+			"attempts":      gorm.Expr("cdc_dead_letters.attempts + excluded.attempts"),
+			"last_tried_at": gorm.Expr("excluded.last_tried_at"),
+		}),
+	}).Create(&deadLetter).Error
+	if err != nil {
+		return fmt.Errorf("failed to store dead letter for event %d: %w", eventID, err)
+	}
+	return nil
+}
+
+// replayDeadLetters retries up to limit dead letters (all when limit < 0),
+// oldest attempt first. Succeeded ones are removed; failed ones have their
+// attempt count and error updated.
+func (s *Service) replayDeadLetters(ctx context.Context, limit int) (int, int, error) {
+	var deadLetters []models.CDCDeadLetter
+	if err := s.db.WithContext(ctx).Order("last_tried_at").Limit(limit).Find(&deadLetters).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch dead letters: %w", err)
+	}
+
+	succeeded, failed := 0, 0
+	for _, deadLetter := range deadLetters {
+		var err error
+		if deadLetter.Operation == opDelete {
+			err = s.searchClient.DeleteEvent(ctx, deadLetter.EventID)
+		} else {
+			err = s.syncEventByID(ctx, deadLetter.EventID)
+		}
+
+		if err != nil {
+			failed++
+			if err := s.db.WithContext(ctx).Model(&deadLetter).Updates(map[string]interface{}{
+				"error":         err.Error(),
+				"attempts":      gorm.Expr("attempts + 1"),
+				"last_tried_at": time.Now(),
+			}).Error; err != nil {
+				return succeeded, failed, fmt.Errorf("failed to update dead letter %d: %w", deadLetter.ID, err)
+			}
+			continue
+		}
+
+		succeeded++
+		if err := s.db.WithContext(ctx).Delete(&deadLetter).Error; err != nil {
+			return succeeded, failed, fmt.Errorf("failed to remove dead letter %d: %w", deadLetter.ID, err)
+		}
+	}
+
+	if len(deadLetters) > 0 {
+		log.Printf("Replayed %d dead letters: %d succeeded, %d failed", len(deadLetters), succeeded, failed)
+	}
+	return succeeded, failed, nil
+}
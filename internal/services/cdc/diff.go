@@ -0,0 +1,155 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Index states reported by a dry-run diff
+const (
+	DiffInSync   = "in_sync"
+	DiffMissing  = "missing"
+	DiffStale    = "stale"
+	DiffOrphaned = "orphaned"
+	DiffAbsent   = "absent"
+)
+
+// FieldChange is a document field whose indexed value differs from the
+// value a sync would write
+type FieldChange struct {
+	Indexed  interface{} `json:"indexed"`
+	Expected interface{} `json:"expected"`
+}
+
+// EventDiff describes how one event's search document differs from Postgres
+type EventDiff struct {
+	EventID uint                   `json:"eventId"`
+	State   string                 `json:"state"`
+	Changes map[string]FieldChange `json:"changes,omitempty"`
+}
+
+// DiffReport summarizes how far the search index has drifted from Postgres
+type DiffReport struct {
+	Checked  int         `json:"checked"`
+	InSync   int         `json:"inSync"`
+	Missing  []uint      `json:"missing"`
+	Stale    []EventDiff `json:"stale"`
+	Orphaned []uint      `json:"orphaned"`
+}
+
+// diffEvent compares one event against its search document without
+// writing anything
+func (s *Service) diffEvent(ctx context.Context, eventID uint) (*EventDiff, error) {
+	indexed, err := s.searchClient.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch indexed event: %w", err)
+	}
+
+	var event models.Event
+	err = s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, eventID).Error
+	if err == gorm.ErrRecordNotFound {
+		state := DiffAbsent
+		if indexed != nil {
+			state = DiffOrphaned
+		}
+		return &EventDiff{EventID: eventID, State: state}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event: %w", err)
+	}
+
+	return compareDocument(s.convertToElasticsearchEvent(&event), indexed), nil
+}
+
+// diffAll compares every event against the index in batches, then lists
+// indexed documents whose event no longer exists
+func (s *Service) diffAll(ctx context.Context) (*DiffReport, error) {
+	report := &DiffReport{Missing: []uint{}, Stale: []EventDiff{}, Orphaned: []uint{}}
+	existing := make(map[uint]bool)
+
+	var lastID uint
+	for {
+		var events []models.Event
+		if err := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").
+			Where("id > ?", lastID).Order("id").Limit(cdcBatchSize).Find(&events).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch events: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		ids := make([]uint, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		indexed, err := s.searchClient.GetEvents(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch indexed events: %w", err)
+		}
+
+		for i := range events {
+			existing[events[i].ID] = true
+			diff := compareDocument(s.convertToElasticsearchEvent(&events[i]), indexed[events[i].ID])
+			report.Checked++
+			switch diff.State {
+			case DiffInSync:
+				report.InSync++
+			case DiffMissing:
+				report.Missing = append(report.Missing, diff.EventID)
+			case DiffStale:
+				report.Stale = append(report.Stale, *diff)
+			}
+		}
+		lastID = ids[len(ids)-1]
+	}
+
+	indexedIDs, err := s.searchClient.IndexedEventIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed events: %w", err)
+	}
+	for _, id := range indexedIDs {
+		if !existing[id] {
+			report.Orphaned = append(report.Orphaned, id)
+		}
+	}
+
+	return report, nil
+}
+
+// compareDocument diffs the document a sync would write against the indexed
+// one (nil when not indexed), field by field under their JSON names
+func compareDocument(expected, indexed *models.ElasticsearchEvent) *EventDiff {
+	diff := &EventDiff{EventID: expected.ID, State: DiffInSync}
+	if indexed == nil {
+		diff.State = DiffMissing
+		return diff
+	}
+
+	expectedValue := reflect.ValueOf(*expected)
+	indexedValue := reflect.ValueOf(*indexed)
+	fields := expectedValue.Type()
+	for i := 0; i < fields.NumField(); i++ {
+		want := expectedValue.Field(i).Interface()
+		got := indexedValue.Field(i).Interface()
+		if want == got {
+			continue
+		}
+
+		if diff.Changes == nil {
+			diff.Changes = make(map[string]FieldChange)
+		}
+		name := strings.Split(fields.Field(i).Tag.Get("json"), ",")[0]
+		diff.Changes[name] = FieldChange{Indexed: got, Expected: want}
+	}
+
+	if len(diff.Changes) > 0 {
+		diff.State = DiffStale
+	}
+	return diff
+}
@@ -0,0 +1,27 @@
+package cdc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+)
+
+// expireFeatured clears the featured flag of events whose FeaturedUntil has
+// passed. Nothing else touches those rows when a feature lapses, so without
+// this the index would keep ranking them as featured; the update bumps
+// updated_at and the change scan reindexes them.
+func (s *Service) expireFeatured(ctx context.Context) error {
+	result := s.db.WithContext(ctx).Model(&models.Event{}).
+		Where("featured = ? AND featured_until <= ?", true, time.Now()).
+		Update("featured", false)
+	if result.Error != nil {
+		return fmt.Errorf("failed to expire featured events: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Cleared %d lapsed featured events", result.RowsAffected)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cdc
+
+import (
+	"context"
+	"log"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+)
+
+// EnsureIndices creates the search indices unless the migration history
+// records it, then records it. A mapping change needs a new
+// ESIndexVersion; one made without is logged, since existing indices keep
+// their old mappings.
+func (s *Service) EnsureIndices(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	checksum := database.Checksum(elasticsearch.IndexMappings()...)
+
+	applied, err := database.AppliedMigration(db, database.ESIndexVersion)
+	if err != nil {
+		return err
+	}
+	if applied != nil {
+		if applied.Checksum != checksum {
+			log.Printf("Search index mappings changed since %s was applied; bump the version to recreate them", applied.Version)
+		}
+		return nil
+	}
+
+	if err := s.searchClient.EnsureIndices(); err != nil {
+		return err
+	}
+	return database.RecordMigration(db, database.ESIndexVersion, checksum)
+}
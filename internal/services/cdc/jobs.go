@@ -0,0 +1,483 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Full sync job states
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Sync job kinds
+const (
+	JobKindFull   = "full"
+	JobKindScoped = "scoped"
+)
+
+// maxRetainedJobs caps how many finished jobs are kept for GET /cdc/jobs/:id
+const maxRetainedJobs = 20
+
+var errSyncInProgress = errors.New("a full sync is already running")
+
+// SyncJob tracks a full or scoped reindex
+type SyncJob struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind"`
+	Scope        *SyncScope `json:"scope,omitempty"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	Total        int64      `json:"total"`
+	Indexed      int64      `json:"indexed"`
+	DeadLettered int64      `json:"deadLettered"`
+	Error        string     `json:"error,omitempty"`
+
+	cancel context.CancelFunc
+
+	// progress, when set, receives the ID of every indexed event and is
+	// closed when the job ends; a send gives up once listenerGone is closed
+	progress     chan uint
+	listenerGone chan struct{}
+}
+
+// ndjsonContentType selects the streaming form of POST /cdc/sync-all
+const ndjsonContentType = "application/x-ndjson"
+
+// SyncAllEvents starts a full reindex in the background and returns its job.
+// Clients that accept application/x-ndjson instead get a streamed response
+// with one {"synced": id} line per indexed event and a final summary line.
+//
+// With dryRun=true nothing is written; the response is a DiffReport of
+// missing, stale and orphaned documents instead.
+func (s *Service) SyncAllEvents(c *gin.Context) {
+	if c.Query("dryRun") == "true" {
+		report, err := s.diffAll(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to diff index", gin.H{"reason": err.Error()}))
+			return
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	job, jobCtx, err := s.startFullSync()
+	if err != nil {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeSyncInProgress, "A full sync is already running", gin.H{"jobId": s.activeJobID()}))
+		return
+	}
+
+	streaming := c.NegotiateFormat(gin.MIMEJSON, ndjsonContentType) == ndjsonContentType
+	if streaming {
+		job.progress = make(chan uint)
+		job.listenerGone = make(chan struct{})
+	}
+
+	go func() {
+		if err := s.runFullSync(jobCtx, job); err != nil {
+			log.Printf("Full sync %s failed: %v", job.ID, err)
+		}
+	}()
+
+	if streaming {
+		s.streamSyncProgress(c, job)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"jobId":   job.ID,
+		"status":  JobRunning,
+		"message": "Full sync started",
+	})
+}
+
+// streamSyncProgress writes a job's progress as newline-delimited JSON until
+// the job ends or the client goes away. The job keeps running if the client
+// disconnects.
+func (s *Service) streamSyncProgress(c *gin.Context, job *SyncJob) {
+	defer close(job.listenerGone)
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Header("X-Sync-Job-Id", job.ID)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case id, ok := <-job.progress:
+			if ok {
+				encoder.Encode(gin.H{"synced": id})
+				return true
+			}
+			final, _ := s.jobSnapshot(job.ID)
+			encoder.Encode(gin.H{
+				"done":   true,
+				"jobId":  final.ID,
+				"status": final.Status,
+				"total":  final.Indexed,
+				"errors": final.DeadLettered,
+			})
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// reportProgress passes an indexed event to the job's stream, if any
+func (job *SyncJob) reportProgress(id uint) {
+	if job.progress == nil {
+		return
+	}
+	select {
+	case job.progress <- id:
+	case <-job.listenerGone:
+	}
+}
+
+// ListSyncJobs lists retained jobs, newest first
+func (s *Service) ListSyncJobs(c *gin.Context) {
+	s.jobsMu.Lock()
+	jobs := make([]SyncJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, *job)
+	}
+	s.jobsMu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+func (s *Service) GetSyncJob(c *gin.Context) {
+	job, ok := s.jobSnapshot(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeJobNotFound, "Sync job not found", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelSyncJob asks a running job to stop; it finishes the batches already
+// in flight and stops before starting the next one
+func (s *Service) CancelSyncJob(c *gin.Context) {
+	s.jobsMu.Lock()
+	job, ok := s.jobs[c.Param("id")]
+	if ok && job.Status == JobRunning {
+		job.cancel()
+	}
+	s.jobsMu.Unlock()
+
+	if !ok {
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeJobNotFound, "Sync job not found", nil))
+		return
+	}
+
+	snapshot, _ := s.jobSnapshot(job.ID)
+	c.JSON(http.StatusAccepted, snapshot)
+}
+
+// startFullSync registers a new running job, failing with errSyncInProgress
+// if one is already running. The returned context is cancelled by
+// CancelSyncJob.
+func (s *Service) startFullSync() (*SyncJob, context.Context, error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if s.activeJob != nil {
+		return nil, nil, errSyncInProgress
+	}
+
+	job, ctx := s.registerJob(JobKindFull, nil)
+	s.activeJob = job
+	return job, ctx, nil
+}
+
+// registerJob records a new running job; the caller must hold jobsMu
+func (s *Service) registerJob(kind string, scope *SyncScope) (*SyncJob, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &SyncJob{
+		ID:        uuid.NewString(),
+		Kind:      kind,
+		Scope:     scope,
+		Status:    JobRunning,
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	}
+	s.jobs[job.ID] = job
+	s.pruneJobs()
+
+	return job, ctx
+}
+
+// pruneJobs drops the oldest finished jobs beyond maxRetainedJobs; the
+// caller must hold jobsMu
+func (s *Service) pruneJobs() {
+	for len(s.jobs) > maxRetainedJobs {
+		var oldest *SyncJob
+		for _, job := range s.jobs {
+			if job.Status != JobRunning && (oldest == nil || job.StartedAt.Before(oldest.StartedAt)) {
+				oldest = job
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(s.jobs, oldest.ID)
+	}
+}
+
+// jobDrainPollInterval is how often DrainJobs checks for the running job to end
+const jobDrainPollInterval = 100 * time.Millisecond
+
+// DrainJobs cancels the running full sync, if any, and waits until it has
+// finished its in-flight batches or ctx is done
+func (s *Service) DrainJobs(ctx context.Context) error {
+	s.jobsMu.Lock()
+	if s.activeJob != nil {
+		s.activeJob.cancel()
+	}
+	s.jobsMu.Unlock()
+
+	ticker := time.NewTicker(jobDrainPollInterval)
+	defer ticker.Stop()
+	for s.activeJobID() != "" {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("full sync still running: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// activeJobID returns the ID of the running full sync, if any
+func (s *Service) activeJobID() string {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if s.activeJob == nil {
+		return ""
+	}
+	return s.activeJob.ID
+}
+
+// jobSnapshot returns a copy of a job's progress
+func (s *Service) jobSnapshot(id string) (SyncJob, bool) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return SyncJob{}, false
+	}
+	return *job, true
+}
+
+// updateJob applies fn to a job under jobsMu
+func (s *Service) updateJob(job *SyncJob, fn func(job *SyncJob)) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	fn(job)
+}
+
+// runFullSync reindexes every event. When ctx is cancelled no further
+// batches are started, but batches already handed to a worker are finished.
+func (s *Service) runFullSync(ctx context.Context, job *SyncJob) error {
+	s.updateStatus(func(status *SyncStatus) { status.ReindexInProgress = true })
+	defer s.updateStatus(func(status *SyncStatus) { status.ReindexInProgress = false })
+
+	return s.runJob(ctx, job, func(db *gorm.DB) *gorm.DB { return db })
+}
+
+// runJob bulk-indexes the events selected by scope for a job and records the
+// outcome
+func (s *Service) runJob(ctx context.Context, job *SyncJob, scope func(*gorm.DB) *gorm.DB) error {
+	// In-flight batches must not be cut off by cancellation
+	workCtx := context.WithoutCancel(ctx)
+
+	var total int64
+	err := scope(s.db.WithContext(workCtx).Model(&models.Event{})).Count(&total).Error
+	if err != nil {
+		err = fmt.Errorf("failed to count events: %w", err)
+	} else {
+		s.updateJob(job, func(job *SyncJob) { job.Total = total })
+		err = s.dispatchBatches(ctx, workCtx, job, scope)
+	}
+
+	s.finishJob(job, ctx.Err() != nil, err)
+	if job.progress != nil {
+		close(job.progress)
+	}
+	return err
+}
+
+// dispatchBatches pages through the IDs of the events in scope and feeds
+// them in batches to a pool of CDCSyncConcurrency workers that bulk-index
+// them. It waits for the pool to drain, returning the first error from
+// either side.
+func (s *Service) dispatchBatches(ctx, workCtx context.Context, job *SyncJob, scope func(*gorm.DB) *gorm.DB) error {
+	concurrency := s.config.CDCSyncConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		errMu    sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		errMu.Lock()
+		defer errMu.Unlock()
+		return firstErr != nil
+	}
+
+	batches := make(chan []uint)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ids := range batches {
+				if err := s.indexBatch(workCtx, job, ids); err != nil {
+					setErr(err)
+				}
+			}
+		}()
+	}
+
+	var lastID uint
+produce:
+	for !failed() {
+		var ids []uint
+		if err := scope(s.db.WithContext(workCtx).Model(&models.Event{})).Where("id > ?", lastID).
+			Order("id").Limit(cdcBatchSize).Pluck("id", &ids).Error; err != nil {
+			setErr(fmt.Errorf("failed to fetch event IDs: %w", err))
+			break
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		select {
+		case batches <- ids:
+			lastID = ids[len(ids)-1]
+		case <-ctx.Done():
+			break produce
+		}
+	}
+
+	close(batches)
+	wg.Wait()
+	return firstErr
+}
+
+// indexBatch loads a batch of events and bulk-indexes them. Documents the
+// bulk request rejects are retried one by one and dead-lettered if they
+// still fail.
+func (s *Service) indexBatch(ctx context.Context, job *SyncJob, ids []uint) error {
+	var events []models.Event
+	if err := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").
+		Where("id IN ?", ids).Find(&events).Error; err != nil {
+		return fmt.Errorf("failed to fetch events: %w", err)
+	}
+
+	docs := make([]*models.ElasticsearchEvent, len(events))
+	for i := range events {
+		docs[i] = s.convertToElasticsearchEvent(&events[i])
+	}
+
+	failedIDs, err := s.searchClient.BulkIndexEvents(ctx, docs)
+	if err != nil {
+		log.Printf("Bulk index of %d events failed, indexing individually: %v", len(docs), err)
+		failedIDs = make([]uint, len(docs))
+		for i, doc := range docs {
+			failedIDs[i] = doc.ID
+		}
+	}
+
+	failed := make(map[uint]bool, len(failedIDs))
+	for _, id := range failedIDs {
+		failed[id] = true
+	}
+
+	deadLettered := 0
+	for _, id := range failedIDs {
+		eventID := id
+		err := retry(ctx, func() error { return s.syncEventByID(ctx, eventID) })
+		if err == nil {
+			delete(failed, eventID)
+			continue
+		}
+		if err := s.storeDeadLetter(ctx, eventID, opIndex, err, cdcRetryMaxAttempts); err != nil {
+			return err
+		}
+		deadLettered++
+	}
+
+	for _, doc := range docs {
+		if !failed[doc.ID] {
+			job.reportProgress(doc.ID)
+		}
+	}
+
+	s.updateJob(job, func(job *SyncJob) {
+		job.Indexed += int64(len(docs) - deadLettered)
+		job.DeadLettered += int64(deadLettered)
+	})
+	return nil
+}
+
+// finishJob records the outcome of a job and frees the full sync slot if
+// the job held it
+func (s *Service) finishJob(job *SyncJob, cancelled bool, err error) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case err != nil:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	case cancelled:
+		job.Status = JobCancelled
+	default:
+		job.Status = JobCompleted
+	}
+	job.cancel()
+
+	if s.activeJob == job {
+		s.activeJob = nil
+	}
+	log.Printf("Sync job %s (%s) %s: %d of %d events indexed, %d dead-lettered",
+		job.ID, job.Kind, job.Status, job.Indexed, job.Total, job.DeadLettered)
+}
@@ -0,0 +1,68 @@
+package cdc
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+)
+
+func TestShutdownMidFullSyncLosesNoEvents(t *testing.T) {
+	db := testutil.NewDB(t)
+	events := seedEvents(t, db, 3*cdcBatchSize)
+	cfg := &config.Config{CDCSyncConcurrency: 1, CDCSyncDriftThreshold: 0.01}
+
+	search, client := testutil.NewSearchServer(t)
+	s := NewService(db, client, cfg, nil)
+
+	// The shutdown signal arrives while the second batch is being indexed
+	signalAt := strconv.FormatUint(uint64(events[cdcBatchSize].ID), 10)
+	shutdown := make(chan struct{})
+	var signal sync.Once
+	search.OnIndex = func(id string) error {
+		if id == signalAt {
+			signal.Do(func() { close(shutdown) })
+		}
+		return nil
+	}
+
+	job, jobCtx, err := s.startFullSync()
+	if err != nil {
+		t.Fatalf("failed to start full sync: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.runFullSync(jobCtx, job) }()
+
+	<-shutdown
+	drainCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.DrainJobs(drainCtx); err != nil {
+		t.Fatalf("drain failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("full sync failed: %v", err)
+	}
+
+	snapshot, _ := s.jobSnapshot(job.ID)
+	if snapshot.Status != JobCancelled {
+		t.Errorf("job is %s after shutdown, want %s", snapshot.Status, JobCancelled)
+	}
+	// The batch in flight is finished rather than abandoned half-indexed
+	indexed := len(search.IDs("events"))
+	if indexed%cdcBatchSize != 0 || indexed == 0 || indexed == len(events) {
+		t.Fatalf("%d of %d events indexed at shutdown, want whole batches and not all", indexed, len(events))
+	}
+
+	// The next start sees the index behind the database and catches up
+	restarted := NewService(db, client, cfg, nil)
+	if err := restarted.InitialSync(context.Background()); err != nil {
+		t.Fatalf("initial sync after restart failed: %v", err)
+	}
+	if indexed := len(search.IDs("events")); indexed != len(events) {
+		t.Errorf("%d of %d events indexed after restart", indexed, len(events))
+	}
+}
@@ -0,0 +1,213 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// leaderLeaseKey is the Redis key holding the CDC leader lease
+	leaderLeaseKey = "cdc:leader"
+
+	// forwardedHeader marks requests proxied from a follower to the leader,
+	// so they are never proxied again
+	forwardedHeader = "X-CDC-Forwarded-By"
+
+	// leaseReleaseTimeout bounds releasing the lease on shutdown
+	leaseReleaseTimeout = 2 * time.Second
+)
+
+// Only one CDC replica, the leader, runs worker ticks, change notifications
+// and full syncs. Replicas compete for a Redis lease (SET NX with a TTL) and
+// the leader renews it every third of the TTL. If the leader dies its lease
+// expires and a follower takes over within one lease period. With leader
+// election disabled every replica acts as leader.
+
+// LeaderInfo identifies a CDC replica; it is stored as the lease value
+type LeaderInfo struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// LeaderStatus reports leadership in /cdc/status
+type LeaderStatus struct {
+	ElectionEnabled bool        `json:"electionEnabled"`
+	IsLeader        bool        `json:"isLeader"`
+	Instance        LeaderInfo  `json:"instance"`
+	Leader          *LeaderInfo `json:"leader,omitempty"`
+}
+
+// newInstance identifies this replica by hostname plus a random suffix
+func newInstance(advertiseURL string) LeaderInfo {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "cdc"
+	}
+	return LeaderInfo{
+		ID:  fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8]),
+		URL: advertiseURL,
+	}
+}
+
+// IsLeader reports whether this replica currently holds the leader lease
+func (s *Service) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// StartLeaderElection competes for the leader lease until ctx is done,
+// releasing the lease on the way out. Gaining leadership runs InitialSync to
+// catch up on anything the previous leader left behind.
+func (s *Service) StartLeaderElection(ctx context.Context) {
+	ttl := s.config.CDCLeaderLeaseTTL
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	log.Printf("CDC leader election started as %s", s.instance.ID)
+
+	var renewedAt time.Time
+	for {
+		if s.IsLeader() {
+			renewed, err := s.redis.RenewLease(ctx, leaderLeaseKey, s.leaseValue, ttl)
+			switch {
+			case err == nil && renewed:
+				renewedAt = time.Now()
+			case err == nil:
+				s.stepDown("lease was taken over")
+			case time.Since(renewedAt) >= ttl:
+				// The lease may already belong to someone else
+				s.stepDown(fmt.Sprintf("lease could not be renewed: %v", err))
+			default:
+				log.Printf("Failed to renew CDC leader lease: %v", err)
+			}
+		} else {
+			acquired, err := s.redis.AcquireLease(ctx, leaderLeaseKey, s.leaseValue, ttl)
+			if err != nil {
+				log.Printf("Failed to acquire CDC leader lease: %v", err)
+			} else if acquired {
+				renewedAt = time.Now()
+				s.becomeLeader(ctx)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if s.IsLeader() {
+				s.stepDown("shutting down")
+				releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), leaseReleaseTimeout)
+				if err := s.redis.ReleaseLease(releaseCtx, leaderLeaseKey, s.leaseValue); err != nil {
+					log.Printf("Failed to release CDC leader lease: %v", err)
+				}
+				cancel()
+			}
+			log.Println("CDC leader election stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) becomeLeader(ctx context.Context) {
+	s.leader.Store(true)
+	log.Printf("CDC replica %s is now the leader", s.instance.ID)
+
+	go func() {
+		if err := s.InitialSync(ctx); err != nil {
+			log.Printf("Initial sync failed: %v", err)
+		}
+	}()
+}
+
+// stepDown gives up leadership and cancels any running full sync
+func (s *Service) stepDown(reason string) {
+	s.leader.Store(false)
+	log.Printf("CDC replica %s is no longer the leader: %s", s.instance.ID, reason)
+
+	s.jobsMu.Lock()
+	if s.activeJob != nil {
+		s.activeJob.cancel()
+	}
+	s.jobsMu.Unlock()
+}
+
+// currentLeader reads the lease holder from Redis; nil means no leader
+func (s *Service) currentLeader(ctx context.Context) (*LeaderInfo, error) {
+	value, err := s.redis.LeaseHolder(ctx, leaderLeaseKey)
+	if err != nil || value == "" {
+		return nil, err
+	}
+
+	var leader LeaderInfo
+	if err := json.Unmarshal([]byte(value), &leader); err != nil {
+		return nil, fmt.Errorf("failed to decode leader lease: %w", err)
+	}
+	return &leader, nil
+}
+
+// leaderStatus describes leadership for the status report
+func (s *Service) leaderStatus(ctx context.Context) LeaderStatus {
+	status := LeaderStatus{
+		ElectionEnabled: s.config.CDCLeaderElection,
+		IsLeader:        s.IsLeader(),
+		Instance:        s.instance,
+	}
+	if !s.config.CDCLeaderElection {
+		return status
+	}
+
+	leader, err := s.currentLeader(ctx)
+	if err != nil {
+		log.Printf("Failed to look up CDC leader: %v", err)
+	}
+	status.Leader = leader
+	return status
+}
+
+// leaderOnly runs admin sync handlers on the leader. Followers proxy the
+// request to the leader, unless local=true asks to run it here anyway.
+func (s *Service) leaderOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.IsLeader() || c.Query("local") == "true" || c.GetHeader(forwardedHeader) != "" {
+			c.Next()
+			return
+		}
+
+		leader, err := s.currentLeader(c.Request.Context())
+		if err != nil || leader == nil {
+			details := gin.H{"hint": "retry with local=true to run on this replica"}
+			if err != nil {
+				details["reason"] = err.Error()
+			}
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "No CDC leader available", details))
+			return
+		}
+
+		target, err := url.Parse(leader.URL)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Invalid CDC leader URL", gin.H{"reason": err.Error()}))
+			return
+		}
+
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		// Pass streamed sync progress through as it arrives
+		proxy.FlushInterval = -1
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "CDC leader unreachable", gin.H{"leader": leader.ID, "reason": err.Error()}))
+		}
+
+		c.Request.Header.Set(forwardedHeader, s.instance.ID)
+		proxy.ServeHTTP(c.Writer, c.Request)
+		c.Abort()
+	}
+}
@@ -0,0 +1,59 @@
+package cdc
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+)
+
+// changeDebounce coalesces bursts of change notifications for one event
+const changeDebounce = time.Second
+
+// StartChangeSubscriber re-indexes events as other services announce changes
+// on the Redis changes channel. Notifications for the same event within
+// changeDebounce trigger a single sync. Followers drop notifications, since
+// the leader receives them too. The periodic worker still runs and catches
+// anything missed here.
+func (s *Service) StartChangeSubscriber(ctx context.Context, redisClient redis.Store) {
+	log.Println("CDC change subscriber started")
+
+	var (
+		mu      sync.Mutex
+		pending = make(map[uint]bool)
+	)
+
+	for change := range redisClient.SubscribeChanges(ctx) {
+		eventID := change.EventID
+
+		mu.Lock()
+		if pending[eventID] {
+			mu.Unlock()
+			continue
+		}
+		pending[eventID] = true
+		mu.Unlock()
+
+		time.AfterFunc(changeDebounce, func() {
+			mu.Lock()
+			delete(pending, eventID)
+			mu.Unlock()
+
+			if ctx.Err() != nil || !s.IsLeader() {
+				return
+			}
+			err := s.withRetry(ctx, eventID, opIndex, func() error {
+				return s.syncEventByID(ctx, eventID)
+			})
+			if err != nil {
+				log.Printf("Failed to sync event %d from change notification: %v", eventID, err)
+				return
+			}
+			s.recordSync(1, nil)
+		})
+	}
+
+	log.Println("CDC change subscriber stopped")
+}
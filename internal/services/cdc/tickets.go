@@ -0,0 +1,87 @@
+package cdc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TicketAvailability is the part of an event document derived from its tickets
+type TicketAvailability struct {
+	AvailableTickets int     `json:"availableTickets"`
+	MinPrice         float64 `json:"minPrice"`
+	MaxPrice         float64 `json:"maxPrice"`
+}
+
+// SyncTickets refreshes only the ticket-derived fields of an event's search
+// document. It is cheaper than SyncEvent for availability-only changes as
+// it aggregates in Postgres and patches the document in place. An event
+// that is not indexed yet gets a full sync instead.
+func (s *Service) SyncTickets(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Query("event_id"), 10, 32)
+	if err != nil || eventID == 0 {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := s.db.WithContext(ctx).Select("id").First(&models.Event{}, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	availability, err := s.ticketAvailability(ctx, uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count tickets", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	err = s.searchClient.PartialUpdateEvent(ctx, uint(eventID), map[string]interface{}{
+		"availableTickets": availability.AvailableTickets,
+		"minPrice":         availability.MinPrice,
+		"maxPrice":         availability.MaxPrice,
+	})
+	if errors.Is(err, elasticsearch.ErrEventNotIndexed) {
+		err = s.syncEventByID(context.WithoutCancel(ctx), uint(eventID))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to sync tickets", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":          eventID,
+		"availableTickets": availability.AvailableTickets,
+		"minPrice":         availability.MinPrice,
+		"maxPrice":         availability.MaxPrice,
+	})
+}
+
+// ticketAvailability aggregates an event's tickets the same way
+// convertToElasticsearchEvent does: the price range covers every ticket,
+// the count only available ones
+func (s *Service) ticketAvailability(ctx context.Context, eventID uint) (*TicketAvailability, error) {
+	var availability TicketAvailability
+	err := s.db.WithContext(ctx).Model(&models.Ticket{}).
+		Select("COUNT(*) FILTER (WHERE status = ?) AS available_tickets, "+
+			"COALESCE(MIN(price), 0) / 100.0 AS min_price, COALESCE(MAX(price), 0) / 100.0 AS max_price", models.TicketAvailable).
+		Where("event_id = ?", eventID).
+		Scan(&availability).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate tickets: %w", err)
+	}
+	return &availability, nil
+}
@@ -0,0 +1,238 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxTicketsPerRequest bounds one CreateTickets call, which is inserted in
+// a single transaction
+const maxTicketsPerRequest = 50000
+
+// CreateVenue adds a venue (admin only). SeatMap, when given, must be JSON;
+// it is stored as is.
+func (s *Service) CreateVenue(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	var req struct {
+		Location string `json:"location" binding:"required"`
+		Capacity int    `json:"capacity" binding:"required,min=1"`
+		Country  string `json:"country" binding:"omitempty,len=2"`
+		SeatMap  string `json:"seatMap"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	if req.SeatMap != "" && !json.Valid([]byte(req.SeatMap)) {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "seatMap must be JSON", nil))
+		return
+	}
+
+	venue := models.Venue{
+		Location: req.Location,
+		Capacity: req.Capacity,
+		Country:  strings.ToUpper(req.Country),
+		SeatMap:  req.SeatMap,
+	}
+	if err := s.db.WithContext(c.Request.Context()).Create(&venue).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create venue", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusCreated, venue)
+}
+
+// CreatePerformer adds a performer (admin only)
+func (s *Service) CreatePerformer(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+		Genre       string `json:"genre"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	performer := models.Performer{
+		Name:        req.Name,
+		Description: req.Description,
+		Genre:       req.Genre,
+	}
+	if err := s.db.WithContext(c.Request.Context()).Create(&performer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create performer", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusCreated, performer)
+}
+
+// CreateTickets puts new seats of an event on sale (admin only). Seats must
+// be unique within the event, and the event may not end up with more
+// tickets than its capacity.
+func (s *Service) CreateTickets(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var req struct {
+		Tickets []TicketSpec `json:"tickets" binding:"required,min=1,dive"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+	if len(req.Tickets) > maxTicketsPerRequest {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Too many tickets in one request",
+			gin.H{"max": maxTicketsPerRequest}))
+		return
+	}
+
+	tickets, err := newTickets(uint(eventID), req.Tickets)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+
+	// The checks run with the event row locked, so concurrent requests
+	// cannot both fit under the capacity or both add the same seat
+	var existing []string
+	var capacity int
+	var duplicates []string
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, uint(eventID)).Error; err != nil {
+			return err
+		}
+		capacity = event.Capacity
+		if capacity == 0 {
+			var venue models.Venue
+			if err := tx.Select("capacity").First(&venue, event.VenueID).Error; err != nil {
+				return err
+			}
+			capacity = venue.Capacity
+		}
+
+		if err := tx.Model(&models.Ticket{}).Where("event_id = ?", event.ID).Pluck("seat", &existing).Error; err != nil {
+			return err
+		}
+		if len(existing)+len(tickets) > capacity {
+			return errCapacityExceeded
+		}
+
+		seats := make(map[string]bool, len(existing)+len(tickets))
+		for _, seat := range existing {
+			seats[seat] = true
+		}
+		for _, ticket := range tickets {
+			if seats[ticket.Seat] {
+				duplicates = append(duplicates, ticket.Seat)
+			}
+			seats[ticket.Seat] = true
+		}
+		if len(duplicates) > 0 {
+			return errDuplicateSeats
+		}
+
+		return insertTickets(tx, event.ID, tickets)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+		case errors.Is(err, errCapacityExceeded):
+			c.JSON(http.StatusUnprocessableEntity, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Tickets exceed the event's capacity",
+				gin.H{"existing": len(existing), "requested": len(tickets), "capacity": capacity}))
+		case errors.Is(err, errDuplicateSeats):
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Seats already exist",
+				gin.H{"seats": duplicates}))
+		case database.IsUniqueViolation(err):
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Seats already exist", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create tickets", gin.H{"reason": err.Error()}))
+		}
+		return
+	}
+	s.ticketsCreated(c.Request.Context(), uint(eventID))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"eventId": uint(eventID),
+		"created": len(tickets),
+		"total":   len(existing) + len(tickets),
+	})
+}
+
+// GetQueueStatus reports an event's waiting queue: how many users wait and
+// how many were admitted and may still reserve (admin only)
+func (s *Service) GetQueueStatus(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	if s.redisClient == nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Waiting queue is unavailable", nil))
+		return
+	}
+
+	length, err := s.redisClient.QueueLength(c.Request.Context(), uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Failed to read the waiting queue", gin.H{"reason": err.Error()}))
+		return
+	}
+	admitted, err := s.redisClient.ActiveAdmissions(c.Request.Context(), uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Failed to read admissions", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":          uint(eventID),
+		"queueLength":      length,
+		"activeAdmissions": admitted,
+	})
+}
+
+// requireAdmin validates the bearer token and checks for the admin role,
+// writing the error response when either fails
+func (s *Service) requireAdmin(c *gin.Context) bool {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return false
+	}
+
+	if !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+		return false
+	}
+
+	return true
+}
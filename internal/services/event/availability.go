@@ -0,0 +1,141 @@
+package event
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// availabilityCacheTTL bounds how stale a cached seat status can be; the
+	// booking service also drops a ticket's entry whenever it changes
+	availabilityCacheTTL = 5 * time.Second
+
+	// availabilityCacheTimeout keeps a slow Redis from delaying the answer
+	// more than falling back to Postgres would
+	availabilityCacheTimeout = 20 * time.Millisecond
+)
+
+// availabilityRequest is the body of POST /events/:id/tickets/availability
+type availabilityRequest struct {
+	SeatIDs []uint `json:"seat_ids" binding:"required,min=1,max=200"`
+}
+
+// CheckAvailability returns the status of many tickets of an event at once
+// for the seat picker. Cached statuses are served from Redis; the rest are
+// read from Postgres and cached. Tickets that do not belong to the event
+// are left out.
+func (s *Service) CheckAvailability(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var req availabilityRequest
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	found := s.cachedTicketStatuses(c.Request.Context(), req.SeatIDs)
+
+	var missing []uint
+	for _, id := range req.SeatIDs {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		tickets, err := s.tickets.Statuses(c.Request.Context(), uint(eventID), missing)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch tickets", gin.H{"reason": err.Error()}))
+			return
+		}
+
+		loaded := make([]redis.TicketStatus, len(tickets))
+		for i, ticket := range tickets {
+			loaded[i] = redis.TicketStatus{
+				ID:      ticket.ID,
+				EventID: ticket.EventID,
+				Seat:    ticket.Seat,
+				Status:  string(ticket.Status),
+				Price:   ticket.Price,
+			}
+			found[ticket.ID] = loaded[i]
+		}
+		s.cacheTicketStatuses(c.Request.Context(), loaded)
+	}
+
+	// An empty answer is only ambiguous when the event itself may not exist
+	if len(found) == 0 {
+		exists, err := s.events.Exists(c.Request.Context(), uint(eventID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+	}
+
+	seats := make([]gin.H, 0, len(req.SeatIDs))
+	for _, id := range req.SeatIDs {
+		status, ok := found[id]
+		if !ok || status.EventID != uint(eventID) {
+			continue
+		}
+		seats = append(seats, gin.H{
+			"id":     status.ID,
+			"seat":   status.Seat,
+			"status": status.Status,
+			"price":  status.Price,
+		})
+	}
+
+	c.JSON(http.StatusOK, seats)
+}
+
+// cachedTicketStatuses reads the cached statuses, treating any Redis problem
+// as a miss
+func (s *Service) cachedTicketStatuses(ctx context.Context, ticketIDs []uint) map[uint]redis.TicketStatus {
+	if s.redisClient == nil {
+		return map[uint]redis.TicketStatus{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, availabilityCacheTimeout)
+	defer cancel()
+
+	statuses, err := s.redisClient.GetTicketStatuses(ctx, ticketIDs)
+	if err != nil {
+		log.Printf("Seat availability cache unavailable: %v", err)
+		return map[uint]redis.TicketStatus{}
+	}
+	return statuses
+}
+
+// cacheTicketStatuses stores freshly loaded statuses without delaying the
+// response
+func (s *Service) cacheTicketStatuses(ctx context.Context, statuses []redis.TicketStatus) {
+	if s.redisClient == nil || len(statuses) == 0 {
+		return
+	}
+
+	detached := context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(detached, time.Second)
+		defer cancel()
+		if err := s.redisClient.SetTicketStatuses(ctx, statuses, availabilityCacheTTL); err != nil {
+			log.Printf("Failed to cache seat availability: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	memstore "github.com/JonasLeetTheWay/ticketmaster-go/internal/store/inmem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newStoreService returns an event service backed by the in-memory stores
+// alone, for handlers that do not touch the database directly
+func newStoreService(t *testing.T) (*Service, *memstore.Store, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	rows := memstore.New()
+	cache := inmem.New()
+	t.Cleanup(func() { cache.Close() })
+	s := &Service{
+		tickets:     rows.Tickets(),
+		events:      rows.Events(),
+		users:       rows.Users(),
+		config:      &config.Config{},
+		redisClient: cache,
+	}
+
+	router := gin.New()
+	router.POST("/events/:id/tickets/availability", s.CheckAvailability)
+	return s, rows, router
+}
+
+func checkAvailability(t *testing.T, router *gin.Engine, eventID uint, seatIDs ...uint) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(gin.H{"seat_ids": seatIDs})
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/events/%d/tickets/availability", eventID), bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCheckAvailabilityFromStore(t *testing.T) {
+	_, rows, router := newStoreService(t)
+	concert := rows.AddEvent(models.Event{Name: "Spring Concert", Date: time.Now().Add(24 * time.Hour)})
+	other := rows.AddEvent(models.Event{Name: "Other", Date: time.Now().Add(24 * time.Hour)})
+	a1 := rows.AddTicket(models.Ticket{EventID: concert, Seat: "A1", Price: money.FromFloat(500)})
+	a2 := rows.AddTicket(models.Ticket{EventID: concert, Seat: "A2", Price: money.FromFloat(500), Status: models.TicketBooked})
+	elsewhere := rows.AddTicket(models.Ticket{EventID: other, Seat: "B1", Price: money.FromFloat(300)})
+
+	rec := checkAvailability(t, router, concert, a1, a2, elsewhere)
+	expectStatus(t, rec, http.StatusOK)
+	var seats []struct {
+		ID     uint         `json:"id"`
+		Seat   string       `json:"seat"`
+		Status string       `json:"status"`
+		Price  money.Amount `json:"price"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &seats); err != nil {
+		t.Fatalf("failed to decode seats: %v", err)
+	}
+	if len(seats) != 2 {
+		t.Fatalf("got %d seats, want the event's 2: %s", len(seats), rec.Body)
+	}
+	if seats[0].ID != a1 || seats[0].Status != string(models.TicketAvailable) || seats[0].Price != money.FromFloat(500) {
+		t.Errorf("first seat is %+v, want A1 available at 500", seats[0])
+	}
+	if seats[1].ID != a2 || seats[1].Status != string(models.TicketBooked) {
+		t.Errorf("second seat is %+v, want A2 booked", seats[1])
+	}
+}
+
+func TestCheckAvailabilityUnknownEvent(t *testing.T) {
+	_, rows, router := newStoreService(t)
+	concert := rows.AddEvent(models.Event{Name: "Spring Concert"})
+
+	expectStatus(t, checkAvailability(t, router, concert+100, 1), http.StatusNotFound)
+
+	// A known event without the seats answers with an empty list
+	rec := checkAvailability(t, router, concert, 999)
+	expectStatus(t, rec, http.StatusOK)
+	if body := bytes.TrimSpace(rec.Body.Bytes()); string(body) != "[]" {
+		t.Errorf("got %s, want an empty list", body)
+	}
+
+	expectStatus(t, checkAvailability(t, router, concert), http.StatusBadRequest)
+}
@@ -0,0 +1,281 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// SearchVenues lists venues whose location matches q, optionally filtered by
+// location and a capacity range
+func (s *Service) SearchVenues(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+
+	minCapacity, err := parseOptionalInt(c.Query("min_capacity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "min_capacity must be a non-negative integer", nil))
+		return
+	}
+
+	maxCapacity, err := parseOptionalInt(c.Query("max_capacity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "max_capacity must be a non-negative integer", nil))
+		return
+	}
+
+	query := s.db.WithContext(c.Request.Context()).Model(&models.Venue{})
+	if q := c.Query("q"); q != "" {
+		query = query.Where("location ILIKE ? ESCAPE '\\'", likePattern(q))
+	}
+	if location := c.Query("location"); location != "" {
+		query = query.Where("location ILIKE ? ESCAPE '\\'", likePattern(location))
+	}
+	if minCapacity != nil {
+		query = query.Where("capacity >= ?", *minCapacity)
+	}
+	if maxCapacity != nil {
+		query = query.Where("capacity <= ?", *maxCapacity)
+	}
+
+	var venues []models.Venue
+	total, err := paginate(query, page, pageSize, "location", &venues)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search venues", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venues":   venues,
+		"count":    len(venues),
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// SearchPerformers lists performers whose name matches q, optionally
+// filtered by genre
+func (s *Service) SearchPerformers(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+
+	query := s.db.WithContext(c.Request.Context()).Model(&models.Performer{})
+	if q := c.Query("q"); q != "" {
+		query = query.Where("name ILIKE ? ESCAPE '\\'", likePattern(q))
+	}
+	if genre := c.Query("genre"); genre != "" {
+		// Case-insensitive equality, so wildcards in genre match literally
+		query = query.Where("genre ILIKE ? ESCAPE '\\'", likeEscaper.Replace(genre))
+	}
+
+	var performers []models.Performer
+	total, err := paginate(query, page, pageSize, "name", &performers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search performers", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"performers": performers,
+		"count":      len(performers),
+		"total":      total,
+		"page":       page,
+		"pageSize":   pageSize,
+	})
+}
+
+// performerEvent is an upcoming event on a performer's tour dates page
+type performerEvent struct {
+	models.Event
+	AvailableTicketsCount int64 `json:"availableTicketsCount"`
+}
+
+// GetPerformerEvents lists a performer's upcoming events, soonest first,
+// with their venue and how many tickets are still available
+func (s *Service) GetPerformerEvents(c *gin.Context) {
+	performerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid performer ID", nil))
+		return
+	}
+
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+
+	var performer models.Performer
+	if err := s.db.WithContext(c.Request.Context()).First(&performer, uint(performerID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodePerformerNotFound, "Performer not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch performer", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	query := s.db.WithContext(c.Request.Context()).Model(&models.Event{}).Preload("Venue").
+		Where("performer_id = ? AND date > ?", performer.ID, time.Now())
+
+	var events []models.Event
+	total, err := paginate(query, page, pageSize, "date", &events)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch events", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	counts, err := s.availableTicketCounts(c.Request.Context(), events)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count tickets", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	results := make([]performerEvent, len(events))
+	for i, event := range events {
+		results[i] = performerEvent{Event: event, AvailableTicketsCount: counts[event.ID]}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"performerId": performer.ID,
+		"events":      results,
+		"count":       len(results),
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+	})
+}
+
+// ListEvents lists events soonest first, with their Venue and Performer:
+// upcoming ones, or those in a date window such as this_weekend
+func (s *Service) ListEvents(c *gin.Context) {
+	page, pageSize, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+		return
+	}
+
+	query := s.db.WithContext(c.Request.Context()).Model(&models.Event{}).Preload("Venue").Preload("Performer")
+
+	window := c.Query("window")
+	if window == "" {
+		query = query.Where("events.date > ?", time.Now())
+	} else {
+		if _, _, err := windowRange(window, time.Now()); err != nil {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, err.Error(), nil))
+			return
+		}
+		query = query.Scopes(EventWindow(window))
+	}
+
+	var events []models.Event
+	total, err := paginate(query, page, pageSize, "date", &events)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch events", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":   events,
+		"count":    len(events),
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// availableTicketCounts counts the available tickets of each event in one
+// query; events with none are left out of the map
+func (s *Service) availableTicketCounts(ctx context.Context, events []models.Event) (map[uint]int64, error) {
+	ids := make([]uint, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return s.tickets.CountAvailable(ctx, ids)
+}
+
+// paginate counts the rows matched by query and loads one page of them into dest
+func paginate(query *gorm.DB, page, pageSize int, order string, dest interface{}) (int64, error) {
+	// Sessions keep the count from leaking into the page query
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	if err := query.Order(order).Order("id").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(dest).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// parsePagination reads the page (1-based) and page_size query parameters
+func parsePagination(c *gin.Context) (int, int, error) {
+	page := 1
+	if value := c.Query("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+		page = parsed
+	}
+
+	pageSize := defaultPageSize
+	if value := c.Query("page_size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			return 0, 0, fmt.Errorf("page_size must be between 1 and %d", maxPageSize)
+		}
+		pageSize = parsed
+	}
+
+	return page, pageSize, nil
+}
+
+// parseOptionalInt parses an optional non-negative integer query parameter;
+// empty means unset
+func parseOptionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return nil, fmt.Errorf("invalid integer: %q", value)
+	}
+
+	return &parsed, nil
+}
+
+// likeEscaper escapes LIKE wildcards with a backslash; patterns are used
+// with an explicit ESCAPE so they do not depend on the server's default
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern builds a substring ILIKE pattern, escaping LIKE wildcards in term
+func likePattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
@@ -0,0 +1,105 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// capacityCacheTimeout keeps a slow Redis from delaying the answer more
+// than counting in Postgres would
+const capacityCacheTimeout = 50 * time.Millisecond
+
+// GetEventCapacity returns an event's ticket counts in real time: total,
+// available, reserved and sold tickets, and the event's capacity. Counts
+// come from the Redis counters the booking service keeps current; when
+// they are missing they are counted in Postgres and stored.
+func (s *Service) GetEventCapacity(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).Preload("Venue").Select("id", "venue_id", "capacity").First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	counters, ok := s.cachedEventCounters(c.Request.Context(), event.ID)
+	if !ok {
+		if counters, err = s.refreshEventCounters(c.Request.Context(), event.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count tickets", gin.H{"reason": err.Error()}))
+			return
+		}
+	}
+
+	capacity := event.Capacity
+	if capacity == 0 {
+		capacity = event.Venue.Capacity
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":     counters.Total,
+		"available": counters.Available,
+		"reserved":  counters.Total - counters.Available - counters.Sold,
+		"sold":      counters.Sold,
+		"capacity":  capacity,
+	})
+}
+
+// cachedEventCounters reads the event's counters, treating any Redis
+// problem as a miss
+func (s *Service) cachedEventCounters(ctx context.Context, eventID uint) (redis.EventCounters, bool) {
+	if s.redisClient == nil {
+		return redis.EventCounters{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, capacityCacheTimeout)
+	defer cancel()
+
+	counters, ok, err := s.redisClient.GetEventCounters(ctx, eventID)
+	if err != nil {
+		log.Printf("Event capacity counters unavailable: %v", err)
+		return redis.EventCounters{}, false
+	}
+	return counters, ok
+}
+
+// refreshEventCounters counts the event's tickets by status in Postgres and
+// stores the counts in Redis, so later bookings adjust them from there
+func (s *Service) refreshEventCounters(ctx context.Context, eventID uint) (redis.EventCounters, error) {
+	counts, err := s.tickets.CountByStatus(ctx, eventID)
+	if err != nil {
+		return redis.EventCounters{}, fmt.Errorf("failed to count tickets: %w", err)
+	}
+
+	var counters redis.EventCounters
+	for _, count := range counts {
+		counters.Total += count
+	}
+	counters.Available = counts[models.TicketAvailable]
+	counters.Sold = counts[models.TicketBooked]
+
+	if s.redisClient != nil {
+		if err := s.redisClient.SetEventCounters(ctx, eventID, counters); err != nil {
+			log.Printf("Failed to store capacity counters for event %d: %v", eventID, err)
+		}
+	}
+	return counters, nil
+}
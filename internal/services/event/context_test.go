@@ -0,0 +1,73 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// TestCancelledRequestCancelsQuery disconnects the client while the
+// handler's first query is about to run and checks that the query fails
+// with the request's cancellation instead of running to completion
+func TestCancelledRequestCancelsQuery(t *testing.T) {
+	env := newTestEnv(t)
+	event := env.seedEvent(t, "Spring Concert", 2)
+
+	var (
+		mu      sync.Mutex
+		cancel  context.CancelFunc
+		results []error
+	)
+	disconnect := func(tx *gorm.DB) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+	}
+	record := func(tx *gorm.DB) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, tx.Error)
+	}
+	if err := env.db.Callback().Query().Before("gorm:query").Register("test:disconnect", disconnect); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+	if err := env.db.Callback().Query().After("gorm:query").Register("test:record", record); err != nil {
+		t.Fatalf("failed to register callback: %v", err)
+	}
+
+	for _, path := range []string{fmt.Sprintf("/event/%d", event.ID), "/events"} {
+		t.Run(path, func(t *testing.T) {
+			ctx, cancelRequest := context.WithCancel(context.Background())
+			defer cancelRequest()
+			mu.Lock()
+			cancel, results = cancelRequest, nil
+			mu.Unlock()
+
+			req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			env.router.ServeHTTP(rec, req)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(results) == 0 {
+				t.Fatal("handler ran no queries")
+			}
+			for i, err := range results {
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("query %d finished with %v after the client went away, want context.Canceled", i+1, err)
+				}
+			}
+			if rec.Code == http.StatusOK {
+				t.Errorf("handler answered 200 from a cancelled query: %s", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cdcRequestTimeout bounds the call that starts the CDC reindex
+const cdcRequestTimeout = 10 * time.Second
+
+// DevReset wipes the database, reseeds the sample data and starts a full
+// reindex, giving integration tests a known starting state. It is only
+// available with DEV_MODE enabled, and never in production.
+func (s *Service) DevReset(c *gin.Context) {
+	if !s.config.DevMode || s.config.IsProduction() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Reset is only available in dev mode", nil))
+		return
+	}
+
+	if err := database.ResetData(s.db.WithContext(c.Request.Context())); err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to reset database", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if err := database.SeedData(s.db.WithContext(c.Request.Context())); err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to seed database", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// Seeding bypasses the outbox, so reindex everything
+	jobID, err := s.startFullSync(c.Request.Context())
+	if err != nil {
+		log.Printf("Dev reset: failed to start reindex: %v", err)
+		c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Database reset but reindex failed to start", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Database reset and reseeded",
+		"syncJobId": jobID,
+	})
+}
+
+// startFullSync asks the CDC service to reindex every event and returns
+// the sync job ID
+func (s *Service) startFullSync(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cdcRequestTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("http://localhost:%s/cdc/sync-all", s.config.CDCServicePort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create sync request: %w", err)
+	}
+	auth.SetInternalToken(req, s.config)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call CDC service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		JobID string `json:"jobId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode CDC response: %w", err)
+	}
+
+	// A 409 means a sync started before the reset is still running and may
+	// already be past the reseeded rows
+	if resp.StatusCode != http.StatusAccepted {
+		return body.JobID, fmt.Errorf("CDC service returned %s", resp.Status)
+	}
+
+	return body.JobID, nil
+}
@@ -2,28 +2,95 @@ package event
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/store"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errAlreadyWaitlisted = errors.New("user is already on the waitlist")
+	errEventHasBookings  = errors.New("event has active bookings")
+	errCapacityExceeded  = errors.New("tickets exceed the event's capacity")
+	errDuplicateSeats    = errors.New("seats already exist")
 )
 
 type Service struct {
-	db *gorm.DB
+	db          *gorm.DB
+	tickets     store.TicketStore
+	events      store.EventStore
+	users       store.UserStore
+	config      *config.Config
+	notifier    notify.Notifier
+	redisClient redis.Store
+	dbHealth    *database.Readiness
 }
 
-func NewService(db *gorm.DB) *Service {
-	return &Service{db: db}
+// NewService creates the event service. redisClient publishes change
+// notifications and caches seat availability; it may be nil, in which case
+// availability is always read from Postgres.
+func NewService(db *gorm.DB, cfg *config.Config, notifier notify.Notifier, redisClient redis.Store) *Service {
+	return &Service{
+		db:          db,
+		tickets:     store.NewTicketStore(db),
+		events:      store.NewEventStore(db),
+		users:       store.NewUserStore(db),
+		config:      cfg,
+		notifier:    notifier,
+		redisClient: redisClient,
+		dbHealth:    database.NewReadiness("event-service", database.HealthCheckInterval),
+	}
 }
 
 func (s *Service) SetupRoutes(r *gin.Engine) {
 	r.GET("/event/:id", s.GetEvent)
+	r.GET("/event/:id/tiers", s.GetEventTiers)
+	r.GET("/event/:id/price", s.GetCurrentPrice)
 	r.POST("/event", s.CreateEvent)
 	r.PUT("/event/:id", s.UpdateEvent)
+	r.PATCH("/event/:id", s.PatchEvent)
 	r.DELETE("/event/:id", s.DeleteEvent)
+	r.GET("/venues/search", s.SearchVenues)
+	r.GET("/performers/search", s.SearchPerformers)
+	r.GET("/performers/:id/events", s.GetPerformerEvents)
+	r.GET("/performers/:id/stats", s.GetPerformerStats)
+	r.GET("/events", s.ListEvents)
+	r.GET("/events/featured", s.GetFeaturedEvents)
+	r.GET("/events/recommended", s.GetRecommendedEvents)
+	r.GET("/events/trending", s.GetTrendingEvents)
+	r.GET("/events/:id/capacity", s.GetEventCapacity)
+	r.GET("/events/:id/booking-summary", s.GetBookingSummary)
+	r.POST("/events/:id/tickets/availability", s.CheckAvailability)
+	r.POST("/events/:id/waitlist", s.JoinWaitlist)
+	r.GET("/events/:id/waitlist/position", s.GetWaitlistPosition)
+	r.POST("/events/:id/waitlist/release", s.ReleaseWaitlist)
+	r.PUT("/admin/events/:id/feature", s.FeatureEvent)
+	r.POST("/admin/events/:id/tickets", s.CreateTickets)
+	r.GET("/admin/events/:id/queue", s.GetQueueStatus)
+	r.POST("/admin/venues", s.CreateVenue)
+	r.POST("/admin/performers", s.CreatePerformer)
+	r.POST("/admin/dev/reset", s.DevReset)
 	r.GET("/health", s.HealthCheck)
 }
 
@@ -31,78 +98,219 @@ func (s *Service) GetEvent(c *gin.Context) {
 	eventIDStr := c.Param("id")
 	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid event ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
 		return
 	}
 
 	var event models.Event
-	result := s.db.Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, uint(eventID))
+	result := s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, uint(eventID))
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Event not found",
-			})
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch event",
-			"details": result.Error.Error(),
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": result.Error.Error()}))
 		return
 	}
 
-	c.JSON(http.StatusOK, event)
+	body, err := json.Marshal(event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to encode event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// Let clients revalidate instead of refetching unchanged events
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	lastModified := eventLastModified(&event)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-cache")
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 }
 
-func (s *Service) CreateEvent(c *gin.Context) {
+// eventLastModified is the latest update to the event or anything embedded
+// in its response; ticket sales change the payload without touching the
+// event row
+func eventLastModified(event *models.Event) time.Time {
+	latest := event.UpdatedAt
+	for _, updatedAt := range []time.Time{event.Venue.UpdatedAt, event.Performer.UpdatedAt} {
+		if updatedAt.After(latest) {
+			latest = updatedAt
+		}
+	}
+	for _, ticket := range event.Tickets {
+		if ticket.UpdatedAt.After(latest) {
+			latest = ticket.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// notModified evaluates the conditional request headers. If-None-Match takes
+// precedence; If-Modified-Since has one-second resolution.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		sinceTime, err := http.ParseTime(since)
+		return err == nil && !lastModified.Truncate(time.Second).After(sinceTime)
+	}
+
+	return false
+}
+
+func (s *Service) GetEventTiers(c *gin.Context) {
+	eventIDStr := c.Param("id")
+	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
 	var event models.Event
-	if err := c.ShouldBindJSON(&event); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid event data",
-			"details": err.Error(),
-		})
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
 		return
 	}
 
-	// Validate required fields
-	if event.VenueID == 0 || event.PerformerID == 0 || event.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Missing required fields: venueId, performerId, name",
-		})
+	var tiers []models.TicketTier
+	if err := s.db.WithContext(c.Request.Context()).Where("event_id = ?", event.ID).Order("price DESC").Find(&tiers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch ticket tiers", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId": event.ID,
+		"tiers":   tiers,
+		"count":   len(tiers),
+	})
+}
+
+// createEventRequest is the body of POST /event
+type createEventRequest struct {
+	VenueID     uint      `json:"venueId" binding:"required"`
+	PerformerID uint      `json:"performerId" binding:"required"`
+	Name        string    `json:"name" binding:"required"`
+	Description string    `json:"description"`
+	Date        time.Time `json:"date" binding:"required,future_date"`
+	Currency    string    `json:"currency" binding:"omitempty,valid_currency"` // Defaults to DEFAULT_CURRENCY
+	Capacity    *int      `json:"capacity" binding:"omitempty,min=1"`          // Defaults to the venue's capacity
+
+	RequiresQueue            bool `json:"requiresQueue"`
+	ReservationWindowMinutes *int `json:"reservationWindowMinutes" binding:"omitempty,min=1,max=120"`
+
+	DynamicPricingEnabled bool     `json:"dynamicPricingEnabled"`
+	PriceSurgeThreshold   *float64 `json:"priceSurgeThreshold" binding:"omitempty,gt=0,lte=1"`
+	PriceSurgeMultiplier  *float64 `json:"priceSurgeMultiplier" binding:"omitempty,gte=1"`
+}
+
+// updateEventRequest is the body of PUT /event/:id; omitted fields are unchanged
+type updateEventRequest struct {
+	VenueID     *uint      `json:"venueId" binding:"omitempty,min=1"`
+	PerformerID *uint      `json:"performerId" binding:"omitempty,min=1"`
+	Name        *string    `json:"name" binding:"omitempty,min=1"`
+	Description *string    `json:"description"`
+	Date        *time.Time `json:"date" binding:"omitempty,future_date"`
+	Currency    *string    `json:"currency" binding:"omitempty,valid_currency"`
+
+	RequiresQueue            *bool `json:"requiresQueue"`
+	ReservationWindowMinutes *int  `json:"reservationWindowMinutes" binding:"omitempty,min=1,max=120"`
+
+	DynamicPricingEnabled *bool    `json:"dynamicPricingEnabled"`
+	PriceSurgeThreshold   *float64 `json:"priceSurgeThreshold" binding:"omitempty,gt=0,lte=1"`
+	PriceSurgeMultiplier  *float64 `json:"priceSurgeMultiplier" binding:"omitempty,gte=1"`
+}
+
+func (s *Service) CreateEvent(c *gin.Context) {
+	var req createEventRequest
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
+	event := models.Event{
+		VenueID:     req.VenueID,
+		PerformerID: req.PerformerID,
+		Name:        req.Name,
+		Description: req.Description,
+		Date:        req.Date,
+		Currency:    strings.ToLower(req.Currency),
+
+		RequiresQueue:            req.RequiresQueue,
+		ReservationWindowMinutes: req.ReservationWindowMinutes,
+
+		DynamicPricingEnabled: req.DynamicPricingEnabled,
+		PriceSurgeThreshold:   0.2,
+		PriceSurgeMultiplier:  1.5,
+	}
+	if req.PriceSurgeThreshold != nil {
+		event.PriceSurgeThreshold = *req.PriceSurgeThreshold
+	}
+	if req.PriceSurgeMultiplier != nil {
+		event.PriceSurgeMultiplier = *req.PriceSurgeMultiplier
+	}
+
+	if event.Currency == "" {
+		event.Currency = s.config.DefaultCurrency
+	}
+
 	// Check if venue exists
 	var venue models.Venue
-	if err := s.db.First(&venue, event.VenueID).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Venue not found",
-		})
+	if err := s.db.WithContext(c.Request.Context()).First(&venue, event.VenueID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeVenueNotFound, "Venue not found", nil))
 		return
 	}
 
+	event.Capacity = venue.Capacity
+	if req.Capacity != nil {
+		if *req.Capacity > venue.Capacity {
+			c.JSON(http.StatusUnprocessableEntity, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Capacity exceeds the venue's capacity",
+				gin.H{"capacity": *req.Capacity, "venueCapacity": venue.Capacity}))
+			return
+		}
+		event.Capacity = *req.Capacity
+	}
+
 	// Check if performer exists
 	var performer models.Performer
-	if err := s.db.First(&performer, event.PerformerID).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Performer not found",
-		})
+	if err := s.db.WithContext(c.Request.Context()).First(&performer, event.PerformerID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodePerformerNotFound, "Performer not found", nil))
 		return
 	}
 
 	// Create event
-	if err := s.db.Create(&event).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create event",
-			"details": err.Error(),
-		})
+	err := s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		return outbox.RecordEvent(tx, event.ID, outbox.OpUpsert)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create event", gin.H{"reason": err.Error()}))
 		return
 	}
+	s.publishChange(c.Request.Context(), event.ID, outbox.OpUpsert)
 
 	// Load relationships
-	s.db.Preload("Venue").Preload("Performer").First(&event, event.ID)
+	s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").First(&event, event.ID)
 
 	c.JSON(http.StatusCreated, event)
 }
@@ -111,47 +319,75 @@ func (s *Service) UpdateEvent(c *gin.Context) {
 	eventIDStr := c.Param("id")
 	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid event ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
 		return
 	}
 
 	var event models.Event
-	if err := s.db.First(&event, uint(eventID)).Error; err != nil {
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Event not found",
-			})
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch event",
-			"details": err.Error(),
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
 		return
 	}
 
-	var updateData models.Event
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid event data",
-			"details": err.Error(),
-		})
+	var req updateEventRequest
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
+	updateData := make(map[string]interface{})
+	if req.VenueID != nil {
+		updateData["venue_id"] = *req.VenueID
+	}
+	if req.PerformerID != nil {
+		updateData["performer_id"] = *req.PerformerID
+	}
+	if req.Name != nil {
+		updateData["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updateData["description"] = *req.Description
+	}
+	if req.Date != nil {
+		updateData["date"] = *req.Date
+	}
+	if req.Currency != nil {
+		updateData["currency"] = strings.ToLower(*req.Currency)
+	}
+	if req.RequiresQueue != nil {
+		updateData["requires_queue"] = *req.RequiresQueue
+	}
+	if req.ReservationWindowMinutes != nil {
+		updateData["reservation_window_minutes"] = *req.ReservationWindowMinutes
+	}
+	if req.DynamicPricingEnabled != nil {
+		updateData["dynamic_pricing_enabled"] = *req.DynamicPricingEnabled
+	}
+	if req.PriceSurgeThreshold != nil {
+		updateData["price_surge_threshold"] = *req.PriceSurgeThreshold
+	}
+	if req.PriceSurgeMultiplier != nil {
+		updateData["price_surge_multiplier"] = *req.PriceSurgeMultiplier
+	}
+
 	// Update event
-	if err := s.db.Model(&event).Updates(updateData).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to update event",
-			"details": err.Error(),
-		})
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&event).Updates(updateData).Error; err != nil {
+			return err
+		}
+		return outbox.RecordEvent(tx, event.ID, outbox.OpUpsert)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to update event", gin.H{"reason": err.Error()}))
 		return
 	}
+	s.publishChange(c.Request.Context(), event.ID, outbox.OpUpsert)
 
 	// Load relationships
-	s.db.Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, event.ID)
+	s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, event.ID)
 
 	c.JSON(http.StatusOK, event)
 }
@@ -160,36 +396,72 @@ func (s *Service) DeleteEvent(c *gin.Context) {
 	eventIDStr := c.Param("id")
 	eventID, err := strconv.ParseUint(eventIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid event ID",
-		})
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
 		return
 	}
 
 	// Check if event exists
 	var event models.Event
-	if err := s.db.First(&event, uint(eventID)).Error; err != nil {
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Event not found",
-			})
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to fetch event",
-			"details": err.Error(),
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
 		return
 	}
 
-	// Delete event (this will cascade to tickets due to foreign key constraints)
-	if err := s.db.Delete(&event).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete event",
-			"details": err.Error(),
-		})
+	// Soft-delete the event with its tickets and tiers, so none of them are
+	// left listed as available. Events with reserved or confirmed bookings
+	// are kept so those bookings are not orphaned. The event and its
+	// tickets are locked before the bookings are counted: a reservation
+	// updates its ticket row, so one in flight either commits before the
+	// count sees it or waits and then finds the ticket deleted.
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, event.ID).Error; err != nil {
+			return err
+		}
+		var ticketIDs []uint
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&models.Ticket{}).
+			Where("event_id = ?", event.ID).Pluck("id", &ticketIDs).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&models.Booking{}).
+			Joins("JOIN tickets ON tickets.id = bookings.ticket_id").
+			Where("tickets.event_id = ? AND bookings.status IN ?", event.ID, []models.BookingStatus{models.BookingReserved, models.BookingConfirmed}).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return errEventHasBookings
+		}
+
+		if err := tx.Where("event_id = ?", event.ID).Delete(&models.Ticket{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ?", event.ID).Delete(&models.TicketTier{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&event).Error; err != nil {
+			return err
+		}
+		return outbox.RecordEvent(tx, event.ID, outbox.OpDelete)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+		return
+	}
+	if errors.Is(err, errEventHasBookings) {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeEventHasBookings, "Cannot delete event with active bookings. Cancel the event first.", nil))
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to delete event", gin.H{"reason": err.Error()}))
+		return
+	}
+	s.publishChange(c.Request.Context(), event.ID, outbox.OpDelete)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Event deleted successfully",
@@ -197,16 +469,31 @@ func (s *Service) DeleteEvent(c *gin.Context) {
 }
 
 func (s *Service) HealthCheck(c *gin.Context) {
+	if err := s.dbHealth.Err(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":   "NOT_READY",
+			"service":  "event-service",
+			"database": err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "healthy",
 		"service": "event-service",
 	})
 }
 
+// WatchDatabase pings the database until ctx is done; the health check
+// reports NOT_READY while pings fail
+func (s *Service) WatchDatabase(ctx context.Context) {
+	s.dbHealth.Watch(ctx, s.db)
+}
+
 // GetEventByID returns an event by ID with all relationships loaded
 func (s *Service) GetEventByID(ctx context.Context, eventID uint) (*models.Event, error) {
 	var event models.Event
-	result := s.db.Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, eventID)
+	result := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, eventID)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -215,19 +502,71 @@ func (s *Service) GetEventByID(ctx context.Context, eventID uint) (*models.Event
 
 // CreateTicketsForEvent creates tickets for an event
 func (s *Service) CreateTicketsForEvent(ctx context.Context, eventID uint, ticketSpecs []TicketSpec) error {
+	tickets, err := newTickets(eventID, ticketSpecs)
+	if err != nil {
+		return err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return insertTickets(tx, eventID, tickets)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.ticketsCreated(ctx, eventID)
+	return nil
+}
+
+// newTickets validates the specs and builds available tickets from them
+func newTickets(eventID uint, ticketSpecs []TicketSpec) ([]models.Ticket, error) {
 	tickets := make([]models.Ticket, len(ticketSpecs))
 	for i, spec := range ticketSpecs {
+		if err := validation.Validate.Struct(spec); err != nil {
+			return nil, fmt.Errorf("invalid ticket spec %d: %w", i, err)
+		}
 		tickets[i] = models.Ticket{
 			EventID: eventID,
 			Seat:    spec.Seat,
 			Price:   spec.Price,
-			Status:  "available",
+			Status:  models.TicketAvailable,
 		}
 	}
-	return s.db.Create(&tickets).Error
+	return tickets, nil
+}
+
+// insertTickets creates tickets in tx with the outbox change
+func insertTickets(tx *gorm.DB, eventID uint, tickets []models.Ticket) error {
+	// Batched to stay under Postgres's bind parameter limit
+	if err := tx.CreateInBatches(&tickets, 500).Error; err != nil {
+		return err
+	}
+	return outbox.RecordEvent(tx, eventID, outbox.OpUpsert)
+}
+
+// ticketsCreated publishes the change and refreshes the capacity counters
+// once new tickets are committed
+func (s *Service) ticketsCreated(ctx context.Context, eventID uint) {
+	s.publishChange(ctx, eventID, outbox.OpUpsert)
+	if _, err := s.refreshEventCounters(ctx, eventID); err != nil {
+		log.Printf("Failed to populate capacity counters for event %d: %v", eventID, err)
+	}
+}
+
+// publishChange tells the CDC service to re-index an event right away when
+// pub/sub sync is enabled. Failures are only logged; the periodic sync
+// picks the change up anyway. The change is committed by then, so it is
+// published even if ctx was cancelled.
+func (s *Service) publishChange(ctx context.Context, eventID uint, op string) {
+	if !s.config.CDCPubSubEnabled || s.redisClient == nil {
+		return
+	}
+	msg := redis.ChangeMessage{EventID: eventID, Op: op}
+	if err := s.redisClient.PublishChange(context.WithoutCancel(ctx), msg); err != nil {
+		log.Printf("Failed to publish change for event %d: %v", eventID, err)
+	}
 }
 
 type TicketSpec struct {
-	Seat  string  `json:"seat"`
-	Price float64 `json:"price"`
+	Seat  string       `json:"seat" binding:"required"`
+	Price money.Amount `json:"price" binding:"positive_price"`
 }
@@ -0,0 +1,190 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// testEnv is the event service's routes on sqlite and the in-memory Redis
+// store
+type testEnv struct {
+	service *Service
+	router  *gin.Engine
+	db      *gorm.DB
+	config  *config.Config
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpiry: time.Hour, DefaultCurrency: "twd"}
+	store := inmem.New()
+	t.Cleanup(func() { store.Close() })
+	db := testutil.NewDB(t)
+
+	s := NewService(db, cfg, notify.NewLogNotifier(), store)
+	router := gin.New()
+	s.SetupRoutes(router)
+	return &testEnv{service: s, router: router, db: db, config: cfg}
+}
+
+// seedEvent creates an event at a new venue with the given ticket count
+func (env *testEnv) seedEvent(t *testing.T, name string, tickets int) *models.Event {
+	t.Helper()
+	venue := models.Venue{Location: "Taipei Arena", Capacity: 100}
+	performer := models.Performer{Name: "Band", Genre: "rock"}
+	if err := env.db.Create(&venue).Error; err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+	if err := env.db.Create(&performer).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+	event := models.Event{
+		VenueID:     venue.ID,
+		PerformerID: performer.ID,
+		Name:        name,
+		Date:        time.Now().Add(30 * 24 * time.Hour),
+		Currency:    "twd",
+	}
+	if err := env.db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	for i := 0; i < tickets; i++ {
+		ticket := models.Ticket{EventID: event.ID, Seat: "A" + string(rune('1'+i)), Price: money.FromFloat(500), Status: models.TicketAvailable}
+		if err := env.db.Create(&ticket).Error; err != nil {
+			t.Fatalf("failed to create ticket: %v", err)
+		}
+	}
+	return &event
+}
+
+// do sends a request with the given headers through the service's routes
+func (env *testEnv) do(t *testing.T, method, path string, body interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatalf("failed to marshal request: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	env.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// expectStatus fails the test unless the response has the wanted status
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, want, rec.Body)
+	}
+}
+
+func TestGetEventConditionalRequests(t *testing.T) {
+	env := newTestEnv(t)
+	event := env.seedEvent(t, "Spring Concert", 2)
+	path := fmt.Sprintf("/event/%d", event.ID)
+
+	rec := env.do(t, http.MethodGet, path, nil, nil)
+	expectStatus(t, rec, http.StatusOK)
+	etag := rec.Header().Get("ETag")
+	lastModified := rec.Header().Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("response has ETag %q and Last-Modified %q, want both", etag, lastModified)
+	}
+
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-None-Match": etag})
+	expectStatus(t, rec, http.StatusNotModified)
+	if rec.Body.Len() != 0 {
+		t.Errorf("304 response has a body: %s", rec.Body)
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("304 response has ETag %q, want %q", rec.Header().Get("ETag"), etag)
+	}
+
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-None-Match": `"other", W/` + etag})
+	expectStatus(t, rec, http.StatusNotModified)
+
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-Modified-Since": lastModified})
+	expectStatus(t, rec, http.StatusNotModified)
+
+	// If-None-Match wins over a matching If-Modified-Since
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": lastModified})
+	expectStatus(t, rec, http.StatusOK)
+
+	// A ticket sale changes the payload without touching the event row
+	later := time.Now().Add(time.Minute)
+	if err := env.db.Model(&models.Ticket{}).Where("event_id = ?", event.ID).Limit(1).
+		UpdateColumns(map[string]interface{}{"status": models.TicketBooked, "updated_at": later}).Error; err != nil {
+		t.Fatalf("failed to book ticket: %v", err)
+	}
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-None-Match": etag})
+	expectStatus(t, rec, http.StatusOK)
+	if rec.Header().Get("ETag") == etag {
+		t.Error("ETag did not change after a ticket was sold")
+	}
+	rec = env.do(t, http.MethodGet, path, nil, map[string]string{"If-Modified-Since": lastModified})
+	expectStatus(t, rec, http.StatusOK)
+}
+
+func TestDeletedEventLeavesListingsWithItsTickets(t *testing.T) {
+	env := newTestEnv(t)
+	deleted := env.seedEvent(t, "Cancelled Show", 2)
+	kept := env.seedEvent(t, "Spring Concert", 2)
+
+	expectStatus(t, env.do(t, http.MethodDelete, fmt.Sprintf("/event/%d", deleted.ID), nil, nil), http.StatusOK)
+
+	expectStatus(t, env.do(t, http.MethodGet, fmt.Sprintf("/event/%d", deleted.ID), nil, nil), http.StatusNotFound)
+
+	rec := env.do(t, http.MethodGet, "/events", nil, nil)
+	expectStatus(t, rec, http.StatusOK)
+	var list struct {
+		Events []models.Event `json:"events"`
+		Total  int64          `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode events: %v", err)
+	}
+	if list.Total != 1 || len(list.Events) != 1 || list.Events[0].ID != kept.ID {
+		t.Errorf("listing returned %d of %d events, want only event %d", len(list.Events), list.Total, kept.ID)
+	}
+
+	// Its tickets went with it rather than staying listed as available
+	var live, all int64
+	env.db.Model(&models.Ticket{}).Where("event_id = ?", deleted.ID).Count(&live)
+	env.db.Unscoped().Model(&models.Ticket{}).Where("event_id = ?", deleted.ID).Count(&all)
+	if live != 0 || all != 2 {
+		t.Errorf("deleted event has %d live of %d tickets, want 0 live and both kept for audit", live, all)
+	}
+	counts, err := env.service.availableTicketCounts(context.Background(), []models.Event{*deleted, *kept})
+	if err != nil {
+		t.Fatalf("failed to count available tickets: %v", err)
+	}
+	if counts[deleted.ID] != 0 || counts[kept.ID] != 2 {
+		t.Errorf("available counts %v, want none for the deleted event and 2 for the kept one", counts)
+	}
+}
@@ -0,0 +1,95 @@
+package event
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetFeaturedEvents lists the events currently pinned to the homepage,
+// soonest first
+func (s *Service) GetFeaturedEvents(c *gin.Context) {
+	var events []models.Event
+	if err := s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").
+		Where("featured = ? AND (featured_until IS NULL OR featured_until > ?)", true, time.Now()).
+		Order("date").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch featured events", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// FeatureEvent pins an event to the homepage or unpins it (admin only).
+// Without until the event stays featured until unpinned.
+func (s *Service) FeatureEvent(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+	if !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var req struct {
+		Featured *bool      `json:"featured" binding:"required"`
+		Until    *time.Time `json:"until" binding:"omitempty,future_date"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	until := req.Until
+	if !*req.Featured {
+		until = nil
+	}
+
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&event).Updates(map[string]interface{}{
+			"featured":       *req.Featured,
+			"featured_until": until,
+		}).Error; err != nil {
+			return err
+		}
+		return outbox.RecordEvent(tx, event.ID, outbox.OpUpsert)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to update event", gin.H{"reason": err.Error()}))
+		return
+	}
+	s.publishChange(c.Request.Context(), event.ID, outbox.OpUpsert)
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":       event.ID,
+		"featured":      *req.Featured,
+		"featuredUntil": until,
+	})
+}
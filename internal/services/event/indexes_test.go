@@ -0,0 +1,111 @@
+package event
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// recordQueries captures the SQL and arguments of every query run on db
+func recordQueries(t *testing.T, db *gorm.DB) *[]recordedQuery {
+	t.Helper()
+	var queries []recordedQuery
+	record := func(tx *gorm.DB) {
+		queries = append(queries, recordedQuery{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:record_query", record); err != nil {
+		t.Fatalf("failed to register query callback: %v", err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:record_row", record); err != nil {
+		t.Fatalf("failed to register row callback: %v", err)
+	}
+	return &queries
+}
+
+type recordedQuery struct {
+	sql  string
+	vars []interface{}
+}
+
+// queryPlan returns sqlite's plan for a recorded query, one step per line
+func queryPlan(t *testing.T, db *gorm.DB, query recordedQuery) string {
+	t.Helper()
+	var steps []struct {
+		Detail string
+	}
+	if err := db.Raw("EXPLAIN QUERY PLAN "+query.sql, query.vars...).Scan(&steps).Error; err != nil {
+		t.Fatalf("failed to explain %s: %v", query.sql, err)
+	}
+	details := make([]string, len(steps))
+	for i, step := range steps {
+		details[i] = step.Detail
+	}
+	return strings.Join(details, "\n")
+}
+
+// TestAvailabilityQueriesUseIndexes seeds 100k tickets and checks that the
+// availability count and the booking summary search the tickets table by
+// index instead of scanning it
+func TestAvailabilityQueriesUseIndexes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("seeds 100k tickets")
+	}
+	env := newTestEnv(t)
+	const eventCount, ticketsPerEvent = 100, 1000
+
+	event := env.seedEvent(t, "Spring Concert", 0)
+	for i := 1; i < eventCount; i++ {
+		env.seedEvent(t, "Concert", 0)
+	}
+	// One in ten seats is still available
+	err := env.db.Exec(`
+		WITH RECURSIVE n(i) AS (SELECT 0 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		INSERT INTO tickets (created_at, updated_at, event_id, seat, price, status)
+		SELECT ?, ?, ? + i / ?, 'S' || (i % ?), 50000, CASE WHEN i % 10 = 0 THEN ? ELSE ? END
+		FROM n`,
+		eventCount*ticketsPerEvent-1, time.Now(), time.Now(), event.ID, ticketsPerEvent, ticketsPerEvent,
+		models.TicketAvailable, models.TicketBooked).Error
+	if err != nil {
+		t.Fatalf("failed to seed tickets: %v", err)
+	}
+	if err := env.db.Exec("ANALYZE").Error; err != nil {
+		t.Fatalf("failed to analyze: %v", err)
+	}
+
+	queries := recordQueries(t, env.db)
+	ctx := context.Background()
+	counts, err := env.service.availableTicketCounts(ctx, []models.Event{*event})
+	if err != nil {
+		t.Fatalf("failed to count available tickets: %v", err)
+	}
+	if counts[event.ID] != ticketsPerEvent/10 {
+		t.Fatalf("counted %d available tickets, want %d", counts[event.ID], ticketsPerEvent/10)
+	}
+	summary, err := env.service.bookingSummary(ctx, event)
+	if err != nil {
+		t.Fatalf("failed to summarise bookings: %v", err)
+	}
+	if summary.TotalTickets != ticketsPerEvent {
+		t.Fatalf("summary counted %d tickets, want %d", summary.TotalTickets, ticketsPerEvent)
+	}
+
+	if len(*queries) != 2 {
+		t.Fatalf("recorded %d queries, want the count and the summary", len(*queries))
+	}
+	for _, query := range *queries {
+		plan := queryPlan(t, env.db, query)
+		if !strings.Contains(plan, "USING COVERING INDEX idx_tickets_event_status") && !strings.Contains(plan, "USING INDEX idx_tickets_event_status") {
+			t.Errorf("query does not use idx_tickets_event_status:\n%s\nplan:\n%s", query.sql, plan)
+		}
+		for _, step := range strings.Split(plan, "\n") {
+			if strings.HasPrefix(step, "SCAN tickets") || strings.HasPrefix(step, "SCAN tk") {
+				t.Errorf("query scans the tickets table:\n%s\nplan:\n%s", query.sql, plan)
+			}
+		}
+	}
+}
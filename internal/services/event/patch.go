@@ -0,0 +1,204 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// patchField is an event field PATCH /event/:id may set
+type patchField struct {
+	column string
+	// decode parses the raw JSON value and returns it with the binding
+	// rules it must pass
+	decode func(raw json.RawMessage) (value interface{}, rules string, err error)
+}
+
+func decodeString(raw json.RawMessage) (interface{}, string, error) {
+	var value string
+	err := json.Unmarshal(raw, &value)
+	return value, "", err
+}
+
+func decodeRequiredString(raw json.RawMessage) (interface{}, string, error) {
+	var value string
+	err := json.Unmarshal(raw, &value)
+	return value, "min=1", err
+}
+
+func decodeCurrency(raw json.RawMessage) (interface{}, string, error) {
+	var value string
+	err := json.Unmarshal(raw, &value)
+	return strings.ToLower(value), "valid_currency", err
+}
+
+func decodeFutureDate(raw json.RawMessage) (interface{}, string, error) {
+	var value time.Time
+	err := json.Unmarshal(raw, &value)
+	return value, "future_date", err
+}
+
+func decodeBool(raw json.RawMessage) (interface{}, string, error) {
+	var value bool
+	err := json.Unmarshal(raw, &value)
+	return value, "", err
+}
+
+func decodeReservationWindow(raw json.RawMessage) (interface{}, string, error) {
+	var value int
+	err := json.Unmarshal(raw, &value)
+	return value, "min=1,max=120", err
+}
+
+func decodeSurgeThreshold(raw json.RawMessage) (interface{}, string, error) {
+	var value float64
+	err := json.Unmarshal(raw, &value)
+	return value, "gt=0,lte=1", err
+}
+
+func decodeSurgeMultiplier(raw json.RawMessage) (interface{}, string, error) {
+	var value float64
+	err := json.Unmarshal(raw, &value)
+	return value, "gte=1", err
+}
+
+func decodeID(raw json.RawMessage) (interface{}, string, error) {
+	var value uint
+	err := json.Unmarshal(raw, &value)
+	return value, "min=1", err
+}
+
+// patchableFields maps the accepted keys to columns. The snake_case keys
+// are the column names; the camelCase ones match the rest of the API.
+var patchableFields = map[string]patchField{
+	"name":         {column: "name", decode: decodeRequiredString},
+	"description":  {column: "description", decode: decodeString},
+	"date":         {column: "date", decode: decodeFutureDate},
+	"currency":     {column: "currency", decode: decodeCurrency},
+	"venue_id":     {column: "venue_id", decode: decodeID},
+	"venueId":      {column: "venue_id", decode: decodeID},
+	"performer_id": {column: "performer_id", decode: decodeID},
+	"performerId":  {column: "performer_id", decode: decodeID},
+
+	"requires_queue": {column: "requires_queue", decode: decodeBool},
+	"requiresQueue":  {column: "requires_queue", decode: decodeBool},
+
+	"reservation_window_minutes": {column: "reservation_window_minutes", decode: decodeReservationWindow},
+	"reservationWindowMinutes":   {column: "reservation_window_minutes", decode: decodeReservationWindow},
+
+	"dynamic_pricing_enabled": {column: "dynamic_pricing_enabled", decode: decodeBool},
+	"dynamicPricingEnabled":   {column: "dynamic_pricing_enabled", decode: decodeBool},
+	"price_surge_threshold":   {column: "price_surge_threshold", decode: decodeSurgeThreshold},
+	"priceSurgeThreshold":     {column: "price_surge_threshold", decode: decodeSurgeThreshold},
+	"price_surge_multiplier":  {column: "price_surge_multiplier", decode: decodeSurgeMultiplier},
+	"priceSurgeMultiplier":    {column: "price_surge_multiplier", decode: decodeSurgeMultiplier},
+}
+
+// PatchEvent updates only the fields present in the body. Unlike PUT, an
+// explicitly empty value such as "description": "" is applied rather than
+// ignored.
+func (s *Service) PatchEvent(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(c.Request.Body).Decode(&body); err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid request data", gin.H{"reason": err.Error()}))
+		return
+	}
+	if len(body) == 0 {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "No fields to update", nil))
+		return
+	}
+
+	// Validate only the keys that are present, reporting failures in the
+	// same shape as validation.ValidateRequest
+	updateData := make(map[string]interface{}, len(body))
+	fields := make(map[string]string)
+	for key, raw := range body {
+		field, ok := patchableFields[key]
+		if !ok {
+			fields[key] = "unknown_field"
+			continue
+		}
+		if _, duplicate := updateData[field.column]; duplicate {
+			fields[key] = "duplicate_field"
+			continue
+		}
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			fields[key] = "required"
+			continue
+		}
+
+		value, rules, err := field.decode(raw)
+		if err != nil {
+			fields[key] = "type"
+			continue
+		}
+		if rules != "" {
+			if err := validation.Validate.Var(value, rules); err != nil {
+				fields[key] = rules
+				continue
+			}
+		}
+		updateData[field.column] = value
+	}
+	if len(fields) > 0 {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Request validation failed", gin.H{"fields": fields}))
+		return
+	}
+
+	if venueID, ok := updateData["venue_id"]; ok {
+		if err := s.db.WithContext(c.Request.Context()).First(&models.Venue{}, venueID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeVenueNotFound, "Venue not found", nil))
+			return
+		}
+	}
+	if performerID, ok := updateData["performer_id"]; ok {
+		if err := s.db.WithContext(c.Request.Context()).First(&models.Performer{}, performerID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodePerformerNotFound, "Performer not found", nil))
+			return
+		}
+	}
+
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&event).Updates(updateData).Error; err != nil {
+			return err
+		}
+		return outbox.RecordEvent(tx, event.ID, outbox.OpUpsert)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to update event", gin.H{"reason": err.Error()}))
+		return
+	}
+	s.publishChange(c.Request.Context(), event.ID, outbox.OpUpsert)
+
+	// Load relationships
+	s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").Preload("Tickets").First(&event, event.ID)
+
+	c.JSON(http.StatusOK, event)
+}
@@ -0,0 +1,111 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/pricing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ComputeCurrentPrice quotes what a ticket of the tier costs right now,
+// surged when dynamic pricing is on and the tier is nearly sold out. With
+// tierID 0 it quotes the event's cheapest available ticket against the
+// availability of the whole event. Returns gorm.ErrRecordNotFound when
+// the event, the tier or any ticket is missing.
+func (s *Service) ComputeCurrentPrice(ctx context.Context, eventID, tierID uint) (money.Amount, error) {
+	var event models.Event
+	if err := s.db.WithContext(ctx).First(&event, eventID).Error; err != nil {
+		return 0, err
+	}
+
+	var base money.Amount
+	if tierID != 0 {
+		var tier models.TicketTier
+		if err := s.db.WithContext(ctx).Select("price").First(&tier, "id = ? AND event_id = ?", tierID, eventID).Error; err != nil {
+			return 0, err
+		}
+		base = tier.Price
+	} else {
+		var ticket models.Ticket
+		err := s.db.WithContext(ctx).Select("price").Where("event_id = ? AND status = ?", eventID, models.TicketAvailable).Order("price ASC").First(&ticket).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Sold out; quote the cheapest seat anyway
+			err = s.db.WithContext(ctx).Select("price").Where("event_id = ?", eventID).Order("price ASC").First(&ticket).Error
+		}
+		if err != nil {
+			return 0, err
+		}
+		base = ticket.Price
+	}
+
+	available, total, err := pricing.Availability(s.db.WithContext(ctx), eventID, tierID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read availability: %w", err)
+	}
+	return pricing.Apply(&event, base, available, total), nil
+}
+
+// GetCurrentPrice serves GET /event/:id/price?tierId=. The quote is not
+// held; the booking service locks the price in at reservation.
+func (s *Service) GetCurrentPrice(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var tierID uint64
+	if value := c.Query("tierId"); value != "" {
+		tierID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || tierID == 0 {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid tier ID", nil))
+			return
+		}
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	price, err := s.ComputeCurrentPrice(c.Request.Context(), event.ID, uint(tierID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeTicketNotFound, "No tickets to price", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute price", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	available, total, err := pricing.Availability(s.db.WithContext(c.Request.Context()), event.ID, uint(tierID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute price", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	response := gin.H{
+		"eventId":  event.ID,
+		"price":    price,
+		"currency": event.Currency,
+		"surging":  pricing.Surging(&event, available, total),
+	}
+	if tierID != 0 {
+		response["tierId"] = tierID
+	}
+	c.JSON(http.StatusOK, response)
+}
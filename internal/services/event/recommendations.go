@@ -0,0 +1,107 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// recommendationLimit is how many events a user is recommended
+	recommendationLimit = 10
+
+	// recommendationCacheTTL is how long a user's recommendations are cached;
+	// a booking made in the meantime may still show up until it expires
+	recommendationCacheTTL = time.Hour
+)
+
+func recommendationsKey(userID uint) string {
+	return fmt.Sprintf("recommendations:%d", userID)
+}
+
+// GetRecommendedEvents lists upcoming events in the genres of the caller's
+// confirmed bookings, soonest first. userId may name the caller; only
+// admins may ask for someone else.
+func (s *Service) GetRecommendedEvents(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	userID := claims.UserID
+	if value := c.Query("userId"); value != "" {
+		requested, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid user ID", nil))
+			return
+		}
+		if uint(requested) != claims.UserID && !claims.IsAdmin() {
+			c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Cannot view another user's recommendations", nil))
+			return
+		}
+		userID = uint(requested)
+	}
+
+	var events []models.Event
+	var err error
+	if s.redisClient == nil {
+		events, err = s.recommendations(c.Request.Context(), userID)
+	} else {
+		err = s.redisClient.Remember(c.Request.Context(), recommendationsKey(userID), recommendationCacheTTL, &events,
+			func(ctx context.Context) (any, error) { return s.recommendations(ctx, userID) })
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch recommendations", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"userId": userID,
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+// recommendations finds upcoming events whose performers share a genre with
+// the user's confirmed bookings, leaving out events the user already holds
+// a ticket for
+func (s *Service) recommendations(ctx context.Context, userID uint) ([]models.Event, error) {
+	var genres []string
+	if err := s.db.WithContext(ctx).Model(&models.Booking{}).
+		Joins("JOIN tickets ON tickets.id = bookings.ticket_id").
+		Joins("JOIN events ON events.id = tickets.event_id").
+		Joins("JOIN performers ON performers.id = events.performer_id").
+		Where("bookings.user_id = ? AND bookings.status = ? AND performers.genre <> ''", userID, models.BookingConfirmed).
+		Distinct().Pluck("performers.genre", &genres).Error; err != nil {
+		return nil, fmt.Errorf("failed to read booked genres: %w", err)
+	}
+
+	events := []models.Event{}
+	if len(genres) == 0 {
+		return events, nil
+	}
+
+	booked := s.db.WithContext(ctx).Model(&models.Booking{}).
+		Select("tickets.event_id").
+		Joins("JOIN tickets ON tickets.id = bookings.ticket_id").
+		Where("bookings.user_id = ? AND bookings.status IN ?", userID, []models.BookingStatus{models.BookingReserved, models.BookingConfirmed})
+
+	if err := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").
+		Joins("JOIN performers ON performers.id = events.performer_id AND performers.deleted_at IS NULL").
+		Where("performers.genre IN ?", genres).
+		Where("events.date > ?", time.Now()).
+		Where("events.id NOT IN (?)", booked).
+		Order("events.date").
+		Limit(recommendationLimit).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to find events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,152 @@
+package event
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// topVenuesLimit is how many venues the performer stats list
+const topVenuesLimit = 5
+
+// VenueStat counts the events a performer played at one venue
+type VenueStat struct {
+	VenueID  uint   `json:"venueId"`
+	Location string `json:"location"`
+	Events   int    `json:"events"`
+}
+
+// PerformerStats summarizes a performer's events and sales. TotalRevenue
+// is what confirmed bookings were charged, less what was refunded of it.
+type PerformerStats struct {
+	PerformerID      uint         `json:"performerId"`
+	TotalEvents      int          `json:"totalEvents"`
+	UpcomingEvents   int          `json:"upcomingEvents"`
+	TotalTicketsSold int          `json:"totalTicketsSold"`
+	TotalRevenue     money.Amount `json:"totalRevenue"`
+	TopVenues        []VenueStat  `json:"topVenues"`
+}
+
+// GetPerformerStats reports event counts, confirmed sales and the most
+// played venues of a performer. Open to admins and to the account linked
+// to the performer.
+func (s *Service) GetPerformerStats(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	performerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid performer ID", nil))
+		return
+	}
+
+	if !s.canViewPerformer(c, claims, uint(performerID)) {
+		return
+	}
+
+	var performer models.Performer
+	if err := s.db.WithContext(c.Request.Context()).First(&performer, uint(performerID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodePerformerNotFound, "Performer not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch performer", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// Each Scan resets its destination, so every query gets its own
+	var events struct {
+		TotalEvents    int
+		UpcomingEvents int
+	}
+	err = s.db.WithContext(c.Request.Context()).Raw(`
+		SELECT COUNT(*) AS total_events,
+		       COUNT(*) FILTER (WHERE date > ?) AS upcoming_events
+		FROM events
+		WHERE performer_id = ? AND deleted_at IS NULL`,
+		time.Now(), performer.ID).Scan(&events).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute performer stats", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var sales struct {
+		TotalTicketsSold int
+		TotalRevenue     money.Amount
+	}
+	err = s.db.WithContext(c.Request.Context()).Raw(`
+		SELECT COUNT(*) AS total_tickets_sold,
+		       (COALESCE(SUM(b.amount_paid), 0) - COALESCE(SUM(rf.refunded), 0))::bigint AS total_revenue
+		FROM bookings b
+		JOIN tickets t ON t.id = b.ticket_id AND t.deleted_at IS NULL
+		JOIN events e ON e.id = t.event_id AND e.deleted_at IS NULL
+		LEFT JOIN (
+			SELECT booking_id, SUM(amount) AS refunded
+			FROM refunds
+			WHERE status = ?
+			GROUP BY booking_id
+		) rf ON rf.booking_id = b.id
+		WHERE e.performer_id = ? AND b.status = ? AND b.deleted_at IS NULL`,
+		models.RefundSucceeded, performer.ID, models.BookingConfirmed).Scan(&sales).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute performer stats", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	topVenues := []VenueStat{}
+	err = s.db.WithContext(c.Request.Context()).Raw(`
+		SELECT v.id AS venue_id, v.location, COUNT(e.id) AS events
+		FROM events e
+		JOIN venues v ON v.id = e.venue_id AND v.deleted_at IS NULL
+		WHERE e.performer_id = ? AND e.deleted_at IS NULL
+		GROUP BY v.id, v.location
+		ORDER BY events DESC, v.id
+		LIMIT ?`,
+		performer.ID, topVenuesLimit).Scan(&topVenues).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute performer stats", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, PerformerStats{
+		PerformerID:      performer.ID,
+		TotalEvents:      events.TotalEvents,
+		UpcomingEvents:   events.UpcomingEvents,
+		TotalTicketsSold: sales.TotalTicketsSold,
+		TotalRevenue:     sales.TotalRevenue,
+		TopVenues:        topVenues,
+	})
+}
+
+// canViewPerformer allows admins and the user linked to the performer,
+// writing a 403 otherwise. The link is read from the database so that
+// changing it takes effect without reissuing tokens.
+func (s *Service) canViewPerformer(c *gin.Context, claims *auth.Claims, performerID uint) bool {
+	if claims.IsAdmin() {
+		return true
+	}
+
+	user, err := s.users.FindByID(c.Request.Context(), claims.UserID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch user", gin.H{"reason": err.Error()}))
+		return false
+	}
+	if user == nil || user.PerformerID == nil || *user.PerformerID != performerID {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Access to this performer's stats is not allowed", nil))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,160 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bookingSummaryCacheTTL is how long a booking summary is cached; the
+// aggregate reads every booking of the event
+const bookingSummaryCacheTTL = 30 * time.Second
+
+func bookingSummaryKey(eventID uint) string {
+	return fmt.Sprintf("booking_summary:%d", eventID)
+}
+
+// BookingSummary is an event's ticket and sales figures for organizer
+// dashboards. Revenue counts confirmed bookings; refunds are reported
+// separately.
+type BookingSummary struct {
+	EventID          uint         `json:"eventId"`
+	Currency         string       `json:"currency"`
+	TotalTickets     int          `json:"totalTickets"`
+	SoldTickets      int          `json:"soldTickets"`
+	AvailableTickets int          `json:"availableTickets"`
+	ReservedTickets  int          `json:"reservedTickets"`
+	CancelledTickets int          `json:"cancelledTickets"`
+	TotalRevenue     money.Amount `json:"totalRevenue"`
+	AveragePrice     money.Amount `json:"averagePrice"`
+	RefundedAmount   money.Amount `json:"refundedAmount"`
+	UniqueBuyers     int          `json:"uniqueBuyers"`
+	ConversionRate   float64      `json:"conversionRate"` // confirmed / (confirmed + cancelled)
+}
+
+// GetBookingSummary reports an event's ticket counts and sales. Open to
+// admins and to the account linked to the event's performer.
+func (s *Service) GetBookingSummary(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).Select("id", "performer_id", "currency").First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if !s.canViewPerformer(c, claims, event.PerformerID) {
+		return
+	}
+
+	var summary *BookingSummary
+	if s.redisClient == nil {
+		summary, err = s.bookingSummary(c.Request.Context(), &event)
+	} else {
+		err = s.redisClient.Remember(c.Request.Context(), bookingSummaryKey(event.ID), bookingSummaryCacheTTL, &summary,
+			func(ctx context.Context) (any, error) { return s.bookingSummary(ctx, &event) })
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to compute booking summary", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// bookingSummary aggregates the event's tickets, bookings and refunds in
+// one query. Bookings confirmed before AmountPaid existed count at the
+// ticket's price; only refunds that went through count as refunded.
+func (s *Service) bookingSummary(ctx context.Context, event *models.Event) (*BookingSummary, error) {
+	var row struct {
+		TotalTickets      int
+		SoldTickets       int
+		AvailableTickets  int
+		ReservedTickets   int
+		ConfirmedBookings int
+		CancelledTickets  int
+		TotalRevenue      money.Amount
+		UniqueBuyers      int
+		RefundedAmount    money.Amount
+	}
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT t.total_tickets, t.sold_tickets, t.available_tickets, t.reserved_tickets,
+		       b.confirmed_bookings, b.cancelled_tickets, b.total_revenue, b.unique_buyers,
+		       r.refunded_amount
+		FROM (
+			SELECT COUNT(*) AS total_tickets,
+			       COUNT(CASE WHEN status = @booked THEN 1 END) AS sold_tickets,
+			       COUNT(CASE WHEN status = @available THEN 1 END) AS available_tickets,
+			       COUNT(CASE WHEN status = @reserved THEN 1 END) AS reserved_tickets
+			FROM tickets
+			WHERE event_id = @event AND deleted_at IS NULL
+		) t, (
+			SELECT COUNT(CASE WHEN b.status = @confirmed THEN 1 END) AS confirmed_bookings,
+			       COUNT(CASE WHEN b.status = @cancelled THEN 1 END) AS cancelled_tickets,
+			       COALESCE(SUM(CASE WHEN b.status = @confirmed THEN COALESCE(NULLIF(b.amount_paid, 0), tk.price) END), 0)::bigint AS total_revenue,
+			       COUNT(DISTINCT CASE WHEN b.status = @confirmed THEN b.user_id END) AS unique_buyers
+			FROM bookings b
+			JOIN tickets tk ON tk.id = b.ticket_id
+			WHERE tk.event_id = @event AND b.deleted_at IS NULL
+		) b, (
+			SELECT COALESCE(SUM(rf.amount), 0)::bigint AS refunded_amount
+			FROM refunds rf
+			JOIN bookings b ON b.id = rf.booking_id
+			JOIN tickets tk ON tk.id = b.ticket_id
+			WHERE tk.event_id = @event AND rf.status = @refunded
+		) r`,
+		map[string]interface{}{
+			"event":     event.ID,
+			"available": models.TicketAvailable,
+			"reserved":  models.TicketReserved,
+			"booked":    models.TicketBooked,
+			"confirmed": models.BookingConfirmed,
+			"cancelled": models.BookingCancelled,
+			"refunded":  models.RefundSucceeded,
+		}).Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate bookings: %w", err)
+	}
+
+	summary := &BookingSummary{
+		EventID:          event.ID,
+		Currency:         event.Currency,
+		TotalTickets:     row.TotalTickets,
+		SoldTickets:      row.SoldTickets,
+		AvailableTickets: row.AvailableTickets,
+		ReservedTickets:  row.ReservedTickets,
+		CancelledTickets: row.CancelledTickets,
+		TotalRevenue:     row.TotalRevenue,
+		RefundedAmount:   row.RefundedAmount,
+		UniqueBuyers:     row.UniqueBuyers,
+	}
+	if row.ConfirmedBookings > 0 {
+		summary.AveragePrice = row.TotalRevenue / money.Amount(row.ConfirmedBookings)
+	}
+	if decided := row.ConfirmedBookings + row.CancelledTickets; decided > 0 {
+		summary.ConversionRate = float64(row.ConfirmedBookings) / float64(decided)
+	}
+	return summary, nil
+}
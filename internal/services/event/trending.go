@@ -0,0 +1,51 @@
+package event
+
+import (
+	"net/http"
+	"sort"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trendingLimit is how many events GET /events/trending returns
+const trendingLimit = 10
+
+// GetTrendingEvents lists the most popular events, most popular first.
+// Popularity is scored in Redis from search hits, reservations and
+// confirmed bookings; events deleted since are skipped.
+func (s *Service) GetTrendingEvents(c *gin.Context) {
+	if s.redisClient == nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Trending events are unavailable", nil))
+		return
+	}
+
+	eventIDs, err := s.redisClient.GetTopEvents(c.Request.Context(), trendingLimit)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Trending events are unavailable", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	events := []models.Event{}
+	if len(eventIDs) > 0 {
+		if err := s.db.WithContext(c.Request.Context()).Preload("Venue").Preload("Performer").
+			Where("events.id IN ?", eventIDs).Find(&events).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch events", gin.H{"reason": err.Error()}))
+			return
+		}
+	}
+
+	// Put the events back in popularity order
+	rank := make(map[uint]int, len(eventIDs))
+	for i, eventID := range eventIDs {
+		rank[eventID] = i
+	}
+	sort.Slice(events, func(i, j int) bool { return rank[events[i].ID] < rank[events[j].ID] })
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
@@ -0,0 +1,213 @@
+package event
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The waitlist records pre-sale interest in an event, in sign-up order. It is
+// unrelated to the Redis booking queue, which only orders users contending
+// for a locked ticket once the event is on sale.
+
+func (s *Service) JoinWaitlist(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var entry models.WaitlistEntry
+	err = s.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// Lock the event row so concurrent sign-ups get distinct positions
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, uint(eventID)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("event_id = ? AND user_id = ?", event.ID, claims.UserID).First(&entry).Error; err == nil {
+			return errAlreadyWaitlisted
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		var lastPosition int
+		if err := tx.Model(&models.WaitlistEntry{}).Where("event_id = ?", event.ID).
+			Select("COALESCE(MAX(position), 0)").Scan(&lastPosition).Error; err != nil {
+			return err
+		}
+
+		entry = models.WaitlistEntry{
+			EventID:  event.ID,
+			UserID:   claims.UserID,
+			Position: lastPosition + 1,
+		}
+		return tx.Create(&entry).Error
+	})
+	if err != nil {
+		switch err {
+		case gorm.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+		case errAlreadyWaitlisted:
+			c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeAlreadyWaitlisted, "Already on the waitlist for this event", nil))
+		default:
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to join waitlist", gin.H{"reason": err.Error()}))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"eventId":  entry.EventID,
+		"position": entry.Position,
+		"message":  "Joined waitlist successfully",
+	})
+}
+
+func (s *Service) GetWaitlistPosition(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var entry models.WaitlistEntry
+	if err := s.db.WithContext(c.Request.Context()).Where("event_id = ? AND user_id = ?", uint(eventID), claims.UserID).First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeNotWaitlisted, "Not on the waitlist for this event", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch waitlist entry", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	// Count users still waiting ahead of this one
+	var ahead int64
+	if entry.NotifiedAt == nil {
+		if err := s.db.WithContext(c.Request.Context()).Model(&models.WaitlistEntry{}).
+			Where("event_id = ? AND position < ? AND notified_at IS NULL", entry.EventID, entry.Position).
+			Count(&ahead).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch waitlist position", gin.H{"reason": err.Error()}))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":    entry.EventID,
+		"position":   entry.Position,
+		"ahead":      ahead,
+		"notified":   entry.NotifiedAt != nil,
+		"notifiedAt": entry.NotifiedAt,
+	})
+}
+
+// ReleaseWaitlist notifies the next count waiting users (admin only). An
+// entry is only marked notified once its notification was sent.
+func (s *Service) ReleaseWaitlist(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+	if !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid event ID", nil))
+		return
+	}
+
+	var req struct {
+		Count int `json:"count" binding:"required,min=1"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	var event models.Event
+	if err := s.db.WithContext(c.Request.Context()).First(&event, uint(eventID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeEventNotFound, "Event not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch event", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var entries []models.WaitlistEntry
+	if err := s.db.WithContext(c.Request.Context()).Where("event_id = ? AND notified_at IS NULL", event.ID).
+		Order("position").Limit(req.Count).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch waitlist", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	subject := fmt.Sprintf("Tickets for %s are available", event.Name)
+	message := fmt.Sprintf("You have been released from the waitlist for %s. Book your tickets now.", event.Name)
+
+	released := make([]models.WaitlistEntry, 0, len(entries))
+	for _, entry := range entries {
+		if err := s.notifier.Notify(c.Request.Context(), entry.UserID, subject, message); err != nil {
+			log.Printf("Failed to notify user %d for event %d waitlist: %v", entry.UserID, event.ID, err)
+			continue
+		}
+
+		now := time.Now()
+		if err := s.db.WithContext(c.Request.Context()).Model(&entry).Update("notified_at", now).Error; err != nil {
+			log.Printf("Failed to mark waitlist entry %d notified: %v", entry.ID, err)
+			continue
+		}
+		released = append(released, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":  event.ID,
+		"released": released,
+		"count":    len(released),
+	})
+}
+
+// authenticate validates the bearer token, writing the error response and
+// returning false if it is missing or invalid
+func (s *Service) authenticate(c *gin.Context) (*auth.Claims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return nil, false
+	}
+
+	tokenString, err := auth.ExtractTokenFromHeader(authHeader)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
+		return nil, false
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return nil, false
+	}
+
+	return claims, true
+}
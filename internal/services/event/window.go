@@ -0,0 +1,60 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Date windows accepted by GET /events?window=
+const (
+	WindowToday       = "today"
+	WindowThisWeek    = "this_week"
+	WindowThisWeekend = "this_weekend"
+	WindowNextWeek    = "next_week"
+	WindowNextMonth   = "next_month"
+)
+
+// windowRange returns the [from, to) range a window covers at now. Weeks
+// start on Monday; this week and this weekend start no earlier than today.
+func windowRange(window string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Days since Monday, with Sunday last
+	weekday := (int(today.Weekday()) + 6) % 7
+	nextMonday := today.AddDate(0, 0, 7-weekday)
+
+	switch window {
+	case WindowToday:
+		return today, today.AddDate(0, 0, 1), nil
+	case WindowThisWeek:
+		return today, nextMonday, nil
+	case WindowThisWeekend:
+		saturday := nextMonday.AddDate(0, 0, -2)
+		if today.After(saturday) {
+			saturday = today
+		}
+		return saturday, nextMonday, nil
+	case WindowNextWeek:
+		return nextMonday, nextMonday.AddDate(0, 0, 7), nil
+	case WindowNextMonth:
+		first := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location())
+		return first, first.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("window must be one of %s, %s, %s, %s or %s",
+		WindowToday, WindowThisWeek, WindowThisWeekend, WindowNextWeek, WindowNextMonth)
+}
+
+// EventWindow is a scope limiting events to a date window relative to the
+// current time. Venues have no time zone yet, so days are counted in the
+// server's. An unknown window adds an error to the query.
+func EventWindow(window string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		from, to, err := windowRange(window, time.Now())
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		return db.Where("events.date >= ? AND events.date < ?", from, to)
+	}
+}
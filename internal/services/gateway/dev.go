@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/session"
+)
+
+// devAdminEmail is the admin account kept in development databases
+const devAdminEmail = "admin@localhost"
+
+// DevAdminTokens makes sure the development admin account exists and
+// starts a session for it, returning its access and refresh tokens. The
+// account's password is random; the tokens are the way in. Only for
+// APP_ENV=development.
+func (s *Service) DevAdminTokens(ctx context.Context) (string, string, error) {
+	if !s.config.IsDevelopment() {
+		return "", "", fmt.Errorf("admin tokens are only issued in development")
+	}
+
+	password, err := hashPassword(session.NewID())
+	if err != nil {
+		return "", "", err
+	}
+
+	user := models.User{Email: devAdminEmail}
+	if err := s.db.WithContext(ctx).
+		Attrs(models.User{Name: "Development Admin", Password: password}).
+		Assign(models.User{Role: models.RoleAdmin}).
+		FirstOrCreate(&user, "email = ?", devAdminEmail).Error; err != nil {
+		return "", "", fmt.Errorf("failed to create admin account: %w", err)
+	}
+
+	return s.startSession(ctx, &user, "127.0.0.1", "dev-admin")
+}
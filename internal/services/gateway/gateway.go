@@ -1,31 +1,51 @@
 package gateway
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/server"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/session"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	config *config.Config
-	db     *gorm.DB
-	client *http.Client
+	config   *config.Config
+	db       *gorm.DB
+	sessions *session.Store
+	client   *http.Client
+	dbHealth *database.Readiness
 }
 
-func NewService(cfg *config.Config, db *gorm.DB) *Service {
+func NewService(cfg *config.Config, db *gorm.DB, sessions *session.Store) *Service {
 	return &Service{
-		config: cfg,
-		db:     db,
+		config:   cfg,
+		db:       db,
+		sessions: sessions,
+		dbHealth: database.NewReadiness("api-gateway", database.HealthCheckInterval),
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: server.Transport(cfg),
 		},
 	}
 }
@@ -34,12 +54,51 @@ func (s *Service) SetupRoutes(r *gin.Engine) {
 	// Authentication routes
 	r.POST("/auth/register", s.Register)
 	r.POST("/auth/login", s.Login)
+	r.POST("/auth/mfa/challenge", s.MFAChallenge)
+	r.POST("/auth/logout", s.AuthMiddleware(), s.Logout)
+	r.POST("/auth/token/refresh", s.RefreshToken)
+
+	// MFA enrollment (requires authentication)
+	mfa := r.Group("/auth/mfa")
+	mfa.Use(s.AuthMiddleware())
+	{
+		mfa.POST("/enroll", s.EnrollMFA)
+		mfa.POST("/verify", s.VerifyMFA)
+	}
 
 	// Search routes (forwarded to search service)
 	r.GET("/search", s.ForwardToSearchService)
+	r.GET("/search/explain", s.AuthMiddleware(), s.ForwardToSearchService)
+	r.GET("/search/global", s.ForwardToSearchService)
+	r.GET("/search/venues", s.ForwardToSearchService)
+	r.GET("/search/performers", s.ForwardToSearchService)
+	r.GET("/search/history", s.AuthMiddleware(), s.ForwardToSearchService)
+	r.DELETE("/search/history", s.AuthMiddleware(), s.ForwardToSearchService)
 
 	// Event routes (forwarded to event service)
 	r.GET("/event/:id", s.ForwardToEventService)
+	r.GET("/event/:id/tiers", s.ForwardToEventService)
+	r.GET("/event/:id/price", s.ForwardToEventService)
+	r.GET("/venues/search", s.ForwardToEventService)
+	r.GET("/performers/search", s.ForwardToEventService)
+	r.GET("/performers/:id/events", s.ForwardToEventService)
+	r.GET("/performers/:id/stats", s.AuthMiddleware(), s.ForwardToEventService)
+	r.GET("/events", s.ForwardToEventService)
+	r.GET("/events/featured", s.ForwardToEventService)
+	r.GET("/events/recommended", s.AuthMiddleware(), s.ForwardToEventService)
+	r.GET("/events/trending", s.ForwardToEventService)
+	r.GET("/events/:id/capacity", s.ForwardToEventService)
+	r.GET("/events/:id/booking-summary", s.AuthMiddleware(), s.ForwardToEventService)
+	r.POST("/events/:id/tickets/availability", s.ForwardToEventService)
+
+	// Waitlist routes (require authentication, forwarded to event service)
+	waitlist := r.Group("/events/:id/waitlist")
+	waitlist.Use(s.AuthMiddleware())
+	{
+		waitlist.POST("", s.ForwardToEventService)
+		waitlist.GET("/position", s.ForwardToEventService)
+		waitlist.POST("/release", s.ForwardToEventService)
+	}
 
 	// Booking routes (require authentication)
 	booking := r.Group("/booking")
@@ -49,8 +108,43 @@ func (s *Service) SetupRoutes(r *gin.Engine) {
 		booking.PUT("/confirm", s.ForwardToBookingService)
 		booking.DELETE("/cancel/:id", s.ForwardToBookingService)
 		booking.GET("/user/:userId", s.ForwardToBookingService)
+		booking.GET("/:id/refund", s.ForwardToBookingService)
+		booking.GET("/:id/payment", s.ForwardToBookingService)
+		booking.GET("/:id/receipt", s.ForwardToBookingService)
+		booking.GET("/:id/payment-status", s.ForwardToBookingService)
+		booking.POST("/:id/retry-payment", s.ForwardToBookingService)
 	}
 
+	// User management (admin only)
+	admin := r.Group("/admin/users")
+	admin.Use(s.AuthMiddleware(), s.AdminMiddleware())
+	{
+		admin.GET("", s.ListUsers)
+		admin.PUT("/:id/ban", s.BanUser)
+		admin.PUT("/:id/unban", s.UnbanUser)
+		admin.PUT("/:id/role", s.SetUserRole)
+	}
+
+	// Event curation (admin only, forwarded to event service)
+	r.POST("/event", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+	r.PUT("/admin/events/:id/feature", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+	r.POST("/admin/events/:id/tickets", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+	r.GET("/admin/events/:id/queue", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+	r.POST("/admin/venues", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+	r.POST("/admin/performers", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToEventService)
+
+	// Search index sync (admin only, forwarded to the CDC service)
+	r.POST("/cdc/sync-event/:id", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToCDCService)
+	r.POST("/cdc/sync-all", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToCDCService)
+
+	// Lock contention report (admin only, forwarded to booking service)
+	r.GET("/admin/locks/contended", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToBookingService)
+	r.GET("/admin/payments/unreconciled", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToBookingService)
+	r.GET("/admin/bookings/by-event/:eventId", s.AuthMiddleware(), s.AdminMiddleware(), s.ForwardToBookingService)
+
+	// Payment provider webhooks; the booking service verifies the signature
+	r.POST("/webhooks/stripe", s.ForwardToBookingService)
+
 	// Health check
 	r.GET("/health", s.HealthCheck)
 }
@@ -62,51 +156,46 @@ func (s *Service) Register(c *gin.Context) {
 		Name     string `json:"name" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
 	// Check if user already exists
 	var existingUser models.User
-	if err := s.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": "User already exists",
-		})
+	if err := s.db.WithContext(c.Request.Context()).Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeUserExists, "User already exists", nil))
 		return
 	}
 
-	// Hash password (simplified - in production use bcrypt)
-	hashedPassword := hashPassword(req.Password)
+	hashedPassword, err := hashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create user", nil))
+		return
+	}
 
 	// Create user
 	user := models.User{
 		Email:    req.Email,
 		Password: hashedPassword,
 		Name:     req.Name,
+		Role:     models.RoleUser,
 	}
 
-	if err := s.db.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create user",
-		})
+	if err := s.db.WithContext(c.Request.Context()).Create(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create user", nil))
 		return
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(s.config, user.ID, user.Email)
+	// Start a session and issue its access and refresh tokens
+	token, refreshToken, err := s.issueToken(c, &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate token",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate token", nil))
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"token": token,
+		"token":        token,
+		"refreshToken": refreshToken,
 		"user": gin.H{
 			"id":    user.ID,
 			"email": user.Email,
@@ -121,42 +210,52 @@ func (s *Service) Login(c *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+	if !validation.ValidateRequest(c, &req) {
 		return
 	}
 
 	// Find user
 	var user models.User
-	if err := s.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid credentials",
-		})
+	if err := s.db.WithContext(c.Request.Context()).Where("email = ?", req.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidCredentials, "Invalid credentials", nil))
+		return
+	}
+
+	if !s.verifyPassword(c.Request.Context(), &user, req.Password) {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidCredentials, "Invalid credentials", nil))
+		return
+	}
+
+	if user.BannedAt != nil {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeAccountSuspended, "Account suspended", nil))
 		return
 	}
 
-	// Verify password (simplified - in production use bcrypt)
-	if !verifyPassword(req.Password, user.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "Invalid credentials",
+	// Users with MFA must exchange a challenge token and TOTP code for the JWT
+	if user.TOTPEnabled {
+		challengeToken, err := auth.GenerateMFAChallengeToken(s.config, user.ID, user.Email)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate token", nil))
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"requires_mfa":    true,
+			"challenge_token": challengeToken,
 		})
 		return
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(s.config, user.ID, user.Email)
+	// Start a session and issue its access and refresh tokens
+	token, refreshToken, err := s.issueToken(c, &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate token",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate token", nil))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
+		"token":        token,
+		"refreshToken": refreshToken,
 		"user": gin.H{
 			"id":    user.ID,
 			"email": user.Email,
@@ -165,6 +264,278 @@ func (s *Service) Login(c *gin.Context) {
 	})
 }
 
+func (s *Service) EnrollMFA(c *gin.Context) {
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, c.GetUint("userID")).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeMFAAlreadyEnabled, "MFA is already enabled", nil))
+		return
+	}
+
+	enrollment, err := auth.GenerateTOTP(user.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate MFA secret", nil))
+		return
+	}
+
+	encryptedSecret, err := auth.EncryptTOTPSecret(s.config, enrollment.Secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to store MFA secret", nil))
+		return
+	}
+
+	// Enrollment only takes effect once confirmed via /auth/mfa/verify
+	if err := s.db.WithContext(c.Request.Context()).Model(&user).Update("totp_secret", encryptedSecret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to store MFA secret", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"provisioning_uri": enrollment.ProvisioningURI,
+		"qr_code":          enrollment.QRCodeDataURL,
+	})
+}
+
+func (s *Service) VerifyMFA(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, c.GetUint("userID")).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return
+	}
+
+	if user.TOTPEnabled {
+		c.JSON(http.StatusConflict, apperrors.NewResponse(apperrors.ErrCodeMFAAlreadyEnabled, "MFA is already enabled", nil))
+		return
+	}
+
+	if !s.validateTOTPCode(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidMFACode, "Invalid MFA code", nil))
+		return
+	}
+
+	if err := s.db.WithContext(c.Request.Context()).Model(&user).Update("totp_enabled", true).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to enable MFA", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "MFA enabled successfully",
+	})
+}
+
+func (s *Service) MFAChallenge(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	claims, err := auth.ValidateMFAChallengeToken(s.config, req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid challenge token", nil))
+		return
+	}
+
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, claims.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid challenge token", nil))
+		return
+	}
+
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeMFANotEnrolled, "MFA is not enabled", nil))
+		return
+	}
+
+	if user.BannedAt != nil {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeAccountSuspended, "Account suspended", nil))
+		return
+	}
+
+	if !s.validateTOTPCode(&user, req.Code) {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidMFACode, "Invalid MFA code", nil))
+		return
+	}
+
+	// Start a session and issue its access and refresh tokens
+	token, refreshToken, err := s.issueToken(c, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate token", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":        token,
+		"refreshToken": refreshToken,
+		"user": gin.H{
+			"id":    user.ID,
+			"email": user.Email,
+			"name":  user.Name,
+		},
+	})
+}
+
+// Logout revokes the session of the presented token and its refresh tokens
+func (s *Service) Logout(c *gin.Context) {
+	sessionID := c.GetString("sessionID")
+	if err := s.sessions.Delete(c.Request.Context(), sessionID); err != nil {
+		log.Printf("Failed to delete session: %v", err)
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to log out", nil))
+		return
+	}
+
+	// The deleted session already rejects them; this keeps the table accurate
+	if err := s.db.WithContext(c.Request.Context()).Model(&models.UserRefreshToken{}).Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		log.Printf("Failed to revoke refresh tokens of session %s: %v", sessionID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// RefreshToken exchanges a refresh token for a new access token of the
+// same session. The refresh token is not rotated and stays valid until it
+// expires, the user logs out, or it is revoked.
+func (s *Service) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	claims, err := auth.ValidateRefreshToken(s.config, req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid refresh token", nil))
+		return
+	}
+
+	var record models.UserRefreshToken
+	if err := s.db.WithContext(c.Request.Context()).Where("token_hash = ?", auth.HashRefreshToken(req.RefreshToken)).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid refresh token", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to verify refresh token", nil))
+		return
+	}
+	if record.RevokedAt != nil || time.Now().After(record.ExpiresAt) || record.UserID != claims.UserID {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Refresh token expired or revoked", nil))
+		return
+	}
+
+	// Logging out deletes the session, which ends its refresh tokens too
+	if _, err := s.sessions.Get(c.Request.Context(), claims.SessionID); err != nil {
+		if errors.Is(err, session.ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Session expired or revoked", nil))
+			return
+		}
+		log.Printf("Failed to look up session: %v", err)
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to verify session", nil))
+		return
+	}
+
+	// Reissue with the current role; a ban ends the session's refreshes
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, claims.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid refresh token", nil))
+		return
+	}
+	if user.BannedAt != nil {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeAccountSuspended, "Account suspended", nil))
+		return
+	}
+
+	token, err := auth.GenerateToken(s.config, user.ID, user.Email, user.Role, claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to generate token", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":     token,
+		"expiresIn": int(s.config.JWTExpiry.Seconds()),
+	})
+}
+
+// issueToken starts a session for the user and returns an access token and
+// a refresh token bound to it. The session lives as long as the refresh
+// token.
+func (s *Service) issueToken(c *gin.Context, user *models.User) (string, string, error) {
+	return s.startSession(c.Request.Context(), user, c.ClientIP(), c.Request.UserAgent())
+}
+
+// startSession records a session for the user and issues its tokens
+func (s *Service) startSession(ctx context.Context, user *models.User, ipAddress, userAgent string) (string, string, error) {
+	sessionID := session.NewID()
+	now := time.Now()
+	data := session.SessionData{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: now,
+		LastSeen:  now,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+	if err := s.sessions.Set(ctx, sessionID, data, s.config.JWTRefreshExpiry); err != nil {
+		return "", "", err
+	}
+
+	token, err := auth.GenerateToken(s.config, user.ID, user.Email, user.Role, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, expiresAt, err := auth.GenerateRefreshToken(s.config, user.ID, user.Email, user.Role, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+	record := models.UserRefreshToken{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: auth.HashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return "", "", fmt.Errorf("failed to record refresh token: %w", err)
+	}
+
+	return token, refreshToken, nil
+}
+
+// validateTOTPCode checks a code against the user's stored (encrypted) secret
+func (s *Service) validateTOTPCode(user *models.User, code string) bool {
+	if user.TOTPSecret == "" {
+		return false
+	}
+
+	secret, err := auth.DecryptTOTPSecret(s.config, user.TOTPSecret)
+	if err != nil {
+		return false
+	}
+
+	return auth.ValidateTOTP(code, secret)
+}
+
 func (s *Service) ForwardToSearchService(c *gin.Context) {
 	s.forwardRequest(c, fmt.Sprintf("http://localhost:%s", s.config.SearchServicePort))
 }
@@ -177,33 +548,62 @@ func (s *Service) ForwardToBookingService(c *gin.Context) {
 	s.forwardRequest(c, fmt.Sprintf("http://localhost:%s", s.config.BookingServicePort))
 }
 
+// ForwardToCDCService forwards an admin's request with the internal token
+// the CDC service's write endpoints require
+func (s *Service) ForwardToCDCService(c *gin.Context) {
+	auth.SetInternalToken(c.Request, s.config)
+	s.forwardRequest(c, fmt.Sprintf("http://localhost:%s", s.config.CDCServicePort))
+}
+
 func (s *Service) forwardRequest(c *gin.Context, targetURL string) {
-	// Create new request
-	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, targetURL+c.Request.URL.Path, c.Request.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to create request",
-		})
-		return
+	// Buffer the body so a retry can send it again
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(c.Request.Body); err != nil {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Failed to read request body", nil))
+			return
+		}
 	}
 
-	// Copy headers
-	for key, values := range c.Request.Header {
-		for _, value := range values {
-			req.Header.Add(key, value)
+	// Make request, retrying transient failures
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, targetURL+c.Request.URL.Path, bytes.NewReader(body))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to create request", nil))
+			return
 		}
-	}
 
-	// Add query parameters
-	req.URL.RawQuery = c.Request.URL.RawQuery
+		// Copy headers
+		for key, values := range c.Request.Header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		req.Header.Set("X-Retry-Count", strconv.Itoa(attempt))
+
+		// Add query parameters
+		req.URL.RawQuery = c.Request.URL.RawQuery
+
+		resp, err = s.client.Do(req)
+		if attempt >= s.config.GatewayRetryAttempts || !retryable(req, resp, err) {
+			if err != nil {
+				c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Service unavailable", nil))
+				return
+			}
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
 
-	// Make request
-	resp, err := s.client.Do(req)
-	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error": "Service unavailable",
-		})
-		return
+		select {
+		case <-time.After(s.config.GatewayRetryDelay << attempt):
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusBadGateway, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Service unavailable", nil))
+			return
+		}
 	}
 	defer resp.Body.Close()
 
@@ -215,42 +615,90 @@ func (s *Service) forwardRequest(c *gin.Context, targetURL string) {
 	}
 
 	// Copy response body
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to read response",
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to read response", nil))
 		return
 	}
 
-	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), respBody)
+}
+
+// retryable reports whether a forwarded request may be sent again. A
+// connection that could not be opened never reached the service, so any
+// method is retried; a 5xx or a dropped connection only for GET and HEAD,
+// since the service may already have acted on anything else (a DELETE
+// that cancels a booking refunds it).
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return safeMethod(req.Method)
+	}
+	return resp.StatusCode >= 500 && safeMethod(req.Method)
+}
+
+// safeMethod reports whether sending a request again cannot change anything
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
 }
 
 func (s *Service) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header required",
-			})
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
 			c.Abort()
 			return
 		}
 
 		tokenString, err := auth.ExtractTokenFromHeader(authHeader)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header",
-			})
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Invalid authorization header", nil))
 			c.Abort()
 			return
 		}
 
 		claims, err := auth.ValidateToken(s.config, tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
-			})
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+			c.Abort()
+			return
+		}
+
+		// The session must still exist; logging out deletes it
+		if claims.SessionID == "" {
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+			c.Abort()
+			return
+		}
+		sess, err := s.sessions.Get(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			if errors.Is(err, session.ErrNotFound) {
+				c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Session expired or revoked", nil))
+			} else {
+				log.Printf("Failed to look up session: %v", err)
+				c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to verify session", nil))
+			}
+			c.Abort()
+			return
+		}
+		s.touchSession(c, claims, sess)
+
+		// Tokens stay valid until they expire, so check for a ban on every request
+		var user models.User
+		if err := s.db.WithContext(c.Request.Context()).Select("id", "banned_at").First(&user, claims.UserID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+			c.Abort()
+			return
+		}
+		if user.BannedAt != nil {
+			c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeAccountSuspended, "Account suspended", nil))
 			c.Abort()
 			return
 		}
@@ -258,27 +706,189 @@ func (s *Service) AuthMiddleware() gin.HandlerFunc {
 		// Add user info to context
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("userRole", claims.Role)
+		c.Set("sessionID", claims.SessionID)
 		c.Next()
 	}
 }
 
+// sessionTouchInterval limits how often a session's LastSeen is rewritten
+const sessionTouchInterval = time.Minute
+
+// touchSession records the session's latest activity, keeping its expiry
+// aligned with the refresh token issued with it
+func (s *Service) touchSession(c *gin.Context, claims *auth.Claims, sess *session.SessionData) {
+	if time.Since(sess.LastSeen) < sessionTouchInterval {
+		return
+	}
+	ttl := time.Until(sess.CreatedAt.Add(s.config.JWTRefreshExpiry))
+	if ttl <= 0 {
+		return
+	}
+
+	sess.LastSeen = time.Now()
+	sess.IPAddress = c.ClientIP()
+	sess.UserAgent = c.Request.UserAgent()
+	if err := s.sessions.Set(c.Request.Context(), claims.SessionID, *sess, ttl); err != nil {
+		log.Printf("Failed to update session: %v", err)
+	}
+}
+
+// AdminMiddleware rejects non-admin users; it must run after AuthMiddleware
+func (s *Service) AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("userRole") != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// healthCheckTimeout bounds each downstream probe of the aggregated health check
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck reports the gateway and its downstream services. The overall
+// status is degraded when a service is unreachable or search indexing lags
+// behind by more than the configured threshold.
 func (s *Service) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	upstreams := map[string]string{
+		"search-service":  s.config.SearchServicePort,
+		"event-service":   s.config.EventServicePort,
+		"booking-service": s.config.BookingServicePort,
+		"cdc-service":     s.config.CDCServicePort,
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		services = make(map[string]string)
+		cdc      struct {
+			PendingChanges      int64   `json:"pendingChanges"`
+			LagSeconds          float64 `json:"lagSeconds"`
+			ConsecutiveFailures int     `json:"consecutiveFailures"`
+		}
+		cdcErr error
+	)
+
+	for name, port := range upstreams {
+		wg.Add(1)
+		go func(name, port string) {
+			defer wg.Done()
+			status := "healthy"
+			if err := s.getJSON(ctx, fmt.Sprintf("http://localhost:%s/health", port), nil); err != nil {
+				status = "unreachable"
+			}
+			mu.Lock()
+			services[name] = status
+			mu.Unlock()
+		}(name, port)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cdcErr = s.getJSON(ctx, fmt.Sprintf("http://localhost:%s/cdc/status", s.config.CDCServicePort), &cdc)
+	}()
+	wg.Wait()
+
+	status := "healthy"
+	for _, serviceStatus := range services {
+		if serviceStatus != "healthy" {
+			status = "degraded"
+		}
+	}
+
+	response := gin.H{
+		"status":    status,
 		"service":   "api-gateway",
 		"timestamp": time.Now(),
-	})
+		"services":  services,
+	}
+
+	if cdcErr == nil {
+		lagging := time.Duration(cdc.LagSeconds*float64(time.Second)) > s.config.CDCLagDegradedThreshold
+		if lagging {
+			response["status"] = "degraded"
+		}
+		response["searchIndex"] = gin.H{
+			"pendingChanges":      cdc.PendingChanges,
+			"lagSeconds":          cdc.LagSeconds,
+			"consecutiveFailures": cdc.ConsecutiveFailures,
+			"lagging":             lagging,
+		}
+	}
+
+	// The gateway cannot authenticate anyone without its database
+	httpStatus := http.StatusOK
+	if err := s.dbHealth.Err(); err != nil {
+		response["status"] = "NOT_READY"
+		response["database"] = err.Error()
+		httpStatus = http.StatusServiceUnavailable
+	}
+
+	c.JSON(httpStatus, response)
 }
 
-// Simple password hashing (in production, use bcrypt)
-func hashPassword(password string) string {
-	// This is a simplified implementation
-	// In production, use: bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return fmt.Sprintf("hashed_%s", password)
+// WatchDatabase pings the database until ctx is done; the health check
+// reports NOT_READY while pings fail
+func (s *Service) WatchDatabase(ctx context.Context) {
+	s.dbHealth.Watch(ctx, s.db)
 }
 
-func verifyPassword(password, hashedPassword string) bool {
-	// This is a simplified implementation
-	// In production, use: bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return hashedPassword == fmt.Sprintf("hashed_%s", password)
+// getJSON fetches url and decodes a successful JSON response into out (when
+// non-nil)
+func (s *Service) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// legacyPasswordPrefix marks passwords stored before bcrypt was used. They
+// are only accepted outside production and are rehashed on login.
+const legacyPasswordPrefix = "hashed_"
+
+func hashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hashed), nil
+}
+
+// verifyPassword checks password against the user's stored hash, upgrading
+// a legacy hash to bcrypt when it matches
+func (s *Service) verifyPassword(ctx context.Context, user *models.User, password string) bool {
+	if !strings.HasPrefix(user.Password, legacyPasswordPrefix) {
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+	}
+
+	if s.config.IsProduction() || user.Password != legacyPasswordPrefix+password {
+		return false
+	}
+	if hashed, err := hashPassword(password); err == nil {
+		if err := s.db.WithContext(ctx).Model(user).Update("password", hashed).Error; err != nil {
+			log.Printf("Failed to rehash password of user %d: %v", user.ID, err)
+		}
+	}
+	return true
 }
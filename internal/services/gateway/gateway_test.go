@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/session"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// testGateway is the gateway's routes on sqlite and in-memory sessions
+type testGateway struct {
+	service  *Service
+	router   *gin.Engine
+	db       *gorm.DB
+	sessions *session.Store
+	config   *config.Config
+}
+
+func newTestGateway(t *testing.T) *testGateway {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		JWTSecret:        "test-secret",
+		JWTExpiry:        time.Hour,
+		JWTRefreshExpiry: 24 * time.Hour,
+	}
+	store := inmem.New()
+	t.Cleanup(func() { store.Close() })
+	sessions := session.NewStore(store)
+	db := testutil.NewDB(t)
+
+	s := NewService(cfg, db, sessions)
+	router := gin.New()
+	s.SetupRoutes(router)
+	return &testGateway{service: s, router: router, db: db, sessions: sessions, config: cfg}
+}
+
+// seedUser creates a user with a live session and returns it with an
+// access token for that session
+func (g *testGateway) seedUser(t *testing.T, email, role string) (*models.User, string) {
+	t.Helper()
+	user := models.User{Email: email, Password: "hash", Name: email, Role: role}
+	if err := g.db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	sessionID := session.NewID()
+	now := time.Now()
+	data := session.SessionData{UserID: user.ID, Email: user.Email, Role: role, CreatedAt: now, LastSeen: now}
+	if err := g.sessions.Set(context.Background(), sessionID, data, g.config.JWTRefreshExpiry); err != nil {
+		t.Fatalf("failed to store session: %v", err)
+	}
+	token, err := auth.GenerateToken(g.config, user.ID, user.Email, role, sessionID)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	return &user, token
+}
+
+// do sends a request through the gateway's routes
+func (g *testGateway) do(t *testing.T, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatalf("failed to marshal request: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	g.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, dest interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), dest); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body, err)
+	}
+}
+
+// expectStatus fails the test unless the response has the wanted status
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rec.Code != want {
+		t.Fatalf("got status %d, want %d: %s", rec.Code, want, rec.Body)
+	}
+}
+
+func TestRegisterReusesDeletedAccountEmail(t *testing.T) {
+	g := newTestGateway(t)
+	register := gin.H{"email": "alice@example.com", "password": "s3cret-pass", "name": "Alice"}
+
+	expectStatus(t, g.do(t, http.MethodPost, "/auth/register", "", register), http.StatusCreated)
+	expectStatus(t, g.do(t, http.MethodPost, "/auth/register", "", register), http.StatusConflict)
+
+	var first models.User
+	if err := g.db.First(&first, "email = ?", "alice@example.com").Error; err != nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if err := g.db.Delete(&first).Error; err != nil {
+		t.Fatalf("failed to delete user: %v", err)
+	}
+
+	rec := g.do(t, http.MethodPost, "/auth/register", "", register)
+	expectStatus(t, rec, http.StatusCreated)
+	var resp struct {
+		User struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	decode(t, rec, &resp)
+	if resp.User.ID == first.ID {
+		t.Fatal("re-registration revived the deleted account")
+	}
+
+	var accounts int64
+	g.db.Unscoped().Model(&models.User{}).Where("email = ?", "alice@example.com").Count(&accounts)
+	if accounts != 2 {
+		t.Errorf("found %d accounts for the email, want the deleted one and the new one", accounts)
+	}
+
+	// The new account is live, so the email is taken again
+	expectStatus(t, g.do(t, http.MethodPost, "/auth/register", "", register), http.StatusConflict)
+	if err := g.db.Create(&models.User{Email: "alice@example.com", Password: "hash", Name: "Dup", Role: models.RoleUser}).Error; err == nil {
+		t.Error("database accepted a second live account for the email")
+	}
+}
@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// ListUsers pages through user accounts, optionally filtered by an email
+// substring. With includeDeleted=true deleted accounts are listed too, with
+// DeletedAt set, for audits.
+func (s *Service) ListUsers(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "page must be a positive integer", nil))
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultUserPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxUserPageSize {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "page_size must be between 1 and 100", nil))
+		return
+	}
+
+	query := s.db.WithContext(c.Request.Context()).Model(&models.User{})
+	if c.Query("includeDeleted") == "true" {
+		query = query.Unscoped()
+	}
+	if search := c.Query("search"); search != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(search)
+		query = query.Where("email ILIKE ? ESCAPE '\\'", "%"+escaped+"%")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to count users", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var users []models.User
+	if err := query.Order("id").Offset((page - 1) * pageSize).Limit(pageSize).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch users", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":    users,
+		"count":    len(users),
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// BanUser suspends an account; its tokens are rejected from then on
+func (s *Service) BanUser(c *gin.Context) {
+	now := time.Now()
+	s.setBannedAt(c, &now)
+}
+
+// UnbanUser lifts a suspension
+func (s *Service) UnbanUser(c *gin.Context) {
+	s.setBannedAt(c, nil)
+}
+
+func (s *Service) setBannedAt(c *gin.Context, bannedAt *time.Time) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid user ID", nil))
+		return
+	}
+
+	if bannedAt != nil && uint(userID) == c.GetUint("userID") {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Admins cannot ban themselves", nil))
+		return
+	}
+
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, uint(userID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeUserNotFound, "User not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch user", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if err := s.db.WithContext(c.Request.Context()).Model(&user).Update("banned_at", bannedAt).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to update user", gin.H{"reason": err.Error()}))
+		return
+	}
+	user.BannedAt = bannedAt
+
+	c.JSON(http.StatusOK, user)
+}
+
+// SetUserRole grants or revokes the admin role. Tokens already issued keep
+// the role they were issued with until they expire.
+func (s *Service) SetUserRole(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid user ID", nil))
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=user admin"`
+	}
+	if !validation.ValidateRequest(c, &req) {
+		return
+	}
+
+	if uint(userID) == c.GetUint("userID") {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Admins cannot change their own role", nil))
+		return
+	}
+
+	var user models.User
+	if err := s.db.WithContext(c.Request.Context()).First(&user, uint(userID)).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, apperrors.NewResponse(apperrors.ErrCodeUserNotFound, "User not found", nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to fetch user", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if err := s.db.WithContext(c.Request.Context()).Model(&user).Update("role", req.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to update user", gin.H{"reason": err.Error()}))
+		return
+	}
+	user.Role = req.Role
+
+	c.JSON(http.StatusOK, user)
+}
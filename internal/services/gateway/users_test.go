@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+)
+
+func TestListUsers(t *testing.T) {
+	g := newTestGateway(t)
+	_, adminToken := g.seedUser(t, "admin@example.com", models.RoleAdmin)
+	for i := 1; i <= 3; i++ {
+		g.seedUser(t, fmt.Sprintf("user%d@example.com", i), models.RoleUser)
+	}
+	g.seedUser(t, "user_x@example.com", models.RoleUser)
+
+	var page struct {
+		Users []models.User `json:"users"`
+		Total int64         `json:"total"`
+		Page  int           `json:"page"`
+	}
+	rec := g.do(t, http.MethodGet, "/admin/users?page=2&page_size=2", adminToken, nil)
+	expectStatus(t, rec, http.StatusOK)
+	decode(t, rec, &page)
+	if page.Total != 5 || page.Page != 2 || len(page.Users) != 2 {
+		t.Fatalf("got page %d with %d of %d users, want page 2 with 2 of 5", page.Page, len(page.Users), page.Total)
+	}
+	if page.Users[0].Email != "user2@example.com" {
+		t.Errorf("page 2 starts with %s, want user2@example.com", page.Users[0].Email)
+	}
+
+	// The search is a case-insensitive substring, and _ is not a wildcard
+	rec = g.do(t, http.MethodGet, "/admin/users?search=USER_", adminToken, nil)
+	expectStatus(t, rec, http.StatusOK)
+	decode(t, rec, &page)
+	if page.Total != 1 || page.Users[0].Email != "user_x@example.com" {
+		t.Errorf("search for USER_ matched %d users, want only user_x@example.com", page.Total)
+	}
+
+	rec = g.do(t, http.MethodGet, "/admin/users?page_size=500", adminToken, nil)
+	expectStatus(t, rec, http.StatusBadRequest)
+}
+
+func TestListUsersRequiresAdmin(t *testing.T) {
+	g := newTestGateway(t)
+	_, userToken := g.seedUser(t, "user@example.com", models.RoleUser)
+
+	expectStatus(t, g.do(t, http.MethodGet, "/admin/users", "", nil), http.StatusUnauthorized)
+	expectStatus(t, g.do(t, http.MethodGet, "/admin/users", userToken, nil), http.StatusForbidden)
+}
+
+func TestBanAndUnbanUser(t *testing.T) {
+	g := newTestGateway(t)
+	_, adminToken := g.seedUser(t, "admin@example.com", models.RoleAdmin)
+	other, otherToken := g.seedUser(t, "other-admin@example.com", models.RoleAdmin)
+	expectStatus(t, g.do(t, http.MethodGet, "/admin/users", otherToken, nil), http.StatusOK)
+
+	var banned models.User
+	rec := g.do(t, http.MethodPut, fmt.Sprintf("/admin/users/%d/ban", other.ID), adminToken, nil)
+	expectStatus(t, rec, http.StatusOK)
+	decode(t, rec, &banned)
+	if banned.BannedAt == nil {
+		t.Fatal("ban response has no BannedAt")
+	}
+
+	// The banned user's unexpired token stops working at once
+	expectStatus(t, g.do(t, http.MethodGet, "/admin/users", otherToken, nil), http.StatusForbidden)
+
+	var unbanned models.User
+	rec = g.do(t, http.MethodPut, fmt.Sprintf("/admin/users/%d/unban", other.ID), adminToken, nil)
+	expectStatus(t, rec, http.StatusOK)
+	decode(t, rec, &unbanned)
+	if unbanned.BannedAt != nil {
+		t.Errorf("unban response still has BannedAt %v", unbanned.BannedAt)
+	}
+	expectStatus(t, g.do(t, http.MethodGet, "/admin/users", otherToken, nil), http.StatusOK)
+}
+
+func TestBanUserErrors(t *testing.T) {
+	g := newTestGateway(t)
+	admin, adminToken := g.seedUser(t, "admin@example.com", models.RoleAdmin)
+	_, userToken := g.seedUser(t, "user@example.com", models.RoleUser)
+
+	expectStatus(t, g.do(t, http.MethodPut, fmt.Sprintf("/admin/users/%d/ban", admin.ID), adminToken, nil), http.StatusBadRequest)
+	expectStatus(t, g.do(t, http.MethodPut, "/admin/users/9999/ban", adminToken, nil), http.StatusNotFound)
+	expectStatus(t, g.do(t, http.MethodPut, "/admin/users/abc/unban", adminToken, nil), http.StatusBadRequest)
+	expectStatus(t, g.do(t, http.MethodPut, fmt.Sprintf("/admin/users/%d/ban", admin.ID), userToken, nil), http.StatusForbidden)
+}
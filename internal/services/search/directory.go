@@ -0,0 +1,39 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchVenues finds venues whose location matches q
+func (s *Service) SearchVenues(c *gin.Context) {
+	venues, err := s.esClient.SearchVenues(c.Request.Context(), elasticsearch.BuildVenueQuery(c.Query("q")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search venues", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venues": venues,
+		"count":  len(venues),
+	})
+}
+
+// SearchPerformers finds performers whose name or description matches q,
+// optionally in one genre
+func (s *Service) SearchPerformers(c *gin.Context) {
+	performers, err := s.esClient.SearchPerformers(c.Request.Context(), elasticsearch.BuildPerformerQuery(c.Query("q"), c.Query("genre")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search performers", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"performers": performers,
+		"count":      len(performers),
+	})
+}
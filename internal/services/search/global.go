@@ -0,0 +1,79 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventsIndex is the index SearchEvents queries
+const eventsIndex = "events"
+
+// GlobalSearch matches q against events, venues and performers at once,
+// in one multi-search request
+func (s *Service) GlobalSearch(c *gin.Context) {
+	term := c.Query("q")
+	if term == "" {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "q is required", nil))
+		return
+	}
+
+	params := elasticsearch.SearchParams{Term: term}
+	results, err := s.esClient.MultiSearch(c.Request.Context(), map[string]map[string]interface{}{
+		eventsIndex:                   elasticsearch.BuildSearchQuery(params),
+		elasticsearch.VenuesIndex:     elasticsearch.BuildVenueQuery(term),
+		elasticsearch.PerformersIndex: elasticsearch.BuildPerformerQuery(term, ""),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	var (
+		events     []elasticsearch.SearchHit
+		venues     []models.ElasticsearchVenue
+		performers []models.ElasticsearchPerformer
+	)
+	err = decodeSources(results[eventsIndex], &events)
+	if err == nil {
+		err = decodeSources(results[elasticsearch.VenuesIndex], &venues)
+	}
+	if err == nil {
+		err = decodeSources(results[elasticsearch.PerformersIndex], &performers)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to read search results", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	s.recordSearch(c, params, len(events))
+	s.recordHits(c, events)
+
+	c.JSON(http.StatusOK, gin.H{
+		"events":     events,
+		"venues":     venues,
+		"performers": performers,
+	})
+}
+
+// decodeSources decodes hit sources into dest, which must point to a slice.
+// An index without hits decodes to an empty slice.
+func decodeSources(sources []json.RawMessage, dest interface{}) error {
+	if sources == nil {
+		sources = []json.RawMessage{}
+	}
+	raw, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return fmt.Errorf("failed to decode search hits: %w", err)
+	}
+	return nil
+}
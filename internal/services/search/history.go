@@ -0,0 +1,124 @@
+package search
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/auth"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historyTimeout bounds recording one search in the user's history
+const historyTimeout = 2 * time.Second
+
+// historyQuery encodes the filters of a search as a query string, so a
+// history entry can be replayed as /search?<query>. Presentation options
+// like highlighting are left out. Returns "" for a search with no filters.
+func historyQuery(params elasticsearch.SearchParams) string {
+	values := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	set("term", params.Term)
+	set("location", params.Location)
+	set("type", params.EventType)
+	set("date", params.Date)
+	if params.VenueID != 0 {
+		values.Set("venueId", strconv.FormatUint(uint64(params.VenueID), 10))
+	}
+	if params.PerformerID != 0 {
+		values.Set("performerId", strconv.FormatUint(uint64(params.PerformerID), 10))
+	}
+	return values.Encode()
+}
+
+// recordSearch adds the search to the history of a signed-in caller. It is
+// best effort and runs in the background: anonymous searches, invalid
+// tokens and Redis errors only skip the history.
+func (s *Service) recordSearch(c *gin.Context, params elasticsearch.SearchParams, resultCount int) {
+	query := historyQuery(params)
+	if query == "" {
+		return
+	}
+
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		return
+	}
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		return
+	}
+
+	// Recording outlives the request, so it keeps the request's values but
+	// not its cancellation
+	detached := context.WithoutCancel(c.Request.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(detached, historyTimeout)
+		defer cancel()
+		if err := s.redisClient.PushSearchHistory(ctx, claims.UserID, query, resultCount); err != nil {
+			log.Printf("Failed to record search of user %d: %v", claims.UserID, err)
+		}
+	}()
+}
+
+// GetSearchHistory returns the caller's last distinct searches, newest first
+func (s *Service) GetSearchHistory(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	history, err := s.redisClient.GetSearchHistory(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Search history is temporarily unavailable", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+		"count":   len(history),
+	})
+}
+
+// ClearSearchHistory deletes the caller's search history
+func (s *Service) ClearSearchHistory(c *gin.Context) {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return
+	}
+
+	if err := s.redisClient.ClearSearchHistory(c.Request.Context(), claims.UserID); err != nil {
+		c.JSON(http.StatusServiceUnavailable, apperrors.NewResponse(apperrors.ErrCodeUpstreamUnavailable, "Search history is temporarily unavailable", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Search history cleared"})
+}
+
+// authenticate validates the caller's JWT. Returns false when a response was
+// written.
+func (s *Service) authenticate(c *gin.Context) (*auth.Claims, bool) {
+	tokenString, err := auth.ExtractTokenFromHeader(c.GetHeader("Authorization"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeUnauthorized, "Authorization header required", nil))
+		return nil, false
+	}
+
+	claims, err := auth.ValidateToken(s.config, tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, apperrors.NewResponse(apperrors.ErrCodeInvalidToken, "Invalid token", nil))
+		return nil, false
+	}
+
+	return claims, true
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+
+	"github.com/gin-gonic/gin"
+)
+
+// popularityTimeout bounds scoring the events of one search
+const popularityTimeout = 2 * time.Second
+
+// recordHits raises the popularity score of every event a search returned.
+// It runs in the background and failures are only logged.
+func (s *Service) recordHits(c *gin.Context, hits []elasticsearch.SearchHit) {
+	if len(hits) == 0 {
+		return
+	}
+
+	eventIDs := make([]uint, len(hits))
+	for i, hit := range hits {
+		eventIDs[i] = hit.ID
+	}
+
+	detached := context.WithoutCancel(c.Request.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(detached, popularityTimeout)
+		defer cancel()
+		for _, eventID := range eventIDs {
+			if err := s.redisClient.IncrEventScore(ctx, eventID, redis.PopularitySearchHit); err != nil {
+				log.Printf("Failed to score search hits: %v", err)
+				return
+			}
+		}
+	}()
+}
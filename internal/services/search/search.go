@@ -4,60 +4,214 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
 	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
 
 	"github.com/gin-gonic/gin"
 )
 
+// explainSummarySize is the number of scoring factors summarized by /search/explain
+const explainSummarySize = 5
+
 type Service struct {
-	esClient *elasticsearch.Client
+	esClient    *elasticsearch.Client
+	redisClient redis.Store
+	config      *config.Config
 }
 
-func NewService(cfg *config.Config) (*Service, error) {
+func NewService(cfg *config.Config, redisClient redis.Store) (*Service, error) {
 	esClient, err := elasticsearch.NewClient(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
 	}
+	// The CDC service records index creation in the database; search has
+	// no database, so it checks for the indices itself
+	if err := esClient.EnsureIndices(); err != nil {
+		return nil, err
+	}
 
 	return &Service{
-		esClient: esClient,
+		esClient:    esClient,
+		redisClient: redisClient,
+		config:      cfg,
 	}, nil
 }
 
 func (s *Service) SetupRoutes(r *gin.Engine) {
 	r.GET("/search", s.SearchEvents)
+	r.GET("/search/explain", s.ExplainSearch)
+	r.GET("/search/global", s.GlobalSearch)
+	r.GET("/search/venues", s.SearchVenues)
+	r.GET("/search/performers", s.SearchPerformers)
+	r.GET("/search/history", s.GetSearchHistory)
+	r.DELETE("/search/history", s.ClearSearchHistory)
 	r.GET("/health", s.HealthCheck)
 }
 
 func (s *Service) SearchEvents(c *gin.Context) {
 	// Extract query parameters
-	term := c.Query("term")
-	location := c.Query("location")
-	eventType := c.Query("type")
-	date := c.Query("date")
+	params := elasticsearch.SearchParams{
+		Term:      c.Query("term"),
+		Location:  c.Query("location"),
+		EventType: c.Query("type"),
+		Date:      c.Query("date"),
+		Highlight: c.Query("highlight") == "true",
+		Sort:      c.Query("sort"),
+	}
+
+	if params.Sort != elasticsearch.SortRelevance && params.Sort != elasticsearch.SortFeatured {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "sort must be featured or omitted", nil))
+		return
+	}
+
+	venueID, err := parsePositiveID(c.Query("venueId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "venueId must be a positive integer", nil))
+		return
+	}
+	params.VenueID = venueID
+
+	performerID, err := parsePositiveID(c.Query("performerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "performerId must be a positive integer", nil))
+		return
+	}
+	params.PerformerID = performerID
 
 	// Build Elasticsearch query
-	query := elasticsearch.BuildSearchQuery(term, location, eventType, date)
+	query := elasticsearch.BuildSearchQuery(params)
 
 	// Execute search
-	events, err := s.esClient.SearchEvents(query)
+	events, err := s.esClient.SearchEvents(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to search events",
-			"details": err.Error(),
-		})
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to search events", gin.H{"reason": err.Error()}))
 		return
 	}
 
+	s.recordSearch(c, params, len(events))
+	s.recordHits(c, events)
+
 	c.JSON(http.StatusOK, gin.H{
 		"events": events,
 		"count":  len(events),
 	})
 }
 
+// parsePositiveID parses an optional ID query parameter; empty means unset
+func parsePositiveID(value string) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid id: %q", value)
+	}
+
+	return uint(id), nil
+}
+
+// ExplainSearch shows how an event scores for a search term, for tuning the
+// field boosts in BuildSearchQuery (admin only)
+func (s *Service) ExplainSearch(c *gin.Context) {
+	if !s.requireAdmin(c) {
+		return
+	}
+
+	term := c.Query("term")
+	if term == "" {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "term is required", nil))
+		return
+	}
+
+	eventID, err := parsePositiveID(c.Query("event_id"))
+	if err != nil || eventID == 0 {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "event_id must be a positive integer", nil))
+		return
+	}
+
+	query := elasticsearch.BuildSearchQuery(elasticsearch.SearchParams{Term: term})
+	explanation, err := s.esClient.ExplainEvent(c.Request.Context(), query, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, apperrors.NewResponse(apperrors.ErrCodeInternal, "Failed to explain search", gin.H{"reason": err.Error()}))
+		return
+	}
+
+	if explanation == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"eventId": eventID,
+			"term":    term,
+			"matched": false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"eventId":     eventID,
+		"term":        term,
+		"matched":     true,
+		"score":       explanation.Value,
+		"summary":     summarizeExplanation(explanation, explainSummarySize),
+		"explanation": explanation,
+	})
+}
+
+// ScoreFactor is one term's contribution to a document's score
+type ScoreFactor struct {
+	Factor string  `json:"factor"`
+	Score  float64 `json:"score"`
+}
+
+// summarizeExplanation lists the highest scoring per-field term matches
+// ("weight(name:coldplay in 3)" nodes) in an explanation tree
+func summarizeExplanation(explanation *elasticsearch.Explanation, limit int) []ScoreFactor {
+	var factors []ScoreFactor
+	var walk func(node *elasticsearch.Explanation)
+	walk = func(node *elasticsearch.Explanation) {
+		if strings.HasPrefix(node.Description, "weight(") {
+			factor := strings.TrimPrefix(node.Description, "weight(")
+			if end := strings.Index(factor, " in "); end >= 0 {
+				factor = factor[:end]
+			}
+			factors = append(factors, ScoreFactor{Factor: factor, Score: node.Value})
+			return
+		}
+		for i := range node.Details {
+			walk(&node.Details[i])
+		}
+	}
+	walk(explanation)
+
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Score > factors[j].Score })
+	if len(factors) > limit {
+		factors = factors[:limit]
+	}
+	return factors
+}
+
+// requireAdmin validates the bearer token and checks for the admin role,
+// writing the error response and returning false otherwise
+func (s *Service) requireAdmin(c *gin.Context) bool {
+	claims, ok := s.authenticate(c)
+	if !ok {
+		return false
+	}
+
+	if !claims.IsAdmin() {
+		c.JSON(http.StatusForbidden, apperrors.NewResponse(apperrors.ErrCodeForbidden, "Admin access required", nil))
+		return false
+	}
+
+	return true
+}
+
 func (s *Service) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "healthy",
@@ -79,6 +233,8 @@ func (s *Service) IndexEvent(ctx context.Context, event *models.Event) error {
 		Performer:   event.Performer.Name,
 		Genre:       event.Performer.Genre,
 		Location:    event.Venue.Location,
+		Currency:    event.Currency,
+		ESVersion:   event.UpdatedAt.UnixMilli(),
 	}
 
 	// Calculate price range and available tickets
@@ -94,17 +250,17 @@ func (s *Service) IndexEvent(ctx context.Context, event *models.Event) error {
 			if ticket.Price > maxPrice {
 				maxPrice = ticket.Price
 			}
-			if ticket.Status == "available" {
+			if ticket.Status == models.TicketAvailable {
 				availableCount++
 			}
 		}
 
-		esEvent.MinPrice = minPrice
-		esEvent.MaxPrice = maxPrice
+		esEvent.MinPrice = minPrice.Float64()
+		esEvent.MaxPrice = maxPrice.Float64()
 		esEvent.AvailableTickets = availableCount
 	}
 
-	return s.esClient.IndexEvent(esEvent)
+	return s.esClient.IndexEvent(ctx, esEvent)
 }
 
 // UpdateEvent updates an event in Elasticsearch
@@ -114,5 +270,5 @@ func (s *Service) UpdateEvent(ctx context.Context, event *models.Event) error {
 
 // DeleteEvent removes an event from Elasticsearch
 func (s *Service) DeleteEvent(ctx context.Context, eventID uint) error {
-	return s.esClient.DeleteEvent(eventID)
+	return s.esClient.DeleteEvent(ctx, eventID)
 }
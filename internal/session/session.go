@@ -0,0 +1,74 @@
+// Package session keeps server-side records of issued login tokens so they
+// can be revoked before they expire
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis"
+	"github.com/google/uuid"
+)
+
+// ErrNotFound means the session expired or was revoked
+var ErrNotFound = errors.New("session not found")
+
+// SessionData is what the gateway remembers about a login. LastSeen,
+// IPAddress and UserAgent are kept for security monitoring.
+type SessionData struct {
+	UserID    uint      `json:"userId"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	IPAddress string    `json:"ipAddress"`
+	UserAgent string    `json:"userAgent"`
+}
+
+// Store keeps sessions in Redis
+type Store struct {
+	redis redis.Store
+}
+
+func NewStore(redisClient redis.Store) *Store {
+	return &Store{redis: redisClient}
+}
+
+// NewID returns a random session ID
+func NewID() string {
+	return uuid.NewString()
+}
+
+// Set stores the session, replacing any previous data and TTL
+func (s *Store) Set(ctx context.Context, sessionID string, data SessionData, ttl time.Duration) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return s.redis.SetSession(ctx, sessionID, encoded, ttl)
+}
+
+// Get returns the session, or ErrNotFound if it expired or was deleted
+func (s *Store) Get(ctx context.Context, sessionID string) (*SessionData, error) {
+	encoded, err := s.redis.GetSession(ctx, sessionID)
+	if errors.Is(err, redis.ErrSessionNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data SessionData
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &data, nil
+}
+
+// Delete revokes the session
+func (s *Store) Delete(ctx context.Context, sessionID string) error {
+	return s.redis.DeleteSession(ctx, sessionID)
+}
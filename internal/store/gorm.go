@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/database"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/outbox"
+	"gorm.io/gorm"
+)
+
+// notFound translates GORM's missing-row error to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type ticketStore struct{ db *gorm.DB }
+
+// NewTicketStore returns a TicketStore backed by db
+func NewTicketStore(db *gorm.DB) TicketStore {
+	return &ticketStore{db: db}
+}
+
+func (s *ticketStore) FindForReservation(ctx context.Context, id uint) (*models.Ticket, error) {
+	var ticket models.Ticket
+	if err := database.Primary(s.db.WithContext(ctx)).Preload("Event").First(&ticket, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &ticket, nil
+}
+
+func (s *ticketStore) FindTier(ctx context.Context, id uint) (*models.TicketTier, error) {
+	var tier models.TicketTier
+	if err := database.Primary(s.db.WithContext(ctx)).First(&tier, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &tier, nil
+}
+
+func (s *ticketStore) Statuses(ctx context.Context, eventID uint, ids []uint) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if err := s.db.WithContext(ctx).Select("id", "event_id", "seat", "status", "price").
+		Where("id IN ? AND event_id = ?", ids, eventID).
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+func (s *ticketStore) CountByStatus(ctx context.Context, eventID uint) (map[models.TicketStatus]int, error) {
+	var rows []struct {
+		Status models.TicketStatus
+		Count  int
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Ticket{}).
+		Select("status, COUNT(*) AS count").
+		Where("event_id = ?", eventID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.TicketStatus]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (s *ticketStore) CountAvailable(ctx context.Context, eventIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		EventID uint
+		Count   int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Ticket{}).Select("event_id, COUNT(*) AS count").
+		Where("event_id IN ? AND status = ?", eventIDs, models.TicketAvailable).
+		Group("event_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	return counts, nil
+}
+
+func (s *ticketStore) ChangedSince(ctx context.Context, after Cursor, upTo time.Time, limit int) ([]TicketChange, error) {
+	var changes []TicketChange
+	if err := s.db.WithContext(ctx).Model(&models.Ticket{}).Select("id, event_id, updated_at").
+		Where("(updated_at, id) > (?, ?)", after.UpdatedAt, after.ID).
+		Where("updated_at <= ?", upTo).
+		Order("updated_at, id").Limit(limit).Scan(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+type bookingStore struct{ db *gorm.DB }
+
+// NewBookingStore returns a BookingStore backed by db
+func NewBookingStore(db *gorm.DB) BookingStore {
+	return &bookingStore{db: db}
+}
+
+func (s *bookingStore) Reserve(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error {
+	return database.WithTransaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		// The lock may have been taken in process while Redis was down, so
+		// it does not exclude other replicas: only an available ticket is taken
+		result := tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", ticket.ID, models.TicketAvailable).
+			Update("status", models.TicketReserved)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTicketContended
+		}
+		ticket.Status = models.TicketReserved
+
+		// The Redis counter gates the tier, but the database count stays
+		// authoritative when the two drift apart
+		if ticket.TierID != nil {
+			result := tx.Model(&models.TicketTier{}).Where("id = ? AND available_count > 0", *ticket.TierID).
+				Update("available_count", gorm.Expr("available_count - 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrTierSoldOut
+			}
+		}
+
+		if err := tx.Create(booking).Error; err != nil {
+			return err
+		}
+		return outbox.RecordBooking(tx, booking, ticket.EventID)
+	})
+}
+
+func (s *bookingStore) ReserveWithAdvisoryLock(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error {
+	return database.WithTransaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", ticket.ID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return ErrTicketContended
+		}
+
+		result := tx.Model(&models.Ticket{}).Where("id = ? AND status = ?", ticket.ID, models.TicketAvailable).
+			Update("status", models.TicketReserved)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTicketContended
+		}
+
+		if ticket.TierID != nil {
+			result := tx.Model(&models.TicketTier{}).Where("id = ? AND available_count > 0", *ticket.TierID).
+				Update("available_count", gorm.Expr("available_count - 1"))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrTierSoldOut
+			}
+		}
+
+		if err := tx.Create(booking).Error; err != nil {
+			return err
+		}
+		return outbox.RecordBooking(tx, booking, ticket.EventID)
+	})
+}
+
+func (s *bookingStore) ListByUser(ctx context.Context, userID uint, page Page) ([]models.Booking, error) {
+	query := s.db.WithContext(ctx).Preload("Ticket").Preload("Ticket.Event").Preload("Ticket.Event.Venue").Preload("Ticket.Event.Performer")
+	if page.Size > 0 {
+		query = query.Order("id").Offset((page.Number - 1) * page.Size).Limit(page.Size)
+	}
+
+	var bookings []models.Booking
+	if err := query.Find(&bookings, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+type eventStore struct{ db *gorm.DB }
+
+// NewEventStore returns an EventStore backed by db
+func NewEventStore(db *gorm.DB) EventStore {
+	return &eventStore{db: db}
+}
+
+func (s *eventStore) Exists(ctx context.Context, id uint) (bool, error) {
+	if err := s.db.WithContext(ctx).Select("id").First(&models.Event{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *eventStore) ChangedSince(ctx context.Context, after Cursor, upTo time.Time, limit int) ([]models.Event, error) {
+	var events []models.Event
+	if err := s.db.WithContext(ctx).Preload("Venue").Preload("Performer").Preload("Tickets").
+		Where("(updated_at, id) > (?, ?)", after.UpdatedAt, after.ID).
+		Where("updated_at <= ?", upTo).
+		Order("updated_at, id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+type userStore struct{ db *gorm.DB }
+
+// NewUserStore returns a UserStore backed by db
+func NewUserStore(db *gorm.DB) UserStore {
+	return &userStore{db: db}
+}
+
+func (s *userStore) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
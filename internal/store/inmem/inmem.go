@@ -0,0 +1,417 @@
+// Package inmem implements the store interfaces in process memory for
+// unit tests. Rows are added with the Add methods; soft-deleted rows are
+// skipped like in Postgres. There are no outbox records or row locks, and
+// every store returned by one Store shares its rows.
+package inmem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/store"
+)
+
+// Store holds the rows behind its ticket, booking, event and user stores
+type Store struct {
+	mu       sync.Mutex
+	events   map[uint]models.Event
+	tickets  map[uint]models.Ticket
+	tiers    map[uint]models.TicketTier
+	bookings map[uint]models.Booking
+	users    map[uint]models.User
+	nextID   uint
+}
+
+// New returns an empty Store
+func New() *Store {
+	return &Store{
+		events:   make(map[uint]models.Event),
+		tickets:  make(map[uint]models.Ticket),
+		tiers:    make(map[uint]models.TicketTier),
+		bookings: make(map[uint]models.Booking),
+		users:    make(map[uint]models.User),
+	}
+}
+
+// Tickets returns the Store's TicketStore
+func (s *Store) Tickets() store.TicketStore { return (*ticketStore)(s) }
+
+// Bookings returns the Store's BookingStore
+func (s *Store) Bookings() store.BookingStore { return (*bookingStore)(s) }
+
+// Events returns the Store's EventStore
+func (s *Store) Events() store.EventStore { return (*eventStore)(s) }
+
+// Users returns the Store's UserStore
+func (s *Store) Users() store.UserStore { return (*userStore)(s) }
+
+// id assigns the next ID when the row has none; s.mu must be held
+func (s *Store) id(current uint) uint {
+	if current != 0 {
+		if current > s.nextID {
+			s.nextID = current
+		}
+		return current
+	}
+	s.nextID++
+	return s.nextID
+}
+
+// AddEvent stores event without its relations' rows; Venue and Performer
+// are kept as given. It returns the event's ID.
+func (s *Store) AddEvent(event models.Event) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.ID = s.id(event.ID)
+	event.Tickets = nil
+	if event.UpdatedAt.IsZero() {
+		event.UpdatedAt = time.Now()
+	}
+	s.events[event.ID] = event
+	return event.ID
+}
+
+// AddTicket stores ticket and returns its ID
+func (s *Store) AddTicket(ticket models.Ticket) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket.ID = s.id(ticket.ID)
+	ticket.Event = nil
+	if ticket.Status == "" {
+		ticket.Status = models.TicketAvailable
+	}
+	if ticket.UpdatedAt.IsZero() {
+		ticket.UpdatedAt = time.Now()
+	}
+	s.tickets[ticket.ID] = ticket
+	return ticket.ID
+}
+
+// AddTier stores tier and returns its ID
+func (s *Store) AddTier(tier models.TicketTier) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tier.ID = s.id(tier.ID)
+	s.tiers[tier.ID] = tier
+	return tier.ID
+}
+
+// AddUser stores user and returns its ID
+func (s *Store) AddUser(user models.User) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.ID = s.id(user.ID)
+	s.users[user.ID] = user
+	return user.ID
+}
+
+// Booking returns a stored booking, for assertions
+func (s *Store) Booking(id uint) (models.Booking, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	booking, ok := s.bookings[id]
+	return booking, ok && !booking.DeletedAt.Valid
+}
+
+// Ticket returns a stored ticket, for assertions
+func (s *Store) Ticket(id uint) (models.Ticket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, ok := s.tickets[id]
+	return ticket, ok && !ticket.DeletedAt.Valid
+}
+
+// event returns a live event; s.mu must be held
+func (s *Store) event(id uint) (models.Event, bool) {
+	event, ok := s.events[id]
+	return event, ok && !event.DeletedAt.Valid
+}
+
+// ticket returns a live ticket; s.mu must be held
+func (s *Store) ticket(id uint) (models.Ticket, bool) {
+	ticket, ok := s.tickets[id]
+	return ticket, ok && !ticket.DeletedAt.Valid
+}
+
+// sortedIDs returns the keys of m in ascending order
+func sortedIDs[T any](m map[uint]T) []uint {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// after reports whether (updatedAt, id) lies after the cursor and no later
+// than upTo
+func after(updatedAt time.Time, id uint, cursor store.Cursor, upTo time.Time) bool {
+	if updatedAt.After(upTo) {
+		return false
+	}
+	if updatedAt.Equal(cursor.UpdatedAt) {
+		return id > cursor.ID
+	}
+	return updatedAt.After(cursor.UpdatedAt)
+}
+
+type ticketStore Store
+
+func (t *ticketStore) FindForReservation(ctx context.Context, id uint) (*models.Ticket, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, ok := s.ticket(id)
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	if event, ok := s.event(ticket.EventID); ok {
+		ticket.Event = &event
+	}
+	return &ticket, nil
+}
+
+func (t *ticketStore) FindTier(ctx context.Context, id uint) (*models.TicketTier, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tier, ok := s.tiers[id]
+	if !ok || tier.DeletedAt.Valid {
+		return nil, store.ErrNotFound
+	}
+	return &tier, nil
+}
+
+func (t *ticketStore) Statuses(ctx context.Context, eventID uint, ids []uint) ([]models.Ticket, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tickets []models.Ticket
+	for _, id := range ids {
+		if ticket, ok := s.ticket(id); ok && ticket.EventID == eventID {
+			tickets = append(tickets, models.Ticket{
+				Model:   ticket.Model,
+				EventID: ticket.EventID,
+				Seat:    ticket.Seat,
+				Status:  ticket.Status,
+				Price:   ticket.Price,
+			})
+		}
+	}
+	return tickets, nil
+}
+
+func (t *ticketStore) CountByStatus(ctx context.Context, eventID uint) (map[models.TicketStatus]int, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[models.TicketStatus]int)
+	for _, ticket := range s.tickets {
+		if ticket.EventID == eventID && !ticket.DeletedAt.Valid {
+			counts[ticket.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (t *ticketStore) CountAvailable(ctx context.Context, eventIDs []uint) (map[uint]int64, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[uint]bool, len(eventIDs))
+	for _, id := range eventIDs {
+		wanted[id] = true
+	}
+
+	counts := make(map[uint]int64, len(eventIDs))
+	for _, ticket := range s.tickets {
+		if wanted[ticket.EventID] && ticket.Status == models.TicketAvailable && !ticket.DeletedAt.Valid {
+			counts[ticket.EventID]++
+		}
+	}
+	return counts, nil
+}
+
+func (t *ticketStore) ChangedSince(ctx context.Context, cursor store.Cursor, upTo time.Time, limit int) ([]store.TicketChange, error) {
+	s := (*Store)(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changes []store.TicketChange
+	for _, ticket := range s.tickets {
+		if !ticket.DeletedAt.Valid && after(ticket.UpdatedAt, ticket.ID, cursor, upTo) {
+			changes = append(changes, store.TicketChange{ID: ticket.ID, EventID: ticket.EventID, UpdatedAt: ticket.UpdatedAt})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].UpdatedAt.Equal(changes[j].UpdatedAt) {
+			return changes[i].UpdatedAt.Before(changes[j].UpdatedAt)
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+type bookingStore Store
+
+func (b *bookingStore) Reserve(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error {
+	s := (*Store)(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ticket(ticket.ID)
+	if !ok {
+		return store.ErrNotFound
+	}
+	if stored.Status != models.TicketAvailable {
+		return store.ErrTicketContended
+	}
+	if ticket.TierID != nil {
+		if tier, ok := s.tiers[*ticket.TierID]; ok && tier.AvailableCount <= 0 {
+			return store.ErrTierSoldOut
+		}
+	}
+	s.reserve(booking, ticket)
+	return nil
+}
+
+func (b *bookingStore) ReserveWithAdvisoryLock(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error {
+	s := (*Store)(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ticket(ticket.ID)
+	if !ok || stored.Status != models.TicketAvailable {
+		return store.ErrTicketContended
+	}
+	if ticket.TierID != nil {
+		if tier, ok := s.tiers[*ticket.TierID]; !ok || tier.AvailableCount <= 0 {
+			return store.ErrTierSoldOut
+		}
+	}
+	s.reserve(booking, ticket)
+	return nil
+}
+
+// reserve stores the booking, marks the ticket reserved and takes it from
+// its tier; s.mu must be held
+func (s *Store) reserve(booking *models.Booking, ticket *models.Ticket) {
+	now := time.Now()
+	booking.ID = s.id(booking.ID)
+	booking.CreatedAt = now
+	booking.UpdatedAt = now
+	s.bookings[booking.ID] = *booking
+
+	stored := s.tickets[ticket.ID]
+	stored.Status = models.TicketReserved
+	stored.UpdatedAt = now
+	s.tickets[ticket.ID] = stored
+	ticket.Status = models.TicketReserved
+	ticket.UpdatedAt = now
+
+	if ticket.TierID != nil {
+		if tier, ok := s.tiers[*ticket.TierID]; ok {
+			tier.AvailableCount--
+			s.tiers[tier.ID] = tier
+		}
+	}
+}
+
+func (b *bookingStore) ListByUser(ctx context.Context, userID uint, page store.Page) ([]models.Booking, error) {
+	s := (*Store)(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bookings []models.Booking
+	for _, id := range sortedIDs(s.bookings) {
+		booking := s.bookings[id]
+		if booking.UserID != userID || booking.DeletedAt.Valid {
+			continue
+		}
+		if ticket, ok := s.tickets[booking.TicketID]; ok {
+			if event, ok := s.event(ticket.EventID); ok {
+				ticket.Event = &event
+			}
+			booking.Ticket = ticket
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if page.Size > 0 {
+		start := min((page.Number-1)*page.Size, len(bookings))
+		end := min(start+page.Size, len(bookings))
+		bookings = bookings[start:end]
+	}
+	return bookings, nil
+}
+
+type eventStore Store
+
+func (e *eventStore) Exists(ctx context.Context, id uint) (bool, error) {
+	s := (*Store)(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.event(id)
+	return ok, nil
+}
+
+func (e *eventStore) ChangedSince(ctx context.Context, cursor store.Cursor, upTo time.Time, limit int) ([]models.Event, error) {
+	s := (*Store)(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []models.Event
+	for _, event := range s.events {
+		if event.DeletedAt.Valid || !after(event.UpdatedAt, event.ID, cursor, upTo) {
+			continue
+		}
+		for _, id := range sortedIDs(s.tickets) {
+			if ticket := s.tickets[id]; ticket.EventID == event.ID && !ticket.DeletedAt.Valid {
+				event.Tickets = append(event.Tickets, ticket)
+			}
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].UpdatedAt.Equal(events[j].UpdatedAt) {
+			return events[i].UpdatedAt.Before(events[j].UpdatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+type userStore Store
+
+func (u *userStore) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	s := (*Store)(u)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, store.ErrNotFound
+	}
+	return &user, nil
+}
@@ -0,0 +1,98 @@
+// Package store holds the persistence the booking, event and CDC services
+// share, behind interfaces so the handlers, the expiry worker and tests can
+// use the same queries. The GORM implementations run against Postgres;
+// inmem implements the interfaces in process memory.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+)
+
+var (
+	// ErrNotFound means the requested row does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrTicketContended means the ticket was taken or locked by another
+	// reservation
+	ErrTicketContended = errors.New("ticket is not available")
+	// ErrTierSoldOut means the ticket's tier has no tickets left
+	ErrTierSoldOut = errors.New("ticket tier is sold out")
+)
+
+// Cursor is a position in an (updated_at, id) ordered scan; rows strictly
+// after it are returned
+type Cursor struct {
+	UpdatedAt time.Time
+	ID        uint
+}
+
+// Page selects one page of a listing. Number is 1-based; a zero Size
+// returns every row.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// TicketChange is a ticket row seen by a change scan
+type TicketChange struct {
+	ID        uint
+	EventID   uint
+	UpdatedAt time.Time
+}
+
+// TicketStore reads tickets and their tiers
+type TicketStore interface {
+	// FindForReservation loads a ticket with its event from the primary,
+	// so a replica's stale status cannot offer a ticket that was just
+	// taken
+	FindForReservation(ctx context.Context, id uint) (*models.Ticket, error)
+	// FindTier loads a tier from the primary
+	FindTier(ctx context.Context, id uint) (*models.TicketTier, error)
+	// Statuses loads the id, event, seat, status and price of the given
+	// tickets of an event; tickets of other events are left out
+	Statuses(ctx context.Context, eventID uint, ids []uint) ([]models.Ticket, error)
+	// CountByStatus counts an event's tickets by status
+	CountByStatus(ctx context.Context, eventID uint) (map[models.TicketStatus]int, error)
+	// CountAvailable counts the available tickets of each event; events
+	// without any are left out
+	CountAvailable(ctx context.Context, eventIDs []uint) (map[uint]int64, error)
+	// ChangedSince returns up to limit tickets updated after the cursor and
+	// no later than upTo, in (updated_at, id) order
+	ChangedSince(ctx context.Context, after Cursor, upTo time.Time, limit int) ([]TicketChange, error)
+}
+
+// BookingStore creates and lists bookings
+type BookingStore interface {
+	// Reserve creates the reserved booking, marks its ticket reserved and
+	// takes a ticket from its tier in one transaction, with the outbox
+	// change. The caller holds the ticket's lock; it fails with
+	// ErrTicketContended when the ticket is no longer available anyway, and
+	// ErrTierSoldOut when the tier's count has run out.
+	Reserve(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error
+	// ReserveWithAdvisoryLock does what Reserve does without the caller
+	// holding a lock, serializing competing reservations with a
+	// transaction-scoped Postgres advisory lock. It fails with
+	// ErrTicketContended or ErrTierSoldOut when the seat cannot be taken.
+	ReserveWithAdvisoryLock(ctx context.Context, booking *models.Booking, ticket *models.Ticket) error
+	// ListByUser loads a user's bookings with their tickets, events,
+	// venues and performers
+	ListByUser(ctx context.Context, userID uint, page Page) ([]models.Booking, error)
+}
+
+// EventStore reads events
+type EventStore interface {
+	// Exists reports whether the event exists and is not deleted
+	Exists(ctx context.Context, id uint) (bool, error)
+	// ChangedSince returns up to limit events updated after the cursor and
+	// no later than upTo, in (updated_at, id) order, with their venues,
+	// performers and tickets
+	ChangedSince(ctx context.Context, after Cursor, upTo time.Time, limit int) ([]models.Event, error)
+}
+
+// UserStore reads users
+type UserStore interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+}
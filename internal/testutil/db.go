@@ -0,0 +1,116 @@
+// Package testutil provides the database and search fakes shared by the
+// service tests
+package testutil
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqliteDriver is sqlite3 plus the Postgres functions the queries under
+// test use
+const sqliteDriver = "sqlite3_testutil"
+
+var registerDriver sync.Once
+
+// NewDB opens a migrated sqlite database in a temporary file that is
+// removed when the test ends
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	return OpenDB(t, filepath.Join(t.TempDir(), "test.db"))
+}
+
+// OpenDB opens the sqlite database at path, creating and migrating it if
+// needed. Several handles to one path share the data, as separate
+// connections to Postgres would.
+func OpenDB(t testing.TB, path string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(Dialector(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sqlite handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db.ConnPool = &dialectPool{dialectConn: dialectConn{pool: sqlDB}, db: sqlDB}
+	db.Statement.ConnPool = db.ConnPool
+	if err := db.AutoMigrate(models.All()...); err != nil {
+		t.Fatalf("failed to migrate sqlite database: %v", err)
+	}
+	return db
+}
+
+// Dialector returns a dialector for the sqlite database at path, for tests
+// that open it through something other than OpenDB, such as a replica
+func Dialector(path string) gorm.Dialector {
+	registerDriver.Do(func() {
+		sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{ConnectHook: registerFunctions})
+	})
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=5000&_foreign_keys=on", path)
+	return sqlite.New(sqlite.Config{DriverName: sqliteDriver, DSN: dsn})
+}
+
+// registerFunctions adds GREATEST and LEAST, which sqlite spells as the
+// multi-argument max and min, and makes Postgres advisory locks always
+// succeed: sqlite has a single writer anyway
+func registerFunctions(conn *sqlite3.SQLiteConn) error {
+	if err := conn.RegisterFunc("pg_try_advisory_xact_lock", func(key int64) bool {
+		return true
+	}, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("greatest", func(args ...interface{}) interface{} {
+		return extreme(args, 1)
+	}, true); err != nil {
+		return err
+	}
+	return conn.RegisterFunc("least", func(args ...interface{}) interface{} {
+		return extreme(args, -1)
+	}, true)
+}
+
+// extreme returns the largest (sign 1) or smallest (sign -1) non-NULL
+// argument, as Postgres does
+func extreme(args []interface{}, sign int) interface{} {
+	var best interface{}
+	for _, arg := range args {
+		if arg == nil {
+			continue
+		}
+		if best == nil || compare(arg, best)*sign > 0 {
+			best = arg
+		}
+	}
+	return best
+}
+
+// compare orders two sqlite values of the same kind
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmp.Compare(a, b)
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,73 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// postgresSyntax rewrites the Postgres-only syntax the queries under test
+// use into its sqlite equivalent. sqlite's LIKE already ignores ASCII case.
+var postgresSyntax = strings.NewReplacer(
+	" ILIKE ", " LIKE ",
+	"::bigint", "",
+)
+
+// dialectConn is a gorm.ConnPool that rewrites every statement with
+// postgresSyntax before running it
+type dialectConn struct {
+	pool gorm.ConnPool
+}
+
+// dialectPool is a dialectConn over the database handle, starting
+// transactions that rewrite too
+type dialectPool struct {
+	dialectConn
+	db *sql.DB
+}
+
+// dialectTx is a dialectConn over a transaction. It must not start
+// transactions itself, which is how gorm tells it is already in one.
+type dialectTx struct {
+	dialectConn
+	tx *sql.Tx
+}
+
+var (
+	_ gorm.ConnPoolBeginner = (*dialectPool)(nil)
+	_ gorm.TxCommitter      = (*dialectTx)(nil)
+)
+
+func (c *dialectConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.pool.PrepareContext(ctx, postgresSyntax.Replace(query))
+}
+
+func (c *dialectConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.pool.ExecContext(ctx, postgresSyntax.Replace(query), args...)
+}
+
+func (c *dialectConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.pool.QueryContext(ctx, postgresSyntax.Replace(query), args...)
+}
+
+func (c *dialectConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.pool.QueryRowContext(ctx, postgresSyntax.Replace(query), args...)
+}
+
+func (p *dialectPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &dialectTx{dialectConn: dialectConn{pool: tx}, tx: tx}, nil
+}
+
+// GetDBConn lets gorm.DB.DB return the underlying handle
+func (p *dialectPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+func (t *dialectTx) Commit() error   { return t.tx.Commit() }
+func (t *dialectTx) Rollback() error { return t.tx.Rollback() }
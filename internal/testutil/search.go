@@ -0,0 +1,259 @@
+package testutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+)
+
+// SearchServer is an in-memory stand-in for the Elasticsearch document,
+// bulk and search APIs the client uses. Searches ignore the query and
+// return every document in the index.
+type SearchServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	indices map[string]map[string]*document
+	seqNo   int64
+
+	// OnIndex, when set, is called before each event document write;
+	// a non-nil error fails the write with a 500
+	OnIndex func(id string) error
+}
+
+type document struct {
+	source  json.RawMessage
+	seqNo   int64
+	version int64
+}
+
+// NewSearchServer starts a fake Elasticsearch and returns it with a client
+// pointed at it. The server is closed when the test ends.
+func NewSearchServer(t testing.TB) (*SearchServer, *elasticsearch.Client) {
+	t.Helper()
+	s := &SearchServer{indices: make(map[string]map[string]*document)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	client, err := elasticsearch.NewClient(&config.Config{ElasticsearchURL: s.URL})
+	if err != nil {
+		t.Fatalf("failed to create search client: %v", err)
+	}
+	return s, client
+}
+
+// IDs returns the sorted IDs of the documents in index
+func (s *SearchServer) IDs(index string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.indices[index]))
+	for id := range s.indices[index] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Document decodes the source of a document into dest, reporting whether
+// it exists
+func (s *SearchServer) Document(index string, id uint, dest interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, ok := s.indices[index][strconv.FormatUint(uint64(id), 10)]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(doc.source, dest) == nil
+}
+
+func (s *SearchServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tagline": "You Know, for Search"})
+	case parts[0] == "_bulk":
+		s.bulk(w, r)
+	case len(parts) == 1:
+		// Index existence checks and creation
+		if s.indices[parts[0]] == nil {
+			s.indices[parts[0]] = make(map[string]*document)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+	case len(parts) == 3 && parts[1] == "_doc":
+		s.doc(w, r, parts[0], parts[2])
+	case parts[1] == "_search":
+		s.search(w, parts[0])
+	case parts[1] == "_count":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"count": len(s.indices[parts[0]])})
+	case parts[1] == "_mget":
+		s.mget(w, r, parts[0])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": "unsupported path " + r.URL.Path})
+	}
+}
+
+func (s *SearchServer) doc(w http.ResponseWriter, r *http.Request, index, id string) {
+	docs := s.index(index)
+	doc, exists := docs[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"found": false})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"found": true, "_seq_no": doc.seqNo, "_primary_term": 1, "_source": doc.source,
+		})
+	case http.MethodPut:
+		query := r.URL.Query()
+		if query.Get("op_type") == "create" && exists {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"error": "document already exists"})
+			return
+		}
+		if seqNo := query.Get("if_seq_no"); seqNo != "" && (!exists || seqNo != strconv.FormatInt(doc.seqNo, 10)) {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"error": "sequence number conflict"})
+			return
+		}
+		var source json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&source); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+			return
+		}
+		if status, err := s.write(index, id, source, 0); err != nil {
+			writeJSON(w, status, map[string]interface{}{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": "updated"})
+	case http.MethodDelete:
+		if !exists {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"result": "not_found"})
+			return
+		}
+		delete(docs, id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"result": "deleted"})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"error": r.Method})
+	}
+}
+
+// write stores a document, honouring an external version when one is given
+func (s *SearchServer) write(index, id string, source json.RawMessage, version int64) (int, error) {
+	if index == "events" && s.OnIndex != nil {
+		if err := s.OnIndex(id); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+	docs := s.index(index)
+	if current, ok := docs[id]; ok && version > 0 && current.version > version {
+		return http.StatusConflict, fmt.Errorf("version %d is older than %d", version, current.version)
+	}
+	s.seqNo++
+	docs[id] = &document{source: source, seqNo: s.seqNo, version: version}
+	return http.StatusOK, nil
+}
+
+func (s *SearchServer) bulk(w http.ResponseWriter, r *http.Request) {
+	var items []map[string]interface{}
+	failed := false
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 1<<20), 1<<20)
+	for scanner.Scan() {
+		var action struct {
+			Index struct {
+				Index   string      `json:"_index"`
+				ID      json.Number `json:"_id"`
+				Version int64       `json:"version"`
+			} `json:"index"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+			return
+		}
+		if !scanner.Scan() {
+			break
+		}
+		source := json.RawMessage(append([]byte(nil), scanner.Bytes()...))
+
+		status, err := s.write(action.Index.Index, action.Index.ID.String(), source, action.Index.Version)
+		item := map[string]interface{}{"status": status}
+		if err != nil {
+			failed = true
+			item["error"] = err.Error()
+		}
+		items = append(items, map[string]interface{}{"index": item})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"errors": failed, "items": items})
+}
+
+func (s *SearchServer) search(w http.ResponseWriter, index string) {
+	docs := s.index(index)
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	hits := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		hits[i] = map[string]interface{}{"_id": id, "_source": docs[id].source}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"hits": map[string]interface{}{"total": map[string]interface{}{"value": len(hits)}, "hits": hits},
+	})
+}
+
+func (s *SearchServer) mget(w http.ResponseWriter, r *http.Request, index string) {
+	var request struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	docs := s.index(index)
+	results := make([]map[string]interface{}, len(request.IDs))
+	for i, id := range request.IDs {
+		result := map[string]interface{}{"_id": id, "found": false}
+		if doc, ok := docs[id]; ok {
+			result["found"] = true
+			result["_source"] = doc.source
+		}
+		results[i] = result
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"docs": results})
+}
+
+// index returns the documents of an index, creating it on first write as
+// Elasticsearch does
+func (s *SearchServer) index(name string) map[string]*document {
+	docs, ok := s.indices[name]
+	if !ok {
+		docs = make(map[string]*document)
+		s.indices[name] = docs
+	}
+	return docs
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,134 @@
+// Package validation validates request bodies. Rules are declared in
+// `binding` struct tags (the tag gin already uses), extended with the
+// business rules registered here.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// SupportedCurrencies lists the ISO currency codes (lowercase) accepted by
+// the valid_currency rule
+var SupportedCurrencies = []string{"usd", "eur", "gbp", "jpy", "twd"}
+
+// statusEnums checks the values accepted by valid_status=<kind>
+var statusEnums = map[string]func(string) bool{
+	"ticket":  func(v string) bool { return models.TicketStatus(v).IsValid() },
+	"booking": func(v string) bool { return models.BookingStatus(v).IsValid() },
+}
+
+// Validate is the shared validator with the custom rules registered
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+
+	// Report fields by their JSON names
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	rules := map[string]validator.Func{
+		"future_date":    futureDate,
+		"positive_price": positivePrice,
+		"valid_currency": validCurrency,
+		"valid_status":   validStatus,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+
+	return v
+}
+
+// futureDate requires a time.Time after now
+func futureDate(fl validator.FieldLevel) bool {
+	date, ok := fl.Field().Interface().(time.Time)
+	return ok && date.After(time.Now())
+}
+
+// positivePrice requires a price greater than zero
+func positivePrice(fl validator.FieldLevel) bool {
+	switch fl.Field().Kind() {
+	case reflect.Float32, reflect.Float64:
+		return fl.Field().Float() > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fl.Field().Int() > 0
+	}
+	return false
+}
+
+// validCurrency requires one of SupportedCurrencies (case-insensitive)
+func validCurrency(fl validator.FieldLevel) bool {
+	return contains(SupportedCurrencies, strings.ToLower(fl.Field().String()))
+}
+
+// validStatus requires a value of the status enum named by the tag
+// parameter, e.g. valid_status=booking
+func validStatus(fl validator.FieldLevel) bool {
+	isValid, ok := statusEnums[fl.Param()]
+	return ok && isValid(fl.Field().String())
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRequest decodes the JSON body into req and validates it. On
+// failure it writes a 400 in the standard error format, with the failed
+// rule of each invalid field under details.fields, and returns false.
+func ValidateRequest(c *gin.Context, req interface{}) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid request data", gin.H{"reason": err.Error()}))
+		return false
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Invalid request data", gin.H{"reason": err.Error()}))
+			return false
+		}
+
+		fields := make(map[string]string, len(validationErrors))
+		for _, fieldErr := range validationErrors {
+			fields[fieldPath(fieldErr.Namespace())] = fieldErr.Tag()
+		}
+		c.JSON(http.StatusBadRequest, apperrors.NewResponse(apperrors.ErrCodeInvalidRequest, "Request validation failed", gin.H{"fields": fields}))
+		return false
+	}
+
+	return true
+}
+
+// fieldPath drops the struct name from a validator namespace
+// ("createEventRequest.date" becomes "date")
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
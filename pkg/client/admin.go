@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// VenueInput describes a venue to create
+type VenueInput struct {
+	Location string `json:"location"`
+	Capacity int    `json:"capacity"`
+	Country  string `json:"country,omitempty"` // ISO 3166-1 alpha-2
+	SeatMap  string `json:"seatMap,omitempty"` // JSON
+}
+
+// PerformerInput describes a performer to create
+type PerformerInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+}
+
+// EventInput describes an event to create. Zero optional fields take the
+// server's defaults.
+type EventInput struct {
+	VenueID       uint      `json:"venueId"`
+	PerformerID   uint      `json:"performerId"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	Date          time.Time `json:"date"`
+	Currency      string    `json:"currency,omitempty"`
+	Capacity      *int      `json:"capacity,omitempty"`
+	RequiresQueue bool      `json:"requiresQueue,omitempty"`
+}
+
+// TicketInput is a seat to put on sale
+type TicketInput struct {
+	Seat  string      `json:"seat"`
+	Price json.Number `json:"price"`
+}
+
+// TicketBatch is the result of CreateTickets
+type TicketBatch struct {
+	EventID uint `json:"eventId"`
+	Created int  `json:"created"`
+	Total   int  `json:"total"`
+}
+
+// SyncJob is a full search index sync running in the CDC service
+type SyncJob struct {
+	JobID   string `json:"jobId"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Account is a user account as admins see it
+type Account struct {
+	ID        uint       `json:"ID"`
+	Email     string     `json:"Email"`
+	Name      string     `json:"Name"`
+	Role      string     `json:"Role"`
+	BannedAt  *time.Time `json:"BannedAt"`
+	CreatedAt time.Time  `json:"CreatedAt"`
+}
+
+// QueueStatus is an event's waiting queue
+type QueueStatus struct {
+	EventID          uint  `json:"eventId"`
+	QueueLength      int64 `json:"queueLength"`
+	ActiveAdmissions int64 `json:"activeAdmissions"`
+}
+
+// Capacity is an event's live ticket counts
+type Capacity struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Reserved  int `json:"reserved"`
+	Sold      int `json:"sold"`
+	Capacity  int `json:"capacity"`
+}
+
+// BookingSummary is an event's ticket counts and sales
+type BookingSummary struct {
+	EventID          uint    `json:"eventId"`
+	Currency         string  `json:"currency"`
+	TotalTickets     int     `json:"totalTickets"`
+	SoldTickets      int     `json:"soldTickets"`
+	AvailableTickets int     `json:"availableTickets"`
+	ReservedTickets  int     `json:"reservedTickets"`
+	CancelledTickets int     `json:"cancelledTickets"`
+	TotalRevenue     Amount  `json:"totalRevenue"`
+	AveragePrice     Amount  `json:"averagePrice"`
+	RefundedAmount   Amount  `json:"refundedAmount"`
+	UniqueBuyers     int     `json:"uniqueBuyers"`
+	ConversionRate   float64 `json:"conversionRate"`
+}
+
+// CreateVenue adds a venue
+func (c *Client) CreateVenue(ctx context.Context, input VenueInput) (*Venue, error) {
+	var venue Venue
+	if err := c.do(ctx, http.MethodPost, "/admin/venues", nil, input, &venue); err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// CreatePerformer adds a performer
+func (c *Client) CreatePerformer(ctx context.Context, input PerformerInput) (*Performer, error) {
+	var performer Performer
+	if err := c.do(ctx, http.MethodPost, "/admin/performers", nil, input, &performer); err != nil {
+		return nil, err
+	}
+	return &performer, nil
+}
+
+// CreateEvent adds an event without tickets; see CreateTickets
+func (c *Client) CreateEvent(ctx context.Context, input EventInput) (*Event, error) {
+	var event Event
+	if err := c.do(ctx, http.MethodPost, "/event", nil, input, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// CreateTickets puts seats of an event on sale. Seats must be new to the
+// event, and the event's capacity bounds its tickets.
+func (c *Client) CreateTickets(ctx context.Context, eventID uint, tickets []TicketInput) (*TicketBatch, error) {
+	body := map[string]interface{}{"tickets": tickets}
+	var batch TicketBatch
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/admin/events/%d/tickets", eventID), nil, body, &batch); err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// SyncEvent re-indexes one event in the search index
+func (c *Client) SyncEvent(ctx context.Context, eventID uint) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/cdc/sync-event/%d", eventID), nil, nil, nil)
+}
+
+// Reindex starts a full sync of the search index. It fails with an
+// *APIError coded SYNC_IN_PROGRESS while one is running.
+func (c *Client) Reindex(ctx context.Context) (*SyncJob, error) {
+	var job SyncJob
+	if err := c.do(ctx, http.MethodPost, "/cdc/sync-all", nil, nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListUserBookings returns a user's bookings; only admins may list other
+// users'
+func (c *Client) ListUserBookings(ctx context.Context, userID uint) ([]Booking, error) {
+	var response struct {
+		Bookings []Booking `json:"bookings"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/booking/user/%d", userID), nil, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Bookings, nil
+}
+
+// SetUserRole grants ("admin") or revokes ("user") the admin role. The
+// user's current tokens keep their old role until they expire.
+func (c *Client) SetUserRole(ctx context.Context, userID uint, role string) (*Account, error) {
+	body := map[string]string{"role": role}
+	var account Account
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/admin/users/%d/role", userID), nil, body, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// QueueStatus returns an event's waiting queue length and admissions
+func (c *Client) QueueStatus(ctx context.Context, eventID uint) (*QueueStatus, error) {
+	var status QueueStatus
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/admin/events/%d/queue", eventID), nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// EventCapacity returns an event's live ticket counts
+func (c *Client) EventCapacity(ctx context.Context, eventID uint) (*Capacity, error) {
+	var capacity Capacity
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/events/%d/capacity", eventID), nil, nil, &capacity); err != nil {
+		return nil, err
+	}
+	return &capacity, nil
+}
+
+// BookingSummary returns an event's ticket counts and sales; open to admins
+// and the event's performer
+func (c *Client) BookingSummary(ctx context.Context, eventID uint) (*BookingSummary, error) {
+	var summary BookingSummary
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/events/%d/booking-summary", eventID), nil, nil, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -0,0 +1,288 @@
+// Package client is a typed Go client for the gateway's HTTP API. Logging
+// in or registering keeps the session's JWT for later calls, and error
+// responses come back as *APIError, which errors.Is matches against this
+// package's Err values. The admin calls need a session with the admin
+// role. Money amounts are Amount, which keeps the exact decimal string
+// so prices are not rounded through float64.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each request unless WithTimeout or WithHTTPClient
+// says otherwise
+const DefaultTimeout = 10 * time.Second
+
+// Client calls the gateway at one base URL. It is safe for concurrent use;
+// all calls share the session of the last successful Login or Register.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+
+	mu      sync.RWMutex
+	session *Session
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTimeout bounds each request, including reading the response
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithHTTPClient sends requests with a copy of httpClient, e.g. for a
+// custom transport; its timeout replaces DefaultTimeout unless WithTimeout
+// is also given
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken starts the client with an existing access token, e.g. one
+// saved from an earlier session
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.session = &Session{Token: token}
+	}
+}
+
+// New returns a client of the gateway at baseURL, e.g.
+// "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Copied so the timeout does not change a client the caller shares
+	httpClient := *c.httpClient
+	if c.timeout > 0 {
+		httpClient.Timeout = c.timeout
+	}
+	c.httpClient = &httpClient
+	return c
+}
+
+// Session returns the current session, or nil before Login or Register
+func (c *Client) Session() *Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.session == nil {
+		return nil
+	}
+	session := *c.session
+	return &session
+}
+
+// Register creates an account and logs in as it
+func (c *Client) Register(ctx context.Context, email, password, name string) (*Session, error) {
+	body := map[string]string{"email": email, "password": password, "name": name}
+	var session Session
+	if err := c.do(ctx, http.MethodPost, "/auth/register", nil, body, &session); err != nil {
+		return nil, err
+	}
+	c.setSession(&session)
+	return &session, nil
+}
+
+// Login starts a session. Accounts with MFA enabled fail with
+// ErrMFARequired.
+func (c *Client) Login(ctx context.Context, email, password string) (*Session, error) {
+	body := map[string]string{"email": email, "password": password}
+	var response struct {
+		Session
+		RequiresMFA bool `json:"requires_mfa"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/auth/login", nil, body, &response); err != nil {
+		return nil, err
+	}
+	if response.RequiresMFA {
+		return nil, ErrMFARequired
+	}
+
+	session := response.Session
+	c.setSession(&session)
+	return &session, nil
+}
+
+// SearchEvents searches events by the non-zero fields of params
+func (c *Client) SearchEvents(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	query := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	set("term", params.Term)
+	set("location", params.Location)
+	set("type", params.Type)
+	set("date", params.Date)
+	set("sort", params.Sort)
+	if params.VenueID != 0 {
+		query.Set("venueId", strconv.FormatUint(uint64(params.VenueID), 10))
+	}
+	if params.PerformerID != 0 {
+		query.Set("performerId", strconv.FormatUint(uint64(params.PerformerID), 10))
+	}
+	if params.Highlight {
+		query.Set("highlight", "true")
+	}
+
+	var result SearchResult
+	if err := c.do(ctx, http.MethodGet, "/search", query, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetEvent returns an event with its venue, performer and tickets
+func (c *Client) GetEvent(ctx context.Context, eventID uint) (*Event, error) {
+	var event Event
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/event/%d", eventID), nil, nil, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ReserveTicket holds a ticket for the caller until the reservation
+// expires
+func (c *Client) ReserveTicket(ctx context.Context, ticketID uint) (*Reservation, error) {
+	body := map[string]interface{}{"ticketId": ticketID}
+	var reservation Reservation
+	if err := c.do(ctx, http.MethodPost, "/booking/reserve", nil, body, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ConfirmBooking pays for the caller's reservation of a ticket. The charge
+// is accepted, not yet made; see Confirmation.StatusURL.
+func (c *Client) ConfirmBooking(ctx context.Context, ticketID uint, paymentDetails string) (*Confirmation, error) {
+	body := map[string]interface{}{"ticketId": ticketID, "paymentDetails": paymentDetails}
+	var confirmation Confirmation
+	if err := c.do(ctx, http.MethodPut, "/booking/confirm", nil, body, &confirmation); err != nil {
+		return nil, err
+	}
+	return &confirmation, nil
+}
+
+// CancelBooking cancels one of the caller's bookings
+func (c *Client) CancelBooking(ctx context.Context, bookingID uint) (*Cancellation, error) {
+	var cancellation Cancellation
+	if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/booking/cancel/%d", bookingID), nil, nil, &cancellation); err != nil {
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+// ListMyBookings returns the bookings of the logged in user
+func (c *Client) ListMyBookings(ctx context.Context) ([]Booking, error) {
+	session := c.Session()
+	if session == nil || session.User.ID == 0 {
+		return nil, ErrNotLoggedIn
+	}
+
+	return c.ListUserBookings(ctx, session.User.ID)
+}
+
+func (c *Client) setSession(session *Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored := *session
+	c.session = &stored
+}
+
+// do sends a request with the session's token, if any, and decodes a
+// successful response into out or an error response into an *APIError
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if session := c.Session(); session != nil && session.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+session.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return decodeError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// decodeError reads an error envelope, keeping the raw body as the message
+// when the response is not one
+func decodeError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	var envelope struct {
+		Code      string                 `json:"code"`
+		Message   string                 `json:"message"`
+		RequestID string                 `json:"request_id"`
+		Details   map[string]interface{} `json:"details"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err == nil && envelope.Code != "" {
+		apiErr.Code = envelope.Code
+		apiErr.Message = envelope.Message
+		apiErr.RequestID = envelope.RequestID
+		apiErr.Details = envelope.Details
+	} else {
+		apiErr.Message = strings.TrimSpace(string(raw))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	return apiErr
+}
@@ -0,0 +1,367 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/config"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/elasticsearch"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/email"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/models"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/money"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/notify"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/payment"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/redis/inmem"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/booking"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/event"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/gateway"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/services/search"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/session"
+	"github.com/JonasLeetTheWay/ticketmaster-go/internal/testutil"
+	"github.com/JonasLeetTheWay/ticketmaster-go/pkg/client"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// testAPI is the gateway's real routes forwarding to the search, event and
+// booking services, all on sqlite, in-memory Redis and a fake Elasticsearch
+type testAPI struct {
+	url    string
+	db     *gorm.DB
+	search *elasticsearch.Client
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		JWTSecret:                "test-secret",
+		JWTExpiry:                time.Hour,
+		JWTRefreshExpiry:         24 * time.Hour,
+		ReservationWindowMinutes: 10,
+		LockKeeperMax:            10,
+		PaymentWorkers:           1,
+		PaymentQueueSize:         10,
+		PaymentRetryLimit:        3,
+		MockStripeEnabled:        true,
+		MockStripeSuccessRate:    1,
+		MockStripeLatency:        "0s",
+		DefaultCurrency:          "twd",
+	}
+	db := testutil.NewDB(t)
+	store := inmem.New()
+	t.Cleanup(func() { store.Close() })
+	searchServer, searchClient := testutil.NewSearchServer(t)
+	cfg.ElasticsearchURL = searchServer.URL
+
+	searchService, err := search.NewService(cfg, store)
+	if err != nil {
+		t.Fatalf("failed to create search service: %v", err)
+	}
+	cfg.SearchServicePort = serve(t, searchService.SetupRoutes)
+	cfg.EventServicePort = serve(t, event.NewService(db, cfg, notify.NewLogNotifier(), store).SetupRoutes)
+	bookingService := booking.NewService(db, store, cfg, payment.NewMockStripeClient(cfg), email.NewMockSender(), notify.NewLogNotifier(), nil)
+	t.Cleanup(func() { bookingService.Shutdown(context.Background()) })
+	cfg.BookingServicePort = serve(t, bookingService.SetupRoutes)
+
+	router := gin.New()
+	gateway.NewService(cfg, db, session.NewStore(store)).SetupRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return &testAPI{url: server.URL, db: db, search: searchClient}
+}
+
+// serve starts a service's routes on a local port, which the gateway
+// forwards to, and returns the port
+func serve(t *testing.T, setupRoutes func(*gin.Engine)) string {
+	t.Helper()
+	router := gin.New()
+	setupRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+	return target.Port()
+}
+
+// seedEvent creates an event with one available ticket and returns both
+func (api *testAPI) seedEvent(t *testing.T) (*models.Event, *models.Ticket) {
+	t.Helper()
+	venue := models.Venue{Location: "Taipei Arena", Capacity: 100}
+	performer := models.Performer{Name: "Band"}
+	if err := api.db.Create(&venue).Error; err != nil {
+		t.Fatalf("failed to create venue: %v", err)
+	}
+	if err := api.db.Create(&performer).Error; err != nil {
+		t.Fatalf("failed to create performer: %v", err)
+	}
+	event := models.Event{
+		VenueID:     venue.ID,
+		PerformerID: performer.ID,
+		Name:        "Spring Concert",
+		Date:        time.Now().Add(30 * 24 * time.Hour),
+		Currency:    "twd",
+	}
+	if err := api.db.Create(&event).Error; err != nil {
+		t.Fatalf("failed to create event: %v", err)
+	}
+	ticket := models.Ticket{EventID: event.ID, Seat: "A1", Price: money.FromFloat(1000), Status: models.TicketAvailable}
+	if err := api.db.Create(&ticket).Error; err != nil {
+		t.Fatalf("failed to create ticket: %v", err)
+	}
+	return &event, &ticket
+}
+
+// register returns a client logged in as a new account
+func (api *testAPI) register(t *testing.T, emailAddr string) *client.Client {
+	t.Helper()
+	c := client.New(api.url)
+	if _, err := c.Register(context.Background(), emailAddr, "s3cret-pass", "Alice"); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	return c
+}
+
+// expectErr fails the test unless err is an *APIError matching want
+func expectErr(t *testing.T, err, want error, status int) {
+	t.Helper()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want an API error", err)
+	}
+	if !errors.Is(err, want) || apiErr.StatusCode != status {
+		t.Fatalf("got %v, want %v with status %d", err, want, status)
+	}
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	api := newTestAPI(t)
+	ctx := context.Background()
+
+	c := client.New(api.url + "/")
+	registered, err := c.Register(ctx, "alice@example.com", "s3cret-pass", "Alice")
+	if err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+	if registered.Token == "" || registered.RefreshToken == "" || registered.User.ID == 0 || registered.User.Email != "alice@example.com" {
+		t.Fatalf("incomplete session from register: %+v", registered)
+	}
+	if c.Session().Token != registered.Token {
+		t.Error("client did not keep the registered session")
+	}
+
+	_, err = c.Register(ctx, "alice@example.com", "s3cret-pass", "Alice")
+	expectErr(t, err, client.ErrUserExists, http.StatusConflict)
+	_, err = client.New(api.url).Register(ctx, "bob@example.com", "short", "Bob")
+	expectErr(t, err, client.ErrInvalidRequest, http.StatusBadRequest)
+
+	fresh := client.New(api.url)
+	_, err = fresh.Login(ctx, "alice@example.com", "wrong-pass")
+	expectErr(t, err, client.ErrInvalidCredentials, http.StatusUnauthorized)
+	if fresh.Session() != nil {
+		t.Error("a failed login left a session")
+	}
+
+	loggedIn, err := fresh.Login(ctx, "alice@example.com", "s3cret-pass")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if loggedIn.Token == "" || loggedIn.User.ID != registered.User.ID {
+		t.Errorf("login session %+v does not belong to user %d", loggedIn, registered.User.ID)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	api := newTestAPI(t)
+	c := client.New(api.url)
+
+	indexed := models.ElasticsearchEvent{ID: 7, VenueID: 1, Name: "Spring Concert", Venue: "Taipei Arena", Location: "Taipei", Currency: "twd", MinPrice: 800, MaxPrice: 1200, AvailableTickets: 3}
+	if err := api.search.IndexEvent(context.Background(), &indexed); err != nil {
+		t.Fatalf("failed to index event: %v", err)
+	}
+
+	result, err := c.SearchEvents(context.Background(), client.SearchParams{Term: "spring", Location: "Taipei", VenueID: 1, Highlight: true})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if result.Count != 1 || len(result.Events) != 1 {
+		t.Fatalf("got %d events (count %d), want the indexed one", len(result.Events), result.Count)
+	}
+	got := result.Events[0]
+	if got.ID != indexed.ID || got.VenueID != indexed.VenueID || got.Name != indexed.Name || got.Location != indexed.Location ||
+		got.MinPrice != indexed.MinPrice || got.MaxPrice != indexed.MaxPrice || got.AvailableTickets != indexed.AvailableTickets {
+		t.Errorf("got %+v, want %+v", got, indexed)
+	}
+
+	_, err = c.SearchEvents(context.Background(), client.SearchParams{Sort: "price"})
+	expectErr(t, err, client.ErrInvalidRequest, http.StatusBadRequest)
+}
+
+func TestGetEvent(t *testing.T) {
+	api := newTestAPI(t)
+	seeded, ticket := api.seedEvent(t)
+	c := client.New(api.url)
+
+	event, err := c.GetEvent(context.Background(), seeded.ID)
+	if err != nil {
+		t.Fatalf("get event failed: %v", err)
+	}
+	if event.ID != seeded.ID || event.Name != seeded.Name || event.Currency != "twd" {
+		t.Errorf("got event %+v, want %+v", event, seeded)
+	}
+	if event.Venue.Location != "Taipei Arena" || event.Performer.Name != "Band" {
+		t.Errorf("got venue %+v and performer %+v, want the seeded ones", event.Venue, event.Performer)
+	}
+	if len(event.Tickets) != 1 || event.Tickets[0].ID != ticket.ID || event.Tickets[0].Seat != "A1" || event.Tickets[0].Status != string(models.TicketAvailable) {
+		t.Fatalf("got tickets %+v, want the seeded one", event.Tickets)
+	}
+	if price, err := event.Tickets[0].Price.Float64(); err != nil || price != 1000 {
+		t.Errorf("got ticket price %q, want 1000", event.Tickets[0].Price)
+	}
+
+	_, err = c.GetEvent(context.Background(), seeded.ID+100)
+	expectErr(t, err, client.ErrNotFound, http.StatusNotFound)
+}
+
+func TestBookingLifecycle(t *testing.T) {
+	api := newTestAPI(t)
+	_, ticket := api.seedEvent(t)
+	ctx := context.Background()
+	c := api.register(t, "alice@example.com")
+
+	reservation, err := c.ReserveTicket(ctx, ticket.ID)
+	if err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if reservation.BookingID == 0 || reservation.TicketID != ticket.ID || reservation.Currency != "twd" || reservation.ExpiresAt.Before(time.Now()) {
+		t.Fatalf("incomplete reservation: %+v", reservation)
+	}
+	if price, err := reservation.Price.Float64(); err != nil || price != 1000 || reservation.Price.Decimal != "1000.00" {
+		t.Errorf("got reservation price %q, want 1000", reservation.Price)
+	}
+
+	// Another account finds the ticket held
+	_, err = api.register(t, "bob@example.com").ReserveTicket(ctx, ticket.ID)
+	if !errors.Is(err, client.ErrTicketLocked) && !errors.Is(err, client.ErrTicketUnavailable) {
+		t.Fatalf("got %v reserving a held ticket, want it locked or unavailable", err)
+	}
+
+	bookings, err := c.ListMyBookings(ctx)
+	if err != nil {
+		t.Fatalf("list bookings failed: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].ID != reservation.BookingID || bookings[0].TicketID != ticket.ID || bookings[0].Status != string(models.BookingReserved) {
+		t.Fatalf("got bookings %+v, want the reservation", bookings)
+	}
+
+	cancellation, err := c.CancelBooking(ctx, reservation.BookingID)
+	if err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if cancellation.Message == "" || cancellation.Refund != nil {
+		t.Errorf("got %+v cancelling an unpaid booking, want a message and no refund", cancellation)
+	}
+	_, err = c.CancelBooking(ctx, reservation.BookingID+100)
+	expectErr(t, err, client.ErrNotFound, http.StatusNotFound)
+}
+
+func TestConfirmBooking(t *testing.T) {
+	api := newTestAPI(t)
+	_, ticket := api.seedEvent(t)
+	ctx := context.Background()
+	c := api.register(t, "alice@example.com")
+
+	_, err := c.ConfirmBooking(ctx, ticket.ID, "pm_card_visa")
+	if !errors.Is(err, client.ErrNotFound) && !errors.Is(err, client.ErrInvalidRequest) {
+		t.Fatalf("got %v confirming without a reservation, want it rejected", err)
+	}
+
+	reservation, err := c.ReserveTicket(ctx, ticket.ID)
+	if err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	confirmation, err := c.ConfirmBooking(ctx, ticket.ID, "pm_card_visa")
+	if err != nil {
+		t.Fatalf("confirm failed: %v", err)
+	}
+	if confirmation.BookingID != reservation.BookingID || confirmation.TicketID != ticket.ID || confirmation.JobID == "" || confirmation.StatusURL == "" {
+		t.Fatalf("incomplete confirmation: %+v", confirmation)
+	}
+	if total, err := confirmation.Receipt.Total.Float64(); err != nil || total <= 0 || confirmation.Receipt.Currency != "twd" {
+		t.Errorf("got receipt %+v, want a positive twd total", confirmation.Receipt)
+	}
+}
+
+func TestCallsWithoutSession(t *testing.T) {
+	api := newTestAPI(t)
+	_, ticket := api.seedEvent(t)
+	ctx := context.Background()
+	c := client.New(api.url)
+
+	_, err := c.ReserveTicket(ctx, ticket.ID)
+	expectErr(t, err, client.ErrUnauthorized, http.StatusUnauthorized)
+	if _, err := c.ListMyBookings(ctx); !errors.Is(err, client.ErrNotLoggedIn) {
+		t.Errorf("got %v listing bookings before login, want ErrNotLoggedIn", err)
+	}
+
+	_, err = client.New(api.url, client.WithToken("not-a-jwt")).ReserveTicket(ctx, ticket.ID)
+	expectErr(t, err, client.ErrUnauthorized, http.StatusUnauthorized)
+
+	// A saved token keeps working in a new client
+	token := api.register(t, "alice@example.com").Session().Token
+	if _, err := client.New(api.url, client.WithToken(token)).ReserveTicket(ctx, ticket.ID); err != nil {
+		t.Errorf("reserve with a saved token failed: %v", err)
+	}
+}
+
+func TestRequestsHonourContextAndTimeout(t *testing.T) {
+	api := newTestAPI(t)
+	seeded, _ := api.seedEvent(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.New(api.url).GetEvent(ctx, seeded.ID); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v with a cancelled context, want context.Canceled", err)
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	start := time.Now()
+	if _, err := client.New(slow.URL, client.WithTimeout(50*time.Millisecond)).GetEvent(context.Background(), seeded.ID); err == nil {
+		t.Fatal("a request outliving the timeout succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v, want it cut off by the 50ms timeout", elapsed)
+	}
+}
+
+func TestNonEnvelopeErrorKeepsBody(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-1")
+		http.Error(w, "upstream connect error", http.StatusServiceUnavailable)
+	}))
+	defer proxy.Close()
+
+	_, err := client.New(proxy.URL).GetEvent(context.Background(), 1)
+	expectErr(t, err, client.ErrUnavailable, http.StatusServiceUnavailable)
+	var apiErr *client.APIError
+	errors.As(err, &apiErr)
+	if apiErr.Code != "" || apiErr.Message != "upstream connect error" || apiErr.RequestID != "req-1" {
+		t.Errorf("got %+v, want the raw body and the request ID header", apiErr)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	apperrors "github.com/JonasLeetTheWay/ticketmaster-go/internal/errors"
+)
+
+// Errors an *APIError matches with errors.Is, by its code
+var (
+	ErrInvalidRequest     = errors.New("invalid request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrForbidden          = errors.New("forbidden")
+	ErrAccountSuspended   = errors.New("account suspended")
+	ErrUserExists         = errors.New("user already exists")
+	ErrNotFound           = errors.New("not found")
+	ErrTicketUnavailable  = errors.New("ticket unavailable")
+	ErrTicketLocked       = errors.New("ticket locked")
+	ErrTierSoldOut        = errors.New("ticket tier sold out")
+	ErrNotAdmitted        = errors.New("not admitted from the queue")
+	ErrPaymentFailed      = errors.New("payment failed")
+	ErrPaymentInProgress  = errors.New("payment already in progress")
+	ErrNotCancellable     = errors.New("booking cannot be cancelled")
+	ErrRateLimited        = errors.New("rate limited")
+	ErrUnavailable        = errors.New("service unavailable")
+	ErrSyncInProgress     = errors.New("a full search index sync is already running")
+)
+
+// ErrMFARequired is returned by Login for accounts with MFA enabled, which
+// this client does not support
+var ErrMFARequired = errors.New("login requires an MFA code")
+
+// ErrNotLoggedIn is returned by calls that need the caller's user ID before
+// Login or Register has succeeded
+var ErrNotLoggedIn = errors.New("not logged in")
+
+// codeErrors maps the envelope's codes to the errors above
+var codeErrors = map[string]error{
+	apperrors.ErrCodeInvalidRequest:      ErrInvalidRequest,
+	apperrors.ErrCodeUnauthorized:        ErrUnauthorized,
+	apperrors.ErrCodeInvalidToken:        ErrUnauthorized,
+	apperrors.ErrCodeInvalidCredentials:  ErrInvalidCredentials,
+	apperrors.ErrCodeForbidden:           ErrForbidden,
+	apperrors.ErrCodeAccountSuspended:    ErrAccountSuspended,
+	apperrors.ErrCodeUserExists:          ErrUserExists,
+	apperrors.ErrCodeUserNotFound:        ErrNotFound,
+	apperrors.ErrCodeEventNotFound:       ErrNotFound,
+	apperrors.ErrCodeTicketNotFound:      ErrNotFound,
+	apperrors.ErrCodeBookingNotFound:     ErrNotFound,
+	apperrors.ErrCodeTicketUnavailable:   ErrTicketUnavailable,
+	apperrors.ErrCodeTicketLocked:        ErrTicketLocked,
+	apperrors.ErrCodeTierSoldOut:         ErrTierSoldOut,
+	apperrors.ErrCodeNotAdmitted:         ErrNotAdmitted,
+	apperrors.ErrCodePaymentFailed:       ErrPaymentFailed,
+	apperrors.ErrCodePaymentInProgress:   ErrPaymentInProgress,
+	apperrors.ErrCodeNotCancellable:      ErrNotCancellable,
+	apperrors.ErrCodeLockingUnavailable:  ErrUnavailable,
+	apperrors.ErrCodeUpstreamUnavailable: ErrUnavailable,
+	apperrors.ErrCodeSyncInProgress:      ErrSyncInProgress,
+}
+
+// APIError is an error response from the API. Code is the envelope's
+// machine-readable code; it is empty when the body was not an envelope,
+// e.g. from a proxy in front of the gateway.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	Details    map[string]interface{}
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("ticketmaster: HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("ticketmaster: HTTP %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Unwrap returns the error matching the code, falling back on the status
+// for codes this client does not know
+func (e *APIError) Unwrap() error {
+	if err, ok := codeErrors[e.Code]; ok {
+		return err
+	}
+	switch e.StatusCode {
+	case 401:
+		return ErrUnauthorized
+	case 403:
+		return ErrForbidden
+	case 404:
+		return ErrNotFound
+	case 429:
+		return ErrRateLimited
+	case 502, 503, 504:
+		return ErrUnavailable
+	}
+	return nil
+}
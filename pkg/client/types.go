@@ -0,0 +1,198 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Amount is a sum of money as the API writes it: Decimal is exact and
+// Value is the same amount as a JSON number. Bare numbers, as older
+// servers sent them, fill both.
+type Amount struct {
+	Decimal string      `json:"amount"`
+	Value   json.Number `json:"value"`
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		type object Amount
+		return json.Unmarshal(data, (*object)(a))
+	}
+
+	var value json.Number
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = Amount{Decimal: value.String(), Value: value}
+	return nil
+}
+
+// Float64 returns the amount as a float, for display; compute with Decimal
+func (a Amount) Float64() (float64, error) {
+	return a.Value.Float64()
+}
+
+func (a Amount) String() string {
+	return a.Decimal
+}
+
+// User is the account a session belongs to
+type User struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// Session is the result of logging in or registering. The client keeps
+// using Token for later calls.
+type Session struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	User         User   `json:"user"`
+}
+
+// SearchParams filters SearchEvents; zero fields are not sent
+type SearchParams struct {
+	Term        string
+	Location    string
+	Type        string
+	Date        string // YYYY-MM-DD
+	VenueID     uint
+	PerformerID uint
+	Sort        string // "featured", or empty for relevance
+	Highlight   bool
+}
+
+// EventSummary is an event as the search index has it
+type EventSummary struct {
+	ID               uint                `json:"id"`
+	VenueID          uint                `json:"venueId"`
+	PerformerID      uint                `json:"performerId"`
+	Name             string              `json:"name"`
+	Description      string              `json:"description"`
+	Date             string              `json:"date"`
+	Venue            string              `json:"venue"`
+	Performer        string              `json:"performer"`
+	Genre            string              `json:"genre"`
+	Location         string              `json:"location"`
+	Currency         string              `json:"currency"`
+	MinPrice         float64             `json:"minPrice"`
+	MaxPrice         float64             `json:"maxPrice"`
+	AvailableTickets int                 `json:"availableTickets"`
+	Featured         bool                `json:"featured"`
+	Highlights       map[string][]string `json:"highlights,omitempty"`
+}
+
+// SearchResult is a page of SearchEvents matches
+type SearchResult struct {
+	Events []EventSummary `json:"events"`
+	Count  int            `json:"count"`
+}
+
+// Venue is where an event takes place
+type Venue struct {
+	ID       uint   `json:"ID"`
+	Location string `json:"Location"`
+	Capacity int    `json:"Capacity"`
+	Country  string `json:"Country"`
+	SeatMap  string `json:"SeatMap"`
+}
+
+// Performer is who an event features
+type Performer struct {
+	ID          uint   `json:"ID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	Genre       string `json:"Genre"`
+}
+
+// Ticket is one seat of an event
+type Ticket struct {
+	ID      uint   `json:"ID"`
+	EventID uint   `json:"EventID"`
+	TierID  *uint  `json:"TierID"`
+	Seat    string `json:"Seat"`
+	Price   Amount `json:"Price"`
+	Status  string `json:"Status"`
+}
+
+// Event is an event with its venue, performer and tickets
+type Event struct {
+	ID          uint      `json:"ID"`
+	Name        string    `json:"Name"`
+	Description string    `json:"Description"`
+	Date        time.Time `json:"Date"`
+	SoldOut     bool      `json:"SoldOut"`
+	Capacity    int       `json:"Capacity"`
+	Currency    string    `json:"Currency"`
+	Featured    bool      `json:"Featured"`
+	Venue       Venue     `json:"Venue"`
+	Performer   Performer `json:"Performer"`
+	Tickets     []Ticket  `json:"Tickets"`
+}
+
+// Reservation holds a ticket for the caller until ExpiresAt
+type Reservation struct {
+	BookingID uint      `json:"bookingId"`
+	TicketID  uint      `json:"ticketId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Price     Amount    `json:"price"`
+	Currency  string    `json:"currency"`
+	Message   string    `json:"message"`
+}
+
+// Receipt itemizes what a booking is charged
+type Receipt struct {
+	BookingID  uint    `json:"bookingId"`
+	FaceValue  Amount  `json:"faceValue"`
+	ServiceFee Amount  `json:"serviceFee"`
+	TaxCountry string  `json:"taxCountry"`
+	TaxRate    float64 `json:"taxRate"` // Percent
+	Tax        Amount  `json:"tax"`
+	Rounding   Amount  `json:"rounding"`
+	Total      Amount  `json:"total"`
+	Currency   string  `json:"currency"`
+}
+
+// Confirmation is an accepted payment. The charge runs in the background;
+// StatusURL reports how it went.
+type Confirmation struct {
+	BookingID uint    `json:"bookingId"`
+	TicketID  uint    `json:"ticketId"`
+	JobID     string  `json:"jobId"`
+	Status    string  `json:"status"`
+	StatusURL string  `json:"statusUrl"`
+	Receipt   Receipt `json:"receipt"`
+	Message   string  `json:"message"`
+}
+
+// Refund is the money returned for a cancelled, paid booking
+type Refund struct {
+	ID        uint      `json:"ID"`
+	BookingID uint      `json:"BookingID"`
+	Amount    Amount    `json:"Amount"`
+	Currency  string    `json:"Currency"`
+	Status    string    `json:"Status"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// Cancellation is a cancelled booking; Refund is nil when nothing was paid
+type Cancellation struct {
+	Message string  `json:"message"`
+	Refund  *Refund `json:"refund,omitempty"`
+}
+
+// Booking is one of the caller's bookings
+type Booking struct {
+	ID         uint      `json:"ID"`
+	TicketID   uint      `json:"TicketID"`
+	UserID     uint      `json:"UserID"`
+	Status     string    `json:"Status"`
+	ReservedAt time.Time `json:"ReservedAt"`
+	ExpiresAt  time.Time `json:"ExpiresAt"`
+	PaymentID  string    `json:"PaymentID"`
+	Price      Amount    `json:"Price"`
+	AmountPaid Amount    `json:"AmountPaid"`
+	Ticket     Ticket    `json:"Ticket"`
+}